@@ -10,7 +10,29 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/attachments"
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/internal/apps/budgets"
+	"github.com/ZiadMansourM/budgetly/internal/apps/debts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/filters"
+	"github.com/ZiadMansourM/budgetly/internal/apps/forecasts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/goals"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/internal/apps/investments"
+	"github.com/ZiadMansourM/budgetly/internal/apps/loans"
+	"github.com/ZiadMansourM/budgetly/internal/apps/notifications"
+	"github.com/ZiadMansourM/budgetly/internal/apps/payees"
+	"github.com/ZiadMansourM/budgetly/internal/apps/periods"
+	"github.com/ZiadMansourM/budgetly/internal/apps/rates"
+	"github.com/ZiadMansourM/budgetly/internal/apps/reconciliations"
+	"github.com/ZiadMansourM/budgetly/internal/apps/reports"
+	"github.com/ZiadMansourM/budgetly/internal/apps/rules"
+	"github.com/ZiadMansourM/budgetly/internal/apps/tags"
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
 	"github.com/ZiadMansourM/budgetly/internal/apps/users"
+	"github.com/ZiadMansourM/budgetly/internal/apps/webhooks"
+	"github.com/ZiadMansourM/budgetly/pkg/blob"
 	"github.com/ZiadMansourM/budgetly/pkg/db"
 	"github.com/ZiadMansourM/budgetly/utils"
 	"github.com/jmoiron/sqlx"
@@ -53,6 +75,133 @@ func (b *serverBuilder) WithUserApp() *serverBuilder {
 	return b
 }
 
+// WithHouseholdsApp sets up the households application (model, service, handler, and routes)
+func (b *serverBuilder) WithHouseholdsApp() *serverBuilder {
+	households.NewHouseholdsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithAccountsApp sets up the accounts application (model, service, handler, and routes)
+func (b *serverBuilder) WithAccountsApp() *serverBuilder {
+	accounts.NewAccountsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithTransactionsApp sets up the transactions application (model, service, handler, and routes)
+func (b *serverBuilder) WithTransactionsApp() *serverBuilder {
+	transactions.NewTransactionsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithRulesApp sets up the categorization rules application (model, service, handler, and routes)
+func (b *serverBuilder) WithRulesApp() *serverBuilder {
+	rules.NewRulesApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithRatesApp sets up the exchange rates application (model, service, handler, and routes)
+func (b *serverBuilder) WithRatesApp() *serverBuilder {
+	rates.NewRatesApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithTagsApp sets up the transaction tags application (model, service, handler, and routes)
+func (b *serverBuilder) WithTagsApp() *serverBuilder {
+	tags.NewTagsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithFiltersApp sets up the saved filters application (model, service, handler, and routes)
+func (b *serverBuilder) WithFiltersApp() *serverBuilder {
+	filters.NewFiltersApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithAttachmentsApp sets up the attachments application (model, service, handler, and routes),
+// storing files in the given blob store and accepting uploads of up to maxSize bytes
+func (b *serverBuilder) WithAttachmentsApp(store blob.Store, maxSize int64) *serverBuilder {
+	attachments.NewAttachmentsApp(b.dbPool, b.logger, b.router, store, maxSize)
+	return b
+}
+
+// WithBudgetsApp sets up the budgets application (model, service, handler, and routes)
+func (b *serverBuilder) WithBudgetsApp() *serverBuilder {
+	budgets.NewBudgetsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithGoalsApp sets up the savings goals application (model, service, handler, and routes)
+func (b *serverBuilder) WithGoalsApp() *serverBuilder {
+	goals.NewGoalsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithDebtsApp sets up the debt payoff planner application (model, service, handler, and routes)
+func (b *serverBuilder) WithDebtsApp() *serverBuilder {
+	debts.NewDebtsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithLoansApp sets up the loans application (model, service, handler, and routes)
+func (b *serverBuilder) WithLoansApp() *serverBuilder {
+	loans.NewLoansApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithForecastsApp sets up the cash-flow forecast application (model, service, handler, and routes)
+func (b *serverBuilder) WithForecastsApp() *serverBuilder {
+	forecasts.NewForecastsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithNotificationsApp sets up the alerts and notifications application (model, service, handler, and routes)
+func (b *serverBuilder) WithNotificationsApp() *serverBuilder {
+	notifications.NewNotificationsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithWebhooksApp sets up the outbound webhooks application (model, service, handler, routes, and dispatcher)
+func (b *serverBuilder) WithWebhooksApp() *serverBuilder {
+	webhooks.NewWebhooksApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithPayeesApp sets up the payee directory application (model, service, handler, and routes)
+func (b *serverBuilder) WithPayeesApp() *serverBuilder {
+	payees.NewPayeesApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithInvestmentsApp sets up the investment holdings application (model, service, handler, and routes)
+func (b *serverBuilder) WithInvestmentsApp() *serverBuilder {
+	investments.NewInvestmentsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithBalancesApp sets up the balance history and net worth application (model, service, handler, routes, and snapshot job)
+func (b *serverBuilder) WithBalancesApp() *serverBuilder {
+	balances.NewBalancesApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithPeriodsApp sets up the fiscal periods application (model, service, handler, and routes)
+func (b *serverBuilder) WithPeriodsApp() *serverBuilder {
+	periods.NewPeriodsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithReconciliationsApp sets up the reconciliations application (model, service, handler, and routes)
+func (b *serverBuilder) WithReconciliationsApp() *serverBuilder {
+	reconciliations.NewReconciliationsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
+// WithReportsApp sets up the reports application (model, service, handler, and routes)
+func (b *serverBuilder) WithReportsApp() *serverBuilder {
+	reports.NewReportsApp(b.dbPool, b.logger, b.router)
+	return b
+}
+
 // WithHealthCheck adds a health check route
 func (b *serverBuilder) WithHealthCheck() *serverBuilder {
 	b.router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
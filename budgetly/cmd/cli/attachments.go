@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/attachments"
+	"github.com/ZiadMansourM/budgetly/pkg/db"
+	"github.com/ZiadMansourM/budgetly/pkg/settings"
+)
+
+// runAttachments dispatches the "attachments" subcommands
+func runAttachments(args []string, settings *settings.Settings) error {
+	if len(args) == 0 || args[0] != "gc" {
+		return fmt.Errorf("%w: expected \"attachments gc\"\n\n%s", ErrUnknownCommand, usage)
+	}
+	return runAttachmentsGC(args[1:], settings)
+}
+
+// runAttachmentsGC deletes orphaned blobs, e.g. files of deleted attachments
+func runAttachmentsGC(args []string, settings *settings.Settings) error {
+	flags := flag.NewFlagSet("attachments gc", flag.ContinueOnError)
+	grace := flags.Duration("grace", 24*time.Hour, "keep orphaned files younger than this, as their upload may still be in progress (at least 1h)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	pool, err := db.OpenDB("postgres", settings.DBConnectionString)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	deleted, err := attachments.CollectGarbage(context.Background(), pool, settings.Logger, settings.BlobStore, *grace)
+	if err != nil {
+		return fmt.Errorf("error collecting orphaned attachments: %w", err)
+	}
+
+	settings.Logger.Info("Orphaned attachment files deleted", "count", len(deleted))
+	return nil
+}
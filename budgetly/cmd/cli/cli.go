@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ZiadMansourM/budgetly/pkg/settings"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+// Run executes the command line subcommand named by args[0], e.g. "rates import <file>"
+func Run(args []string, settings *settings.Settings) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: expected a subcommand\n\n%s", ErrUnknownCommand, usage)
+	}
+
+	switch args[0] {
+	case "rates":
+		return runRates(args[1:], settings)
+	case "attachments":
+		return runAttachments(args[1:], settings)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return nil
+	default:
+		return fmt.Errorf("%w: %q\n\n%s", ErrUnknownCommand, args[0], usage)
+	}
+}
+
+const usage = `Usage:
+  budgetly                                    Start the HTTP server
+  budgetly rates import [flags] <file>        Import exchange rates from an ECB XML or CSV file
+  budgetly attachments gc [flags]             Delete stored files no attachment references
+`
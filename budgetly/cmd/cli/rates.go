@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/rates"
+	"github.com/ZiadMansourM/budgetly/pkg/db"
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/settings"
+)
+
+// runRates dispatches the "rates" subcommands
+func runRates(args []string, settings *settings.Settings) error {
+	if len(args) == 0 || args[0] != "import" {
+		return fmt.Errorf("%w: expected \"rates import\"\n\n%s", ErrUnknownCommand, usage)
+	}
+	return runRatesImport(args[1:], settings)
+}
+
+// runRatesImport imports exchange rates from a downloaded ECB XML or CSV file
+func runRatesImport(args []string, settings *settings.Settings) error {
+	flags := flag.NewFlagSet("rates import", flag.ContinueOnError)
+	fill := flags.Bool("fill", true, "fill weekends and holidays with the last known rate")
+	until := flags.String("until", "", "fill gaps through this date (YYYY-MM-DD) instead of the last date in the file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("expected exactly one file, got %d\n\n%s", flags.NArg(), usage)
+	}
+
+	var untilDate time.Time
+	if *until != "" {
+		var err error
+		if untilDate, err = time.Parse(time.DateOnly, *until); err != nil {
+			return fmt.Errorf("invalid -until date: %w", err)
+		}
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("error opening rate file: %w", err)
+	}
+	defer file.Close()
+
+	parsed, err := fx.Parse(file)
+	if err != nil {
+		return err
+	}
+
+	if *fill {
+		parsed = fx.FillGaps(parsed, untilDate)
+	}
+
+	pool, err := db.OpenDB("postgres", settings.DBConnectionString)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	if err := rates.ImportRates(pool, settings.Logger, parsed); err != nil {
+		return fmt.Errorf("error storing exchange rates: %w", err)
+	}
+
+	settings.Logger.Info("Exchange rates imported", "file", flags.Arg(0), "count", len(parsed))
+	return nil
+}
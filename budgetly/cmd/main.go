@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/ZiadMansourM/budgetly/cmd/api"
+	"github.com/ZiadMansourM/budgetly/cmd/cli"
 	"github.com/ZiadMansourM/budgetly/pkg/middlewares"
 	"github.com/ZiadMansourM/budgetly/pkg/settings"
 )
@@ -16,17 +18,48 @@ func main() {
 		WithLogger().
 		WithDBConnection().
 		WithServerAddress().
+		WithBlobStore().
 		Build()
 
 	if err != nil {
 		panic(fmt.Sprintf("Error initializing settings: %v", err))
 	}
 
+	// Run a command line subcommand (e.g. "rates import <file>") instead of the server
+	if len(os.Args) > 1 {
+		if err := cli.Run(os.Args[1:], settings); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Use the builder to assemble the server with plug-and-play apps
 	// E.g. WithUserApp which encapsulate all its components (model, service, handler, routes).
 	serverBuilder := api.NewServerBuilder(settings.Logger).
 		WithDatabase("postgres", settings.DBConnectionString).
 		WithUserApp().
+		WithHouseholdsApp().
+		WithAccountsApp().
+		WithTransactionsApp().
+		WithRulesApp().
+		WithRatesApp().
+		WithTagsApp().
+		WithFiltersApp().
+		WithAttachmentsApp(settings.BlobStore, settings.MaxAttachmentSize).
+		WithBudgetsApp().
+		WithGoalsApp().
+		WithDebtsApp().
+		WithLoansApp().
+		WithForecastsApp().
+		WithNotificationsApp().
+		WithWebhooksApp().
+		WithPayeesApp().
+		WithInvestmentsApp().
+		WithBalancesApp().
+		WithPeriodsApp().
+		WithReconciliationsApp().
+		WithReportsApp().
 		WithHealthCheck().
 		Use(middlewares.LoggingMiddleware).
 		BuildServer(settings.ServerAddress)
@@ -0,0 +1,25 @@
+package accounts
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewAccountsApp creates a new accounts application with the provided database connection
+func NewAccountsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	accountModel := newAccountModel(db, logger)
+	accountService := newAccountService(accountModel, logger)
+	newAccountHandler(accountService, logger, router)
+}
+
+// Get returns an account by ID, or ErrAccountNotFound
+func Get(db *sqlx.DB, logger *slog.Logger, id int64) (*Account, error) {
+	return newAccountModel(db, logger).getByID(id)
+}
+
+// List returns a household's accounts ordered by name
+func List(db *sqlx.DB, logger *slog.Logger, householdID int64) ([]Account, error) {
+	return newAccountModel(db, logger).list(householdID)
+}
@@ -0,0 +1,104 @@
+package accounts
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// Account is a bank, cash or card account of a household. Its transactions are all in
+// the account's currency. Off-budget (tracking) accounts, such as a mortgage or a pension,
+// hold money that is not part of the envelope budget.
+type Account struct {
+	ID          int64          `db:"id"`
+	HouseholdID int64          `db:"household_id"`
+	Name        string         `db:"name"`
+	IBAN        sql.NullString `db:"iban"`
+	Currency    string         `db:"currency"`
+	OnBudget    bool           `db:"on_budget"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+// MatchesIBAN reports whether a statement for iban may be imported into the account:
+// either side being unknown matches, otherwise the IBANs must be equal.
+func (a *Account) MatchesIBAN(iban string) bool {
+	iban = NormalizeIBAN(iban)
+	return !a.IBAN.Valid || iban == "" || a.IBAN.String == iban
+}
+
+// NormalizeIBAN removes spaces from an IBAN and upper-cases it.
+func NormalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+}
+
+// AccountRequest represents the input data for creating or updating an account.
+type AccountRequest struct {
+	Name     string `json:"name"`
+	IBAN     string `json:"iban"`
+	Currency string `json:"currency"`
+	// OnBudget defaults to true; set it to false for a tracking account.
+	OnBudget *bool `json:"on_budget"`
+}
+
+// Validate validates the AccountRequest struct.
+func (input *AccountRequest) Validate() map[string]string {
+	input.Name = strings.TrimSpace(input.Name)
+	input.IBAN = NormalizeIBAN(input.IBAN)
+	input.Currency = strings.ToUpper(strings.TrimSpace(input.Currency))
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Name": validate.Rules(
+			validate.Required,
+			validate.Max(100),
+			validate.ErrorMessage("Name is required and must be at most 100 characters long"),
+		),
+		"IBAN": validate.Rules(
+			validate.Max(34),
+			validate.ErrorMessage("IBAN must be at most 34 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if !money.IsCurrency(input.Currency) {
+		errors["Currency"] = "Currency must be a supported ISO 4217 code"
+	}
+	return errors
+}
+
+// toAccount converts a validated AccountRequest into an Account of the household.
+func (input *AccountRequest) toAccount(householdID int64) *Account {
+	return &Account{
+		HouseholdID: householdID,
+		Name:        input.Name,
+		IBAN:        sql.NullString{String: input.IBAN, Valid: input.IBAN != ""},
+		Currency:    input.Currency,
+		OnBudget:    input.OnBudget == nil || *input.OnBudget,
+	}
+}
+
+// AccountResponse represents the account data to return in responses.
+type AccountResponse struct {
+	ID          int64  `json:"id"`
+	HouseholdID int64  `json:"household_id"`
+	Name        string `json:"name"`
+	IBAN        string `json:"iban,omitempty"`
+	Currency    string `json:"currency"`
+	OnBudget    bool   `json:"on_budget"`
+}
+
+// ToResponse converts an Account (from database) to an AccountResponse (for API responses).
+func (a *Account) ToResponse() *AccountResponse {
+	return &AccountResponse{
+		ID:          a.ID,
+		HouseholdID: a.HouseholdID,
+		Name:        a.Name,
+		IBAN:        a.IBAN.String,
+		Currency:    a.Currency,
+		OnBudget:    a.OnBudget,
+	}
+}
@@ -0,0 +1,8 @@
+package accounts
+
+import "errors"
+
+var (
+	ErrInternalServer  error = errors.New("internal server error")
+	ErrAccountNotFound error = errors.New("account not found")
+)
@@ -0,0 +1,186 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// accountHandler is an HTTP handler for account operations
+// (e.g., opening, renaming, closing accounts, etc.)
+type accountHandler struct {
+	accountService *accountService
+	logger         *slog.Logger
+	router         *http.ServeMux
+}
+
+// newAccountHandler creates a new account handler with the provided account service and logger
+func newAccountHandler(accountService *accountService, logger *slog.Logger, router *http.ServeMux) *accountHandler {
+	accountHandler := &accountHandler{
+		accountService: accountService,
+		logger:         logger,
+		router:         router,
+	}
+	accountHandler.registerRoutes()
+	return accountHandler
+}
+
+// Register routes for account-related actions
+func (h *accountHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/accounts", h.create)
+	h.router.HandleFunc("GET /households/{household}/accounts", h.list)
+	h.router.HandleFunc("GET /accounts/{id}", h.get)
+	h.router.HandleFunc("PUT /accounts/{id}", h.update)
+	h.router.HandleFunc("DELETE /accounts/{id}", h.delete)
+}
+
+// Create is an HTTP handler for opening a new account in a household
+func (h *accountHandler) create(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req AccountRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	account, err := h.accountService.create(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, account)
+}
+
+// List is an HTTP handler for listing a household's accounts
+func (h *accountHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.accountService.list(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, accounts)
+}
+
+// Get is an HTTP handler for retrieving an account by ID
+func (h *accountHandler) get(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	account, err := h.accountService.get(accountID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, account)
+}
+
+// Update is an HTTP handler for renaming an account or changing its IBAN
+func (h *accountHandler) update(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req AccountRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	account, err := h.accountService.update(accountID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, account)
+}
+
+// Delete is an HTTP handler for deleting an account and its transactions
+func (h *accountHandler) delete(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.accountService.delete(accountID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *accountHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses the account ID path parameter, writing a 400 response on failure
+func (h *accountHandler) pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	accountID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || accountID <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid account ID"},
+		)
+		return 0, false
+	}
+	return accountID, true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *accountHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *accountHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrAccountNotFound), errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling account request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,113 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package accounts
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// accountColumns lists the columns selected for an Account
+const accountColumns = `id, household_id, name, iban, currency, on_budget, created_at`
+
+// accountModel wraps the database connection pool using sqlx
+type accountModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newAccountModel(db *sqlx.DB, logger *slog.Logger) *accountModel {
+	return &accountModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new account into the database and returns its ID
+func (m *accountModel) create(a *Account) (int64, error) {
+	query := `INSERT INTO accounts (household_id, name, iban, currency, on_budget, created_at)
+	VALUES (:household_id, :name, :iban, :currency, :on_budget, :created_at)
+	RETURNING id`
+
+	a.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, a)
+	if err != nil {
+		m.logger.Error("Error inserting account", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&a.ID); err != nil {
+			m.logger.Error("Error scanning account ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Account created successfully", "id", a.ID)
+	return a.ID, nil
+}
+
+// List returns a household's accounts ordered by name
+func (m *accountModel) list(householdID int64) ([]Account, error) {
+	accounts := []Account{}
+	query := `SELECT ` + accountColumns + ` FROM accounts WHERE household_id = $1 ORDER BY name, id`
+	if err := m.DB.Select(&accounts, query, householdID); err != nil {
+		m.logger.Error("Error listing accounts", "error", err)
+		return nil, ErrInternalServer
+	}
+	return accounts, nil
+}
+
+// GetByID returns an account by ID
+func (m *accountModel) getByID(id int64) (*Account, error) {
+	a := &Account{}
+	err := m.DB.Get(a, `SELECT `+accountColumns+` FROM accounts WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAccountNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting account by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return a, nil
+}
+
+// Update renames an account, changes its IBAN or moves it on or off the budget and returns it
+func (m *accountModel) update(a *Account) (*Account, error) {
+	updated := &Account{}
+	query := `UPDATE accounts SET name = $2, iban = $3, on_budget = $4 WHERE id = $1 RETURNING ` + accountColumns
+	err := m.DB.Get(updated, query, a.ID, a.Name, a.IBAN, a.OnBudget)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAccountNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error updating account", "error", err)
+		return nil, ErrInternalServer
+	}
+	return updated, nil
+}
+
+// Delete removes an account by ID together with its transactions
+func (m *accountModel) delete(id int64) error {
+	result, err := m.DB.Exec(`DELETE FROM accounts WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting account", "error", err)
+		return ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected account count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrAccountNotFound
+	}
+	return nil
+}
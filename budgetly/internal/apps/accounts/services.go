@@ -0,0 +1,97 @@
+package accounts
+
+import (
+	"log/slog"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type accountService struct {
+	accountRepo *accountModel
+	logger      *slog.Logger
+}
+
+func newAccountService(accountRepo *accountModel, logger *slog.Logger) *accountService {
+	return &accountService{
+		accountRepo: accountRepo,
+		logger:      logger,
+	}
+}
+
+// create validates and stores a new account of a household
+func (s *accountService) create(householdID int64, input AccountRequest) (*AccountResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Account validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.accountRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	account := input.toAccount(householdID)
+	if _, err := s.accountRepo.create(account); err != nil {
+		return nil, err
+	}
+	return account.ToResponse(), nil
+}
+
+// list returns a household's accounts
+func (s *accountService) list(householdID int64) ([]*AccountResponse, error) {
+	if _, err := households.Get(s.accountRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	accounts, err := s.accountRepo.list(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*AccountResponse, 0, len(accounts))
+	for i := range accounts {
+		responses = append(responses, accounts[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// get returns an account by ID
+func (s *accountService) get(id int64) (*AccountResponse, error) {
+	account, err := s.accountRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return account.ToResponse(), nil
+}
+
+// update validates and renames an account, changes its IBAN or moves it on or off the
+// budget, which is kept when not given. The currency cannot change, since the account's
+// transactions are all in it.
+func (s *accountService) update(id int64, input AccountRequest) (*AccountResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Account validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	existing, err := s.accountRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Currency != input.Currency {
+		return nil, &validate.ValidationError{Errors: map[string]string{"Currency": "Currency cannot be changed"}}
+	}
+
+	account := input.toAccount(existing.HouseholdID)
+	account.ID = id
+	if input.OnBudget == nil {
+		account.OnBudget = existing.OnBudget
+	}
+	updated, err := s.accountRepo.update(account)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToResponse(), nil
+}
+
+// delete removes an account and its transactions
+func (s *accountService) delete(id int64) error {
+	return s.accountRepo.delete(id)
+}
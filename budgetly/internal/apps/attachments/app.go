@@ -0,0 +1,28 @@
+package attachments
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/blob"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewAttachmentsApp creates a new attachments application storing files in the blob store,
+// accepting uploads of up to maxSize bytes
+func NewAttachmentsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux, store blob.Store, maxSize int64) {
+	attachmentModel := newAttachmentModel(db, logger)
+	attachmentService := newAttachmentService(attachmentModel, store, maxSize, logger)
+	newAttachmentHandler(attachmentService, logger, router)
+}
+
+// CollectGarbage deletes blobs that no attachment references and that are older than grace,
+// e.g. files left behind by deleted attachments. It returns the deleted blob keys.
+func CollectGarbage(ctx context.Context, db *sqlx.DB, logger *slog.Logger, store blob.Store, grace time.Duration) ([]string, error) {
+	attachmentModel := newAttachmentModel(db, logger)
+	return blob.CollectGarbage(ctx, store, func(context.Context) (map[string]bool, error) {
+		return attachmentModel.referencedKeys()
+	}, grace)
+}
@@ -0,0 +1,41 @@
+package attachments
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Attachment struct {
+	ID            int            `db:"id"`
+	TransactionID int            `db:"transaction_id"`
+	Filename      string         `db:"filename"`
+	ContentType   string         `db:"content_type"`
+	Size          int64          `db:"size"`
+	BlobKey       string         `db:"blob_key"`
+	ThumbnailKey  sql.NullString `db:"thumbnail_key"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
+
+// AttachmentResponse represents the attachment data to return in responses.
+type AttachmentResponse struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	Filename      string    `json:"filename"`
+	ContentType   string    `json:"content_type"`
+	Size          int64     `json:"size"`
+	HasThumbnail  bool      `json:"has_thumbnail"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ToResponse converts an Attachment (from database) to an AttachmentResponse (for API responses).
+func (a *Attachment) ToResponse() *AttachmentResponse {
+	return &AttachmentResponse{
+		ID:            a.ID,
+		TransactionID: a.TransactionID,
+		Filename:      a.Filename,
+		ContentType:   a.ContentType,
+		Size:          a.Size,
+		HasThumbnail:  a.ThumbnailKey.Valid,
+		CreatedAt:     a.CreatedAt,
+	}
+}
@@ -0,0 +1,10 @@
+package attachments
+
+import "errors"
+
+var (
+	ErrInternalServer     error = errors.New("internal server error")
+	ErrAttachmentNotFound error = errors.New("attachment not found")
+	ErrNoThumbnail        error = errors.New("attachment has no thumbnail")
+	ErrUnsupportedType    error = errors.New("unsupported attachment type, expected an image or a PDF")
+)
@@ -0,0 +1,204 @@
+package attachments
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
+	"github.com/ZiadMansourM/budgetly/pkg/blob"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// multipartOverhead allows for the multipart headers and boundaries around an uploaded file
+const multipartOverhead = 1 << 20
+
+// attachmentHandler is an HTTP handler for attachment operations
+// (e.g., uploading, downloading, listing, deleting files, etc.)
+type attachmentHandler struct {
+	attachmentService *attachmentService
+	logger            *slog.Logger
+	router            *http.ServeMux
+}
+
+// newAttachmentHandler creates a new attachment handler with the provided attachment service and logger
+func newAttachmentHandler(attachmentService *attachmentService, logger *slog.Logger, router *http.ServeMux) *attachmentHandler {
+	attachmentHandler := &attachmentHandler{
+		attachmentService: attachmentService,
+		logger:            logger,
+		router:            router,
+	}
+	attachmentHandler.registerRoutes()
+	return attachmentHandler
+}
+
+// Register routes for attachment actions
+func (h *attachmentHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/transactions/{id}/attachments", h.upload)
+	h.router.HandleFunc("GET /households/{household}/transactions/{id}/attachments", h.list)
+	h.router.HandleFunc("GET /attachments/{id}", h.download)
+	h.router.HandleFunc("GET /attachments/{id}/thumbnail", h.thumbnail)
+	h.router.HandleFunc("DELETE /attachments/{id}", h.delete)
+}
+
+// Upload is an HTTP handler for attaching a file, sent as a multipart "file" field, to a
+// transaction of a household
+func (h *attachmentHandler) upload(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+	transactionID, ok := h.pathID(w, r, "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.attachmentService.maxSize+multipartOverhead)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.writeError(w, blob.ErrTooLarge)
+			return
+		}
+		h.logger.Warn("Invalid upload", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Expected a multipart \"file\" field"},
+		)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.upload(r.Context(), householdID, transactionID, header.Filename, file)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, attachment)
+}
+
+// List is an HTTP handler for listing the attachments of a transaction of a household
+func (h *attachmentHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+	transactionID, ok := h.pathID(w, r, "Invalid transaction ID")
+	if !ok {
+		return
+	}
+
+	attachments, err := h.attachmentService.list(householdID, transactionID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, attachments)
+}
+
+// Download is an HTTP handler for downloading an attachment
+func (h *attachmentHandler) download(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, false)
+}
+
+// Thumbnail is an HTTP handler for downloading the thumbnail of an image attachment
+func (h *attachmentHandler) thumbnail(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, true)
+}
+
+// Delete is an HTTP handler for deleting an attachment
+func (h *attachmentHandler) delete(w http.ResponseWriter, r *http.Request) {
+	attachmentID, ok := h.pathID(w, r, "Invalid attachment ID")
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.delete(attachmentID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serve streams an attachment, or its thumbnail, to the client
+func (h *attachmentHandler) serve(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	attachmentID, ok := h.pathID(w, r, "Invalid attachment ID")
+	if !ok {
+		return
+	}
+
+	attachment, reader, err := h.attachmentService.open(r.Context(), attachmentID, thumbnail)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	defer reader.Close()
+
+	contentType := attachment.ContentType
+	if thumbnail {
+		contentType = "image/jpeg"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.Filename}))
+	if !thumbnail {
+		w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	}
+
+	if _, err := io.Copy(w, reader); err != nil {
+		h.logger.Warn("Error streaming attachment", "id", attachmentID, "error", err)
+	}
+}
+
+// pathID parses the ID from the URL, writing a 400 response on failure
+func (h *attachmentHandler) pathID(w http.ResponseWriter, r *http.Request, message string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		utils.WriteJson(w, http.StatusBadRequest, map[string]string{"error": message})
+		return 0, false
+	}
+	return id, true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *attachmentHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *attachmentHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, blob.ErrEmpty):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, blob.ErrTooLarge):
+		utils.WriteJson(w, http.StatusRequestEntityTooLarge, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrUnsupportedType):
+		utils.WriteJson(w, http.StatusUnsupportedMediaType, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrAttachmentNotFound), errors.Is(err, ErrNoThumbnail), errors.Is(err, transactions.ErrTransactionNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling attachment request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
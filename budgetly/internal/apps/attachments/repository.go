@@ -0,0 +1,116 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package attachments
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// attachmentColumns lists the columns selected for an Attachment
+const attachmentColumns = `id, transaction_id, filename, content_type, size, blob_key, thumbnail_key, created_at`
+
+// attachmentModel wraps the database connection pool using sqlx
+type attachmentModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newAttachmentModel(db *sqlx.DB, logger *slog.Logger) *attachmentModel {
+	return &attachmentModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new attachment into the database and returns its ID
+func (m *attachmentModel) create(a *Attachment) (int, error) {
+	query := `INSERT INTO attachments (transaction_id, filename, content_type, size, blob_key, thumbnail_key, created_at)
+	VALUES (:transaction_id, :filename, :content_type, :size, :blob_key, :thumbnail_key, :created_at)
+	RETURNING id`
+
+	a.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, a)
+	if err != nil {
+		m.logger.Error("Error inserting attachment", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&a.ID); err != nil {
+			m.logger.Error("Error scanning attachment ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Attachment created successfully", "id", a.ID)
+	return a.ID, nil
+}
+
+// ListByTransaction returns the attachments of a transaction, oldest first
+func (m *attachmentModel) listByTransaction(transactionID int) ([]Attachment, error) {
+	attachments := []Attachment{}
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE transaction_id = $1 ORDER BY created_at, id`
+	if err := m.DB.Select(&attachments, query, transactionID); err != nil {
+		m.logger.Error("Error listing attachments", "error", err)
+		return nil, ErrInternalServer
+	}
+	return attachments, nil
+}
+
+// GetByID returns an attachment by ID
+func (m *attachmentModel) getByID(id int) (*Attachment, error) {
+	a := &Attachment{}
+	err := m.DB.Get(a, `SELECT `+attachmentColumns+` FROM attachments WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting attachment by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return a, nil
+}
+
+// Delete removes an attachment by ID. Its blobs are left for garbage collection,
+// since identical files uploaded elsewhere share them.
+func (m *attachmentModel) delete(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting attachment", "error", err)
+		return ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected attachment count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrAttachmentNotFound
+	}
+	return nil
+}
+
+// ReferencedKeys returns every blob key still used by an attachment or its thumbnail
+func (m *attachmentModel) referencedKeys() (map[string]bool, error) {
+	var keys []string
+	query := `SELECT blob_key FROM attachments
+	UNION SELECT thumbnail_key FROM attachments WHERE thumbnail_key IS NOT NULL`
+	if err := m.DB.Select(&keys, query); err != nil {
+		m.logger.Error("Error listing referenced blob keys", "error", err)
+		return nil, ErrInternalServer
+	}
+
+	referenced := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		referenced[key] = true
+	}
+	return referenced, nil
+}
@@ -0,0 +1,154 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
+	"github.com/ZiadMansourM/budgetly/pkg/blob"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// thumbnailSize is the maximum width and height of generated thumbnails in pixels
+const thumbnailSize = 256
+
+// allowedTypes lists the sniffed content types accepted as attachments,
+// and whether a thumbnail can be generated for them
+var allowedTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      false,
+	"application/pdf": false,
+}
+
+type attachmentService struct {
+	attachmentRepo *attachmentModel
+	store          blob.Store
+	maxSize        int64
+	logger         *slog.Logger
+}
+
+func newAttachmentService(attachmentRepo *attachmentModel, store blob.Store, maxSize int64, logger *slog.Logger) *attachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		store:          store,
+		maxSize:        maxSize,
+		logger:         logger,
+	}
+}
+
+// upload stores the file in the blob store, generates a thumbnail for images,
+// and records the attachment against a transaction of the household
+func (s *attachmentService) upload(ctx context.Context, householdID int64, transactionID int, filename string, file io.Reader) (*AttachmentResponse, error) {
+	filename = strings.TrimSpace(filepath.Base(filename))
+	if filename == "" || filename == "." || len(filename) > 255 {
+		return nil, &validate.ValidationError{Errors: map[string]string{
+			"Filename": "Filename is required and must be at most 255 characters long",
+		}}
+	}
+	// Check the transaction before storing anything, so no blob is kept for it otherwise.
+	if err := s.checkTransaction(householdID, transactionID); err != nil {
+		return nil, err
+	}
+
+	object, data, err := blob.Upload(ctx, s.store, file, s.maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop parameters such as "; charset=utf-8" before checking the type.
+	contentType, _, _ := strings.Cut(object.ContentType, ";")
+	thumbnailable, ok := allowedTypes[contentType]
+	if !ok {
+		// The blob is left for garbage collection, as another attachment may share it.
+		return nil, fmt.Errorf("%w, got %s", ErrUnsupportedType, contentType)
+	}
+
+	attachment := &Attachment{
+		TransactionID: transactionID,
+		Filename:      filename,
+		ContentType:   contentType,
+		Size:          object.Size,
+		BlobKey:       object.Key,
+	}
+
+	if thumbnailable {
+		// A thumbnail is a convenience, so a broken image is still accepted without one.
+		if thumbnail, err := blob.Thumbnail(data, thumbnailSize); err != nil {
+			s.logger.Warn("Error generating thumbnail", "blob", object.Key, "error", err)
+		} else if thumb, _, err := blob.Upload(ctx, s.store, bytes.NewReader(thumbnail), s.maxSize); err != nil {
+			s.logger.Warn("Error storing thumbnail", "blob", object.Key, "error", err)
+		} else {
+			attachment.ThumbnailKey = sql.NullString{String: thumb.Key, Valid: true}
+		}
+	}
+
+	if _, err := s.attachmentRepo.create(attachment); err != nil {
+		return nil, err
+	}
+	return attachment.ToResponse(), nil
+}
+
+// list returns the attachments of a transaction of the household
+func (s *attachmentService) list(householdID int64, transactionID int) ([]*AttachmentResponse, error) {
+	if err := s.checkTransaction(householdID, transactionID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.listByTransaction(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*AttachmentResponse, 0, len(attachments))
+	for i := range attachments {
+		responses = append(responses, attachments[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// checkTransaction ensures the transaction exists and belongs to the household
+func (s *attachmentService) checkTransaction(householdID int64, transactionID int) error {
+	transaction, err := transactions.Get(s.attachmentRepo.DB, s.logger, int64(transactionID))
+	if err != nil {
+		return err
+	}
+	if transaction.HouseholdID != householdID {
+		return transactions.ErrTransactionNotFound
+	}
+	return nil
+}
+
+// open returns the attachment and a reader for its file, or for its thumbnail
+func (s *attachmentService) open(ctx context.Context, id int, thumbnail bool) (*Attachment, io.ReadCloser, error) {
+	attachment, err := s.attachmentRepo.getByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := attachment.BlobKey
+	if thumbnail {
+		if !attachment.ThumbnailKey.Valid {
+			return nil, nil, ErrNoThumbnail
+		}
+		key = attachment.ThumbnailKey.String
+	}
+
+	reader, err := s.store.Get(ctx, key)
+	if err != nil {
+		s.logger.Error("Error opening attachment blob", "id", id, "blob", key, "error", err)
+		return nil, nil, ErrInternalServer
+	}
+	return attachment, reader, nil
+}
+
+// delete removes an attachment by ID
+func (s *attachmentService) delete(id int) error {
+	return s.attachmentRepo.delete(id)
+}
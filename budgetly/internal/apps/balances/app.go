@@ -0,0 +1,58 @@
+package balances
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/balances"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/jmoiron/sqlx"
+)
+
+// snapshotInterval is how often month-end balance snapshots are checked for.
+const snapshotInterval = time.Hour
+
+// NewBalancesApp creates a new balances application with the provided database
+// connection, and starts the job that writes month-end snapshots as months close
+func NewBalancesApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	balanceModel := newBalanceModel(db, logger)
+	balanceService := newBalanceService(balanceModel, logger)
+	newBalanceHandler(balanceService, logger, router)
+
+	if db != nil {
+		go balanceService.run(context.Background(), snapshotInterval)
+	}
+}
+
+// RecordChange keeps balances up to date when a transaction is created (previous is nil),
+// edited, or deleted (current is nil). Snapshots after the change are adjusted in place,
+// so back-dated edits never require recomputing history.
+func RecordChange(db *sqlx.DB, logger *slog.Logger, previous, current *balances.Change) error {
+	return newBalanceService(newBalanceModel(db, logger), logger).apply(previous, current)
+}
+
+// RecordChangeTx records a change like RecordChange, within the caller's database
+// transaction, so the balances commit or roll back together with the transaction itself
+func RecordChangeTx(tx *sqlx.Tx, logger *slog.Logger, previous, current *balances.Change) error {
+	return newBalanceService(newBalanceModel(nil, logger), logger).applyTx(tx, previous, current)
+}
+
+// Balance returns an account's balance at the end of a date, computed from its latest
+// snapshot and the daily changes after it. ok is false when the account has no balance.
+func Balance(db *sqlx.DB, logger *slog.Logger, accountID int64, asOf time.Time) (balance money.Money, ok bool, err error) {
+	responses, err := newBalanceService(newBalanceModel(db, logger), logger).balancesAsOf(accountID, asOf)
+	if err != nil || len(responses) == 0 {
+		return money.Money{}, false, err
+	}
+	return responses[0].Balance, true, nil
+}
+
+// Timeline returns net worth at the end of each period from..to across all accounts,
+// like GET /networth. With a household, only its accounts count, the periods are its
+// fiscal periods and the total is converted to its reporting currency unless another
+// currency is given.
+func Timeline(db *sqlx.DB, logger *slog.Logger, householdID int64, from, to time.Time, granularity, currency string) (*TimelineResponse, error) {
+	return newBalanceService(newBalanceModel(db, logger), logger).timeline(from, to, granularity, householdID, currency)
+}
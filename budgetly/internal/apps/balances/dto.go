@@ -0,0 +1,140 @@
+package balances
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/balances"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+)
+
+// DailyChange is the net amount posted to an account on a day. Amounts are kept in minor
+// units so that back-dated edits can be applied with SQL arithmetic.
+type DailyChange struct {
+	AccountID int64     `db:"account_id"`
+	Date      time.Time `db:"date"`
+	Currency  string    `db:"currency"`
+	Amount    int64     `db:"amount"`
+}
+
+// toChange converts a stored daily change into the form used by the balances package.
+func (c *DailyChange) toChange() (balances.Change, error) {
+	amount, err := money.New(c.Amount, c.Currency)
+	if err != nil {
+		return balances.Change{}, err
+	}
+	return balances.Change{AccountID: c.AccountID, Date: c.Date, Amount: amount}, nil
+}
+
+// Snapshot is an account's stored balance at the end of a day.
+type Snapshot struct {
+	AccountID int64     `db:"account_id"`
+	Date      time.Time `db:"date"`
+	Currency  string    `db:"currency"`
+	Balance   int64     `db:"balance"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// toSnapshot converts a stored snapshot into the form used by the balances package.
+func (s *Snapshot) toSnapshot() (balances.Snapshot, error) {
+	balance, err := money.New(s.Balance, s.Currency)
+	if err != nil {
+		return balances.Snapshot{}, err
+	}
+	return balances.Snapshot{AccountID: s.AccountID, Date: s.Date, Balance: balance}, nil
+}
+
+// Account is an account with posted changes, and where its snapshots have got to.
+type Account struct {
+	AccountID    int64        `db:"account_id"`
+	Currency     string       `db:"currency"`
+	FirstChange  time.Time    `db:"first_change"`
+	LastSnapshot sql.NullTime `db:"last_snapshot"`
+}
+
+// PostingRequest is a transaction's account, date and amount.
+type PostingRequest struct {
+	AccountID int64       `json:"account_id"`
+	Date      string      `json:"date"`
+	Amount    money.Money `json:"amount"`
+}
+
+// validate reports invalid fields of the PostingRequest under prefix.
+func (input *PostingRequest) validate(prefix string, errors map[string]string) {
+	if input.AccountID <= 0 {
+		errors[prefix+".AccountID"] = "AccountID is required"
+	}
+	if _, err := time.Parse(time.DateOnly, input.Date); err != nil {
+		errors[prefix+".Date"] = "Date must be in YYYY-MM-DD format"
+	}
+	if !money.IsCurrency(input.Amount.Currency()) {
+		errors[prefix+".Amount"] = "Amount must be a valid amount"
+	}
+}
+
+// toChange converts a validated PostingRequest into a balance change, or nil when absent.
+func (input *PostingRequest) toChange() *balances.Change {
+	if input == nil {
+		return nil
+	}
+	date, _ := time.Parse(time.DateOnly, input.Date)
+	return &balances.Change{AccountID: input.AccountID, Date: date, Amount: input.Amount}
+}
+
+// ChangeRequest represents a transaction being created (only current), edited (both)
+// or deleted (only previous).
+type ChangeRequest struct {
+	Previous *PostingRequest `json:"previous"`
+	Current  *PostingRequest `json:"current"`
+}
+
+// Validate validates the ChangeRequest struct.
+func (input *ChangeRequest) Validate() map[string]string {
+	errors := map[string]string{}
+	if input.Previous == nil && input.Current == nil {
+		errors["Current"] = "At least one of previous or current is required"
+	}
+	if input.Previous != nil {
+		input.Previous.validate("Previous", errors)
+	}
+	if input.Current != nil {
+		input.Current.validate("Current", errors)
+	}
+	return errors
+}
+
+// BalanceResponse represents an account's balance as of a date.
+type BalanceResponse struct {
+	AccountID int64       `json:"account_id"`
+	AsOf      string      `json:"as_of"`
+	Balance   money.Money `json:"balance"`
+	// SnapshotDate is the snapshot the balance was computed from, if any.
+	SnapshotDate string `json:"snapshot_date,omitempty"`
+}
+
+// SnapshotResponse reports how many snapshots a snapshot run wrote.
+type SnapshotResponse struct {
+	Created int `json:"created"`
+}
+
+// PointResponse represents net worth at the end of one timeline period.
+type PointResponse struct {
+	Date        string        `json:"date"`
+	Accounts    report.Totals `json:"accounts"`
+	Investments report.Totals `json:"investments"`
+	NetWorth    report.Totals `json:"net_worth"`
+	// Total is the net worth converted to the requested currency, if one was requested.
+	Total *money.Money `json:"total,omitempty"`
+}
+
+// TimelineResponse represents net worth over time.
+type TimelineResponse struct {
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	Granularity report.Granularity `json:"granularity,omitempty"`
+	// HouseholdID is set when the points are the household's fiscal period ends.
+	HouseholdID int64           `json:"household_id,omitempty"`
+	Currency    string          `json:"currency,omitempty"`
+	Points      []PointResponse `json:"points"`
+}
@@ -0,0 +1,9 @@
+package balances
+
+import "errors"
+
+var (
+	ErrInternalServer   error = errors.New("internal server error")
+	ErrCurrencyMismatch error = errors.New("the amount is not in the account's currency")
+	ErrRateNotFound     error = errors.New("no exchange rate found to convert net worth")
+)
@@ -0,0 +1,223 @@
+package balances
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// balanceHandler is an HTTP handler for balance operations
+// (e.g., recording changes, balance-as-of queries, the net worth timeline, etc.)
+type balanceHandler struct {
+	balanceService *balanceService
+	logger         *slog.Logger
+	router         *http.ServeMux
+}
+
+// newBalanceHandler creates a new balance handler with the provided balance service and logger
+func newBalanceHandler(balanceService *balanceService, logger *slog.Logger, router *http.ServeMux) *balanceHandler {
+	balanceHandler := &balanceHandler{
+		balanceService: balanceService,
+		logger:         logger,
+		router:         router,
+	}
+	balanceHandler.registerRoutes()
+	return balanceHandler
+}
+
+// Register routes for balance-related actions
+func (h *balanceHandler) registerRoutes() {
+	h.router.HandleFunc("GET /balances", h.list)
+	h.router.HandleFunc("POST /balances/changes", h.record)
+	h.router.HandleFunc("POST /balances/snapshots/rebuild", h.rebuild)
+	h.router.HandleFunc("GET /networth", h.timeline)
+	h.router.HandleFunc("GET /households/{household}/networth", h.householdTimeline)
+}
+
+// List is an HTTP handler for account balances as_of a date (default today), optionally
+// for one account_id
+func (h *balanceHandler) list(w http.ResponseWriter, r *http.Request) {
+	errs := map[string]string{}
+	query := r.URL.Query()
+	accountID, asOf := queryID(query, "account_id", errs), queryDate(query, "as_of", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+	if !asOf.Valid {
+		asOf.Time = today()
+	}
+
+	balances, err := h.balanceService.balancesAsOf(accountID, asOf.Time)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, balances)
+}
+
+// Record is an HTTP handler for applying a transaction being created, edited or deleted
+// to the balance history
+func (h *balanceHandler) record(w http.ResponseWriter, r *http.Request) {
+	var req ChangeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := h.balanceService.record(req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Rebuild is an HTTP handler for recomputing the snapshots of one account_id, or all
+// accounts, from the daily totals
+func (h *balanceHandler) rebuild(w http.ResponseWriter, r *http.Request) {
+	errs := map[string]string{}
+	accountID := queryID(r.URL.Query(), "account_id", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+
+	result, err := h.balanceService.rebuild(accountID, time.Now())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, result)
+}
+
+// Timeline is an HTTP handler for net worth at the end of each monthly, quarterly or
+// yearly period between from (default a year ago) and to (default today), optionally
+// converted to a currency
+func (h *balanceHandler) timeline(w http.ResponseWriter, r *http.Request) {
+	h.writeTimeline(w, r, 0)
+}
+
+// HouseholdTimeline is an HTTP handler for net worth at the end of each of a household's
+// fiscal periods between from and to, converted to its reporting currency or another one
+func (h *balanceHandler) householdTimeline(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+	h.writeTimeline(w, r, householdID)
+}
+
+// writeTimeline parses the timeline query parameters and writes the net worth timeline,
+// over the household's fiscal periods when householdID is set
+func (h *balanceHandler) writeTimeline(w http.ResponseWriter, r *http.Request, householdID int64) {
+	errs := map[string]string{}
+	query := r.URL.Query()
+	from, to := queryDate(query, "from", errs), queryDate(query, "to", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+	if !to.Valid {
+		to.Time = today()
+	}
+	if !from.Valid {
+		from.Time = to.Time.AddDate(-1, 0, 0)
+	}
+
+	timeline, err := h.balanceService.timeline(from.Time, to.Time, query.Get("granularity"), householdID, strings.ToUpper(query.Get("currency")))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, timeline)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *balanceHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *balanceHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// today returns the current UTC date
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// queryID parses an optional positive ID query parameter, recording an error when it is invalid
+func queryID(query url.Values, name string, errs map[string]string) int64 {
+	value := query.Get(name)
+	if value == "" {
+		return 0
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		errs[name] = name + " must be a positive integer"
+		return 0
+	}
+	return id
+}
+
+// queryDate parses an optional YYYY-MM-DD query parameter, recording an error when it is invalid
+func queryDate(query url.Values, name string, errs map[string]string) sql.NullTime {
+	value := query.Get(name)
+	if value == "" {
+		return sql.NullTime{}
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		errs[name] = name + " must be in YYYY-MM-DD format"
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: date, Valid: true}
+}
+
+// writeError maps service errors to HTTP responses
+func (h *balanceHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrCurrencyMismatch):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrRateNotFound), errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling balance request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,220 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package balances
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/balances"
+	"github.com/jmoiron/sqlx"
+)
+
+// changeColumns lists the columns selected for a DailyChange
+const changeColumns = `account_id, date, currency, amount`
+
+// snapshotColumns lists the columns selected for a Snapshot
+const snapshotColumns = `account_id, date, currency, balance, created_at`
+
+// householdAccounts limits %s.account_id to the accounts of the household in %s, or
+// leaves it unfiltered when that parameter is 0.
+const householdAccounts = `(%[2]s = 0 OR %[1]s.account_id IN (SELECT a.id FROM accounts a WHERE a.household_id = %[2]s))`
+
+// baseSnapshot is the date of an account's latest snapshot on or before $2, or -infinity
+// when it has none. Balances on or after $2 start from that snapshot.
+const baseSnapshot = `COALESCE((SELECT max(b.date) FROM balance_snapshots b WHERE b.account_id = %s.account_id AND b.date <= $2), '-infinity')`
+
+// balanceModel wraps the database connection pool using sqlx
+type balanceModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newBalanceModel(db *sqlx.DB, logger *slog.Logger) *balanceModel {
+	return &balanceModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// ApplyChanges adds each change to its account's daily total and to every snapshot of
+// the account dated on or after it, in a single transaction
+func (m *balanceModel) applyChanges(changes []balances.Change) error {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting balance change transaction", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	if err := m.applyChangesTx(tx, changes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing balance changes", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Balance changes applied successfully", "count", len(changes))
+	return nil
+}
+
+// ApplyChangesTx applies changes like applyChanges, within the caller's transaction
+func (m *balanceModel) applyChangesTx(tx *sqlx.Tx, changes []balances.Change) error {
+	for _, change := range changes {
+		// Concurrent changes to the same account apply one after the other.
+		if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, change.AccountID); err != nil {
+			m.logger.Error("Error locking account balances", "error", err)
+			return ErrInternalServer
+		}
+
+		var currencies []string
+		if err := tx.Select(&currencies, `SELECT currency FROM account_daily_changes WHERE account_id = $1 LIMIT 1`, change.AccountID); err != nil {
+			m.logger.Error("Error reading account currency", "error", err)
+			return ErrInternalServer
+		}
+		if len(currencies) > 0 && currencies[0] != change.Amount.Currency() {
+			return ErrCurrencyMismatch
+		}
+
+		if _, err := tx.Exec(`INSERT INTO account_daily_changes (account_id, date, currency, amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account_id, date) DO UPDATE SET amount = account_daily_changes.amount + EXCLUDED.amount`,
+			change.AccountID, change.Date, change.Amount.Currency(), change.Amount.Amount(),
+		); err != nil {
+			m.logger.Error("Error updating daily change", "error", err)
+			return ErrInternalServer
+		}
+
+		if _, err := tx.Exec(`UPDATE balance_snapshots SET balance = balance + $3 WHERE account_id = $1 AND date >= $2`,
+			change.AccountID, change.Date, change.Amount.Amount(),
+		); err != nil {
+			m.logger.Error("Error adjusting balance snapshots", "error", err)
+			return ErrInternalServer
+		}
+	}
+	return nil
+}
+
+// Accounts returns every account with changes, those of a household, or just one, with
+// its first change and latest snapshot
+func (m *balanceModel) accounts(householdID, accountID int64) ([]Account, error) {
+	query := `SELECT c.account_id, min(c.currency) AS currency, min(c.date) AS first_change,
+		(SELECT max(s.date) FROM balance_snapshots s WHERE s.account_id = c.account_id) AS last_snapshot
+	FROM account_daily_changes c
+	WHERE ($1 = 0 OR c.account_id = $1) AND ` + fmt.Sprintf(householdAccounts, "c", "$2") + `
+	GROUP BY c.account_id
+	ORDER BY c.account_id`
+
+	accounts := []Account{}
+	if err := m.DB.Select(&accounts, query, accountID, householdID); err != nil {
+		m.logger.Error("Error listing balance accounts", "error", err)
+		return nil, ErrInternalServer
+	}
+	return accounts, nil
+}
+
+// Snapshots returns the snapshots needed for balances from..to: each account's latest
+// snapshot on or before from and every snapshot after it up to to, for every account,
+// those of a household, or just one
+func (m *balanceModel) snapshots(householdID, accountID int64, from, to time.Time) ([]Snapshot, error) {
+	return m.selectSnapshots(m.DB, householdID, accountID, from, to)
+}
+
+func (m *balanceModel) selectSnapshots(q sqlx.Queryer, householdID, accountID int64, from, to time.Time) ([]Snapshot, error) {
+	query := `SELECT ` + snapshotColumns + `
+	FROM balance_snapshots s
+	WHERE ($1 = 0 OR s.account_id = $1) AND ` + fmt.Sprintf(householdAccounts, "s", "$4") + `
+		AND s.date <= $3 AND s.date >= ` + fmt.Sprintf(baseSnapshot, "s") + `
+	ORDER BY s.account_id, s.date`
+
+	snapshots := []Snapshot{}
+	if err := sqlx.Select(q, &snapshots, query, accountID, from, to, householdID); err != nil {
+		m.logger.Error("Error listing balance snapshots", "error", err)
+		return nil, ErrInternalServer
+	}
+	return snapshots, nil
+}
+
+// Changes returns the daily changes needed for balances from..to: those after each
+// account's latest snapshot on or before from, up to to, for every account, those of a
+// household, or just one
+func (m *balanceModel) changes(householdID, accountID int64, from, to time.Time) ([]DailyChange, error) {
+	return m.selectChanges(m.DB, householdID, accountID, from, to)
+}
+
+func (m *balanceModel) selectChanges(q sqlx.Queryer, householdID, accountID int64, from, to time.Time) ([]DailyChange, error) {
+	query := `SELECT ` + changeColumns + `
+	FROM account_daily_changes c
+	WHERE ($1 = 0 OR c.account_id = $1) AND ` + fmt.Sprintf(householdAccounts, "c", "$4") + `
+		AND c.date <= $3 AND c.date > ` + fmt.Sprintf(baseSnapshot, "c") + `
+	ORDER BY c.account_id, c.date`
+
+	changes := []DailyChange{}
+	if err := sqlx.Select(q, &changes, query, accountID, from, to, householdID); err != nil {
+		m.logger.Error("Error listing daily changes", "error", err)
+		return nil, ErrInternalServer
+	}
+	return changes, nil
+}
+
+// SnapshotAccount stores the snapshots that build computes from an account's snapshots
+// and changes for from..to. The account is locked throughout, so a change applied at the
+// same time is either included in the new snapshots or adjusts them afterwards.
+func (m *balanceModel) snapshotAccount(accountID int64, from, to time.Time, build func([]Snapshot, []DailyChange) ([]Snapshot, error)) (int, error) {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting balance snapshot transaction", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, accountID); err != nil {
+		m.logger.Error("Error locking account balances", "error", err)
+		return 0, ErrInternalServer
+	}
+	existing, err := m.selectSnapshots(tx, 0, accountID, from, to)
+	if err != nil {
+		return 0, err
+	}
+	changes, err := m.selectChanges(tx, 0, accountID, from, to)
+	if err != nil {
+		return 0, err
+	}
+	snapshots, err := build(existing, changes)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `INSERT INTO balance_snapshots (account_id, date, currency, balance, created_at)
+	VALUES (:account_id, :date, :currency, :balance, :created_at)
+	ON CONFLICT (account_id, date) DO UPDATE SET balance = EXCLUDED.balance, created_at = EXCLUDED.created_at`
+
+	now := time.Now()
+	for i := range snapshots {
+		snapshots[i].CreatedAt = now
+		if _, err := tx.NamedExec(query, snapshots[i]); err != nil {
+			m.logger.Error("Error inserting balance snapshot", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing balance snapshots", "error", err)
+		return 0, ErrInternalServer
+	}
+
+	m.logger.Debug("Balance snapshots stored successfully", "account_id", accountID, "count", len(snapshots))
+	return len(snapshots), nil
+}
+
+// DeleteSnapshots deletes every snapshot of an account, or of all accounts
+func (m *balanceModel) deleteSnapshots(accountID int64) error {
+	if _, err := m.DB.Exec(`DELETE FROM balance_snapshots WHERE $1 = 0 OR account_id = $1`, accountID); err != nil {
+		m.logger.Error("Error deleting balance snapshots", "error", err)
+		return ErrInternalServer
+	}
+	return nil
+}
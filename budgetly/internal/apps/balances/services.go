@@ -0,0 +1,330 @@
+package balances
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/internal/apps/investments"
+	"github.com/ZiadMansourM/budgetly/internal/apps/periods"
+	"github.com/ZiadMansourM/budgetly/internal/apps/rates"
+	"github.com/ZiadMansourM/budgetly/pkg/balances"
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/jmoiron/sqlx"
+)
+
+type balanceService struct {
+	balanceRepo *balanceModel
+	logger      *slog.Logger
+}
+
+func newBalanceService(balanceRepo *balanceModel, logger *slog.Logger) *balanceService {
+	return &balanceService{
+		balanceRepo: balanceRepo,
+		logger:      logger,
+	}
+}
+
+// record validates a transaction being created, edited or deleted and applies it
+func (s *balanceService) record(input ChangeRequest) error {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Balance change validation failed", "errors", validationErrors)
+		return &validate.ValidationError{Errors: validationErrors}
+	}
+	return s.apply(input.Previous.toChange(), input.Current.toChange())
+}
+
+// apply moves a transaction's posting from previous to current in the daily totals and
+// adjusts the snapshots after it, instead of recomputing history
+func (s *balanceService) apply(previous, current *balances.Change) error {
+	changes, err := delta(previous, current)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+	return s.balanceRepo.applyChanges(changes)
+}
+
+// applyTx applies a change like apply, within the caller's transaction
+func (s *balanceService) applyTx(tx *sqlx.Tx, previous, current *balances.Change) error {
+	changes, err := delta(previous, current)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+	return s.balanceRepo.applyChangesTx(tx, changes)
+}
+
+// delta returns the daily total changes that move a posting from previous to current
+func delta(previous, current *balances.Change) ([]balances.Change, error) {
+	changes, err := balances.Delta(previous, current)
+	if errors.Is(err, money.ErrCurrencyMismatch) {
+		return nil, ErrCurrencyMismatch
+	}
+	if err != nil {
+		return nil, &validate.ValidationError{Errors: map[string]string{"Change": err.Error()}}
+	}
+	return changes, nil
+}
+
+// balancesAsOf returns the balance of every account, or of one, at the end of a date
+func (s *balanceService) balancesAsOf(accountID int64, asOf time.Time) ([]*BalanceResponse, error) {
+	series, err := s.series(0, accountID, []time.Time{asOf})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*BalanceResponse, 0, len(series))
+	for _, account := range series {
+		response := &BalanceResponse{
+			AccountID: account.AccountID,
+			AsOf:      asOf.Format(time.DateOnly),
+			Balance:   account.balances[0],
+		}
+		if !account.base.IsZero() {
+			response.SnapshotDate = account.base.Format(time.DateOnly)
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// timeline returns net worth at the end of each period from..to: account balances plus
+// the market value of investment holdings, optionally converted to one currency. With a
+// household, only its accounts and holdings count, the periods are its fiscal periods
+// instead of the granularity's, and the total is converted to its reporting currency
+// unless another currency is given.
+func (s *balanceService) timeline(from, to time.Time, granularity string, householdID int64, currency string) (*TimelineResponse, error) {
+	g, err := report.ParseGranularity(granularity)
+	if err != nil {
+		return nil, &validate.ValidationError{Errors: map[string]string{"granularity": "granularity must be one of monthly, quarterly or yearly"}}
+	}
+	if householdID > 0 && currency == "" {
+		household, err := households.Get(s.balanceRepo.DB, s.logger, householdID)
+		if err != nil {
+			return nil, err
+		}
+		currency = household.ReportingCurrency
+	}
+	if currency != "" && !money.IsCurrency(currency) {
+		return nil, &validate.ValidationError{Errors: map[string]string{"currency": "currency must be a supported ISO 4217 code"}}
+	}
+
+	var dates []time.Time
+	if householdID > 0 {
+		fiscalPeriods, err := periods.Periods(s.balanceRepo.DB, s.logger, householdID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		dates, g = balances.Ends(fiscalPeriods, to), ""
+	} else if dates, err = balances.PeriodEnds(from, to, g); err != nil {
+		return nil, &validate.ValidationError{Errors: map[string]string{"to": err.Error()}}
+	}
+
+	series, err := s.series(householdID, 0, dates)
+	if err != nil {
+		return nil, err
+	}
+	response := &TimelineResponse{
+		From:        from.Format(time.DateOnly),
+		To:          to.Format(time.DateOnly),
+		Granularity: g,
+		HouseholdID: householdID,
+		Currency:    currency,
+		Points:      make([]PointResponse, 0, len(dates)),
+	}
+	for i, date := range dates {
+		point := PointResponse{Date: date.Format(time.DateOnly), Accounts: report.Totals{}, NetWorth: report.Totals{}}
+		for _, account := range series {
+			if err := point.Accounts.Add(account.balances[i]); err != nil {
+				return nil, err
+			}
+		}
+		if point.Investments, err = investments.MarketValue(s.balanceRepo.DB, s.logger, householdID, date); err != nil {
+			return nil, err
+		}
+		for _, totals := range []report.Totals{point.Accounts, point.Investments} {
+			for _, amount := range totals {
+				if err := point.NetWorth.Add(amount); err != nil {
+					return nil, err
+				}
+			}
+		}
+		response.Points = append(response.Points, point)
+	}
+	if currency == "" {
+		return response, nil
+	}
+
+	// Only the rates between the currencies actually held are needed for the totals.
+	held := []string{currency}
+	for _, point := range response.Points {
+		held = append(held, point.NetWorth.Currencies()...)
+	}
+	table, err := rates.LoadTable(s.balanceRepo.DB, s.logger, held, dates[0], dates[len(dates)-1])
+	if err != nil {
+		return nil, err
+	}
+	for i, date := range dates {
+		total, err := response.Points[i].NetWorth.Convert(table, currency, date)
+		if errors.Is(err, fx.ErrRateNotFound) {
+			return nil, ErrRateNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		response.Points[i].Total = &total
+	}
+	return response, nil
+}
+
+// accountSeries is an account's balances on a list of dates.
+type accountSeries struct {
+	Account
+	balances []money.Money
+	// base is the snapshot the first balance started from, if any.
+	base time.Time
+}
+
+// series computes the balance of every account, those of a household, or just one, at
+// the end of each date, starting from the nearest snapshot rather than the beginning of
+// its history
+func (s *balanceService) series(householdID, accountID int64, dates []time.Time) ([]accountSeries, error) {
+	from, to := dates[0], dates[len(dates)-1]
+	accounts, err := s.balanceRepo.accounts(householdID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := s.balanceRepo.snapshots(householdID, accountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.balanceRepo.changes(householdID, accountID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotsByAccount := map[int64][]balances.Snapshot{}
+	for i := range snapshots {
+		snapshot, err := snapshots[i].toSnapshot()
+		if err != nil {
+			s.logger.Error("Invalid stored balance snapshot", "account_id", snapshots[i].AccountID, "error", err)
+			return nil, ErrInternalServer
+		}
+		snapshotsByAccount[snapshot.AccountID] = append(snapshotsByAccount[snapshot.AccountID], snapshot)
+	}
+	changesByAccount, err := s.groupChanges(changes)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]accountSeries, 0, len(accounts))
+	for _, account := range accounts {
+		accountSnapshots := snapshotsByAccount[account.AccountID]
+		values, err := balances.At(account.Currency, accountSnapshots, changesByAccount[account.AccountID], dates)
+		if err != nil {
+			s.logger.Error("Error computing balances", "account_id", account.AccountID, "error", err)
+			return nil, ErrInternalServer
+		}
+
+		entry := accountSeries{Account: account, balances: values}
+		if len(accountSnapshots) > 0 && !accountSnapshots[0].Date.After(from) {
+			entry.base = accountSnapshots[0].Date
+		}
+		series = append(series, entry)
+	}
+	return series, nil
+}
+
+// snapshot writes the month-end snapshots that have become due for every account
+func (s *balanceService) snapshot(today time.Time) (int, error) {
+	accounts, err := s.balanceRepo.accounts(0, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, account := range accounts {
+		due := balances.DueSnapshots(account.LastSnapshot.Time, account.FirstChange, today)
+		if len(due) == 0 {
+			continue
+		}
+
+		n, err := s.balanceRepo.snapshotAccount(account.AccountID, due[0], due[len(due)-1], func(existing []Snapshot, changes []DailyChange) ([]Snapshot, error) {
+			base := make([]balances.Snapshot, 0, len(existing))
+			for i := range existing {
+				snapshot, err := existing[i].toSnapshot()
+				if err != nil {
+					return nil, err
+				}
+				base = append(base, snapshot)
+			}
+			grouped, err := s.groupChanges(changes)
+			if err != nil {
+				return nil, err
+			}
+
+			values, err := balances.At(account.Currency, base, grouped[account.AccountID], due)
+			if err != nil {
+				return nil, err
+			}
+			snapshots := make([]Snapshot, 0, len(due))
+			for i, date := range due {
+				snapshots = append(snapshots, Snapshot{AccountID: account.AccountID, Date: date, Currency: account.Currency, Balance: values[i].Amount()})
+			}
+			return snapshots, nil
+		})
+		if err != nil {
+			s.logger.Error("Error snapshotting account balances", "account_id", account.AccountID, "error", err)
+			return created, err
+		}
+		created += n
+	}
+	return created, nil
+}
+
+// rebuild drops the snapshots of one account, or all, and writes them again from the daily totals
+func (s *balanceService) rebuild(accountID int64, today time.Time) (*SnapshotResponse, error) {
+	if err := s.balanceRepo.deleteSnapshots(accountID); err != nil {
+		return nil, err
+	}
+	created, err := s.snapshot(today)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapshotResponse{Created: created}, nil
+}
+
+// run writes due snapshots every interval until the context is cancelled
+func (s *balanceService) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.snapshot(time.Now()); err != nil {
+			s.logger.Error("Error writing balance snapshots", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// groupChanges converts stored daily changes and groups them by account
+func (s *balanceService) groupChanges(changes []DailyChange) (map[int64][]balances.Change, error) {
+	grouped := map[int64][]balances.Change{}
+	for i := range changes {
+		change, err := changes[i].toChange()
+		if err != nil {
+			s.logger.Error("Invalid stored daily change", "account_id", changes[i].AccountID, "error", err)
+			return nil, ErrInternalServer
+		}
+		grouped[change.AccountID] = append(grouped[change.AccountID], change)
+	}
+	return grouped, nil
+}
@@ -0,0 +1,67 @@
+package balances
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/balances"
+	"github.com/ZiadMansourM/budgetly/pkg/db"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+// TestTimelineHouseholds needs the database from scripts/initdb.sql and is skipped
+// unless DB_CONNECTION_STRING points at one.
+func TestTimelineHouseholds(t *testing.T) {
+	conn := os.Getenv("DB_CONNECTION_STRING")
+	if conn == "" {
+		t.Skip("DB_CONNECTION_STRING is not set")
+	}
+	pool, err := db.OpenDB("postgres", conn)
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	defer pool.Close()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Each household has one account with a single deposit.
+	deposits := []int64{10000, 25000}
+	householdIDs := make([]int64, len(deposits))
+	for i, amount := range deposits {
+		var accountID int64
+		if err := pool.Get(&householdIDs[i], `INSERT INTO households (name) VALUES ('Balances test') RETURNING id`); err != nil {
+			t.Fatalf("Error creating household: %v", err)
+		}
+		if err := pool.Get(&accountID, `INSERT INTO accounts (household_id, name, currency) VALUES ($1, 'Checking', 'EUR') RETURNING id`, householdIDs[i]); err != nil {
+			t.Fatalf("Error creating account: %v", err)
+		}
+		t.Cleanup(func() {
+			pool.MustExec(`DELETE FROM account_daily_changes WHERE account_id = $1`, accountID)
+			pool.MustExec(`DELETE FROM balance_snapshots WHERE account_id = $1`, accountID)
+			pool.MustExec(`DELETE FROM households WHERE id = $1`, householdIDs[i])
+		})
+
+		deposit, _ := money.New(amount, "EUR")
+		change := &balances.Change{AccountID: accountID, Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Amount: deposit}
+		if err := RecordChange(pool, logger, nil, change); err != nil {
+			t.Fatalf("Error recording change: %v", err)
+		}
+	}
+
+	from, to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	for i, householdID := range householdIDs {
+		timeline, err := Timeline(pool, logger, householdID, from, to, "monthly", "")
+		if err != nil {
+			t.Fatalf("Error computing household %d timeline: %v", householdID, err)
+		}
+		if len(timeline.Points) == 0 {
+			t.Fatalf("Expected points for household %d", householdID)
+		}
+		last := timeline.Points[len(timeline.Points)-1]
+		if total := last.NetWorth["EUR"]; total.Amount() != deposits[i] {
+			t.Errorf("Expected household %d to be worth %d, got %v", householdID, deposits[i], total)
+		}
+	}
+}
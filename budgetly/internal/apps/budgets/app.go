@@ -0,0 +1,23 @@
+package budgets
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewBudgetsApp creates a new budgets application with the provided database connection
+func NewBudgetsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	budgetModel := newBudgetModel(db, logger)
+	budgetService := newBudgetService(budgetModel, logger)
+	newBudgetHandler(budgetService, logger, router)
+}
+
+// Status returns how much of the household's budgets for the categories is spent in the
+// budget period containing date, e.g. to evaluate budget alerts after a transaction
+// changed. Categories without a budget are left out.
+func Status(db *sqlx.DB, logger *slog.Logger, householdID int64, categories []string, date time.Time) ([]*StatusResponse, error) {
+	return newBudgetService(newBudgetModel(db, logger), logger).status(householdID, categories, date)
+}
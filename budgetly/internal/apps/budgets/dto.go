@@ -0,0 +1,89 @@
+package budgets
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// Budget is the amount a household budgets for a category in each budget period.
+// Categories are unique per household, ignoring case.
+type Budget struct {
+	ID          int64       `db:"id"`
+	HouseholdID int64       `db:"household_id"`
+	Category    string      `db:"category"`
+	Amount      money.Money `db:"amount"`
+	CreatedAt   time.Time   `db:"created_at"`
+}
+
+// BudgetRequest represents the input data for creating or updating a budget.
+type BudgetRequest struct {
+	Category string      `json:"category"`
+	Amount   money.Money `json:"amount"`
+}
+
+// Validate validates the BudgetRequest struct.
+func (input *BudgetRequest) Validate() map[string]string {
+	input.Category = strings.TrimSpace(input.Category)
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Category": validate.Rules(
+			validate.Required,
+			validate.Max(100),
+			validate.ErrorMessage("Category is required and must be at most 100 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if input.Amount.Currency() == "" || input.Amount.IsNegative() {
+		errors["Amount"] = "Amount is required and must not be negative"
+	}
+	return errors
+}
+
+// toBudget converts a validated BudgetRequest into a Budget of the household.
+func (input *BudgetRequest) toBudget(householdID int64) *Budget {
+	return &Budget{
+		HouseholdID: householdID,
+		Category:    input.Category,
+		Amount:      input.Amount,
+	}
+}
+
+// BudgetResponse represents the budget data to return in responses.
+type BudgetResponse struct {
+	ID          int64       `json:"id"`
+	HouseholdID int64       `json:"household_id"`
+	Category    string      `json:"category"`
+	Amount      money.Money `json:"amount"`
+}
+
+// ToResponse converts a Budget (from database) to a BudgetResponse (for API responses).
+func (b *Budget) ToResponse() *BudgetResponse {
+	return &BudgetResponse{
+		ID:          b.ID,
+		HouseholdID: b.HouseholdID,
+		Category:    b.Category,
+		Amount:      b.Amount,
+	}
+}
+
+// Line is a transaction line counted against a budget.
+type Line struct {
+	Category string      `db:"category"`
+	Amount   money.Money `db:"amount"`
+}
+
+// StatusResponse represents a budget's spending in the budget period [PeriodStart,
+// PeriodEnd). Spent is the outflows less refunds in the budget's currency.
+type StatusResponse struct {
+	Category    string      `json:"category"`
+	Budgeted    money.Money `json:"budgeted"`
+	Spent       money.Money `json:"spent"`
+	PeriodStart time.Time   `json:"period_start"`
+	PeriodEnd   time.Time   `json:"period_end"`
+}
@@ -0,0 +1,9 @@
+package budgets
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+	ErrBudgetNotFound error = errors.New("budget not found")
+	ErrBudgetExists   error = errors.New("a budget for this category already exists")
+)
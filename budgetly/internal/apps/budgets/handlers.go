@@ -0,0 +1,217 @@
+package budgets
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// budgetHandler is an HTTP handler for budget operations
+// (e.g., creating, updating, deleting budgets, etc.)
+type budgetHandler struct {
+	budgetService *budgetService
+	logger        *slog.Logger
+	router        *http.ServeMux
+}
+
+// newBudgetHandler creates a new budget handler with the provided budget service and logger
+func newBudgetHandler(budgetService *budgetService, logger *slog.Logger, router *http.ServeMux) *budgetHandler {
+	budgetHandler := &budgetHandler{
+		budgetService: budgetService,
+		logger:        logger,
+		router:        router,
+	}
+	budgetHandler.registerRoutes()
+	return budgetHandler
+}
+
+// Register routes for budget actions
+func (h *budgetHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/budgets", h.create)
+	h.router.HandleFunc("GET /households/{household}/budgets", h.list)
+	h.router.HandleFunc("GET /households/{household}/budgets/status", h.status)
+	h.router.HandleFunc("GET /budgets/{id}", h.get)
+	h.router.HandleFunc("PUT /budgets/{id}", h.update)
+	h.router.HandleFunc("DELETE /budgets/{id}", h.delete)
+}
+
+// Create is an HTTP handler for creating a new budget in a household
+func (h *budgetHandler) create(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req BudgetRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	budget, err := h.budgetService.create(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, budget)
+}
+
+// List is an HTTP handler for listing a household's budgets
+func (h *budgetHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	budgets, err := h.budgetService.list(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, budgets)
+}
+
+// Status is an HTTP handler for the spending of a household's budgets in the budget period
+// containing a date (default today)
+func (h *budgetHandler) status(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			h.writeError(w, &validate.ValidationError{Errors: map[string]string{"date": "date must be in YYYY-MM-DD format"}})
+			return
+		}
+		date = parsed
+	}
+
+	status, err := h.budgetService.status(householdID, nil, date)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, status)
+}
+
+// Get is an HTTP handler for retrieving a budget by ID
+func (h *budgetHandler) get(w http.ResponseWriter, r *http.Request) {
+	budgetID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	budget, err := h.budgetService.get(budgetID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, budget)
+}
+
+// Update is an HTTP handler for updating a budget
+func (h *budgetHandler) update(w http.ResponseWriter, r *http.Request) {
+	budgetID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req BudgetRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	budget, err := h.budgetService.update(budgetID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, budget)
+}
+
+// Delete is an HTTP handler for deleting a budget
+func (h *budgetHandler) delete(w http.ResponseWriter, r *http.Request) {
+	budgetID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.budgetService.delete(budgetID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *budgetHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses the budget ID from the URL, writing a 400 response on failure
+func (h *budgetHandler) pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid budget ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// householdID parses the household ID from the URL, writing a 400 response on failure
+func (h *budgetHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *budgetHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrBudgetNotFound), errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrBudgetExists):
+		utils.WriteJson(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling budget request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,155 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package budgets
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code for unique constraint violations
+const uniqueViolation = "23505"
+
+// budgetColumns lists the columns selected for a Budget
+const budgetColumns = `id, household_id, category, amount, created_at`
+
+// budgetModel wraps the database connection pool using sqlx
+type budgetModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newBudgetModel(db *sqlx.DB, logger *slog.Logger) *budgetModel {
+	return &budgetModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new budget into the database and returns its ID
+func (m *budgetModel) create(b *Budget) (int64, error) {
+	query := `INSERT INTO budgets (household_id, category, amount, created_at)
+	VALUES (:household_id, :category, :amount, :created_at)
+	RETURNING id`
+
+	b.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, b)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, ErrBudgetExists
+		}
+		m.logger.Error("Error inserting budget", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&b.ID); err != nil {
+			m.logger.Error("Error scanning budget ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Budget created successfully", "id", b.ID)
+	return b.ID, nil
+}
+
+// List returns a household's budgets ordered by category, or only those of the
+// categories when any are given
+func (m *budgetModel) list(householdID int64, categories []string) ([]Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM budgets
+	WHERE household_id = $1 AND (cardinality($2::text[]) = 0 OR lower(category) = ANY($2))
+	ORDER BY category`
+
+	budgets := []Budget{}
+	if err := m.DB.Select(&budgets, query, householdID, pq.Array(lowercase(categories))); err != nil {
+		m.logger.Error("Error listing budgets", "error", err)
+		return nil, ErrInternalServer
+	}
+	return budgets, nil
+}
+
+// GetByID returns a budget by ID
+func (m *budgetModel) getByID(id int64) (*Budget, error) {
+	b := &Budget{}
+	err := m.DB.Get(b, `SELECT `+budgetColumns+` FROM budgets WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrBudgetNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting budget by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return b, nil
+}
+
+// Update replaces a budget's category and amount and returns it
+func (m *budgetModel) update(b *Budget) (*Budget, error) {
+	updated := &Budget{}
+	query := `UPDATE budgets SET category = $2, amount = $3 WHERE id = $1 RETURNING ` + budgetColumns
+	err := m.DB.Get(updated, query, b.ID, b.Category, b.Amount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrBudgetNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, ErrBudgetExists
+	}
+	if err != nil {
+		m.logger.Error("Error updating budget", "error", err)
+		return nil, ErrInternalServer
+	}
+	return updated, nil
+}
+
+// Delete removes a budget by ID
+func (m *budgetModel) delete(id int64) error {
+	result, err := m.DB.Exec(`DELETE FROM budgets WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting budget", "error", err)
+		return ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected budget count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// Lines returns the household's transaction lines in the categories from..to (exclusive),
+// counting split transactions per split line. Scheduled transactions and uncategorized
+// transfers within the budget are left out, as in the reports.
+func (m *budgetModel) lines(householdID int64, categories []string, from, to time.Time) ([]Line, error) {
+	query := `SELECT category, amount FROM transaction_lines
+	WHERE household_id = $1 AND lower(category) = ANY($2) AND date >= $3 AND date < $4
+		AND NOT scheduled AND (transfer_id IS NULL OR category <> '')`
+
+	lines := []Line{}
+	if err := m.DB.Select(&lines, query, householdID, pq.Array(lowercase(categories)), from, to); err != nil {
+		m.logger.Error("Error listing budget lines", "error", err)
+		return nil, ErrInternalServer
+	}
+	return lines, nil
+}
+
+// lowercase returns the categories in lower case, to match them ignoring case
+func lowercase(categories []string) []string {
+	lowered := make([]string, 0, len(categories))
+	for _, category := range categories {
+		lowered = append(lowered, strings.ToLower(category))
+	}
+	return lowered
+}
@@ -0,0 +1,135 @@
+package budgets
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/internal/apps/periods"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type budgetService struct {
+	budgetRepo *budgetModel
+	logger     *slog.Logger
+}
+
+func newBudgetService(budgetRepo *budgetModel, logger *slog.Logger) *budgetService {
+	return &budgetService{
+		budgetRepo: budgetRepo,
+		logger:     logger,
+	}
+}
+
+// create validates and stores a new budget of a household
+func (s *budgetService) create(householdID int64, input BudgetRequest) (*BudgetResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Budget validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.budgetRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	budget := input.toBudget(householdID)
+	if _, err := s.budgetRepo.create(budget); err != nil {
+		return nil, err
+	}
+	return budget.ToResponse(), nil
+}
+
+// list returns a household's budgets
+func (s *budgetService) list(householdID int64) ([]*BudgetResponse, error) {
+	if _, err := households.Get(s.budgetRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	budgets, err := s.budgetRepo.list(householdID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*BudgetResponse, 0, len(budgets))
+	for i := range budgets {
+		responses = append(responses, budgets[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// get returns a budget by ID
+func (s *budgetService) get(id int64) (*BudgetResponse, error) {
+	budget, err := s.budgetRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return budget.ToResponse(), nil
+}
+
+// update validates and replaces a budget's category and amount
+func (s *budgetService) update(id int64, input BudgetRequest) (*BudgetResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Budget validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	budget := input.toBudget(0)
+	budget.ID = id
+	updated, err := s.budgetRepo.update(budget)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToResponse(), nil
+}
+
+// delete removes a budget by ID
+func (s *budgetService) delete(id int64) error {
+	return s.budgetRepo.delete(id)
+}
+
+// status returns the spending of the household's budgets in the budget period containing
+// date: of every budget, or of those of the categories when any are given
+func (s *budgetService) status(householdID int64, categories []string, date time.Time) ([]*StatusResponse, error) {
+	budgets, err := s.budgetRepo.list(householdID, categories)
+	if err != nil || len(budgets) == 0 {
+		return []*StatusResponse{}, err
+	}
+	budgetPeriods, err := periods.Periods(s.budgetRepo.DB, s.logger, householdID, date, date)
+	if err != nil {
+		return nil, err
+	}
+	period := budgetPeriods[0]
+
+	names := make([]string, 0, len(budgets))
+	for _, budget := range budgets {
+		names = append(names, budget.Category)
+	}
+	lines, err := s.budgetRepo.lines(householdID, names, period.Start, period.End)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*StatusResponse, 0, len(budgets))
+	for _, budget := range budgets {
+		spent, err := money.Zero(budget.Amount.Currency())
+		if err != nil {
+			return nil, err
+		}
+		// Outflows are negative, so spending is what the lines take away.
+		for _, line := range lines {
+			if strings.EqualFold(line.Category, budget.Category) && line.Amount.Currency() == spent.Currency() {
+				if spent, err = spent.Sub(line.Amount); err != nil {
+					return nil, err
+				}
+			}
+		}
+		responses = append(responses, &StatusResponse{
+			Category:    budget.Category,
+			Budgeted:    budget.Amount,
+			Spent:       spent,
+			PeriodStart: period.Start,
+			PeriodEnd:   period.End,
+		})
+	}
+	return responses, nil
+}
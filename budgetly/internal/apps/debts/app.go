@@ -0,0 +1,16 @@
+package debts
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewDebtsApp creates a new debt payoff planner application with the provided database
+// connection. Debts are the household's liability accounts with stored payoff terms.
+func NewDebtsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	debtModel := newDebtModel(db, logger)
+	debtService := newDebtService(debtModel, logger)
+	newDebtHandler(debtService, logger, router)
+}
@@ -0,0 +1,90 @@
+package debts
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/debt"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+// Debt is the payoff terms of a liability account, such as a loan or a credit card. The
+// amount owed is the account's balance, so only the rate and the minimum are stored.
+// Priority is the account's place in a custom payoff order, lowest first.
+type Debt struct {
+	AccountID      int64         `db:"account_id"`
+	HouseholdID    int64         `db:"household_id"`
+	Name           string        `db:"name"`
+	Currency       string        `db:"currency"`
+	APR            string        `db:"apr"`
+	MinimumPayment money.Money   `db:"minimum_payment"`
+	Priority       sql.NullInt64 `db:"priority"`
+	UpdatedAt      time.Time     `db:"updated_at"`
+}
+
+// DebtRequest represents the input data for setting an account's payoff terms.
+type DebtRequest struct {
+	APR            string      `json:"apr"`
+	MinimumPayment money.Money `json:"minimum_payment"`
+	Priority       *int64      `json:"priority"`
+}
+
+// Validate validates the DebtRequest struct.
+func (input *DebtRequest) Validate() map[string]string {
+	errors := map[string]string{}
+	if _, err := debt.ParseAPR(input.APR); err != nil {
+		errors["APR"] = "APR must be a non-negative number such as 19.99"
+	}
+	if input.MinimumPayment.Currency() == "" || input.MinimumPayment.IsNegative() {
+		errors["MinimumPayment"] = "MinimumPayment is required and must not be negative"
+	}
+	return errors
+}
+
+// toDebt converts a validated DebtRequest into the terms of an account.
+func (input *DebtRequest) toDebt(accountID int64) *Debt {
+	d := &Debt{AccountID: accountID, APR: input.APR, MinimumPayment: input.MinimumPayment}
+	if input.Priority != nil {
+		d.Priority = sql.NullInt64{Int64: *input.Priority, Valid: true}
+	}
+	return d
+}
+
+// DebtResponse represents a debt to return in responses, with the amount owed as of today.
+type DebtResponse struct {
+	AccountID      int64       `json:"account_id"`
+	Name           string      `json:"name"`
+	Balance        money.Money `json:"balance"`
+	APR            string      `json:"apr"`
+	MinimumPayment money.Money `json:"minimum_payment"`
+	Priority       *int64      `json:"priority,omitempty"`
+}
+
+// ToResponse converts a Debt into a DebtResponse owing balance.
+func (d *Debt) ToResponse(balance money.Money) *DebtResponse {
+	response := &DebtResponse{
+		AccountID:      d.AccountID,
+		Name:           d.Name,
+		Balance:        balance,
+		APR:            d.APR,
+		MinimumPayment: d.MinimumPayment,
+	}
+	if d.Priority.Valid {
+		response.Priority = &d.Priority.Int64
+	}
+	return response
+}
+
+// PlanRequest represents the input data for planning the payoff of a household's debts.
+// Strategy may be left empty to compare all strategies side by side; the custom strategy
+// pays the debts in priority order. ExtraMonthly defaults to nothing extra.
+type PlanRequest struct {
+	ExtraMonthly money.Money   `json:"extra_monthly"`
+	Strategy     debt.Strategy `json:"strategy"`
+}
+
+// PlanResponse represents the payoff plans to return in responses.
+type PlanResponse struct {
+	HouseholdID int64       `json:"household_id"`
+	Plans       []debt.Plan `json:"plans"`
+}
@@ -0,0 +1,8 @@
+package debts
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+	ErrDebtNotFound   error = errors.New("debt not found")
+)
@@ -0,0 +1,188 @@
+package debts
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// debtHandler is an HTTP handler for debt payoff terms and planning
+type debtHandler struct {
+	debtService *debtService
+	logger      *slog.Logger
+	router      *http.ServeMux
+}
+
+// newDebtHandler creates a new debt handler with the provided debt service and logger
+func newDebtHandler(debtService *debtService, logger *slog.Logger, router *http.ServeMux) *debtHandler {
+	debtHandler := &debtHandler{
+		debtService: debtService,
+		logger:      logger,
+		router:      router,
+	}
+	debtHandler.registerRoutes()
+	return debtHandler
+}
+
+// Register routes for debt actions
+func (h *debtHandler) registerRoutes() {
+	h.router.HandleFunc("GET /households/{household}/debts", h.list)
+	h.router.HandleFunc("POST /households/{household}/debts/plan", h.plan)
+	h.router.HandleFunc("PUT /accounts/{id}/debt", h.save)
+	h.router.HandleFunc("GET /accounts/{id}/debt", h.get)
+	h.router.HandleFunc("DELETE /accounts/{id}/debt", h.delete)
+}
+
+// List is an HTTP handler for listing a household's debts with the amount owed today
+func (h *debtHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	debts, err := h.debtService.list(householdID, time.Now())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, debts)
+}
+
+// Plan is an HTTP handler for simulating payoff strategies for a household's debts
+func (h *debtHandler) plan(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req PlanRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	plans, err := h.debtService.plan(householdID, req, time.Now())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, plans)
+}
+
+// Save is an HTTP handler for setting the payoff terms of a liability account
+func (h *debtHandler) save(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req DebtRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	d, err := h.debtService.save(accountID, req, time.Now())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, d)
+}
+
+// Get is an HTTP handler for retrieving an account's payoff terms
+func (h *debtHandler) get(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	d, err := h.debtService.get(accountID, time.Now())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, d)
+}
+
+// Delete is an HTTP handler for removing an account's payoff terms
+func (h *debtHandler) delete(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.debtService.delete(accountID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *debtHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses the account ID from the URL, writing a 400 response on failure
+func (h *debtHandler) pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid account ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// householdID parses the household ID from the URL, writing a 400 response on failure
+func (h *debtHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *debtHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrDebtNotFound), errors.Is(err, accounts.ErrAccountNotFound),
+		errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling debt request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
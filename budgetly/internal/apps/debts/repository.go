@@ -0,0 +1,93 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package debts
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// debtColumns lists the columns selected for a Debt, joined with its account
+const debtColumns = `d.account_id, a.household_id, a.name, a.currency, d.apr, d.minimum_payment,
+	d.priority, d.updated_at`
+
+// debtModel wraps the database connection pool using sqlx
+type debtModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newDebtModel(db *sqlx.DB, logger *slog.Logger) *debtModel {
+	return &debtModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Save stores an account's payoff terms, replacing any it already has
+func (m *debtModel) save(d *Debt) error {
+	query := `INSERT INTO debts (account_id, apr, minimum_payment, priority, updated_at)
+	VALUES (:account_id, :apr, :minimum_payment, :priority, :updated_at)
+	ON CONFLICT (account_id) DO UPDATE SET apr = EXCLUDED.apr,
+		minimum_payment = EXCLUDED.minimum_payment, priority = EXCLUDED.priority,
+		updated_at = EXCLUDED.updated_at`
+
+	d.UpdatedAt = time.Now()
+	if _, err := m.DB.NamedExec(query, d); err != nil {
+		m.logger.Error("Error saving debt", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Debt saved successfully", "account_id", d.AccountID)
+	return nil
+}
+
+// List returns a household's debts in custom payoff order: by priority, then by account
+func (m *debtModel) list(householdID int64) ([]Debt, error) {
+	debts := []Debt{}
+	query := `SELECT ` + debtColumns + ` FROM debts d JOIN accounts a ON a.id = d.account_id
+	WHERE a.household_id = $1 ORDER BY d.priority NULLS LAST, d.account_id`
+	if err := m.DB.Select(&debts, query, householdID); err != nil {
+		m.logger.Error("Error listing debts", "error", err)
+		return nil, ErrInternalServer
+	}
+	return debts, nil
+}
+
+// GetByAccount returns an account's payoff terms
+func (m *debtModel) getByAccount(accountID int64) (*Debt, error) {
+	d := &Debt{}
+	query := `SELECT ` + debtColumns + ` FROM debts d JOIN accounts a ON a.id = d.account_id
+	WHERE d.account_id = $1`
+	err := m.DB.Get(d, query, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDebtNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting debt by account", "error", err)
+		return nil, ErrInternalServer
+	}
+	return d, nil
+}
+
+// Delete removes an account's payoff terms
+func (m *debtModel) delete(accountID int64) error {
+	result, err := m.DB.Exec(`DELETE FROM debts WHERE account_id = $1`, accountID)
+	if err != nil {
+		m.logger.Error("Error deleting debt", "error", err)
+		return ErrInternalServer
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected debt count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrDebtNotFound
+	}
+	return nil
+}
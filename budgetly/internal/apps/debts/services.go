@@ -0,0 +1,204 @@
+package debts
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/debt"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type debtService struct {
+	debtRepo *debtModel
+	logger   *slog.Logger
+}
+
+func newDebtService(debtRepo *debtModel, logger *slog.Logger) *debtService {
+	return &debtService{
+		debtRepo: debtRepo,
+		logger:   logger,
+	}
+}
+
+// save validates and stores the payoff terms of a liability account
+func (s *debtService) save(accountID int64, input DebtRequest, today time.Time) (*DebtResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Debt validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	account, err := accounts.Get(s.debtRepo.DB, s.logger, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if input.MinimumPayment.Currency() != account.Currency {
+		return nil, &validate.ValidationError{Errors: map[string]string{
+			"MinimumPayment": "MinimumPayment must be in the account's currency " + account.Currency,
+		}}
+	}
+
+	if err := s.debtRepo.save(input.toDebt(accountID)); err != nil {
+		return nil, err
+	}
+	d, err := s.debtRepo.getByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	owed, err := s.owed(d, today)
+	if err != nil {
+		return nil, err
+	}
+	return d.ToResponse(owed), nil
+}
+
+// get returns an account's payoff terms with the amount owed today
+func (s *debtService) get(accountID int64, today time.Time) (*DebtResponse, error) {
+	d, err := s.debtRepo.getByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	owed, err := s.owed(d, today)
+	if err != nil {
+		return nil, err
+	}
+	return d.ToResponse(owed), nil
+}
+
+// delete removes an account's payoff terms, leaving the account itself alone
+func (s *debtService) delete(accountID int64) error {
+	return s.debtRepo.delete(accountID)
+}
+
+// list returns a household's debts with the amount owed on each today
+func (s *debtService) list(householdID int64, today time.Time) ([]*DebtResponse, error) {
+	if _, err := households.Get(s.debtRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	stored, err := s.debtRepo.list(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*DebtResponse, 0, len(stored))
+	for i := range stored {
+		owed, err := s.owed(&stored[i], today)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, stored[i].ToResponse(owed))
+	}
+	return responses, nil
+}
+
+// plan simulates paying off a household's debts from this month with the requested
+// strategy, or compares all of them when none is given
+func (s *debtService) plan(householdID int64, input PlanRequest, today time.Time) (*PlanResponse, error) {
+	if _, err := households.Get(s.debtRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	stored, err := s.debtRepo.list(householdID)
+	if err != nil {
+		return nil, err
+	}
+	if len(stored) == 0 {
+		return nil, &validate.ValidationError{Errors: map[string]string{"Debts": debt.ErrNoDebts.Error()}}
+	}
+
+	// Debts are listed in priority order, which is the custom order once any has a priority.
+	debts := make([]debt.Debt, 0, len(stored))
+	var order []string
+	for i := range stored {
+		d := &stored[i]
+		apr, err := debt.ParseAPR(d.APR)
+		if err != nil {
+			s.logger.Error("Invalid stored debt APR", "account_id", d.AccountID, "error", err)
+			return nil, ErrInternalServer
+		}
+		owed, err := s.owed(d, today)
+		if err != nil {
+			return nil, err
+		}
+		id := strconv.FormatInt(d.AccountID, 10)
+		debts = append(debts, debt.Debt{ID: id, Name: d.Name, Balance: owed, APR: apr, MinimumPayment: d.MinimumPayment})
+		order = append(order, id)
+	}
+	if !hasPriority(stored) {
+		if input.Strategy == debt.StrategyCustom {
+			return nil, &validate.ValidationError{Errors: map[string]string{
+				"Strategy": "the custom strategy needs a priority on the household's debts",
+			}}
+		}
+		order = nil
+	}
+
+	extra := input.ExtraMonthly
+	if extra.Currency() == "" {
+		if extra, err = money.Zero(stored[0].Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	var plans []debt.Plan
+	if input.Strategy == "" {
+		plans, err = debt.Compare(debts, extra, order, today)
+	} else {
+		var plan debt.Plan
+		plan, err = debt.Simulate(debts, extra, input.Strategy, order, today)
+		plans = []debt.Plan{plan}
+	}
+	if err != nil {
+		s.logger.Warn("Debt plan rejected", "error", err)
+		return nil, planError(err)
+	}
+	return &PlanResponse{HouseholdID: householdID, Plans: plans}, nil
+}
+
+// owed returns the amount owed on a debt as of a date: its account's balance below zero
+func (s *debtService) owed(d *Debt, asOf time.Time) (money.Money, error) {
+	zero, err := money.Zero(d.Currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	balance, ok, err := balances.Balance(s.debtRepo.DB, s.logger, d.AccountID, asOf)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if !ok || !balance.IsNegative() {
+		return zero, nil
+	}
+	return balance.Negate(), nil
+}
+
+// hasPriority reports whether any debt has a place in the custom payoff order
+func hasPriority(debts []Debt) bool {
+	for _, d := range debts {
+		if d.Priority.Valid {
+			return true
+		}
+	}
+	return false
+}
+
+// planError turns planner errors caused by the input into validation errors
+func planError(err error) error {
+	if errors.Is(err, debt.ErrNegativeExtra) || errors.Is(err, debt.ErrMixedCurrencies) {
+		return &validate.ValidationError{Errors: map[string]string{"ExtraMonthly": err.Error()}}
+	}
+	if errors.Is(err, debt.ErrUnknownStrategy) {
+		return &validate.ValidationError{Errors: map[string]string{"Strategy": err.Error()}}
+	}
+	for _, inputErr := range []error{
+		debt.ErrNoDebts, debt.ErrInvalidDebt, debt.ErrInvalidOrder,
+		debt.ErrNeverPaidOff, debt.ErrDuplicateDebtID, debt.ErrPlanTooLong,
+	} {
+		if errors.Is(err, inputErr) {
+			return &validate.ValidationError{Errors: map[string]string{"Debts": err.Error()}}
+		}
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+package filters
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewFiltersApp creates a new saved filters application with the provided database connection
+func NewFiltersApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	filterModel := newFilterModel(db, logger)
+	filterService := newFilterService(filterModel, logger)
+	newFilterHandler(filterService, logger, router)
+}
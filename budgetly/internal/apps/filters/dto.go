@@ -0,0 +1,77 @@
+package filters
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// Filter is a household's named set of transaction query parameters. Names are unique per
+// household. Query holds the parameters in URL-encoded form, e.g. "tag=3&from=2026-01-01".
+type Filter struct {
+	ID          int       `db:"id"`
+	HouseholdID int64     `db:"household_id"`
+	Name        string    `db:"name"`
+	Query       string    `db:"query"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// FilterRequest represents the input data for saving a filter.
+type FilterRequest struct {
+	Name  string     `json:"name"`
+	Query url.Values `json:"query"`
+}
+
+// Validate validates the FilterRequest struct.
+func (input *FilterRequest) Validate() map[string]string {
+	input.Name = strings.TrimSpace(input.Name)
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Name": validate.Rules(
+			validate.Required,
+			validate.Max(100),
+			validate.ErrorMessage("Name is required and must be at most 100 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if len(input.Query) == 0 {
+		errors["Query"] = "Query must contain at least one parameter"
+	}
+
+	// Check the query the way listing transactions parses it, so a saved filter always runs.
+	if queryErr, ok := transactions.ValidateQuery(input.Query).(*validate.ValidationError); ok {
+		for name, message := range queryErr.Errors {
+			errors["Query."+name] = message
+		}
+	}
+	return errors
+}
+
+// FilterResponse represents the saved filter data to return in responses.
+// QueryString can be appended to a transaction listing URL as is.
+type FilterResponse struct {
+	ID          int        `json:"id"`
+	HouseholdID int64      `json:"household_id"`
+	Name        string     `json:"name"`
+	Query       url.Values `json:"query"`
+	QueryString string     `json:"query_string"`
+}
+
+// ToResponse converts a Filter (from database) to a FilterResponse (for API responses).
+func (f *Filter) ToResponse() *FilterResponse {
+	// Stored queries are always encoded by us, so a parse error leaves an empty query.
+	query, _ := url.ParseQuery(f.Query)
+	return &FilterResponse{
+		ID:          f.ID,
+		HouseholdID: f.HouseholdID,
+		Name:        f.Name,
+		Query:       query,
+		QueryString: f.Query,
+	}
+}
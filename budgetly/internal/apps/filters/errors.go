@@ -0,0 +1,9 @@
+package filters
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+	ErrFilterNotFound error = errors.New("filter not found")
+	ErrFilterExists   error = errors.New("a filter with this name already exists")
+)
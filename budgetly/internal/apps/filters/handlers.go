@@ -0,0 +1,278 @@
+package filters
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// filterListPage renders a household's saved filters, each linking to its transactions page
+var filterListPage = template.Must(template.New("filters").Parse(`
+		<h1>Saved Filters</h1>
+		<ul>
+			{{- range .}}
+			<li><a href="/filters/{{.ID}}/page">{{.Name}}</a> <code>{{.QueryString}}</code></li>
+			{{- else}}
+			<li>No saved filters yet</li>
+			{{- end}}
+		</ul>
+	`))
+
+// filterTransactionsPage renders the transactions matching a saved filter
+var filterTransactionsPage = template.Must(template.New("filter").Parse(`
+		<h1>{{.Filter.Name}}</h1>
+		<p><code>{{.Filter.QueryString}}</code></p>
+		<table>
+			<tr><th>Date</th><th>Payee</th><th>Category</th><th>Amount</th></tr>
+			{{- range .Transactions}}
+			<tr><td>{{.Date}}</td><td>{{.Payee}}</td><td>{{.Category}}</td><td>{{.Amount}}</td></tr>
+			{{- end}}
+		</table>
+	`))
+
+// filterHandler is an HTTP handler for saved filter operations
+// (e.g., saving, listing, applying, deleting filters, etc.)
+type filterHandler struct {
+	filterService *filterService
+	logger        *slog.Logger
+	router        *http.ServeMux
+}
+
+// newFilterHandler creates a new filter handler with the provided filter service and logger
+func newFilterHandler(filterService *filterService, logger *slog.Logger, router *http.ServeMux) *filterHandler {
+	filterHandler := &filterHandler{
+		filterService: filterService,
+		logger:        logger,
+		router:        router,
+	}
+	filterHandler.registerRoutes()
+	filterHandler.registerSSRRoutes()
+	return filterHandler
+}
+
+// Register routes for saved filter actions
+func (h *filterHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/filters", h.create)
+	h.router.HandleFunc("GET /households/{household}/filters", h.list)
+	h.router.HandleFunc("GET /filters/{id}", h.get)
+	h.router.HandleFunc("PUT /filters/{id}", h.update)
+	h.router.HandleFunc("GET /filters/{id}/transactions", h.transactions)
+	h.router.HandleFunc("DELETE /filters/{id}", h.delete)
+}
+
+// registerSSRRoutes registers SSR routes for saved filter actions
+func (h *filterHandler) registerSSRRoutes() {
+	h.router.HandleFunc("GET /households/{household}/filters/page", h.renderFilterListPage)
+	h.router.HandleFunc("GET /filters/{id}/page", h.renderFilterTransactionsPage)
+}
+
+// renderFilterListPage is an SSR handler for rendering a household's saved filters
+func (h *filterHandler) renderFilterListPage(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	filters, err := h.filterService.list(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.render(w, filterListPage, filters)
+}
+
+// renderFilterTransactionsPage is an SSR handler for rendering the transactions matching a saved filter
+func (h *filterHandler) renderFilterTransactionsPage(w http.ResponseWriter, r *http.Request) {
+	filterID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, matching, err := h.filterService.transactions(filterID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.render(w, filterTransactionsPage, map[string]any{"Filter": filter, "Transactions": matching})
+}
+
+// render executes a page template, logging failures once the response has started
+func (h *filterHandler) render(w http.ResponseWriter, page *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(w, data); err != nil {
+		h.logger.Error("Error rendering saved filter page", "error", err)
+	}
+}
+
+// Create is an HTTP handler for saving a new filter in a household
+func (h *filterHandler) create(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req FilterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return
+	}
+
+	filter, err := h.filterService.create(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, filter)
+}
+
+// List is an HTTP handler for listing a household's saved filters
+func (h *filterHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	filters, err := h.filterService.list(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, filters)
+}
+
+// Get is an HTTP handler for retrieving a saved filter by ID
+func (h *filterHandler) get(w http.ResponseWriter, r *http.Request) {
+	filterID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, err := h.filterService.get(filterID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, filter)
+}
+
+// Update is an HTTP handler for renaming a saved filter or replacing its query
+func (h *filterHandler) update(w http.ResponseWriter, r *http.Request) {
+	filterID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req FilterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return
+	}
+
+	filter, err := h.filterService.update(filterID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, filter)
+}
+
+// Transactions is an HTTP handler for listing the transactions matching a saved filter
+func (h *filterHandler) transactions(w http.ResponseWriter, r *http.Request) {
+	filterID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	_, matching, err := h.filterService.transactions(filterID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, matching)
+}
+
+// Delete is an HTTP handler for deleting a saved filter
+func (h *filterHandler) delete(w http.ResponseWriter, r *http.Request) {
+	filterID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.filterService.delete(filterID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pathID parses the filter ID from the URL, writing a 400 response on failure
+func (h *filterHandler) pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	filterID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || filterID <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid filter ID"},
+		)
+		return 0, false
+	}
+	return filterID, true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *filterHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *filterHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrFilterNotFound), errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrFilterExists):
+		utils.WriteJson(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, transactions.ErrRateNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling saved filter request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,126 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package filters
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code for unique constraint violations
+const uniqueViolation = "23505"
+
+// filterColumns lists the columns selected for a Filter
+const filterColumns = `id, household_id, name, query, created_at`
+
+// filterModel wraps the database connection pool using sqlx
+type filterModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newFilterModel(db *sqlx.DB, logger *slog.Logger) *filterModel {
+	return &filterModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new saved filter into the database and returns its ID
+func (m *filterModel) create(f *Filter) (int, error) {
+	query := `INSERT INTO saved_filters (household_id, name, query, created_at)
+	VALUES (:household_id, :name, :query, :created_at)
+	RETURNING id`
+
+	f.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, f)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, ErrFilterExists
+		}
+		m.logger.Error("Error inserting saved filter", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&f.ID); err != nil {
+			m.logger.Error("Error scanning saved filter ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Saved filter created successfully", "id", f.ID)
+	return f.ID, nil
+}
+
+// List returns a household's saved filters ordered by name
+func (m *filterModel) list(householdID int64) ([]Filter, error) {
+	query := `SELECT ` + filterColumns + ` FROM saved_filters WHERE household_id = $1 ORDER BY name`
+
+	filters := []Filter{}
+	if err := m.DB.Select(&filters, query, householdID); err != nil {
+		m.logger.Error("Error listing saved filters", "error", err)
+		return nil, ErrInternalServer
+	}
+	return filters, nil
+}
+
+// GetByID returns a saved filter by ID
+func (m *filterModel) getByID(id int) (*Filter, error) {
+	f := &Filter{}
+	err := m.DB.Get(f, `SELECT `+filterColumns+` FROM saved_filters WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFilterNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting saved filter by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return f, nil
+}
+
+// Update renames a saved filter or replaces its query and returns it
+func (m *filterModel) update(f *Filter) (*Filter, error) {
+	updated := &Filter{}
+	query := `UPDATE saved_filters SET name = $2, query = $3 WHERE id = $1 RETURNING ` + filterColumns
+	err := m.DB.Get(updated, query, f.ID, f.Name, f.Query)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrFilterNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return nil, ErrFilterExists
+	}
+	if err != nil {
+		m.logger.Error("Error updating saved filter", "error", err)
+		return nil, ErrInternalServer
+	}
+	return updated, nil
+}
+
+// Delete removes a saved filter by ID
+func (m *filterModel) delete(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM saved_filters WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting saved filter", "error", err)
+		return ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected saved filter count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrFilterNotFound
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package filters
+
+import (
+	"log/slog"
+	"net/url"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type filterService struct {
+	filterRepo *filterModel
+	logger     *slog.Logger
+}
+
+func newFilterService(filterRepo *filterModel, logger *slog.Logger) *filterService {
+	return &filterService{
+		filterRepo: filterRepo,
+		logger:     logger,
+	}
+}
+
+// create validates and stores a new saved filter of a household
+func (s *filterService) create(householdID int64, input FilterRequest) (*FilterResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Saved filter validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.filterRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	// Encode sorts the keys, so equal filters are stored identically.
+	filter := &Filter{HouseholdID: householdID, Name: input.Name, Query: input.Query.Encode()}
+	if _, err := s.filterRepo.create(filter); err != nil {
+		return nil, err
+	}
+	return filter.ToResponse(), nil
+}
+
+// list returns a household's saved filters ordered by name
+func (s *filterService) list(householdID int64) ([]*FilterResponse, error) {
+	if _, err := households.Get(s.filterRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	filters, err := s.filterRepo.list(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*FilterResponse, 0, len(filters))
+	for i := range filters {
+		responses = append(responses, filters[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// get returns a saved filter by ID
+func (s *filterService) get(id int) (*FilterResponse, error) {
+	filter, err := s.filterRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return filter.ToResponse(), nil
+}
+
+// update validates and renames a saved filter or replaces its query
+func (s *filterService) update(id int, input FilterRequest) (*FilterResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Saved filter validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	updated, err := s.filterRepo.update(&Filter{ID: id, Name: input.Name, Query: input.Query.Encode()})
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToResponse(), nil
+}
+
+// transactions returns the household's transactions matching a saved filter
+func (s *filterService) transactions(id int) (*FilterResponse, []*transactions.TransactionResponse, error) {
+	filter, err := s.filterRepo.getByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Stored queries are always encoded by us, so a parse error leaves an empty query.
+	query, _ := url.ParseQuery(filter.Query)
+	matching, err := transactions.List(s.filterRepo.DB, s.logger, filter.HouseholdID, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return filter.ToResponse(), matching, nil
+}
+
+// delete removes a saved filter by ID
+func (s *filterService) delete(id int) error {
+	return s.filterRepo.delete(id)
+}
@@ -0,0 +1,34 @@
+package filters
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/url"
+	"testing"
+
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+func TestCreateMalformedQuery(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := newFilterService(newFilterModel(nil, logger), logger)
+
+	_, err := service.create(1, FilterRequest{
+		Name:  "Groceries",
+		Query: url.Values{"from": {"last week"}, "tag": {"3"}, "sort": {"amount"}},
+	})
+
+	var validationErr *validate.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	for _, field := range []string{"Query.from", "Query.sort"} {
+		if validationErr.Errors[field] == "" {
+			t.Errorf("Expected an error for %s, got %v", field, validationErr.Errors)
+		}
+	}
+	if _, ok := validationErr.Errors["Query.tag"]; ok {
+		t.Errorf("Expected no error for a valid tag, got %v", validationErr.Errors)
+	}
+}
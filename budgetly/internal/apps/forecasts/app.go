@@ -0,0 +1,17 @@
+package forecasts
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewForecastsApp creates a new cash-flow forecast application with the provided database
+// connection. Forecasts are built from the household's stored balances, scheduled
+// transactions and detected recurring payments.
+func NewForecastsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	forecastModel := newForecastModel(db, logger)
+	forecastService := newForecastService(forecastModel, logger)
+	newForecastHandler(forecastService, logger, router)
+}
@@ -0,0 +1,50 @@
+package forecasts
+
+import (
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/forecast"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+const (
+	// defaultDays is the forecast length when none is requested.
+	defaultDays = 30
+	// defaultSpendingDays is how many past days discretionary spending is averaged over.
+	defaultSpendingDays = 90
+)
+
+// ScheduledTransaction is a stored scheduled transaction, expected on its date.
+type ScheduledTransaction struct {
+	ID        int64       `db:"id"`
+	AccountID int64       `db:"account_id"`
+	Date      time.Time   `db:"date"`
+	Amount    money.Money `db:"amount"`
+	Payee     string      `db:"payee"`
+}
+
+// SpendingLine is a past transaction line, counted towards day-to-day spending.
+type SpendingLine struct {
+	AccountID int64       `db:"account_id"`
+	Category  string      `db:"category"`
+	Date      time.Time   `db:"date"`
+	Amount    money.Money `db:"amount"`
+}
+
+// ForecastOptions represents the query options of a forecast. Thresholds are balances to
+// warn about crossing, in each account's currency; SpendingDays of zero leaves day-to-day
+// spending out of the forecast.
+type ForecastOptions struct {
+	Days         int
+	AccountID    int64
+	Thresholds   []string
+	SpendingDays int
+}
+
+// ForecastResponse represents the projected balances to return in responses.
+type ForecastResponse struct {
+	HouseholdID int64                      `json:"household_id"`
+	Start       string                     `json:"start"`
+	Days        int                        `json:"days"`
+	Accounts    []forecast.AccountForecast `json:"accounts"`
+}
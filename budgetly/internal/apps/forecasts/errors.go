@@ -0,0 +1,7 @@
+package forecasts
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+)
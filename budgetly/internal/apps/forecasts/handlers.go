@@ -0,0 +1,97 @@
+package forecasts
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// forecastHandler is an HTTP handler for cash-flow forecasts
+type forecastHandler struct {
+	forecastService *forecastService
+	logger          *slog.Logger
+	router          *http.ServeMux
+}
+
+// newForecastHandler creates a new forecast handler with the provided forecast service and logger
+func newForecastHandler(forecastService *forecastService, logger *slog.Logger, router *http.ServeMux) *forecastHandler {
+	forecastHandler := &forecastHandler{
+		forecastService: forecastService,
+		logger:          logger,
+		router:          router,
+	}
+	forecastHandler.registerRoutes()
+	return forecastHandler
+}
+
+// Register routes for forecast actions
+func (h *forecastHandler) registerRoutes() {
+	h.router.HandleFunc("GET /households/{household}/forecast", h.project)
+}
+
+// Project is an HTTP handler for projecting a household's daily account balances over the
+// next days (default 30), optionally for one account_id, warning about crossing each
+// threshold, with day-to-day spending averaged over the last spending_days (default 90)
+func (h *forecastHandler) project(w http.ResponseWriter, r *http.Request) {
+	householdID, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || householdID <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return
+	}
+
+	query := r.URL.Query()
+	errs := map[string]string{}
+	options := ForecastOptions{Days: defaultDays, SpendingDays: defaultSpendingDays, Thresholds: query["threshold"]}
+	if value := query.Get("days"); value != "" {
+		if options.Days, err = strconv.Atoi(value); err != nil {
+			errs["days"] = "days must be a number"
+		}
+	}
+	if value := query.Get("account_id"); value != "" {
+		if options.AccountID, err = strconv.ParseInt(value, 10, 64); err != nil || options.AccountID <= 0 {
+			errs["account_id"] = "account_id must be a positive number"
+		}
+	}
+	if value := query.Get("spending_days"); value != "" {
+		if options.SpendingDays, err = strconv.Atoi(value); err != nil || options.SpendingDays < 0 || options.SpendingDays > 366 {
+			errs["spending_days"] = "spending_days must be a number between 0 and 366"
+		}
+	}
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+
+	forecast, err := h.forecastService.project(householdID, options, time.Now())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, forecast)
+}
+
+// writeError maps service errors to HTTP responses
+func (h *forecastHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, households.ErrHouseholdNotFound), errors.Is(err, accounts.ErrAccountNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error projecting cash flow", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
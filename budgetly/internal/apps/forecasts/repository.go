@@ -0,0 +1,56 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package forecasts
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// forecastModel wraps the database connection pool using sqlx
+type forecastModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newForecastModel(db *sqlx.DB, logger *slog.Logger) *forecastModel {
+	return &forecastModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Scheduled returns a household's scheduled transactions dated after a day, in date order
+func (m *forecastModel) scheduled(householdID int64, after time.Time) ([]ScheduledTransaction, error) {
+	query := `SELECT id, account_id, date, amount, payee FROM transactions
+	WHERE household_id = $1 AND scheduled AND date > $2
+	ORDER BY date, id`
+
+	scheduled := []ScheduledTransaction{}
+	if err := m.DB.Select(&scheduled, query, householdID, after); err != nil {
+		m.logger.Error("Error listing scheduled transactions", "error", err)
+		return nil, ErrInternalServer
+	}
+	return scheduled, nil
+}
+
+// Spending returns a household's outgoing transaction lines from..to (exclusive), counting
+// split transactions per split line. Transfers, scheduled transactions and the payments of
+// recurring bills, which are forecast on their own, are left out.
+func (m *forecastModel) spending(householdID int64, from, to time.Time, recurringIDs []int64) ([]SpendingLine, error) {
+	query := `SELECT account_id, category, date, amount FROM transaction_lines
+	WHERE household_id = $1 AND date >= $2 AND date < $3
+		AND split_part(amount, ' ', 1)::numeric < 0 AND transfer_id IS NULL AND NOT scheduled
+		AND NOT (id = ANY($4))
+	ORDER BY date, id`
+
+	lines := []SpendingLine{}
+	if err := m.DB.Select(&lines, query, householdID, from, to, pq.Array(recurringIDs)); err != nil {
+		m.logger.Error("Error listing spending", "error", err)
+		return nil, ErrInternalServer
+	}
+	return lines, nil
+}
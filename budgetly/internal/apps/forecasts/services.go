@@ -0,0 +1,181 @@
+package forecasts
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
+	"github.com/ZiadMansourM/budgetly/pkg/forecast"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type forecastService struct {
+	forecastRepo *forecastModel
+	logger       *slog.Logger
+}
+
+func newForecastService(forecastRepo *forecastModel, logger *slog.Logger) *forecastService {
+	return &forecastService{
+		forecastRepo: forecastRepo,
+		logger:       logger,
+	}
+}
+
+// project forecasts the daily balances of a household's accounts, or of one, from the day
+// after today. Each account starts from its balance at the end of today and moves with the
+// scheduled transactions after today, the recurring payments detected in its history, and
+// its average day-to-day spending.
+func (s *forecastService) project(householdID int64, options ForecastOptions, today time.Time) (*ForecastResponse, error) {
+	if _, err := households.Get(s.forecastRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	start := today.AddDate(0, 0, 1)
+
+	stored, err := accounts.List(s.forecastRepo.DB, s.logger, householdID)
+	if err != nil {
+		return nil, err
+	}
+	forecastAccounts := make([]forecast.Account, 0, len(stored))
+	included := map[int64]bool{}
+	for _, account := range stored {
+		if options.AccountID != 0 && account.ID != options.AccountID {
+			continue
+		}
+		forecastAccount, err := s.account(&account, options.Thresholds, today)
+		if err != nil {
+			return nil, err
+		}
+		forecastAccounts = append(forecastAccounts, forecastAccount)
+		included[account.ID] = true
+	}
+	if options.AccountID != 0 && len(forecastAccounts) == 0 {
+		return nil, accounts.ErrAccountNotFound
+	}
+
+	items, recurringIDs, err := s.items(householdID, included, today)
+	if err != nil {
+		return nil, err
+	}
+
+	var discretionary []forecast.Discretionary
+	if options.SpendingDays > 0 {
+		lines, err := s.forecastRepo.spending(householdID, start.AddDate(0, 0, -options.SpendingDays), start, recurringIDs)
+		if err != nil {
+			return nil, err
+		}
+		spending := make([]forecast.Spending, 0, len(lines))
+		for _, line := range lines {
+			if included[line.AccountID] {
+				spending = append(spending, forecast.Spending{
+					AccountID: strconv.FormatInt(line.AccountID, 10),
+					Category:  line.Category,
+					Date:      line.Date,
+					Amount:    line.Amount,
+				})
+			}
+		}
+		if discretionary, err = forecast.AverageSpending(spending, start.AddDate(0, 0, -options.SpendingDays), start); err != nil {
+			return nil, err
+		}
+	}
+
+	forecasts, err := forecast.Project(forecastAccounts, items, discretionary, start, options.Days)
+	if err != nil {
+		s.logger.Warn("Forecast rejected", "household_id", householdID, "error", err)
+		if errors.Is(err, forecast.ErrInvalidDays) {
+			return nil, &validate.ValidationError{Errors: map[string]string{"days": err.Error()}}
+		}
+		return nil, err
+	}
+	return &ForecastResponse{
+		HouseholdID: householdID,
+		Start:       start.Format(time.DateOnly),
+		Days:        options.Days,
+		Accounts:    forecasts,
+	}, nil
+}
+
+// account returns an account to forecast from its balance at the end of today, with the
+// thresholds in its currency
+func (s *forecastService) account(account *accounts.Account, thresholds []string, today time.Time) (forecast.Account, error) {
+	balance, ok, err := balances.Balance(s.forecastRepo.DB, s.logger, account.ID, today)
+	if err != nil {
+		return forecast.Account{}, err
+	}
+	if !ok {
+		if balance, err = money.Zero(account.Currency); err != nil {
+			return forecast.Account{}, err
+		}
+	}
+
+	result := forecast.Account{ID: strconv.FormatInt(account.ID, 10), Balance: balance, Thresholds: []money.Money{}}
+	for i, value := range thresholds {
+		threshold, err := money.Parse(value, account.Currency)
+		if err != nil {
+			field := fmt.Sprintf("threshold[%d]", i)
+			return forecast.Account{}, &validate.ValidationError{Errors: map[string]string{field: field + " must be a decimal amount such as 100.00"}}
+		}
+		result.Thresholds = append(result.Thresholds, threshold)
+	}
+	return result, nil
+}
+
+// items returns the expected transactions of the included accounts: the scheduled
+// transactions after today, and the recurring payments detected up to today, each repeating
+// from its next charge. A scheduled transaction that stands for the next charge of a
+// recurring payment starts that payment's repetition instead of being expected once. The
+// IDs of the recurring payments' past transactions are returned so that they are not
+// counted as day-to-day spending as well.
+func (s *forecastService) items(householdID int64, included map[int64]bool, today time.Time) ([]forecast.Item, []int64, error) {
+	scheduled, err := s.forecastRepo.scheduled(householdID, today)
+	if err != nil {
+		return nil, nil, err
+	}
+	subscriptions, err := transactions.Subscriptions(s.forecastRepo.DB, s.logger, householdID, today)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[int64]ScheduledTransaction, len(scheduled))
+	for _, pending := range scheduled {
+		byID[pending.ID] = pending
+	}
+
+	items := []forecast.Item{}
+	recurringIDs := []int64{}
+	for _, subscription := range subscriptions {
+		for _, id := range subscription.TransactionIDs {
+			if parsed, err := strconv.ParseInt(id, 10, 64); err == nil {
+				recurringIDs = append(recurringIDs, parsed)
+			}
+		}
+		if !included[subscription.AccountID] {
+			continue
+		}
+		item := forecast.FromSubscription(strconv.FormatInt(subscription.AccountID, 10), subscription.Subscription)
+		if pending, ok := byID[subscription.ScheduledID]; ok {
+			item.NextDate, item.Amount = pending.Date, pending.Amount
+			delete(byID, pending.ID)
+		}
+		items = append(items, item)
+	}
+	for _, pending := range scheduled {
+		if _, ok := byID[pending.ID]; ok && included[pending.AccountID] {
+			items = append(items, forecast.Item{
+				AccountID:   strconv.FormatInt(pending.AccountID, 10),
+				Description: pending.Payee,
+				Amount:      pending.Amount,
+				NextDate:    pending.Date,
+			})
+		}
+	}
+	return items, recurringIDs, nil
+}
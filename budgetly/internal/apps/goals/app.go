@@ -0,0 +1,15 @@
+package goals
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewGoalsApp creates a new savings goals application with the provided database connection
+func NewGoalsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	goalModel := newGoalModel(db, logger)
+	goalService := newGoalService(goalModel, logger)
+	newGoalHandler(goalService, logger, router)
+}
@@ -0,0 +1,119 @@
+package goals
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/goals"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// Goal is a household's savings target linked to either a category or an account of
+// the household, whose balance counts as the money saved towards it.
+type Goal struct {
+	ID          int            `db:"id"`
+	HouseholdID int64          `db:"household_id"`
+	Name        string         `db:"name"`
+	Target      money.Money    `db:"target"`
+	TargetDate  sql.NullTime   `db:"target_date"`
+	Category    sql.NullString `db:"category"`
+	AccountID   sql.NullInt64  `db:"account_id"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+// GoalRequest represents the input data for creating or updating a goal.
+type GoalRequest struct {
+	Name       string      `json:"name"`
+	Target     money.Money `json:"target"`
+	TargetDate string      `json:"target_date"`
+	Category   string      `json:"category"`
+	AccountID  int64       `json:"account_id"`
+}
+
+// Validate validates the GoalRequest struct.
+func (input *GoalRequest) Validate() map[string]string {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Category = strings.TrimSpace(input.Category)
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Name": validate.Rules(
+			validate.Required,
+			validate.Max(100),
+			validate.ErrorMessage("Name is required and must be at most 100 characters long"),
+		),
+		"Target": validate.Rules(
+			money.Valid,
+			money.Positive,
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if (input.Category == "") == (input.AccountID == 0) {
+		errors["Category"] = "Exactly one of category or account_id is required"
+	}
+	if input.TargetDate != "" {
+		if _, err := time.Parse(time.DateOnly, input.TargetDate); err != nil {
+			errors["TargetDate"] = "TargetDate must be in YYYY-MM-DD format"
+		}
+	}
+	return errors
+}
+
+// toGoal converts a validated GoalRequest into a Goal of the household.
+func (input *GoalRequest) toGoal(householdID int64) *Goal {
+	goal := &Goal{
+		HouseholdID: householdID,
+		Name:        input.Name,
+		Target:      input.Target,
+		Category:    sql.NullString{String: input.Category, Valid: input.Category != ""},
+		AccountID:   sql.NullInt64{Int64: input.AccountID, Valid: input.AccountID != 0},
+	}
+	if date, err := time.Parse(time.DateOnly, input.TargetDate); err == nil {
+		goal.TargetDate = sql.NullTime{Time: date, Valid: true}
+	}
+	return goal
+}
+
+// GoalResponse represents the goal data to return in responses.
+type GoalResponse struct {
+	ID          int         `json:"id"`
+	HouseholdID int64       `json:"household_id"`
+	Name        string      `json:"name"`
+	Target      money.Money `json:"target"`
+	TargetDate  string      `json:"target_date,omitempty"`
+	Category    string      `json:"category,omitempty"`
+	AccountID   int64       `json:"account_id,omitempty"`
+}
+
+// ToResponse converts a Goal (from database) to a GoalResponse (for API responses).
+func (g *Goal) ToResponse() *GoalResponse {
+	response := &GoalResponse{
+		ID:          g.ID,
+		HouseholdID: g.HouseholdID,
+		Name:        g.Name,
+		Target:      g.Target,
+		Category:    g.Category.String,
+		AccountID:   g.AccountID.Int64,
+	}
+	if g.TargetDate.Valid {
+		response.TargetDate = g.TargetDate.Time.Format(time.DateOnly)
+	}
+	return response
+}
+
+// Contribution is a transaction counting towards a goal.
+type Contribution struct {
+	Date   time.Time   `db:"date"`
+	Amount money.Money `db:"amount"`
+}
+
+// StatusResponse represents a goal's progress as of a date.
+type StatusResponse struct {
+	GoalID int    `json:"goal_id"`
+	AsOf   string `json:"as_of"`
+	goals.Status
+}
@@ -0,0 +1,8 @@
+package goals
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+	ErrGoalNotFound   error = errors.New("goal not found")
+)
@@ -0,0 +1,230 @@
+package goals
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// goalHandler is an HTTP handler for savings goal operations
+// (e.g., creating, updating, deleting goals, etc.)
+type goalHandler struct {
+	goalService *goalService
+	logger      *slog.Logger
+	router      *http.ServeMux
+}
+
+// newGoalHandler creates a new goal handler with the provided goal service and logger
+func newGoalHandler(goalService *goalService, logger *slog.Logger, router *http.ServeMux) *goalHandler {
+	goalHandler := &goalHandler{
+		goalService: goalService,
+		logger:      logger,
+		router:      router,
+	}
+	goalHandler.registerRoutes()
+	return goalHandler
+}
+
+// Register routes for savings goal actions
+func (h *goalHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/goals", h.create)
+	h.router.HandleFunc("GET /households/{household}/goals", h.list)
+	h.router.HandleFunc("GET /goals/{id}", h.get)
+	h.router.HandleFunc("GET /goals/{id}/status", h.status)
+	h.router.HandleFunc("PUT /goals/{id}", h.update)
+	h.router.HandleFunc("DELETE /goals/{id}", h.delete)
+}
+
+// Create is an HTTP handler for creating a new goal in a household
+func (h *goalHandler) create(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req GoalRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	goal, err := h.goalService.create(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, goal)
+}
+
+// List is an HTTP handler for listing a household's goals
+func (h *goalHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	goals, err := h.goalService.list(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, goals)
+}
+
+// Get is an HTTP handler for retrieving a goal by ID
+func (h *goalHandler) get(w http.ResponseWriter, r *http.Request) {
+	goalID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	goal, err := h.goalService.get(goalID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, goal)
+}
+
+// Status is an HTTP handler for a goal's progress as of a date (default today), with the
+// saving pace averaged over the last months full months (default 3)
+func (h *goalHandler) status(w http.ResponseWriter, r *http.Request) {
+	goalID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	errs := map[string]string{}
+	query := r.URL.Query()
+	asOf := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := query.Get("as_of"); value != "" {
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			errs["as_of"] = "as_of must be in YYYY-MM-DD format"
+		}
+		asOf = date
+	}
+	months := defaultPaceMonths
+	if value := query.Get("months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 60 {
+			errs["months"] = "months must be a number between 1 and 60"
+		}
+		months = parsed
+	}
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+
+	status, err := h.goalService.status(goalID, asOf, months)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, status)
+}
+
+// Update is an HTTP handler for updating a goal
+func (h *goalHandler) update(w http.ResponseWriter, r *http.Request) {
+	goalID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req GoalRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	goal, err := h.goalService.update(goalID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, goal)
+}
+
+// Delete is an HTTP handler for deleting a goal
+func (h *goalHandler) delete(w http.ResponseWriter, r *http.Request) {
+	goalID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.goalService.delete(goalID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *goalHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses the goal ID from the URL, writing a 400 response on failure
+func (h *goalHandler) pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	goalID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || goalID <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid goal ID"},
+		)
+		return 0, false
+	}
+	return goalID, true
+}
+
+// householdID parses the household ID from the URL, writing a 400 response on failure
+func (h *goalHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *goalHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrGoalNotFound), errors.Is(err, accounts.ErrAccountNotFound),
+		errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling goal request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
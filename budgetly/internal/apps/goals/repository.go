@@ -0,0 +1,132 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package goals
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// goalColumns lists the columns selected for a Goal
+const goalColumns = `id, household_id, name, target, target_date, category, account_id, created_at`
+
+// goalModel wraps the database connection pool using sqlx
+type goalModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newGoalModel(db *sqlx.DB, logger *slog.Logger) *goalModel {
+	return &goalModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new goal into the database and returns its ID
+func (m *goalModel) create(g *Goal) (int, error) {
+	query := `INSERT INTO goals (household_id, name, target, target_date, category, account_id, created_at)
+	VALUES (:household_id, :name, :target, :target_date, :category, :account_id, :created_at)
+	RETURNING id`
+
+	g.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, g)
+	if err != nil {
+		m.logger.Error("Error inserting goal", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&g.ID); err != nil {
+			m.logger.Error("Error scanning goal ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Goal created successfully", "id", g.ID)
+	return g.ID, nil
+}
+
+// List returns a household's goals, those due soonest first
+func (m *goalModel) list(householdID int64) ([]Goal, error) {
+	goals := []Goal{}
+	query := `SELECT ` + goalColumns + ` FROM goals WHERE household_id = $1 ORDER BY target_date NULLS LAST, name`
+	if err := m.DB.Select(&goals, query, householdID); err != nil {
+		m.logger.Error("Error listing goals", "error", err)
+		return nil, ErrInternalServer
+	}
+	return goals, nil
+}
+
+// GetByID returns a goal by ID
+func (m *goalModel) getByID(id int) (*Goal, error) {
+	g := &Goal{}
+	err := m.DB.Get(g, `SELECT `+goalColumns+` FROM goals WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGoalNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting goal by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return g, nil
+}
+
+// Update replaces a goal's definition
+func (m *goalModel) update(g *Goal) error {
+	query := `UPDATE goals SET name = :name, target = :target, target_date = :target_date,
+	category = :category, account_id = :account_id WHERE id = :id`
+
+	result, err := m.DB.NamedExec(query, g)
+	if err != nil {
+		m.logger.Error("Error updating goal", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result)
+}
+
+// Delete removes a goal by ID
+func (m *goalModel) delete(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM goals WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting goal", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result)
+}
+
+// expectRow returns ErrGoalNotFound when a statement affected no rows
+func (m *goalModel) expectRow(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected goal count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrGoalNotFound
+	}
+	return nil
+}
+
+// Contributions returns the household's transactions from..to that count towards a goal:
+// those of its account, or those in its category, counting split transactions per split
+// line, in date order. Scheduled transactions have not happened yet and are left out.
+func (m *goalModel) contributions(g *Goal, from, to time.Time) ([]Contribution, error) {
+	query := `SELECT date, amount FROM transaction_lines
+	WHERE household_id = $1 AND (account_id = $2 OR category = $3) AND date >= $4 AND date <= $5
+		AND NOT scheduled
+	ORDER BY date, id`
+
+	contributions := []Contribution{}
+	if err := m.DB.Select(&contributions, query, g.HouseholdID, g.AccountID, g.Category, from, to); err != nil {
+		m.logger.Error("Error listing goal contributions", "error", err)
+		return nil, ErrInternalServer
+	}
+	return contributions, nil
+}
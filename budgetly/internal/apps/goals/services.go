@@ -0,0 +1,182 @@
+package goals
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/goals"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// defaultPaceMonths is how many full months the saving pace is averaged over by default
+const defaultPaceMonths = 3
+
+type goalService struct {
+	goalRepo *goalModel
+	logger   *slog.Logger
+}
+
+func newGoalService(goalRepo *goalModel, logger *slog.Logger) *goalService {
+	return &goalService{
+		goalRepo: goalRepo,
+		logger:   logger,
+	}
+}
+
+// create validates and stores a new goal of a household
+func (s *goalService) create(householdID int64, input GoalRequest) (*GoalResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Goal validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.goalRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	goal := input.toGoal(householdID)
+	if err := s.checkAccount(goal); err != nil {
+		return nil, err
+	}
+	if _, err := s.goalRepo.create(goal); err != nil {
+		return nil, err
+	}
+	return goal.ToResponse(), nil
+}
+
+// list returns a household's goals
+func (s *goalService) list(householdID int64) ([]*GoalResponse, error) {
+	if _, err := households.Get(s.goalRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	goals, err := s.goalRepo.list(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*GoalResponse, 0, len(goals))
+	for i := range goals {
+		responses = append(responses, goals[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// get returns a goal by ID
+func (s *goalService) get(id int) (*GoalResponse, error) {
+	goal, err := s.goalRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return goal.ToResponse(), nil
+}
+
+// update validates and replaces a goal's definition
+func (s *goalService) update(id int, input GoalRequest) (*GoalResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Goal validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	existing, err := s.goalRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	goal := input.toGoal(existing.HouseholdID)
+	goal.ID = id
+	goal.CreatedAt = existing.CreatedAt
+	if err := s.checkAccount(goal); err != nil {
+		return nil, err
+	}
+	if err := s.goalRepo.update(goal); err != nil {
+		return nil, err
+	}
+	return goal.ToResponse(), nil
+}
+
+// checkAccount rejects an account goal whose account belongs to another household
+func (s *goalService) checkAccount(goal *Goal) error {
+	if !goal.AccountID.Valid {
+		return nil
+	}
+	account, err := accounts.Get(s.goalRepo.DB, s.logger, goal.AccountID.Int64)
+	if err != nil {
+		return err
+	}
+	if account.HouseholdID != goal.HouseholdID {
+		return &validate.ValidationError{Errors: map[string]string{"AccountID": "AccountID must be an account of the goal's household"}}
+	}
+	return nil
+}
+
+// delete removes a goal by ID
+func (s *goalService) delete(id int) error {
+	return s.goalRepo.delete(id)
+}
+
+// status reports a goal's progress as of a date, with the saving pace averaged over the
+// last months full months. An account goal's saved amount is the account's balance; a
+// category goal's is the sum of the category's transactions in the target's currency.
+func (s *goalService) status(id int, asOf time.Time, months int) (*StatusResponse, error) {
+	goal, err := s.goalRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	currency := goal.Target.Currency()
+	saved, err := money.Zero(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// An account's balance comes from its snapshots, so only the pace window is needed.
+	var from time.Time
+	if goal.AccountID.Valid {
+		account, err := accounts.Get(s.goalRepo.DB, s.logger, goal.AccountID.Int64)
+		if err != nil {
+			return nil, err
+		}
+		if account.Currency != currency {
+			return nil, &validate.ValidationError{Errors: map[string]string{
+				"Target": "Target must be in the account's currency " + account.Currency,
+			}}
+		}
+		balance, ok, err := balances.Balance(s.goalRepo.DB, s.logger, account.ID, asOf)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			saved = balance
+		}
+		from = time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -months, 0)
+	}
+
+	contributions, err := s.goalRepo.contributions(goal, from, asOf)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]goals.Contribution, 0, len(contributions))
+	for _, contribution := range contributions {
+		if contribution.Amount.Currency() != currency {
+			continue
+		}
+		history = append(history, goals.Contribution{Date: contribution.Date, Amount: contribution.Amount})
+		if !goal.AccountID.Valid {
+			if saved, err = saved.Add(contribution.Amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	status, err := goals.Progress(goals.Goal{Target: goal.Target, TargetDate: goal.TargetDate.Time}, saved, history, asOf, months)
+	if errors.Is(err, goals.ErrInvalidTarget) {
+		return nil, &validate.ValidationError{Errors: map[string]string{"Target": err.Error()}}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &StatusResponse{GoalID: goal.ID, AsOf: asOf.Format(time.DateOnly), Status: status}, nil
+}
@@ -0,0 +1,21 @@
+package households
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewHouseholdsApp creates a new households application with the provided database connection
+func NewHouseholdsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	householdModel := newHouseholdModel(db, logger)
+	householdService := newHouseholdService(householdModel, logger)
+	newHouseholdHandler(householdService, logger, router)
+}
+
+// Get returns a household by ID, or ErrHouseholdNotFound. Apps that keep data per household
+// call it before reading or writing under /households/{household}/.
+func Get(db *sqlx.DB, logger *slog.Logger, id int64) (*Household, error) {
+	return newHouseholdModel(db, logger).getByID(id)
+}
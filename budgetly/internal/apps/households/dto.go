@@ -0,0 +1,67 @@
+package households
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// defaultReportingCurrency is the reporting currency of a household created without one
+const defaultReportingCurrency = "EUR"
+
+// Household owns accounts, transactions and everything configured for them: tags, rules,
+// alerts, webhooks, fiscal periods and so on. Amounts in other currencies are converted
+// to its ReportingCurrency for reports.
+type Household struct {
+	ID                int64     `db:"id"`
+	Name              string    `db:"name"`
+	ReportingCurrency string    `db:"reporting_currency"`
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+// HouseholdRequest represents the input data for creating or updating a household. An
+// empty ReportingCurrency keeps the current one, or the default for a new household.
+type HouseholdRequest struct {
+	Name              string `json:"name"`
+	ReportingCurrency string `json:"reporting_currency"`
+}
+
+// Validate validates the HouseholdRequest struct.
+func (input *HouseholdRequest) Validate() map[string]string {
+	input.Name = strings.TrimSpace(input.Name)
+	input.ReportingCurrency = strings.ToUpper(strings.TrimSpace(input.ReportingCurrency))
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Name": validate.Rules(
+			validate.Required,
+			validate.Max(100),
+			validate.ErrorMessage("Name is required and must be at most 100 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if input.ReportingCurrency != "" && !money.IsCurrency(input.ReportingCurrency) {
+		errors["ReportingCurrency"] = "ReportingCurrency must be a supported ISO 4217 currency code"
+	}
+	return errors
+}
+
+// HouseholdResponse represents the household data to return in responses.
+type HouseholdResponse struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"`
+	ReportingCurrency string `json:"reporting_currency"`
+}
+
+// ToResponse converts a Household (from database) to a HouseholdResponse (for API responses).
+func (h *Household) ToResponse() *HouseholdResponse {
+	return &HouseholdResponse{
+		ID:                h.ID,
+		Name:              h.Name,
+		ReportingCurrency: h.ReportingCurrency,
+	}
+}
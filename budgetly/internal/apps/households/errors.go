@@ -0,0 +1,8 @@
+package households
+
+import "errors"
+
+var (
+	ErrInternalServer    error = errors.New("internal server error")
+	ErrHouseholdNotFound error = errors.New("household not found")
+)
@@ -0,0 +1,161 @@
+package households
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// householdHandler is an HTTP handler for household operations
+// (e.g., creating, renaming, deleting households, etc.)
+type householdHandler struct {
+	householdService *householdService
+	logger           *slog.Logger
+	router           *http.ServeMux
+}
+
+// newHouseholdHandler creates a new household handler with the provided household service and logger
+func newHouseholdHandler(householdService *householdService, logger *slog.Logger, router *http.ServeMux) *householdHandler {
+	householdHandler := &householdHandler{
+		householdService: householdService,
+		logger:           logger,
+		router:           router,
+	}
+	householdHandler.registerRoutes()
+	return householdHandler
+}
+
+// Register routes for household-related actions
+func (h *householdHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households", h.create)
+	h.router.HandleFunc("GET /households", h.list)
+	h.router.HandleFunc("GET /households/{household}", h.get)
+	h.router.HandleFunc("PUT /households/{household}", h.update)
+	h.router.HandleFunc("DELETE /households/{household}", h.delete)
+}
+
+// Create is an HTTP handler for creating a new household
+func (h *householdHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req HouseholdRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	household, err := h.householdService.create(req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, household)
+}
+
+// List is an HTTP handler for listing all households
+func (h *householdHandler) list(w http.ResponseWriter, r *http.Request) {
+	households, err := h.householdService.list()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, households)
+}
+
+// Get is an HTTP handler for retrieving a household by ID
+func (h *householdHandler) get(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	household, err := h.householdService.get(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, household)
+}
+
+// Update is an HTTP handler for renaming a household or changing its reporting currency
+func (h *householdHandler) update(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req HouseholdRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	household, err := h.householdService.update(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, household)
+}
+
+// Delete is an HTTP handler for deleting a household and everything it owns
+func (h *householdHandler) delete(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.householdService.delete(householdID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *householdHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *householdHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *householdHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling household request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,113 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package households
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// householdColumns lists the columns selected for a Household
+const householdColumns = `id, name, reporting_currency, created_at`
+
+// householdModel wraps the database connection pool using sqlx
+type householdModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newHouseholdModel(db *sqlx.DB, logger *slog.Logger) *householdModel {
+	return &householdModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new household into the database and returns its ID
+func (m *householdModel) create(h *Household) (int64, error) {
+	query := `INSERT INTO households (name, reporting_currency, created_at)
+	VALUES (:name, :reporting_currency, :created_at)
+	RETURNING id`
+
+	h.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, h)
+	if err != nil {
+		m.logger.Error("Error inserting household", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&h.ID); err != nil {
+			m.logger.Error("Error scanning household ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Household created successfully", "id", h.ID)
+	return h.ID, nil
+}
+
+// List returns all households ordered by name
+func (m *householdModel) list() ([]Household, error) {
+	households := []Household{}
+	if err := m.DB.Select(&households, `SELECT `+householdColumns+` FROM households ORDER BY name, id`); err != nil {
+		m.logger.Error("Error listing households", "error", err)
+		return nil, ErrInternalServer
+	}
+	return households, nil
+}
+
+// GetByID returns a household by ID
+func (m *householdModel) getByID(id int64) (*Household, error) {
+	h := &Household{}
+	err := m.DB.Get(h, `SELECT `+householdColumns+` FROM households WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrHouseholdNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting household by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return h, nil
+}
+
+// Update renames a household, changes its reporting currency and returns it
+func (m *householdModel) update(h *Household) (*Household, error) {
+	query := `UPDATE households SET name = $2, reporting_currency = $3 WHERE id = $1 RETURNING ` + householdColumns
+
+	updated := &Household{}
+	err := m.DB.Get(updated, query, h.ID, h.Name, h.ReportingCurrency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrHouseholdNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error updating household", "error", err)
+		return nil, ErrInternalServer
+	}
+	return updated, nil
+}
+
+// Delete removes a household by ID together with everything it owns
+func (m *householdModel) delete(id int64) error {
+	result, err := m.DB.Exec(`DELETE FROM households WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting household", "error", err)
+		return ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected household count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrHouseholdNotFound
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package households
+
+import (
+	"log/slog"
+
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type householdService struct {
+	householdRepo *householdModel
+	logger        *slog.Logger
+}
+
+func newHouseholdService(householdRepo *householdModel, logger *slog.Logger) *householdService {
+	return &householdService{
+		householdRepo: householdRepo,
+		logger:        logger,
+	}
+}
+
+// create validates and stores a new household
+func (s *householdService) create(input HouseholdRequest) (*HouseholdResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Household validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	household := &Household{Name: input.Name, ReportingCurrency: input.ReportingCurrency}
+	if household.ReportingCurrency == "" {
+		household.ReportingCurrency = defaultReportingCurrency
+	}
+	if _, err := s.householdRepo.create(household); err != nil {
+		return nil, err
+	}
+	return household.ToResponse(), nil
+}
+
+// list returns all households
+func (s *householdService) list() ([]*HouseholdResponse, error) {
+	households, err := s.householdRepo.list()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*HouseholdResponse, 0, len(households))
+	for i := range households {
+		responses = append(responses, households[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// get returns a household by ID
+func (s *householdService) get(id int64) (*HouseholdResponse, error) {
+	household, err := s.householdRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return household.ToResponse(), nil
+}
+
+// update validates and renames a household, changing its reporting currency if given
+func (s *householdService) update(id int64, input HouseholdRequest) (*HouseholdResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Household validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	reportingCurrency := input.ReportingCurrency
+	if reportingCurrency == "" {
+		existing, err := s.householdRepo.getByID(id)
+		if err != nil {
+			return nil, err
+		}
+		reportingCurrency = existing.ReportingCurrency
+	}
+
+	household, err := s.householdRepo.update(&Household{ID: id, Name: input.Name, ReportingCurrency: reportingCurrency})
+	if err != nil {
+		return nil, err
+	}
+	return household.ToResponse(), nil
+}
+
+// delete removes a household and everything it owns
+func (s *householdService) delete(id int64) error {
+	return s.householdRepo.delete(id)
+}
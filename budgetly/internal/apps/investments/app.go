@@ -0,0 +1,28 @@
+package investments
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewInvestmentsApp creates a new investments application with the provided database connection
+func NewInvestmentsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	investmentModel := newInvestmentModel(db, logger)
+	investmentService := newInvestmentService(investmentModel, logger)
+	newInvestmentHandler(investmentService, logger, router)
+}
+
+// MarketValue returns the market value of all holdings, or of a household's, on a date
+// per currency, for the net worth report to add to account balances. Holdings without a
+// price on or before the date are valued at their cost basis.
+func MarketValue(db *sqlx.DB, logger *slog.Logger, householdID int64, asOf time.Time) (report.Totals, error) {
+	holdings, err := newInvestmentService(newInvestmentModel(db, logger), logger).holdings(householdID, 0, "", asOf)
+	if err != nil {
+		return nil, err
+	}
+	return holdings.MarketValue, nil
+}
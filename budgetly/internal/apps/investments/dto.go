@@ -0,0 +1,290 @@
+package investments
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/invest"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// Security is a stock, fund or other instrument that can be held.
+type Security struct {
+	ID        int       `db:"id"`
+	Symbol    string    `db:"symbol"`
+	Name      string    `db:"name"`
+	Currency  string    `db:"currency"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// SecurityRequest represents the input data for creating a security.
+type SecurityRequest struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+// Validate validates the SecurityRequest struct.
+func (input *SecurityRequest) Validate() map[string]string {
+	input.Symbol = strings.ToUpper(strings.TrimSpace(input.Symbol))
+	input.Name = strings.TrimSpace(input.Name)
+	input.Currency = strings.ToUpper(strings.TrimSpace(input.Currency))
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Symbol": validate.Rules(
+			validate.Required,
+			validate.Max(20),
+			validate.ErrorMessage("Symbol is required and must be at most 20 characters long"),
+		),
+		"Name": validate.Rules(
+			validate.Required,
+			validate.Max(100),
+			validate.ErrorMessage("Name is required and must be at most 100 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if !money.IsCurrency(input.Currency) {
+		errors["Currency"] = "Currency must be a supported ISO 4217 code"
+	}
+	return errors
+}
+
+// toSecurity converts a validated SecurityRequest into a Security.
+func (input *SecurityRequest) toSecurity() *Security {
+	return &Security{Symbol: input.Symbol, Name: input.Name, Currency: input.Currency}
+}
+
+// SecurityResponse represents the security data to return in responses.
+type SecurityResponse struct {
+	ID       int    `json:"id"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+// ToResponse converts a Security (from database) to a SecurityResponse (for API responses).
+func (s *Security) ToResponse() *SecurityResponse {
+	return &SecurityResponse{ID: s.ID, Symbol: s.Symbol, Name: s.Name, Currency: s.Currency}
+}
+
+// Price is a security's closing price on a day.
+type Price struct {
+	SecurityID int         `db:"security_id"`
+	Date       time.Time   `db:"date"`
+	Close      money.Money `db:"close"`
+	CreatedAt  time.Time   `db:"created_at"`
+}
+
+// PriceResponse represents a closing price to return in responses.
+type PriceResponse struct {
+	Date  string      `json:"date"`
+	Close money.Money `json:"close"`
+}
+
+// ToResponse converts a Price (from database) to a PriceResponse (for API responses).
+func (p *Price) ToResponse() *PriceResponse {
+	return &PriceResponse{Date: p.Date.Format(time.DateOnly), Close: p.Close}
+}
+
+// UploadResponse reports how many prices a price history upload stored.
+type UploadResponse struct {
+	Imported int `json:"imported"`
+}
+
+// Trade is a buy, sell, dividend or split in one security in one account.
+type Trade struct {
+	ID         int            `db:"id"`
+	AccountID  int64          `db:"account_id"`
+	SecurityID int            `db:"security_id"`
+	Kind       invest.Kind    `db:"kind"`
+	TradeDate  time.Time      `db:"trade_date"`
+	Quantity   sql.NullString `db:"quantity"`
+	Price      money.Money    `db:"price"`
+	Fees       money.Money    `db:"fees"`
+	Amount     money.Money    `db:"amount"`
+	SplitFrom  sql.NullInt64  `db:"split_from"`
+	SplitTo    sql.NullInt64  `db:"split_to"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+// toInvestTrade converts a stored trade into the form replayed by the invest package.
+func (t *Trade) toInvestTrade() (invest.Trade, error) {
+	trade := invest.Trade{
+		ID:        t.ID,
+		Date:      t.TradeDate,
+		Kind:      t.Kind,
+		Price:     t.Price,
+		Fees:      t.Fees,
+		Amount:    t.Amount,
+		SplitFrom: t.SplitFrom.Int64,
+		SplitTo:   t.SplitTo.Int64,
+	}
+	if t.Quantity.Valid {
+		quantity, err := invest.ParseQuantity(t.Quantity.String)
+		if err != nil {
+			return invest.Trade{}, err
+		}
+		trade.Quantity = quantity
+	}
+	return trade, nil
+}
+
+// TradeRequest represents the input data for recording a trade.
+type TradeRequest struct {
+	AccountID  int64        `json:"account_id"`
+	SecurityID int          `json:"security_id"`
+	Kind       invest.Kind  `json:"kind"`
+	Date       string       `json:"date"`
+	Quantity   string       `json:"quantity"`
+	Price      *money.Money `json:"price"`
+	Fees       *money.Money `json:"fees"`
+	Amount     *money.Money `json:"amount"`
+	SplitFrom  int64        `json:"split_from"`
+	SplitTo    int64        `json:"split_to"`
+}
+
+// Validate validates the shape of the TradeRequest struct. Amounts are checked against
+// the security's currency when the trade is replayed.
+func (input *TradeRequest) Validate() map[string]string {
+	errors := map[string]string{}
+	if input.AccountID <= 0 {
+		errors["AccountID"] = "AccountID is required"
+	}
+	if input.SecurityID <= 0 {
+		errors["SecurityID"] = "SecurityID is required"
+	}
+	switch input.Kind {
+	case invest.Buy, invest.Sell, invest.Dividend, invest.Split:
+	default:
+		errors["Kind"] = "Kind must be one of buy, sell, dividend or split"
+	}
+	if _, err := time.Parse(time.DateOnly, input.Date); err != nil {
+		errors["Date"] = "Date must be in YYYY-MM-DD format"
+	}
+	if input.Kind == invest.Buy || input.Kind == invest.Sell {
+		if _, err := invest.ParseQuantity(input.Quantity); err != nil {
+			errors["Quantity"] = "Quantity must be a decimal number of shares"
+		}
+	}
+	return errors
+}
+
+// toTrade converts a validated TradeRequest into a Trade, keeping only the fields its kind uses.
+func (input *TradeRequest) toTrade() *Trade {
+	date, _ := time.Parse(time.DateOnly, input.Date)
+	trade := &Trade{
+		AccountID:  input.AccountID,
+		SecurityID: input.SecurityID,
+		Kind:       input.Kind,
+		TradeDate:  date,
+	}
+	switch input.Kind {
+	case invest.Buy, invest.Sell:
+		quantity, _ := invest.ParseQuantity(input.Quantity)
+		trade.Quantity = sql.NullString{String: invest.FormatQuantity(quantity), Valid: true}
+		if input.Price != nil {
+			trade.Price = *input.Price
+		}
+		if input.Fees != nil {
+			trade.Fees = *input.Fees
+		}
+	case invest.Dividend:
+		if input.Amount != nil {
+			trade.Amount = *input.Amount
+		}
+	case invest.Split:
+		trade.SplitFrom = sql.NullInt64{Int64: input.SplitFrom, Valid: true}
+		trade.SplitTo = sql.NullInt64{Int64: input.SplitTo, Valid: true}
+	}
+	return trade
+}
+
+// TradeResponse represents the trade data to return in responses.
+type TradeResponse struct {
+	ID         int          `json:"id"`
+	AccountID  int64        `json:"account_id"`
+	SecurityID int          `json:"security_id"`
+	Kind       invest.Kind  `json:"kind"`
+	Date       string       `json:"date"`
+	Quantity   string       `json:"quantity,omitempty"`
+	Price      *money.Money `json:"price,omitempty"`
+	Fees       *money.Money `json:"fees,omitempty"`
+	Amount     *money.Money `json:"amount,omitempty"`
+	SplitFrom  int64        `json:"split_from,omitempty"`
+	SplitTo    int64        `json:"split_to,omitempty"`
+}
+
+// ToResponse converts a Trade (from database) to a TradeResponse (for API responses).
+func (t *Trade) ToResponse() *TradeResponse {
+	response := &TradeResponse{
+		ID:         t.ID,
+		AccountID:  t.AccountID,
+		SecurityID: t.SecurityID,
+		Kind:       t.Kind,
+		Date:       t.TradeDate.Format(time.DateOnly),
+		SplitFrom:  t.SplitFrom.Int64,
+		SplitTo:    t.SplitTo.Int64,
+	}
+	// NUMERIC columns come back padded to their scale, e.g. "12.50000000".
+	if quantity, err := invest.ParseQuantity(t.Quantity.String); t.Quantity.Valid && err == nil {
+		response.Quantity = invest.FormatQuantity(quantity)
+	}
+	if t.Price.Currency() != "" {
+		response.Price = &t.Price
+	}
+	if t.Fees.Currency() != "" {
+		response.Fees = &t.Fees
+	}
+	if t.Amount.Currency() != "" {
+		response.Amount = &t.Amount
+	}
+	return response
+}
+
+// LotResponse represents an open lot to return in responses.
+type LotResponse struct {
+	TradeID  int         `json:"trade_id"`
+	Date     string      `json:"date"`
+	Quantity string      `json:"quantity"`
+	Cost     money.Money `json:"cost"`
+}
+
+// HoldingResponse represents a position in one security in one account.
+type HoldingResponse struct {
+	AccountID  int64          `json:"account_id"`
+	SecurityID int            `json:"security_id"`
+	Symbol     string         `json:"symbol"`
+	Quantity   string         `json:"quantity"`
+	Price      *PriceResponse `json:"price,omitempty"`
+	invest.Valuation
+	RealizedGain money.Money   `json:"realized_gain"`
+	Dividends    money.Money   `json:"dividends"`
+	Lots         []LotResponse `json:"lots"`
+}
+
+// HoldingsResponse represents all holdings and their market value per currency.
+type HoldingsResponse struct {
+	AsOf        string             `json:"as_of"`
+	Method      invest.Method      `json:"method"`
+	Holdings    []*HoldingResponse `json:"holdings"`
+	MarketValue report.Totals      `json:"market_value"`
+}
+
+// GainResponse represents the realized gain or loss of one sale.
+type GainResponse struct {
+	TradeID    int         `json:"trade_id"`
+	AccountID  int64       `json:"account_id"`
+	SecurityID int         `json:"security_id"`
+	Symbol     string      `json:"symbol"`
+	Date       string      `json:"date"`
+	Quantity   string      `json:"quantity"`
+	Proceeds   money.Money `json:"proceeds"`
+	Cost       money.Money `json:"cost"`
+	Gain       money.Money `json:"gain"`
+}
@@ -0,0 +1,11 @@
+package investments
+
+import "errors"
+
+var (
+	ErrInternalServer   error = errors.New("internal server error")
+	ErrSecurityNotFound error = errors.New("security not found")
+	ErrSecurityExists   error = errors.New("a security with this symbol already exists")
+	ErrTradeNotFound    error = errors.New("trade not found")
+	ErrTradeInUse       error = errors.New("deleting this trade would leave a later sale without enough shares")
+)
@@ -0,0 +1,334 @@
+package investments
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// maxUploadSize limits the size of price history uploads
+const maxUploadSize = 10 << 20
+
+// investmentHandler is an HTTP handler for investment operations
+// (e.g., managing securities, recording trades, valuing holdings, etc.)
+type investmentHandler struct {
+	investmentService *investmentService
+	logger            *slog.Logger
+	router            *http.ServeMux
+}
+
+// newInvestmentHandler creates a new investment handler with the provided investment service and logger
+func newInvestmentHandler(investmentService *investmentService, logger *slog.Logger, router *http.ServeMux) *investmentHandler {
+	investmentHandler := &investmentHandler{
+		investmentService: investmentService,
+		logger:            logger,
+		router:            router,
+	}
+	investmentHandler.registerRoutes()
+	return investmentHandler
+}
+
+// Register routes for investment-related actions
+func (h *investmentHandler) registerRoutes() {
+	h.router.HandleFunc("POST /securities", h.createSecurity)
+	h.router.HandleFunc("GET /securities", h.listSecurities)
+	h.router.HandleFunc("POST /securities/prices", h.uploadPrices)
+	h.router.HandleFunc("GET /securities/{id}", h.getSecurity)
+	h.router.HandleFunc("DELETE /securities/{id}", h.deleteSecurity)
+	h.router.HandleFunc("GET /securities/{id}/prices", h.listPrices)
+	h.router.HandleFunc("POST /investments/trades", h.createTrade)
+	h.router.HandleFunc("GET /investments/trades", h.listTrades)
+	h.router.HandleFunc("DELETE /investments/trades/{id}", h.deleteTrade)
+	h.router.HandleFunc("GET /investments/holdings", h.holdings)
+	h.router.HandleFunc("GET /investments/gains", h.gains)
+}
+
+// CreateSecurity is an HTTP handler for creating a new security
+func (h *investmentHandler) createSecurity(w http.ResponseWriter, r *http.Request) {
+	var req SecurityRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	security, err := h.investmentService.createSecurity(req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, security)
+}
+
+// ListSecurities is an HTTP handler for listing all securities
+func (h *investmentHandler) listSecurities(w http.ResponseWriter, r *http.Request) {
+	securities, err := h.investmentService.listSecurities()
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, securities)
+}
+
+// UploadPrices is an HTTP handler for bulk uploading closing prices as CSV,
+// either as a multipart "file" field or as the raw request body
+func (h *investmentHandler) uploadPrices(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		formFile, _, err := r.FormFile("file")
+		if err != nil {
+			h.logger.Warn("Invalid upload", "error", err)
+			utils.WriteJson(
+				w,
+				http.StatusBadRequest,
+				map[string]string{"error": "A price file is required in the \"file\" field"},
+			)
+			return
+		}
+		defer formFile.Close()
+		file = formFile
+	}
+
+	result, err := h.investmentService.uploadPrices(file)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, result)
+}
+
+// GetSecurity is an HTTP handler for retrieving a security by ID
+func (h *investmentHandler) getSecurity(w http.ResponseWriter, r *http.Request) {
+	securityID, ok := h.pathID(w, r, "security")
+	if !ok {
+		return
+	}
+
+	security, err := h.investmentService.getSecurity(securityID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, security)
+}
+
+// DeleteSecurity is an HTTP handler for deleting a security with its trades and prices
+func (h *investmentHandler) deleteSecurity(w http.ResponseWriter, r *http.Request) {
+	securityID, ok := h.pathID(w, r, "security")
+	if !ok {
+		return
+	}
+
+	if err := h.investmentService.deleteSecurity(securityID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListPrices is an HTTP handler for a security's price history, optionally between
+// the from and to dates
+func (h *investmentHandler) listPrices(w http.ResponseWriter, r *http.Request) {
+	securityID, ok := h.pathID(w, r, "security")
+	if !ok {
+		return
+	}
+
+	errs := map[string]string{}
+	query := r.URL.Query()
+	from, to := queryDate(query, "from", errs), queryDate(query, "to", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+
+	prices, err := h.investmentService.listPrices(securityID, from, to)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, prices)
+}
+
+// CreateTrade is an HTTP handler for recording a buy, sell, dividend or split
+func (h *investmentHandler) createTrade(w http.ResponseWriter, r *http.Request) {
+	var req TradeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	trade, err := h.investmentService.createTrade(req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, trade)
+}
+
+// ListTrades is an HTTP handler for listing trades, optionally filtered by account_id
+// and security_id
+func (h *investmentHandler) listTrades(w http.ResponseWriter, r *http.Request) {
+	errs := map[string]string{}
+	query := r.URL.Query()
+	accountID, securityID := queryID(query, "account_id", errs), queryID(query, "security_id", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+
+	trades, err := h.investmentService.listTrades(accountID, int(securityID))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, trades)
+}
+
+// DeleteTrade is an HTTP handler for deleting a trade
+func (h *investmentHandler) deleteTrade(w http.ResponseWriter, r *http.Request) {
+	tradeID, ok := h.pathID(w, r, "trade")
+	if !ok {
+		return
+	}
+
+	if err := h.investmentService.deleteTrade(tradeID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Holdings is an HTTP handler for the open positions and their market value as_of a date
+// (default today), optionally for one account_id, using the fifo, lifo or average method
+func (h *investmentHandler) holdings(w http.ResponseWriter, r *http.Request) {
+	errs := map[string]string{}
+	query := r.URL.Query()
+	accountID, asOf := queryID(query, "account_id", errs), queryDate(query, "as_of", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+	if !asOf.Valid {
+		asOf.Time = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	holdings, err := h.investmentService.holdings(0, accountID, query.Get("method"), asOf.Time)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, holdings)
+}
+
+// Gains is an HTTP handler for the realized gains of sales between the from and to dates,
+// optionally for one account_id, using the fifo, lifo or average method
+func (h *investmentHandler) gains(w http.ResponseWriter, r *http.Request) {
+	errs := map[string]string{}
+	query := r.URL.Query()
+	accountID := queryID(query, "account_id", errs)
+	from, to := queryDate(query, "from", errs), queryDate(query, "to", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+
+	gains, err := h.investmentService.gains(accountID, query.Get("method"), from, to)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, gains)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *investmentHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses the ID path parameter, writing a 400 response on failure
+func (h *investmentHandler) pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid " + name + " ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// queryID parses an optional positive ID query parameter, recording an error when it is invalid
+func queryID(query url.Values, name string, errs map[string]string) int64 {
+	value := query.Get(name)
+	if value == "" {
+		return 0
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		errs[name] = name + " must be a positive integer"
+		return 0
+	}
+	return id
+}
+
+// queryDate parses an optional YYYY-MM-DD query parameter, recording an error when it is invalid
+func queryDate(query url.Values, name string, errs map[string]string) sql.NullTime {
+	value := query.Get(name)
+	if value == "" {
+		return sql.NullTime{}
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		errs[name] = name + " must be in YYYY-MM-DD format"
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: date, Valid: true}
+}
+
+// writeError maps service errors to HTTP responses
+func (h *investmentHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrSecurityNotFound), errors.Is(err, ErrTradeNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrSecurityExists), errors.Is(err, ErrTradeInUse):
+		utils.WriteJson(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling investment request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,241 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package investments
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code for unique constraint violations
+const uniqueViolation = "23505"
+
+// securityColumns lists the columns selected for a Security
+const securityColumns = `id, symbol, name, currency, created_at`
+
+// priceColumns lists the columns selected for a Price
+const priceColumns = `security_id, date, close, created_at`
+
+// tradeColumns lists the columns selected for a Trade
+const tradeColumns = `id, account_id, security_id, kind, trade_date, quantity, price, fees, amount, split_from, split_to, created_at`
+
+// investmentModel wraps the database connection pool using sqlx
+type investmentModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newInvestmentModel(db *sqlx.DB, logger *slog.Logger) *investmentModel {
+	return &investmentModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// CreateSecurity inserts a new security into the database and returns its ID
+func (m *investmentModel) createSecurity(s *Security) (int, error) {
+	query := `INSERT INTO securities (symbol, name, currency, created_at)
+	VALUES (:symbol, :name, :currency, :created_at)
+	RETURNING id`
+
+	s.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, s)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return 0, ErrSecurityExists
+		}
+		m.logger.Error("Error inserting security", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&s.ID); err != nil {
+			m.logger.Error("Error scanning security ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Security created successfully", "id", s.ID)
+	return s.ID, nil
+}
+
+// ListSecurities returns all securities ordered by symbol
+func (m *investmentModel) listSecurities() ([]Security, error) {
+	securities := []Security{}
+	if err := m.DB.Select(&securities, `SELECT `+securityColumns+` FROM securities ORDER BY symbol`); err != nil {
+		m.logger.Error("Error listing securities", "error", err)
+		return nil, ErrInternalServer
+	}
+	return securities, nil
+}
+
+// GetSecurity returns a security by ID
+func (m *investmentModel) getSecurity(id int) (*Security, error) {
+	s := &Security{}
+	err := m.DB.Get(s, `SELECT `+securityColumns+` FROM securities WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSecurityNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting security by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return s, nil
+}
+
+// DeleteSecurity deletes a security along with its trades and prices
+func (m *investmentModel) deleteSecurity(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM securities WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting security", "error", err)
+		return ErrInternalServer
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrSecurityNotFound
+	}
+
+	m.logger.Debug("Security deleted successfully", "id", id)
+	return nil
+}
+
+// UpsertPrices inserts the prices in a single transaction, replacing any existing
+// price for the same security and date
+func (m *investmentModel) upsertPrices(prices []Price) error {
+	query := `INSERT INTO security_prices (security_id, date, close, created_at)
+	VALUES (:security_id, :date, :close, :created_at)
+	ON CONFLICT (security_id, date) DO UPDATE SET close = EXCLUDED.close`
+
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting security price transaction", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for i := range prices {
+		prices[i].CreatedAt = now
+		if _, err := tx.NamedExec(query, prices[i]); err != nil {
+			m.logger.Error("Error upserting security price", "error", err)
+			return ErrInternalServer
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing security prices", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Security prices stored successfully", "count", len(prices))
+	return nil
+}
+
+// ListPrices returns a security's prices between two dates, newest first
+func (m *investmentModel) listPrices(securityID int, from, to sql.NullTime) ([]Price, error) {
+	query := `SELECT ` + priceColumns + `
+	FROM security_prices
+	WHERE security_id = $1 AND ($2::date IS NULL OR date >= $2) AND ($3::date IS NULL OR date <= $3)
+	ORDER BY date DESC`
+
+	prices := []Price{}
+	if err := m.DB.Select(&prices, query, securityID, from, to); err != nil {
+		m.logger.Error("Error listing security prices", "error", err)
+		return nil, ErrInternalServer
+	}
+	return prices, nil
+}
+
+// LatestPrices returns the most recent price of each security on or before a date
+func (m *investmentModel) latestPrices(asOf time.Time) ([]Price, error) {
+	query := `SELECT DISTINCT ON (security_id) ` + priceColumns + `
+	FROM security_prices
+	WHERE date <= $1
+	ORDER BY security_id, date DESC`
+
+	prices := []Price{}
+	if err := m.DB.Select(&prices, query, asOf); err != nil {
+		m.logger.Error("Error listing latest security prices", "error", err)
+		return nil, ErrInternalServer
+	}
+	return prices, nil
+}
+
+// CreateTrade inserts a new trade into the database and returns its ID
+func (m *investmentModel) createTrade(t *Trade) (int, error) {
+	query := `INSERT INTO investment_trades (account_id, security_id, kind, trade_date, quantity, price, fees, amount, split_from, split_to, created_at)
+	VALUES (:account_id, :security_id, :kind, :trade_date, :quantity, :price, :fees, :amount, :split_from, :split_to, :created_at)
+	RETURNING id`
+
+	t.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, t)
+	if err != nil {
+		m.logger.Error("Error inserting trade", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&t.ID); err != nil {
+			m.logger.Error("Error scanning trade ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Trade created successfully", "id", t.ID)
+	return t.ID, nil
+}
+
+// ListTrades returns trades in date order, optionally filtered by household, account and
+// security and limited to trades on or before a date
+func (m *investmentModel) listTrades(householdID, accountID int64, securityID int, until sql.NullTime) ([]Trade, error) {
+	query := `SELECT ` + tradeColumns + `
+	FROM investment_trades
+	WHERE ($1 = 0 OR account_id = $1) AND ($2 = 0 OR security_id = $2) AND ($3::date IS NULL OR trade_date <= $3)
+		AND ($4 = 0 OR account_id IN (SELECT a.id FROM accounts a WHERE a.household_id = $4))
+	ORDER BY trade_date, id`
+
+	trades := []Trade{}
+	if err := m.DB.Select(&trades, query, accountID, securityID, until, householdID); err != nil {
+		m.logger.Error("Error listing trades", "error", err)
+		return nil, ErrInternalServer
+	}
+	return trades, nil
+}
+
+// GetTrade returns a trade by ID
+func (m *investmentModel) getTrade(id int) (*Trade, error) {
+	t := &Trade{}
+	err := m.DB.Get(t, `SELECT `+tradeColumns+` FROM investment_trades WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTradeNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting trade by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return t, nil
+}
+
+// DeleteTrade deletes a trade by ID
+func (m *investmentModel) deleteTrade(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM investment_trades WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting trade", "error", err)
+		return ErrInternalServer
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrTradeNotFound
+	}
+
+	m.logger.Debug("Trade deleted successfully", "id", id)
+	return nil
+}
@@ -0,0 +1,373 @@
+package investments
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/invest"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type investmentService struct {
+	investmentRepo *investmentModel
+	logger         *slog.Logger
+}
+
+func newInvestmentService(investmentRepo *investmentModel, logger *slog.Logger) *investmentService {
+	return &investmentService{
+		investmentRepo: investmentRepo,
+		logger:         logger,
+	}
+}
+
+// createSecurity validates and stores a new security
+func (s *investmentService) createSecurity(input SecurityRequest) (*SecurityResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Security validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	security := input.toSecurity()
+	if _, err := s.investmentRepo.createSecurity(security); err != nil {
+		return nil, err
+	}
+	return security.ToResponse(), nil
+}
+
+// listSecurities returns all securities
+func (s *investmentService) listSecurities() ([]*SecurityResponse, error) {
+	securities, err := s.investmentRepo.listSecurities()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*SecurityResponse, 0, len(securities))
+	for i := range securities {
+		responses = append(responses, securities[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// getSecurity returns a security by ID
+func (s *investmentService) getSecurity(id int) (*SecurityResponse, error) {
+	security, err := s.investmentRepo.getSecurity(id)
+	if err != nil {
+		return nil, err
+	}
+	return security.ToResponse(), nil
+}
+
+// deleteSecurity deletes a security with its trades and price history
+func (s *investmentService) deleteSecurity(id int) error {
+	return s.investmentRepo.deleteSecurity(id)
+}
+
+// uploadPrices parses a CSV file of closing prices and stores them, matching rows to
+// securities by symbol
+func (s *investmentService) uploadPrices(r io.Reader) (*UploadResponse, error) {
+	parsed, err := invest.ParsePricesCSV(r)
+	if err != nil {
+		s.logger.Warn("Security price upload rejected", "error", err)
+		return nil, &validate.ValidationError{Errors: map[string]string{"file": err.Error()}}
+	}
+
+	securities, err := s.investmentRepo.listSecurities()
+	if err != nil {
+		return nil, err
+	}
+	bySymbol := make(map[string]Security, len(securities))
+	for _, security := range securities {
+		bySymbol[security.Symbol] = security
+	}
+
+	prices := make([]Price, 0, len(parsed))
+	for _, price := range parsed {
+		security, ok := bySymbol[price.Symbol]
+		if !ok {
+			return nil, &validate.ValidationError{Errors: map[string]string{"file": fmt.Sprintf("Unknown symbol %q", price.Symbol)}}
+		}
+		if price.Close.Currency() != security.Currency {
+			return nil, &validate.ValidationError{Errors: map[string]string{
+				"file": fmt.Sprintf("%s is priced in %s, not %s", security.Symbol, security.Currency, price.Close.Currency()),
+			}}
+		}
+		prices = append(prices, Price{SecurityID: security.ID, Date: price.Date, Close: price.Close})
+	}
+
+	if err := s.investmentRepo.upsertPrices(prices); err != nil {
+		return nil, err
+	}
+	return &UploadResponse{Imported: len(prices)}, nil
+}
+
+// listPrices returns a security's price history between two optional dates
+func (s *investmentService) listPrices(securityID int, from, to sql.NullTime) ([]*PriceResponse, error) {
+	if _, err := s.investmentRepo.getSecurity(securityID); err != nil {
+		return nil, err
+	}
+
+	prices, err := s.investmentRepo.listPrices(securityID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*PriceResponse, 0, len(prices))
+	for i := range prices {
+		responses = append(responses, prices[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// createTrade validates a trade against the security's history and stores it
+func (s *investmentService) createTrade(input TradeRequest) (*TradeResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Trade validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	security, err := s.investmentRepo.getSecurity(input.SecurityID)
+	if errors.Is(err, ErrSecurityNotFound) {
+		return nil, &validate.ValidationError{Errors: map[string]string{"SecurityID": "Security does not exist"}}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.investmentRepo.listTrades(0, input.AccountID, input.SecurityID, sql.NullTime{})
+	if err != nil {
+		return nil, err
+	}
+	trade := input.toTrade()
+	if _, err := s.replay(append(history, *trade), invest.FIFO, security.Currency); err != nil {
+		if errors.Is(err, invest.ErrInvalidTrade) || errors.Is(err, invest.ErrOversold) {
+			s.logger.Warn("Trade rejected", "error", err)
+			return nil, &validate.ValidationError{Errors: map[string]string{"Trade": err.Error()}}
+		}
+		return nil, err
+	}
+
+	if _, err := s.investmentRepo.createTrade(trade); err != nil {
+		return nil, err
+	}
+	return trade.ToResponse(), nil
+}
+
+// listTrades returns trades, optionally filtered by account and security
+func (s *investmentService) listTrades(accountID int64, securityID int) ([]*TradeResponse, error) {
+	trades, err := s.investmentRepo.listTrades(0, accountID, securityID, sql.NullTime{})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*TradeResponse, 0, len(trades))
+	for i := range trades {
+		responses = append(responses, trades[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// deleteTrade deletes a trade unless a later sale depends on the shares it bought
+func (s *investmentService) deleteTrade(id int) error {
+	trade, err := s.investmentRepo.getTrade(id)
+	if err != nil {
+		return err
+	}
+	security, err := s.investmentRepo.getSecurity(trade.SecurityID)
+	if err != nil {
+		return err
+	}
+
+	history, err := s.investmentRepo.listTrades(0, trade.AccountID, trade.SecurityID, sql.NullTime{})
+	if err != nil {
+		return err
+	}
+	remaining := make([]Trade, 0, len(history))
+	for _, t := range history {
+		if t.ID != id {
+			remaining = append(remaining, t)
+		}
+	}
+	if _, err := s.replay(remaining, invest.FIFO, security.Currency); errors.Is(err, invest.ErrOversold) {
+		return ErrTradeInUse
+	} else if err != nil {
+		return err
+	}
+
+	return s.investmentRepo.deleteTrade(id)
+}
+
+// holdings values every open position on a date, optionally for one household or account
+func (s *investmentService) holdings(householdID, accountID int64, method string, asOf time.Time) (*HoldingsResponse, error) {
+	costMethod, err := invest.ParseMethod(method)
+	if err != nil {
+		return nil, &validate.ValidationError{Errors: map[string]string{"method": "Method must be one of fifo, lifo or average"}}
+	}
+
+	positions, err := s.positions(householdID, accountID, costMethod, sql.NullTime{Time: asOf, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	prices, err := s.investmentRepo.latestPrices(asOf)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[int]Price, len(prices))
+	for _, price := range prices {
+		latest[price.SecurityID] = price
+	}
+
+	response := &HoldingsResponse{
+		AsOf:        asOf.Format(time.DateOnly),
+		Method:      costMethod,
+		Holdings:    []*HoldingResponse{},
+		MarketValue: report.Totals{},
+	}
+	for _, p := range positions {
+		if p.Quantity().Sign() == 0 {
+			continue
+		}
+
+		holding := &HoldingResponse{
+			AccountID:    p.accountID,
+			SecurityID:   p.security.ID,
+			Symbol:       p.security.Symbol,
+			Quantity:     invest.FormatQuantity(p.Quantity()),
+			RealizedGain: p.RealizedGain(),
+			Dividends:    p.Dividends,
+			Lots:         make([]LotResponse, 0, len(p.Lots)),
+		}
+		for _, lot := range p.Lots {
+			holding.Lots = append(holding.Lots, LotResponse{
+				TradeID:  lot.TradeID,
+				Date:     lot.Date.Format(time.DateOnly),
+				Quantity: invest.FormatQuantity(lot.Quantity),
+				Cost:     lot.Cost,
+			})
+		}
+
+		// Without a price, the holding is valued at what it cost.
+		if price, ok := latest[p.security.ID]; ok {
+			if holding.Valuation, err = p.Value(price.Close); err != nil {
+				s.logger.Error("Error valuing holding", "security", p.security.Symbol, "error", err)
+				return nil, ErrInternalServer
+			}
+			holding.Price = price.ToResponse()
+		} else {
+			basis := p.CostBasis()
+			zero, _ := money.Zero(basis.Currency())
+			holding.Valuation = invest.Valuation{MarketValue: basis, CostBasis: basis, UnrealizedGain: zero}
+		}
+
+		if err := response.MarketValue.Add(holding.MarketValue); err != nil {
+			s.logger.Error("Error totalling market value", "error", err)
+			return nil, ErrInternalServer
+		}
+		response.Holdings = append(response.Holdings, holding)
+	}
+	return response, nil
+}
+
+// gains returns the realized gain of every sale between two optional dates
+func (s *investmentService) gains(accountID int64, method string, from, to sql.NullTime) ([]*GainResponse, error) {
+	costMethod, err := invest.ParseMethod(method)
+	if err != nil {
+		return nil, &validate.ValidationError{Errors: map[string]string{"method": "Method must be one of fifo, lifo or average"}}
+	}
+
+	// Earlier sales consume lots, so the history is replayed from the start.
+	positions, err := s.positions(0, accountID, costMethod, to)
+	if err != nil {
+		return nil, err
+	}
+
+	gains := []*GainResponse{}
+	for _, p := range positions {
+		for _, r := range p.Realized {
+			if from.Valid && r.Date.Before(from.Time) {
+				continue
+			}
+			gains = append(gains, &GainResponse{
+				TradeID:    r.TradeID,
+				AccountID:  p.accountID,
+				SecurityID: p.security.ID,
+				Symbol:     p.security.Symbol,
+				Date:       r.Date.Format(time.DateOnly),
+				Quantity:   invest.FormatQuantity(r.Quantity),
+				Proceeds:   r.Proceeds,
+				Cost:       r.Cost,
+				Gain:       r.Gain,
+			})
+		}
+	}
+	return gains, nil
+}
+
+// position is the replayed history of one security in one account.
+type position struct {
+	*invest.Position
+	accountID int64
+	security  Security
+}
+
+// positions replays the trades up to a date, grouped by account and security, of every
+// account, those of a household, or just one
+func (s *investmentService) positions(householdID, accountID int64, method invest.Method, until sql.NullTime) ([]position, error) {
+	trades, err := s.investmentRepo.listTrades(householdID, accountID, 0, until)
+	if err != nil {
+		return nil, err
+	}
+	securities, err := s.investmentRepo.listSecurities()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]Security, len(securities))
+	for _, security := range securities {
+		byID[security.ID] = security
+	}
+
+	type key struct {
+		accountID  int64
+		securityID int
+	}
+	groups := map[key][]Trade{}
+	var keys []key
+	for _, trade := range trades {
+		k := key{trade.AccountID, trade.SecurityID}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], trade)
+	}
+
+	positions := make([]position, 0, len(keys))
+	for _, k := range keys {
+		security := byID[k.securityID]
+		replayed, err := s.replay(groups[k], method, security.Currency)
+		if err != nil {
+			s.logger.Error("Error replaying trades", "account_id", k.accountID, "security", security.Symbol, "error", err)
+			return nil, ErrInternalServer
+		}
+		positions = append(positions, position{Position: replayed, accountID: k.accountID, security: security})
+	}
+	return positions, nil
+}
+
+// replay converts stored trades and replays them with the invest package
+func (s *investmentService) replay(trades []Trade, method invest.Method, currency string) (*invest.Position, error) {
+	converted := make([]invest.Trade, 0, len(trades))
+	for i := range trades {
+		trade, err := trades[i].toInvestTrade()
+		if err != nil {
+			s.logger.Error("Error reading stored trade", "id", trades[i].ID, "error", err)
+			return nil, ErrInternalServer
+		}
+		converted = append(converted, trade)
+	}
+	return invest.Replay(converted, method, currency)
+}
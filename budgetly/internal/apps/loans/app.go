@@ -0,0 +1,16 @@
+package loans
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewLoansApp creates a new loans application with the provided database connection.
+// Loans are stored against the liability account that tracks what is owed.
+func NewLoansApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	loanModel := newLoanModel(db, logger)
+	loanService := newLoanService(loanModel, logger)
+	newLoanHandler(loanService, logger, router)
+}
@@ -0,0 +1,223 @@
+package loans
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/loan"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// Default categories of the lines a loan payment is split into.
+const (
+	defaultInterestCategory  = "Interest"
+	defaultPrincipalCategory = "Loan Principal"
+)
+
+// Loan is a fixed-rate installment loan tracked in a liability account. Payments to Payee
+// are split into InterestCategory and PrincipalCategory following the loan's schedule.
+type Loan struct {
+	AccountID         int64          `db:"account_id"`
+	HouseholdID       int64          `db:"household_id"`
+	Principal         money.Money    `db:"principal"`
+	APR               string         `db:"apr"`
+	TermMonths        int            `db:"term_months"`
+	FirstPayment      time.Time      `db:"first_payment"`
+	Frequency         string         `db:"frequency"`
+	ExtraPerPayment   money.Money    `db:"extra_per_payment"`
+	Payee             string         `db:"payee"`
+	InterestCategory  string         `db:"interest_category"`
+	PrincipalCategory string         `db:"principal_category"`
+	UpdatedAt         time.Time      `db:"updated_at"`
+	Extras            []ExtraPayment `db:"-"`
+}
+
+// ExtraPayment is a one-off extra payment towards a loan's principal.
+type ExtraPayment struct {
+	AccountID int64       `db:"account_id"`
+	Date      time.Time   `db:"date"`
+	Amount    money.Money `db:"amount"`
+}
+
+// toLoan converts the stored loan into one that can be amortized
+func (l *Loan) toLoan() (loan.Loan, error) {
+	apr, ok := new(big.Rat).SetString(l.APR)
+	if !ok {
+		return loan.Loan{}, fmt.Errorf("invalid stored APR %q", l.APR)
+	}
+	result := loan.Loan{
+		Principal:       l.Principal,
+		APR:             apr,
+		TermMonths:      l.TermMonths,
+		FirstPayment:    l.FirstPayment,
+		Frequency:       loan.Frequency(l.Frequency),
+		ExtraPerPayment: l.ExtraPerPayment,
+	}
+	for _, extra := range l.Extras {
+		result.Extras = append(result.Extras, loan.ExtraPayment{Date: extra.Date, Amount: extra.Amount})
+	}
+	return result, nil
+}
+
+// ExtraPaymentRequest represents a one-off extra payment towards the principal.
+type ExtraPaymentRequest struct {
+	Date   string      `json:"date"`
+	Amount money.Money `json:"amount"`
+}
+
+// LoanRequest represents the input data for storing the loan of a liability account.
+// InterestCategory and PrincipalCategory default to "Interest" and "Loan Principal".
+type LoanRequest struct {
+	Principal         money.Money           `json:"principal"`
+	APR               string                `json:"apr"`
+	TermMonths        int                   `json:"term_months"`
+	FirstPayment      string                `json:"first_payment"`
+	Frequency         loan.Frequency        `json:"frequency"`
+	ExtraPerPayment   *money.Money          `json:"extra_per_payment"`
+	Extras            []ExtraPaymentRequest `json:"extras"`
+	Payee             string                `json:"payee"`
+	InterestCategory  string                `json:"interest_category"`
+	PrincipalCategory string                `json:"principal_category"`
+}
+
+// Validate validates the LoanRequest struct.
+func (input *LoanRequest) Validate() map[string]string {
+	input.Payee = strings.TrimSpace(input.Payee)
+	input.APR = strings.TrimSpace(input.APR)
+	input.InterestCategory = strings.TrimSpace(input.InterestCategory)
+	if input.InterestCategory == "" {
+		input.InterestCategory = defaultInterestCategory
+	}
+	input.PrincipalCategory = strings.TrimSpace(input.PrincipalCategory)
+	if input.PrincipalCategory == "" {
+		input.PrincipalCategory = defaultPrincipalCategory
+	}
+	if input.Frequency == "" {
+		input.Frequency = loan.FrequencyMonthly
+	}
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Payee": validate.Rules(
+			validate.Required,
+			validate.Max(200),
+			validate.ErrorMessage("Payee is required and must be at most 200 characters long"),
+		),
+		"InterestCategory": validate.Rules(
+			validate.Max(100),
+			validate.ErrorMessage("InterestCategory must be at most 100 characters long"),
+		),
+		"PrincipalCategory": validate.Rules(
+			validate.Max(100),
+			validate.ErrorMessage("PrincipalCategory must be at most 100 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if apr, ok := new(big.Rat).SetString(input.APR); !ok || apr.Sign() < 0 {
+		errors["APR"] = "APR must be a non-negative number such as 4.5"
+	}
+	if _, err := time.Parse(time.DateOnly, input.FirstPayment); err != nil {
+		errors["FirstPayment"] = "FirstPayment must be in YYYY-MM-DD format"
+	}
+	for i, extra := range input.Extras {
+		if _, err := time.Parse(time.DateOnly, extra.Date); err != nil {
+			field := fmt.Sprintf("Extras[%d].Date", i)
+			errors[field] = field + " must be in YYYY-MM-DD format"
+		}
+	}
+	return errors
+}
+
+// toLoan converts a validated LoanRequest into the loan of an account.
+func (input *LoanRequest) toLoan(accountID int64) *Loan {
+	firstPayment, _ := time.Parse(time.DateOnly, input.FirstPayment)
+	l := &Loan{
+		AccountID:         accountID,
+		Principal:         input.Principal,
+		APR:               input.APR,
+		TermMonths:        input.TermMonths,
+		FirstPayment:      firstPayment,
+		Frequency:         string(input.Frequency),
+		Payee:             input.Payee,
+		InterestCategory:  input.InterestCategory,
+		PrincipalCategory: input.PrincipalCategory,
+	}
+	if input.ExtraPerPayment != nil {
+		l.ExtraPerPayment = *input.ExtraPerPayment
+	}
+	for _, extra := range input.Extras {
+		date, _ := time.Parse(time.DateOnly, extra.Date)
+		l.Extras = append(l.Extras, ExtraPayment{AccountID: accountID, Date: date, Amount: extra.Amount})
+	}
+	return l
+}
+
+// ExtraPaymentResponse represents a one-off extra payment to return in responses.
+type ExtraPaymentResponse struct {
+	Date   string      `json:"date"`
+	Amount money.Money `json:"amount"`
+}
+
+// LoanResponse represents the loan data to return in responses, with its regular payment.
+type LoanResponse struct {
+	AccountID         int64                  `json:"account_id"`
+	Principal         money.Money            `json:"principal"`
+	APR               string                 `json:"apr"`
+	TermMonths        int                    `json:"term_months"`
+	FirstPayment      string                 `json:"first_payment"`
+	Frequency         string                 `json:"frequency"`
+	ExtraPerPayment   *money.Money           `json:"extra_per_payment,omitempty"`
+	Extras            []ExtraPaymentResponse `json:"extras"`
+	Payee             string                 `json:"payee"`
+	InterestCategory  string                 `json:"interest_category"`
+	PrincipalCategory string                 `json:"principal_category"`
+	Payment           money.Money            `json:"payment"`
+}
+
+// ToResponse converts a Loan with its regular payment into a LoanResponse.
+func (l *Loan) ToResponse(payment money.Money) *LoanResponse {
+	response := &LoanResponse{
+		AccountID:         l.AccountID,
+		Principal:         l.Principal,
+		APR:               l.APR,
+		TermMonths:        l.TermMonths,
+		FirstPayment:      l.FirstPayment.Format(time.DateOnly),
+		Frequency:         l.Frequency,
+		Extras:            make([]ExtraPaymentResponse, 0, len(l.Extras)),
+		Payee:             l.Payee,
+		InterestCategory:  l.InterestCategory,
+		PrincipalCategory: l.PrincipalCategory,
+		Payment:           payment,
+	}
+	if l.ExtraPerPayment.Currency() != "" {
+		response.ExtraPerPayment = &l.ExtraPerPayment
+	}
+	for _, extra := range l.Extras {
+		response.Extras = append(response.Extras, ExtraPaymentResponse{Date: extra.Date.Format(time.DateOnly), Amount: extra.Amount})
+	}
+	return response
+}
+
+// ScheduleResponse represents a loan's amortization schedule to return in responses. When
+// the account has a balance on AsOf, the response also reports the variance between the
+// amount owed on the account and the projected balance on that date.
+type ScheduleResponse struct {
+	loan.Schedule
+	AsOf             string       `json:"as_of"`
+	ProjectedBalance money.Money  `json:"projected_balance"`
+	Owed             *money.Money `json:"owed,omitempty"`
+	Variance         *money.Money `json:"variance,omitempty"`
+}
+
+// SplitResponse represents the payments split into interest and principal. Skipped payments
+// matched an installment but are reconciled or in a closed period.
+type SplitResponse struct {
+	AccountID int64   `json:"account_id"`
+	Split     []int64 `json:"split"`
+	Skipped   []int64 `json:"skipped"`
+}
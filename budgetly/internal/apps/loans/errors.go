@@ -0,0 +1,8 @@
+package loans
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+	ErrLoanNotFound   error = errors.New("loan not found")
+)
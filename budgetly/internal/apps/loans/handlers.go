@@ -0,0 +1,172 @@
+package loans
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// loanHandler is an HTTP handler for loan operations
+// (e.g., storing a loan, building its schedule, splitting its payments, etc.)
+type loanHandler struct {
+	loanService *loanService
+	logger      *slog.Logger
+	router      *http.ServeMux
+}
+
+// newLoanHandler creates a new loan handler with the provided loan service and logger
+func newLoanHandler(loanService *loanService, logger *slog.Logger, router *http.ServeMux) *loanHandler {
+	loanHandler := &loanHandler{
+		loanService: loanService,
+		logger:      logger,
+		router:      router,
+	}
+	loanHandler.registerRoutes()
+	return loanHandler
+}
+
+// Register routes for loan actions
+func (h *loanHandler) registerRoutes() {
+	h.router.HandleFunc("PUT /accounts/{id}/loan", h.save)
+	h.router.HandleFunc("GET /accounts/{id}/loan", h.get)
+	h.router.HandleFunc("DELETE /accounts/{id}/loan", h.delete)
+	h.router.HandleFunc("GET /accounts/{id}/loan/schedule", h.schedule)
+	h.router.HandleFunc("POST /accounts/{id}/loan/split-payments", h.splitPayments)
+}
+
+// Save is an HTTP handler for storing the loan of a liability account
+func (h *loanHandler) save(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req LoanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return
+	}
+
+	l, err := h.loanService.save(accountID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, l)
+}
+
+// Get is an HTTP handler for retrieving an account's loan
+func (h *loanHandler) get(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	l, err := h.loanService.get(accountID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, l)
+}
+
+// Delete is an HTTP handler for removing an account's loan
+func (h *loanHandler) delete(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.loanService.delete(accountID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Schedule is an HTTP handler for an account's amortization schedule, compared with what
+// is owed on the account as of a date (default today)
+func (h *loanHandler) schedule(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	asOf := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			h.writeError(w, &validate.ValidationError{Errors: map[string]string{"as_of": "as_of must be in YYYY-MM-DD format"}})
+			return
+		}
+		asOf = date
+	}
+
+	schedule, err := h.loanService.schedule(accountID, asOf)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, schedule)
+}
+
+// SplitPayments is an HTTP handler for splitting an account's loan payments into
+// interest and principal
+func (h *loanHandler) splitPayments(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	split, err := h.loanService.splitPayments(accountID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, split)
+}
+
+// pathID parses the account ID from the URL, writing a 400 response on failure
+func (h *loanHandler) pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid account ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *loanHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrLoanNotFound), errors.Is(err, accounts.ErrAccountNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling loan request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,141 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package loans
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/jmoiron/sqlx"
+)
+
+// loanColumns lists the columns selected for a Loan, joined with its account
+const loanColumns = `l.account_id, a.household_id, l.principal, l.apr, l.term_months, l.first_payment,
+	l.frequency, l.extra_per_payment, l.payee, l.interest_category, l.principal_category, l.updated_at`
+
+// loanModel wraps the database connection pool using sqlx
+type loanModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newLoanModel(db *sqlx.DB, logger *slog.Logger) *loanModel {
+	return &loanModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Save stores an account's loan and its extra payments, replacing any it already has
+func (m *loanModel) save(l *Loan) error {
+	query := `INSERT INTO loans (account_id, principal, apr, term_months, first_payment, frequency,
+		extra_per_payment, payee, interest_category, principal_category, updated_at)
+	VALUES (:account_id, :principal, :apr, :term_months, :first_payment, :frequency,
+		:extra_per_payment, :payee, :interest_category, :principal_category, :updated_at)
+	ON CONFLICT (account_id) DO UPDATE SET principal = EXCLUDED.principal, apr = EXCLUDED.apr,
+		term_months = EXCLUDED.term_months, first_payment = EXCLUDED.first_payment,
+		frequency = EXCLUDED.frequency, extra_per_payment = EXCLUDED.extra_per_payment,
+		payee = EXCLUDED.payee, interest_category = EXCLUDED.interest_category,
+		principal_category = EXCLUDED.principal_category, updated_at = EXCLUDED.updated_at`
+
+	l.UpdatedAt = time.Now()
+
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting loan transaction", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.NamedExec(query, l); err != nil {
+		m.logger.Error("Error saving loan", "error", err)
+		return ErrInternalServer
+	}
+	if _, err := tx.Exec(`DELETE FROM loan_extra_payments WHERE account_id = $1`, l.AccountID); err != nil {
+		m.logger.Error("Error deleting loan extra payments", "error", err)
+		return ErrInternalServer
+	}
+	for i := range l.Extras {
+		if _, err := tx.NamedExec(`INSERT INTO loan_extra_payments (account_id, date, amount)
+		VALUES (:account_id, :date, :amount)`, &l.Extras[i]); err != nil {
+			m.logger.Error("Error inserting loan extra payment", "error", err)
+			return ErrInternalServer
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing loan", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Loan saved successfully", "account_id", l.AccountID)
+	return nil
+}
+
+// GetByAccount returns an account's loan with its extra payments in date order
+func (m *loanModel) getByAccount(accountID int64) (*Loan, error) {
+	l := &Loan{}
+	query := `SELECT ` + loanColumns + ` FROM loans l JOIN accounts a ON a.id = l.account_id
+	WHERE l.account_id = $1`
+	err := m.DB.Get(l, query, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrLoanNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting loan by account", "error", err)
+		return nil, ErrInternalServer
+	}
+
+	l.Extras = []ExtraPayment{}
+	query = `SELECT account_id, date, amount FROM loan_extra_payments WHERE account_id = $1 ORDER BY date, id`
+	if err := m.DB.Select(&l.Extras, query, accountID); err != nil {
+		m.logger.Error("Error listing loan extra payments", "error", err)
+		return nil, ErrInternalServer
+	}
+	return l, nil
+}
+
+// Delete removes an account's loan
+func (m *loanModel) delete(accountID int64) error {
+	result, err := m.DB.Exec(`DELETE FROM loans WHERE account_id = $1`, accountID)
+	if err != nil {
+		m.logger.Error("Error deleting loan", "error", err)
+		return ErrInternalServer
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected loan count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrLoanNotFound
+	}
+	return nil
+}
+
+// Payment is a transaction that may pay a loan installment.
+type Payment struct {
+	ID     int64       `db:"id"`
+	Date   time.Time   `db:"date"`
+	Amount money.Money `db:"amount"`
+}
+
+// Payments returns the household's unsplit outgoing transactions to a payee from a date on,
+// in date order. Transfers, scheduled and reconciled transactions are left out.
+func (m *loanModel) payments(householdID int64, payee string, from time.Time) ([]Payment, error) {
+	query := `SELECT t.id, t.date, t.amount FROM transactions t
+	WHERE t.household_id = $1 AND lower(t.payee) = lower($2) AND t.date >= $3
+		AND split_part(t.amount, ' ', 1)::numeric < 0 AND t.transfer_id IS NULL AND NOT t.scheduled AND NOT t.reconciled
+		AND NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id)
+	ORDER BY t.date, t.id`
+
+	payments := []Payment{}
+	if err := m.DB.Select(&payments, query, householdID, payee, from); err != nil {
+		m.logger.Error("Error listing loan payments", "error", err)
+		return nil, ErrInternalServer
+	}
+	return payments, nil
+}
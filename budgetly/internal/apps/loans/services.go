@@ -0,0 +1,196 @@
+package loans
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
+	"github.com/ZiadMansourM/budgetly/pkg/fiscal"
+	"github.com/ZiadMansourM/budgetly/pkg/loan"
+	"github.com/ZiadMansourM/budgetly/pkg/reconcile"
+	"github.com/ZiadMansourM/budgetly/pkg/splits"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// matchWindow is how far a payment's date may be from an installment's due date to pay it,
+// e.g. when the due date falls on a weekend
+const matchWindow = 5 * 24 * time.Hour
+
+type loanService struct {
+	loanRepo *loanModel
+	logger   *slog.Logger
+}
+
+func newLoanService(loanRepo *loanModel, logger *slog.Logger) *loanService {
+	return &loanService{
+		loanRepo: loanRepo,
+		logger:   logger,
+	}
+}
+
+// save validates and stores the loan of a liability account
+func (s *loanService) save(accountID int64, input LoanRequest) (*LoanResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Loan validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	account, err := accounts.Get(s.loanRepo.DB, s.logger, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Principal.Currency() != account.Currency {
+		return nil, &validate.ValidationError{Errors: map[string]string{
+			"Principal": "Principal must be in the account's currency " + account.Currency,
+		}}
+	}
+
+	stored := input.toLoan(accountID)
+	if _, _, err := s.amortize(stored); err != nil {
+		return nil, err
+	}
+	if err := s.loanRepo.save(stored); err != nil {
+		return nil, err
+	}
+	return s.get(accountID)
+}
+
+// get returns an account's loan with its regular payment
+func (s *loanService) get(accountID int64) (*LoanResponse, error) {
+	stored, err := s.loanRepo.getByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	_, schedule, err := s.amortize(stored)
+	if err != nil {
+		return nil, err
+	}
+	return stored.ToResponse(schedule.Payment), nil
+}
+
+// delete removes an account's loan, leaving the account and its transactions alone
+func (s *loanService) delete(accountID int64) error {
+	return s.loanRepo.delete(accountID)
+}
+
+// schedule builds an account's amortization schedule and compares the balance it projects
+// on asOf with what is owed on the account that day
+func (s *loanService) schedule(accountID int64, asOf time.Time) (*ScheduleResponse, error) {
+	stored, err := s.loanRepo.getByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	l, schedule, err := s.amortize(stored)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ScheduleResponse{
+		Schedule:         schedule,
+		AsOf:             asOf.Format(time.DateOnly),
+		ProjectedBalance: schedule.BalanceOn(asOf, l.Principal),
+	}
+	balance, ok, err := balances.Balance(s.loanRepo.DB, s.logger, accountID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		// A liability account's balance is negative while money is owed.
+		owed := balance.Negate()
+		variance, err := l.Variance(schedule, asOf, owed)
+		if err != nil {
+			return nil, err
+		}
+		response.Owed, response.Variance = &owed, &variance
+	}
+	return response, nil
+}
+
+// splitPayments splits the household's unsplit payments to the loan's payee that pay an
+// installment in full into its interest and principal, each installment paid at most once
+func (s *loanService) splitPayments(accountID int64) (*SplitResponse, error) {
+	stored, err := s.loanRepo.getByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	_, schedule, err := s.amortize(stored)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.loanRepo.payments(stored.HouseholdID, stored.Payee, stored.FirstPayment.Add(-matchWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SplitResponse{AccountID: accountID, Split: []int64{}, Skipped: []int64{}}
+	paid := make([]bool, len(schedule.Installments))
+	for _, payment := range payments {
+		i := matchInstallment(schedule.Installments, paid, payment)
+		if i < 0 {
+			continue
+		}
+		installment := schedule.Installments[i]
+		if installment.Interest.IsZero() {
+			// Nothing to split: the whole payment goes towards the principal.
+			paid[i] = true
+			continue
+		}
+
+		interest := installment.Interest.Negate()
+		principal, err := payment.Amount.Sub(interest)
+		if err != nil {
+			return nil, err
+		}
+		lines := []splits.Split{
+			{Category: stored.InterestCategory, Memo: "Interest", Amount: interest},
+			{Category: stored.PrincipalCategory, Memo: "Principal", Amount: principal},
+		}
+		_, err = transactions.ReplaceSplits(s.loanRepo.DB, s.logger, payment.ID, transactions.SplitsRequest{Splits: lines}, false)
+		if errors.Is(err, fiscal.ErrPeriodLocked) || errors.Is(err, reconcile.ErrReconciled) {
+			response.Skipped = append(response.Skipped, payment.ID)
+			paid[i] = true
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		response.Split = append(response.Split, payment.ID)
+		paid[i] = true
+	}
+	return response, nil
+}
+
+// matchInstallment returns the index of the unpaid installment the payment pays in full
+// within the match window of its due date, or -1
+func matchInstallment(installments []loan.Installment, paid []bool, payment Payment) int {
+	amount := payment.Amount.Negate()
+	for i, installment := range installments {
+		if paid[i] || !installment.Payment.Equal(amount) {
+			continue
+		}
+		if gap := payment.Date.Sub(installment.Date); gap <= matchWindow && gap >= -matchWindow {
+			return i
+		}
+	}
+	return -1
+}
+
+// amortize builds a stored loan's schedule, reporting a loan that cannot be amortized as a
+// validation error
+func (s *loanService) amortize(stored *Loan) (loan.Loan, loan.Schedule, error) {
+	l, err := stored.toLoan()
+	if err != nil {
+		return loan.Loan{}, loan.Schedule{}, &validate.ValidationError{Errors: map[string]string{"APR": err.Error()}}
+	}
+	schedule, err := l.Amortize()
+	if err != nil {
+		s.logger.Warn("Loan schedule rejected", "account_id", stored.AccountID, "error", err)
+		if errors.Is(err, loan.ErrInvalidLoan) || errors.Is(err, loan.ErrUnknownFrequency) || errors.Is(err, loan.ErrCurrencyMismatch) {
+			return loan.Loan{}, loan.Schedule{}, &validate.ValidationError{Errors: map[string]string{"Loan": err.Error()}}
+		}
+		return loan.Loan{}, loan.Schedule{}, err
+	}
+	return l, schedule, nil
+}
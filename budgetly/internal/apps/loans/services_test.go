@@ -0,0 +1,41 @@
+package loans
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/loan"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func TestMatchInstallment(t *testing.T) {
+	principal, _ := money.New(1200000, "EUR")
+	schedule, err := loan.Loan{
+		Principal:    principal,
+		APR:          big.NewRat(6, 1),
+		TermMonths:   12,
+		FirstPayment: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Frequency:    loan.FrequencyMonthly,
+	}.Amortize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	paid := make([]bool, len(schedule.Installments))
+	payment := Payment{ID: 1, Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Amount: schedule.Payment.Negate()}
+
+	// February's installment is due on the 29th, two days before the payment.
+	if i := matchInstallment(schedule.Installments, paid, payment); i != 1 {
+		t.Fatalf("Expected the payment to match the second installment, got %d", i)
+	}
+	paid[1] = true
+	if i := matchInstallment(schedule.Installments, paid, payment); i != -1 {
+		t.Errorf("Expected a paid installment not to match again, got %d", i)
+	}
+
+	paid[1] = false
+	payment.Amount, _ = money.New(-5000, "EUR")
+	if i := matchInstallment(schedule.Installments, paid, payment); i != -1 {
+		t.Errorf("Expected a partial payment not to match, got %d", i)
+	}
+}
@@ -0,0 +1,25 @@
+package notifications
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/alerts"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewNotificationsApp creates a new notifications application (alert rules, the in-app
+// inbox and delivery channels) with the provided database connection
+func NewNotificationsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	notificationModel := newNotificationModel(db, logger)
+	notificationService := newNotificationService(notificationModel, logger)
+	newNotificationHandler(notificationService, logger, router)
+}
+
+// Evaluate runs the household's alert rules against a change, e.g. a transaction being
+// saved, storing any new notifications in its inbox and delivering them to its enabled channels.
+func Evaluate(db *sqlx.DB, logger *slog.Logger, householdID int64, event alerts.Event) ([]*NotificationResponse, error) {
+	service := newNotificationService(newNotificationModel(db, logger), logger)
+	return service.evaluate(householdID, event, time.Now())
+}
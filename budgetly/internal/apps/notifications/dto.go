@@ -0,0 +1,283 @@
+package notifications
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/alerts"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// Rule is a stored alert rule.
+type Rule struct {
+	ID          int            `db:"id"`
+	HouseholdID int64          `db:"household_id"`
+	Kind        alerts.Kind    `db:"kind"`
+	Category    sql.NullString `db:"category"`
+	AccountID   sql.NullInt64  `db:"account_id"`
+	Percent     sql.NullInt32  `db:"percent"`
+	Amount      money.Money    `db:"amount"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+// toAlertRule converts a stored rule into the form evaluated by the alerts package.
+func (r *Rule) toAlertRule() alerts.Rule {
+	return alerts.Rule{
+		ID:        r.ID,
+		Kind:      r.Kind,
+		Category:  r.Category.String,
+		AccountID: r.AccountID.Int64,
+		Percent:   int(r.Percent.Int32),
+		Amount:    r.Amount,
+	}
+}
+
+// RuleRequest represents the input data for creating or updating an alert rule.
+type RuleRequest struct {
+	Kind      alerts.Kind  `json:"kind"`
+	Category  string       `json:"category"`
+	AccountID int64        `json:"account_id"`
+	Percent   int          `json:"percent"`
+	Amount    *money.Money `json:"amount"`
+}
+
+// Validate validates the RuleRequest struct.
+func (input *RuleRequest) Validate() map[string]string {
+	input.Category = strings.TrimSpace(input.Category)
+
+	errors := map[string]string{}
+	if err := input.toRule(0).toAlertRule().Validate(); err != nil {
+		errors["Rule"] = err.Error()
+	}
+	if len(input.Category) > 100 {
+		errors["Category"] = "Category must be at most 100 characters long"
+	}
+	return errors
+}
+
+// toRule converts a RuleRequest into a Rule of a household.
+func (input *RuleRequest) toRule(householdID int64) *Rule {
+	rule := &Rule{
+		HouseholdID: householdID,
+		Kind:        input.Kind,
+		Category:    sql.NullString{String: input.Category, Valid: input.Category != ""},
+		AccountID:   sql.NullInt64{Int64: input.AccountID, Valid: input.AccountID != 0},
+		Percent:     sql.NullInt32{Int32: int32(input.Percent), Valid: input.Percent != 0},
+	}
+	if input.Amount != nil {
+		rule.Amount = *input.Amount
+	}
+	return rule
+}
+
+// RuleResponse represents the alert rule data to return in responses.
+type RuleResponse struct {
+	ID        int          `json:"id"`
+	Kind      alerts.Kind  `json:"kind"`
+	Category  string       `json:"category,omitempty"`
+	AccountID int64        `json:"account_id,omitempty"`
+	Percent   int          `json:"percent,omitempty"`
+	Amount    *money.Money `json:"amount,omitempty"`
+}
+
+// ToResponse converts a Rule (from database) to a RuleResponse (for API responses).
+func (r *Rule) ToResponse() *RuleResponse {
+	response := &RuleResponse{
+		ID:        r.ID,
+		Kind:      r.Kind,
+		Category:  r.Category.String,
+		AccountID: r.AccountID.Int64,
+		Percent:   int(r.Percent.Int32),
+	}
+	if r.Amount.Currency() != "" {
+		amount := r.Amount
+		response.Amount = &amount
+	}
+	return response
+}
+
+// Notification is an alert that fired, kept in the in-app inbox.
+type Notification struct {
+	ID          int           `db:"id"`
+	HouseholdID int64         `db:"household_id"`
+	RuleID      sql.NullInt64 `db:"rule_id"`
+	DedupeKey   string        `db:"dedupe_key"`
+	Title       string        `db:"title"`
+	Message     string        `db:"message"`
+	ReadAt      sql.NullTime  `db:"read_at"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
+
+// NotificationResponse represents the notification data to return in responses.
+type NotificationResponse struct {
+	ID        int        `json:"id"`
+	RuleID    int64      `json:"rule_id,omitempty"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ToResponse converts a Notification (from database) to a NotificationResponse (for API responses).
+func (n *Notification) ToResponse() *NotificationResponse {
+	response := &NotificationResponse{
+		ID:        n.ID,
+		RuleID:    n.RuleID.Int64,
+		Title:     n.Title,
+		Message:   n.Message,
+		Read:      n.ReadAt.Valid,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.ReadAt.Valid {
+		response.ReadAt = &n.ReadAt.Time
+	}
+	return response
+}
+
+// ChannelKind is where notifications are delivered besides the in-app inbox.
+type ChannelKind string
+
+const (
+	// ChannelWebhook posts each notification as JSON to a URL.
+	ChannelWebhook ChannelKind = "webhook"
+	// ChannelSlack posts each notification to a Slack incoming webhook.
+	ChannelSlack ChannelKind = "slack"
+)
+
+// Channel is a configured delivery channel.
+type Channel struct {
+	ID          int         `db:"id"`
+	HouseholdID int64       `db:"household_id"`
+	Kind        ChannelKind `db:"kind"`
+	Target      string      `db:"target"`
+	Enabled     bool        `db:"enabled"`
+	CreatedAt   time.Time   `db:"created_at"`
+}
+
+// ChannelRequest represents the input data for creating or updating a delivery channel.
+type ChannelRequest struct {
+	Kind    ChannelKind `json:"kind"`
+	Target  string      `json:"target"`
+	Enabled *bool       `json:"enabled"`
+}
+
+// Validate validates the ChannelRequest struct.
+func (input *ChannelRequest) Validate() map[string]string {
+	input.Target = strings.TrimSpace(input.Target)
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Target": validate.Rules(
+			validate.Required,
+			validate.Max(2048),
+			validate.ErrorMessage("Target is required and must be at most 2048 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if input.Kind != ChannelWebhook && input.Kind != ChannelSlack {
+		errors["Kind"] = fmt.Sprintf("Kind must be %q or %q", ChannelWebhook, ChannelSlack)
+	}
+	if _, ok := errors["Target"]; !ok {
+		if u, err := url.Parse(input.Target); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			errors["Target"] = "Target must be an http or https URL"
+		}
+	}
+	return errors
+}
+
+// toChannel converts a validated ChannelRequest into a Channel of a household.
+func (input *ChannelRequest) toChannel(householdID int64) *Channel {
+	return &Channel{
+		HouseholdID: householdID,
+		Kind:        input.Kind,
+		Target:      input.Target,
+		Enabled:     input.Enabled == nil || *input.Enabled,
+	}
+}
+
+// ChannelResponse represents the channel data to return in responses.
+type ChannelResponse struct {
+	ID      int         `json:"id"`
+	Kind    ChannelKind `json:"kind"`
+	Target  string      `json:"target"`
+	Enabled bool        `json:"enabled"`
+}
+
+// ToResponse converts a Channel (from database) to a ChannelResponse (for API responses).
+func (c *Channel) ToResponse() *ChannelResponse {
+	return &ChannelResponse{
+		ID:      c.ID,
+		Kind:    c.Kind,
+		Target:  c.Target,
+		Enabled: c.Enabled,
+	}
+}
+
+// EvaluateRequest represents a change to evaluate the alert rules against. Dates are in
+// YYYY-MM-DD format.
+type EvaluateRequest struct {
+	Budgets []struct {
+		Category    string      `json:"category"`
+		Budgeted    money.Money `json:"budgeted"`
+		Spent       money.Money `json:"spent"`
+		PeriodStart string      `json:"period_start"`
+		PeriodEnd   string      `json:"period_end"`
+	} `json:"budgets"`
+	Transaction *struct {
+		ID        int64       `json:"id"`
+		AccountID int64       `json:"account_id"`
+		Category  string      `json:"category"`
+		Payee     string      `json:"payee"`
+		Amount    money.Money `json:"amount"`
+		Date      string      `json:"date"`
+	} `json:"transaction"`
+	Accounts []alerts.Account `json:"accounts"`
+}
+
+// toEvent converts the request into an alerts.Event, returning validation errors for
+// malformed dates.
+func (input *EvaluateRequest) toEvent() (alerts.Event, map[string]string) {
+	errors := map[string]string{}
+	parse := func(field, value string) time.Time {
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			errors[field] = field + " must be in YYYY-MM-DD format"
+		}
+		return date
+	}
+
+	event := alerts.Event{Accounts: input.Accounts}
+	for i, b := range input.Budgets {
+		event.Budgets = append(event.Budgets, alerts.Budget{
+			Category:    b.Category,
+			Budgeted:    b.Budgeted,
+			Spent:       b.Spent,
+			PeriodStart: parse(fmt.Sprintf("Budgets[%d].PeriodStart", i), b.PeriodStart),
+			PeriodEnd:   parse(fmt.Sprintf("Budgets[%d].PeriodEnd", i), b.PeriodEnd),
+		})
+	}
+	if tx := input.Transaction; tx != nil {
+		event.Transaction = &alerts.Transaction{
+			ID:        tx.ID,
+			AccountID: tx.AccountID,
+			Category:  tx.Category,
+			Payee:     tx.Payee,
+			Amount:    tx.Amount,
+			Date:      parse("Transaction.Date", tx.Date),
+		}
+	}
+	return event, errors
+}
+
+// InboxResponse represents a page of the inbox with the total unread count.
+type InboxResponse struct {
+	Unread        int                     `json:"unread"`
+	Notifications []*NotificationResponse `json:"notifications"`
+}
@@ -0,0 +1,10 @@
+package notifications
+
+import "errors"
+
+var (
+	ErrInternalServer       error = errors.New("internal server error")
+	ErrRuleNotFound         error = errors.New("alert rule not found")
+	ErrNotificationNotFound error = errors.New("notification not found")
+	ErrChannelNotFound      error = errors.New("notification channel not found")
+)
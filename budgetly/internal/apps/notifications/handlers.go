@@ -0,0 +1,365 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// Inbox page sizes.
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// notificationHandler is an HTTP handler for alert rules, the inbox and delivery channels
+type notificationHandler struct {
+	notificationService *notificationService
+	logger              *slog.Logger
+	router              *http.ServeMux
+}
+
+// newNotificationHandler creates a new notification handler with the provided notification service and logger
+func newNotificationHandler(notificationService *notificationService, logger *slog.Logger, router *http.ServeMux) *notificationHandler {
+	notificationHandler := &notificationHandler{
+		notificationService: notificationService,
+		logger:              logger,
+		router:              router,
+	}
+	notificationHandler.registerRoutes()
+	return notificationHandler
+}
+
+// Register routes for alert and notification actions
+func (h *notificationHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/alerts", h.createRule)
+	h.router.HandleFunc("GET /households/{household}/alerts", h.listRules)
+	h.router.HandleFunc("POST /households/{household}/alerts/evaluate", h.evaluate)
+	h.router.HandleFunc("PUT /alerts/{id}", h.updateRule)
+	h.router.HandleFunc("DELETE /alerts/{id}", h.deleteRule)
+
+	h.router.HandleFunc("GET /households/{household}/notifications", h.listNotifications)
+	h.router.HandleFunc("POST /households/{household}/notifications/read", h.markAllRead)
+	h.router.HandleFunc("POST /notifications/{id}/read", h.markRead)
+	h.router.HandleFunc("DELETE /notifications/{id}", h.deleteNotification)
+
+	h.router.HandleFunc("POST /households/{household}/notifications/channels", h.createChannel)
+	h.router.HandleFunc("GET /households/{household}/notifications/channels", h.listChannels)
+	h.router.HandleFunc("PUT /notifications/channels/{id}", h.updateChannel)
+	h.router.HandleFunc("DELETE /notifications/channels/{id}", h.deleteChannel)
+}
+
+// CreateRule is an HTTP handler for creating a new alert rule for a household
+func (h *notificationHandler) createRule(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req RuleRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	rule, err := h.notificationService.createRule(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, rule)
+}
+
+// ListRules is an HTTP handler for listing a household's alert rules
+func (h *notificationHandler) listRules(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	rules, err := h.notificationService.listRules(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, rules)
+}
+
+// UpdateRule is an HTTP handler for updating an alert rule
+func (h *notificationHandler) updateRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, ok := h.pathID(w, r, "alert rule")
+	if !ok {
+		return
+	}
+
+	var req RuleRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	rule, err := h.notificationService.updateRule(ruleID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, rule)
+}
+
+// DeleteRule is an HTTP handler for deleting an alert rule
+func (h *notificationHandler) deleteRule(w http.ResponseWriter, r *http.Request) {
+	ruleID, ok := h.pathID(w, r, "alert rule")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.deleteRule(ruleID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Evaluate is an HTTP handler for running a household's alert rules against a change
+func (h *notificationHandler) evaluate(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req EvaluateRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	event, validationErrors := req.toEvent()
+	if len(validationErrors) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: validationErrors})
+		return
+	}
+
+	notifications, err := h.notificationService.evaluate(householdID, event, time.Now())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, notifications)
+}
+
+// ListNotifications is an HTTP handler for reading a household's inbox, with optional
+// ?unread=true and ?limit= parameters
+func (h *notificationHandler) listNotifications(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	unreadOnly, err := strconv.ParseBool(query.Get("unread"))
+	if query.Get("unread") != "" && err != nil {
+		h.writeError(w, &validate.ValidationError{Errors: map[string]string{"unread": "unread must be true or false"}})
+		return
+	}
+	limit := defaultLimit
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 || limit > maxLimit {
+			h.writeError(w, &validate.ValidationError{Errors: map[string]string{"limit": "limit must be between 1 and 200"}})
+			return
+		}
+	}
+
+	inbox, err := h.notificationService.listNotifications(householdID, unreadOnly, limit)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, inbox)
+}
+
+// MarkRead is an HTTP handler for marking a notification as read
+func (h *notificationHandler) markRead(w http.ResponseWriter, r *http.Request) {
+	notificationID, ok := h.pathID(w, r, "notification")
+	if !ok {
+		return
+	}
+
+	notification, err := h.notificationService.markRead(notificationID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, notification)
+}
+
+// MarkAllRead is an HTTP handler for marking every notification of a household as read
+func (h *notificationHandler) markAllRead(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	count, err := h.notificationService.markAllRead(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]int64{"marked": count})
+}
+
+// DeleteNotification is an HTTP handler for deleting a notification
+func (h *notificationHandler) deleteNotification(w http.ResponseWriter, r *http.Request) {
+	notificationID, ok := h.pathID(w, r, "notification")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.deleteNotification(notificationID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateChannel is an HTTP handler for creating a new delivery channel for a household
+func (h *notificationHandler) createChannel(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req ChannelRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	channel, err := h.notificationService.createChannel(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, channel)
+}
+
+// ListChannels is an HTTP handler for listing a household's delivery channels
+func (h *notificationHandler) listChannels(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	channels, err := h.notificationService.listChannels(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, channels)
+}
+
+// UpdateChannel is an HTTP handler for updating a delivery channel
+func (h *notificationHandler) updateChannel(w http.ResponseWriter, r *http.Request) {
+	channelID, ok := h.pathID(w, r, "channel")
+	if !ok {
+		return
+	}
+
+	var req ChannelRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	channel, err := h.notificationService.updateChannel(channelID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, channel)
+}
+
+// DeleteChannel is an HTTP handler for deleting a delivery channel
+func (h *notificationHandler) deleteChannel(w http.ResponseWriter, r *http.Request) {
+	channelID, ok := h.pathID(w, r, "channel")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.deleteChannel(channelID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *notificationHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses the ID from the URL, writing a 400 response on failure
+func (h *notificationHandler) pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid " + name + " ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *notificationHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *notificationHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, households.ErrHouseholdNotFound), errors.Is(err, ErrRuleNotFound),
+		errors.Is(err, ErrNotificationNotFound), errors.Is(err, ErrChannelNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling notification request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,258 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package notifications
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ruleColumns lists the columns selected for a Rule
+const ruleColumns = `id, household_id, kind, category, account_id, percent, amount, created_at`
+
+// notificationColumns lists the columns selected for a Notification
+const notificationColumns = `id, household_id, rule_id, dedupe_key, title, message, read_at, created_at`
+
+// channelColumns lists the columns selected for a Channel
+const channelColumns = `id, household_id, kind, target, enabled, created_at`
+
+// notificationModel wraps the database connection pool using sqlx
+type notificationModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newNotificationModel(db *sqlx.DB, logger *slog.Logger) *notificationModel {
+	return &notificationModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// CreateRule inserts a new alert rule into the database and returns its ID
+func (m *notificationModel) createRule(r *Rule) (int, error) {
+	query := `INSERT INTO alert_rules (household_id, kind, category, account_id, percent, amount, created_at)
+	VALUES (:household_id, :kind, :category, :account_id, :percent, :amount, :created_at)
+	RETURNING id`
+
+	r.CreatedAt = time.Now()
+	if err := m.insert(query, r, &r.ID); err != nil {
+		m.logger.Error("Error inserting alert rule", "error", err)
+		return 0, ErrInternalServer
+	}
+
+	m.logger.Debug("Alert rule created successfully", "id", r.ID)
+	return r.ID, nil
+}
+
+// ListRules returns a household's alert rules
+func (m *notificationModel) listRules(householdID int64) ([]Rule, error) {
+	rules := []Rule{}
+	query := `SELECT ` + ruleColumns + ` FROM alert_rules WHERE household_id = $1 ORDER BY id`
+	if err := m.DB.Select(&rules, query, householdID); err != nil {
+		m.logger.Error("Error listing alert rules", "error", err)
+		return nil, ErrInternalServer
+	}
+	return rules, nil
+}
+
+// UpdateRule replaces an alert rule's definition
+func (m *notificationModel) updateRule(r *Rule) error {
+	query := `UPDATE alert_rules SET kind = :kind, category = :category, account_id = :account_id,
+	percent = :percent, amount = :amount WHERE id = :id`
+
+	result, err := m.DB.NamedExec(query, r)
+	if err != nil {
+		m.logger.Error("Error updating alert rule", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result, ErrRuleNotFound)
+}
+
+// DeleteRule removes an alert rule by ID. Notifications it produced stay in the inbox.
+func (m *notificationModel) deleteRule(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting alert rule", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result, ErrRuleNotFound)
+}
+
+// CreateNotification stores a notification unless one with the same dedupe key exists,
+// reporting whether it was stored
+func (m *notificationModel) createNotification(n *Notification) (bool, error) {
+	query := `INSERT INTO notifications (household_id, rule_id, dedupe_key, title, message, created_at)
+	VALUES (:household_id, :rule_id, :dedupe_key, :title, :message, :created_at)
+	ON CONFLICT (household_id, dedupe_key) DO NOTHING
+	RETURNING id`
+
+	n.CreatedAt = time.Now()
+	rows, err := m.DB.NamedQuery(query, n)
+	if err != nil {
+		m.logger.Error("Error inserting notification", "error", err)
+		return false, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		// The conflict skipped the insert: this alert was already reported.
+		if err := rows.Err(); err != nil {
+			m.logger.Error("Error inserting notification", "error", err)
+			return false, ErrInternalServer
+		}
+		return false, nil
+	}
+	if err := rows.Scan(&n.ID); err != nil {
+		m.logger.Error("Error scanning notification ID", "error", err)
+		return false, ErrInternalServer
+	}
+	return true, nil
+}
+
+// ListNotifications returns a household's inbox, newest first, optionally only unread notifications
+func (m *notificationModel) listNotifications(householdID int64, unreadOnly bool, limit int) ([]Notification, error) {
+	notifications := []Notification{}
+	query := `SELECT ` + notificationColumns + ` FROM notifications
+	WHERE household_id = $1 AND (NOT $2 OR read_at IS NULL)
+	ORDER BY created_at DESC, id DESC LIMIT $3`
+	if err := m.DB.Select(&notifications, query, householdID, unreadOnly, limit); err != nil {
+		m.logger.Error("Error listing notifications", "error", err)
+		return nil, ErrInternalServer
+	}
+	return notifications, nil
+}
+
+// CountUnread returns the number of a household's unread notifications
+func (m *notificationModel) countUnread(householdID int64) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE household_id = $1 AND read_at IS NULL`
+	if err := m.DB.Get(&count, query, householdID); err != nil {
+		m.logger.Error("Error counting unread notifications", "error", err)
+		return 0, ErrInternalServer
+	}
+	return count, nil
+}
+
+// MarkRead marks a notification as read and returns it
+func (m *notificationModel) markRead(id int) (*Notification, error) {
+	n := &Notification{}
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, $2) WHERE id = $1
+	RETURNING ` + notificationColumns
+	err := m.DB.Get(n, query, id, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotificationNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error marking notification as read", "error", err)
+		return nil, ErrInternalServer
+	}
+	return n, nil
+}
+
+// MarkAllRead marks every unread notification of a household as read and returns how many there were
+func (m *notificationModel) markAllRead(householdID int64) (int64, error) {
+	query := `UPDATE notifications SET read_at = $2 WHERE household_id = $1 AND read_at IS NULL`
+	result, err := m.DB.Exec(query, householdID, time.Now())
+	if err != nil {
+		m.logger.Error("Error marking notifications as read", "error", err)
+		return 0, ErrInternalServer
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected notification count", "error", err)
+		return 0, ErrInternalServer
+	}
+	return affected, nil
+}
+
+// DeleteNotification removes a notification by ID
+func (m *notificationModel) deleteNotification(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM notifications WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting notification", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result, ErrNotificationNotFound)
+}
+
+// CreateChannel inserts a new delivery channel into the database and returns its ID
+func (m *notificationModel) createChannel(c *Channel) (int, error) {
+	query := `INSERT INTO notification_channels (household_id, kind, target, enabled, created_at)
+	VALUES (:household_id, :kind, :target, :enabled, :created_at)
+	RETURNING id`
+
+	c.CreatedAt = time.Now()
+	if err := m.insert(query, c, &c.ID); err != nil {
+		m.logger.Error("Error inserting notification channel", "error", err)
+		return 0, ErrInternalServer
+	}
+
+	m.logger.Debug("Notification channel created successfully", "id", c.ID)
+	return c.ID, nil
+}
+
+// ListChannels returns a household's delivery channels, or only the enabled ones
+func (m *notificationModel) listChannels(householdID int64, enabledOnly bool) ([]Channel, error) {
+	channels := []Channel{}
+	query := `SELECT ` + channelColumns + ` FROM notification_channels
+	WHERE household_id = $1 AND (NOT $2 OR enabled) ORDER BY id`
+	if err := m.DB.Select(&channels, query, householdID, enabledOnly); err != nil {
+		m.logger.Error("Error listing notification channels", "error", err)
+		return nil, ErrInternalServer
+	}
+	return channels, nil
+}
+
+// UpdateChannel replaces a delivery channel's configuration
+func (m *notificationModel) updateChannel(c *Channel) error {
+	query := `UPDATE notification_channels SET kind = :kind, target = :target, enabled = :enabled WHERE id = :id`
+
+	result, err := m.DB.NamedExec(query, c)
+	if err != nil {
+		m.logger.Error("Error updating notification channel", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result, ErrChannelNotFound)
+}
+
+// DeleteChannel removes a delivery channel by ID
+func (m *notificationModel) deleteChannel(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM notification_channels WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting notification channel", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result, ErrChannelNotFound)
+}
+
+// insert runs a named INSERT ... RETURNING id query, scanning the new ID into id
+func (m *notificationModel) insert(query string, arg any, id *int) error {
+	rows, err := m.DB.NamedQuery(query, arg)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		return rows.Scan(id)
+	}
+	return rows.Err()
+}
+
+// expectRow returns notFound when a statement affected no rows
+func (m *notificationModel) expectRow(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected row count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
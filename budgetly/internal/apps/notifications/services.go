@@ -0,0 +1,257 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/alerts"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// deliveryTimeout bounds how long a channel may take to accept a notification.
+const deliveryTimeout = 10 * time.Second
+
+type notificationService struct {
+	notificationRepo *notificationModel
+	client           *http.Client
+	logger           *slog.Logger
+}
+
+func newNotificationService(notificationRepo *notificationModel, logger *slog.Logger) *notificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		client:           &http.Client{Timeout: deliveryTimeout},
+		logger:           logger,
+	}
+}
+
+// createRule validates and stores a new alert rule for a household
+func (s *notificationService) createRule(householdID int64, input RuleRequest) (*RuleResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Alert rule validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.notificationRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	rule := input.toRule(householdID)
+	if _, err := s.notificationRepo.createRule(rule); err != nil {
+		return nil, err
+	}
+	return rule.ToResponse(), nil
+}
+
+// listRules returns a household's alert rules
+func (s *notificationService) listRules(householdID int64) ([]*RuleResponse, error) {
+	if _, err := households.Get(s.notificationRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	rules, err := s.notificationRepo.listRules(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*RuleResponse, 0, len(rules))
+	for i := range rules {
+		responses = append(responses, rules[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// updateRule validates and replaces an alert rule's definition
+func (s *notificationService) updateRule(id int, input RuleRequest) (*RuleResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Alert rule validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	rule := input.toRule(0)
+	rule.ID = id
+	if err := s.notificationRepo.updateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule.ToResponse(), nil
+}
+
+// deleteRule removes an alert rule by ID
+func (s *notificationService) deleteRule(id int) error {
+	return s.notificationRepo.deleteRule(id)
+}
+
+// evaluate runs every alert rule of the household against the event, stores the alerts not
+// reported before and delivers them to the household's enabled channels in the background
+func (s *notificationService) evaluate(householdID int64, event alerts.Event, today time.Time) ([]*NotificationResponse, error) {
+	rules, err := s.notificationRepo.listRules(householdID)
+	if err != nil {
+		return nil, err
+	}
+	alertRules := make([]alerts.Rule, 0, len(rules))
+	for i := range rules {
+		alertRules = append(alertRules, rules[i].toAlertRule())
+	}
+
+	created := []*NotificationResponse{}
+	for _, alert := range alerts.Evaluate(alertRules, event, today) {
+		notification := &Notification{
+			HouseholdID: householdID,
+			DedupeKey:   alert.Key,
+			Title:       alert.Title,
+			Message:     alert.Message,
+		}
+		notification.RuleID.Int64, notification.RuleID.Valid = int64(alert.RuleID), true
+
+		stored, err := s.notificationRepo.createNotification(notification)
+		if err != nil {
+			return nil, err
+		}
+		if stored {
+			created = append(created, notification.ToResponse())
+		}
+	}
+
+	if len(created) > 0 {
+		channels, err := s.notificationRepo.listChannels(householdID, true)
+		if err != nil {
+			return nil, err
+		}
+		go s.deliver(channels, created)
+	}
+	return created, nil
+}
+
+// deliver sends notifications to each channel, logging failures. The inbox already holds
+// them, so a failed delivery loses nothing.
+func (s *notificationService) deliver(channels []Channel, notifications []*NotificationResponse) {
+	for _, channel := range channels {
+		for _, notification := range notifications {
+			if err := s.send(channel, notification); err != nil {
+				s.logger.Warn("Error delivering notification", "channel", channel.ID, "notification", notification.ID, "error", err)
+			}
+		}
+	}
+}
+
+// send posts one notification to a channel in the channel's payload format
+func (s *notificationService) send(channel Channel, notification *NotificationResponse) error {
+	var payload any = notification
+	if channel.Kind == ChannelSlack {
+		payload = map[string]string{"text": fmt.Sprintf("*%s*\n%s", notification.Title, notification.Message)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(channel.Target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("channel responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// listNotifications returns a household's inbox and its unread count
+func (s *notificationService) listNotifications(householdID int64, unreadOnly bool, limit int) (*InboxResponse, error) {
+	if _, err := households.Get(s.notificationRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	notifications, err := s.notificationRepo.listNotifications(householdID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.countUnread(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	inbox := &InboxResponse{Unread: unread, Notifications: make([]*NotificationResponse, 0, len(notifications))}
+	for i := range notifications {
+		inbox.Notifications = append(inbox.Notifications, notifications[i].ToResponse())
+	}
+	return inbox, nil
+}
+
+// markRead marks a notification as read
+func (s *notificationService) markRead(id int) (*NotificationResponse, error) {
+	notification, err := s.notificationRepo.markRead(id)
+	if err != nil {
+		return nil, err
+	}
+	return notification.ToResponse(), nil
+}
+
+// markAllRead marks every notification of a household as read
+func (s *notificationService) markAllRead(householdID int64) (int64, error) {
+	if _, err := households.Get(s.notificationRepo.DB, s.logger, householdID); err != nil {
+		return 0, err
+	}
+	return s.notificationRepo.markAllRead(householdID)
+}
+
+// deleteNotification removes a notification by ID
+func (s *notificationService) deleteNotification(id int) error {
+	return s.notificationRepo.deleteNotification(id)
+}
+
+// createChannel validates and stores a new delivery channel for a household
+func (s *notificationService) createChannel(householdID int64, input ChannelRequest) (*ChannelResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Notification channel validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.notificationRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	channel := input.toChannel(householdID)
+	if _, err := s.notificationRepo.createChannel(channel); err != nil {
+		return nil, err
+	}
+	return channel.ToResponse(), nil
+}
+
+// listChannels returns a household's delivery channels
+func (s *notificationService) listChannels(householdID int64) ([]*ChannelResponse, error) {
+	if _, err := households.Get(s.notificationRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	channels, err := s.notificationRepo.listChannels(householdID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*ChannelResponse, 0, len(channels))
+	for i := range channels {
+		responses = append(responses, channels[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// updateChannel validates and replaces a delivery channel's configuration
+func (s *notificationService) updateChannel(id int, input ChannelRequest) (*ChannelResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Notification channel validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	channel := input.toChannel(0)
+	channel.ID = id
+	if err := s.notificationRepo.updateChannel(channel); err != nil {
+		return nil, err
+	}
+	return channel.ToResponse(), nil
+}
+
+// deleteChannel removes a delivery channel by ID
+func (s *notificationService) deleteChannel(id int) error {
+	return s.notificationRepo.deleteChannel(id)
+}
@@ -0,0 +1,22 @@
+package payees
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/ZiadMansourM/budgetly/pkg/payees"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewPayeesApp creates a new payee directory application with the provided database connection
+func NewPayeesApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	payeeModel := newPayeeModel(db, logger)
+	payeeService := newPayeeService(payeeModel, logger)
+	newPayeeHandler(payeeService, logger, router)
+}
+
+// LoadDirectory loads a household's payees and their aliases for the import pipeline,
+// which passes imported lines through Directory.NormalizeLines
+func LoadDirectory(db *sqlx.DB, logger *slog.Logger, householdID int64) (*payees.Directory, error) {
+	return newPayeeService(newPayeeModel(db, logger), logger).directory(householdID)
+}
@@ -0,0 +1,145 @@
+package payees
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/payees"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// maxResolveNames caps how many names a single resolve request may contain.
+const maxResolveNames = 1000
+
+// Payee is a canonical payee.
+type Payee struct {
+	ID          int            `db:"id"`
+	HouseholdID int64          `db:"household_id"`
+	Name        string         `db:"name"`
+	Category    sql.NullString `db:"category"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+// Alias is a pattern that maps imported payee names to a canonical payee.
+type Alias struct {
+	ID          int       `db:"id"`
+	HouseholdID int64     `db:"household_id"`
+	PayeeID     int       `db:"payee_id"`
+	Pattern     string    `db:"pattern"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// PayeeRequest represents the input data for creating or updating a payee.
+type PayeeRequest struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// Validate validates the PayeeRequest struct.
+func (input *PayeeRequest) Validate() map[string]string {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Category = strings.TrimSpace(input.Category)
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Name": validate.Rules(
+			validate.Required,
+			validate.Max(100),
+			validate.ErrorMessage("Name is required and must be at most 100 characters long"),
+		),
+		"Category": validate.Rules(
+			validate.Max(100),
+			validate.ErrorMessage("Category must be at most 100 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	return validate.Validate(*input, validationFields)
+}
+
+// toPayee converts a validated PayeeRequest into a Payee of the household.
+func (input *PayeeRequest) toPayee(householdID int64) *Payee {
+	return &Payee{
+		HouseholdID: householdID,
+		Name:        input.Name,
+		Category:    sql.NullString{String: input.Category, Valid: input.Category != ""},
+	}
+}
+
+// AliasRequest represents the input data for adding an alias pattern to a payee.
+type AliasRequest struct {
+	Pattern string `json:"pattern"`
+}
+
+// Validate validates the AliasRequest struct.
+func (input *AliasRequest) Validate() map[string]string {
+	input.Pattern = strings.TrimSpace(input.Pattern)
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Pattern": validate.Rules(
+			validate.Required,
+			validate.Max(100),
+			validate.ErrorMessage("Pattern is required and must be at most 100 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if _, ok := errors["Pattern"]; !ok {
+		if _, err := payees.CompilePattern(input.Pattern); err != nil {
+			errors["Pattern"] = err.Error()
+		}
+	}
+	return errors
+}
+
+// MergeRequest represents the payee another payee should be merged into.
+type MergeRequest struct {
+	Into int `json:"into"`
+}
+
+// ResolveRequest represents raw payee names, e.g. from an import, to resolve.
+type ResolveRequest struct {
+	Names []string `json:"names"`
+}
+
+// ResolveResponse represents how a raw payee name resolved.
+type ResolveResponse struct {
+	Raw      string `json:"raw"`
+	Name     string `json:"name"`
+	PayeeID  int    `json:"payee_id,omitempty"`
+	Category string `json:"category,omitempty"`
+	Pattern  string `json:"pattern,omitempty"`
+}
+
+// AliasResponse represents the alias data to return in responses.
+type AliasResponse struct {
+	ID      int    `json:"id"`
+	Pattern string `json:"pattern"`
+}
+
+// PayeeResponse represents the payee data to return in responses.
+type PayeeResponse struct {
+	ID          int              `json:"id"`
+	HouseholdID int64            `json:"household_id"`
+	Name        string           `json:"name"`
+	Category    string           `json:"category,omitempty"`
+	Aliases     []*AliasResponse `json:"aliases"`
+}
+
+// ToResponse converts a Payee and its aliases (from database) to a PayeeResponse (for API responses).
+func (p *Payee) ToResponse(aliases []Alias) *PayeeResponse {
+	response := &PayeeResponse{
+		ID:          p.ID,
+		HouseholdID: p.HouseholdID,
+		Name:        p.Name,
+		Category:    p.Category.String,
+		Aliases:     make([]*AliasResponse, 0, len(aliases)),
+	}
+	for _, alias := range aliases {
+		response.Aliases = append(response.Aliases, &AliasResponse{ID: alias.ID, Pattern: alias.Pattern})
+	}
+	return response
+}
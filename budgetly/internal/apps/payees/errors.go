@@ -0,0 +1,12 @@
+package payees
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+	ErrPayeeNotFound  error = errors.New("payee not found")
+	ErrPayeeExists    error = errors.New("a payee with this name already exists")
+	ErrAliasNotFound  error = errors.New("payee alias not found")
+	ErrAliasExists    error = errors.New("this alias pattern is already in use")
+	ErrMergeIntoSelf  error = errors.New("a payee cannot be merged into itself")
+)
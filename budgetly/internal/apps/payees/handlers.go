@@ -0,0 +1,277 @@
+package payees
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// payeeHandler is an HTTP handler for payee directory operations
+// (e.g., creating payees, adding aliases, merging, etc.)
+type payeeHandler struct {
+	payeeService *payeeService
+	logger       *slog.Logger
+	router       *http.ServeMux
+}
+
+// newPayeeHandler creates a new payee handler with the provided payee service and logger
+func newPayeeHandler(payeeService *payeeService, logger *slog.Logger, router *http.ServeMux) *payeeHandler {
+	payeeHandler := &payeeHandler{
+		payeeService: payeeService,
+		logger:       logger,
+		router:       router,
+	}
+	payeeHandler.registerRoutes()
+	return payeeHandler
+}
+
+// Register routes for payee-related actions
+func (h *payeeHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/payees", h.create)
+	h.router.HandleFunc("GET /households/{household}/payees", h.list)
+	h.router.HandleFunc("POST /households/{household}/payees/resolve", h.resolve)
+	h.router.HandleFunc("GET /payees/{id}", h.get)
+	h.router.HandleFunc("PUT /payees/{id}", h.update)
+	h.router.HandleFunc("DELETE /payees/{id}", h.delete)
+	h.router.HandleFunc("POST /payees/{id}/aliases", h.addAlias)
+	h.router.HandleFunc("DELETE /payees/{id}/aliases/{alias}", h.deleteAlias)
+	h.router.HandleFunc("POST /payees/{id}/merge", h.merge)
+}
+
+// Create is an HTTP handler for creating a new payee of a household
+func (h *payeeHandler) create(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req PayeeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	payee, err := h.payeeService.create(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, payee)
+}
+
+// List is an HTTP handler for listing a household's payees
+func (h *payeeHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	payees, err := h.payeeService.list(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, payees)
+}
+
+// Resolve is an HTTP handler for normalizing raw payee names and matching them to a household's payees
+func (h *payeeHandler) resolve(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req ResolveRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	resolved, err := h.payeeService.resolve(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, resolved)
+}
+
+// Get is an HTTP handler for retrieving a payee by ID
+func (h *payeeHandler) get(w http.ResponseWriter, r *http.Request) {
+	payeeID, ok := h.pathID(w, r, "id", "payee")
+	if !ok {
+		return
+	}
+
+	payee, err := h.payeeService.get(payeeID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, payee)
+}
+
+// Update is an HTTP handler for renaming a payee or changing its default category
+func (h *payeeHandler) update(w http.ResponseWriter, r *http.Request) {
+	payeeID, ok := h.pathID(w, r, "id", "payee")
+	if !ok {
+		return
+	}
+
+	var req PayeeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	payee, err := h.payeeService.update(payeeID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, payee)
+}
+
+// Delete is an HTTP handler for deleting a payee
+func (h *payeeHandler) delete(w http.ResponseWriter, r *http.Request) {
+	payeeID, ok := h.pathID(w, r, "id", "payee")
+	if !ok {
+		return
+	}
+
+	if err := h.payeeService.delete(payeeID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddAlias is an HTTP handler for adding an alias pattern to a payee
+func (h *payeeHandler) addAlias(w http.ResponseWriter, r *http.Request) {
+	payeeID, ok := h.pathID(w, r, "id", "payee")
+	if !ok {
+		return
+	}
+
+	var req AliasRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	payee, err := h.payeeService.addAlias(payeeID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, payee)
+}
+
+// DeleteAlias is an HTTP handler for removing an alias from a payee
+func (h *payeeHandler) deleteAlias(w http.ResponseWriter, r *http.Request) {
+	payeeID, ok := h.pathID(w, r, "id", "payee")
+	if !ok {
+		return
+	}
+	aliasID, ok := h.pathID(w, r, "alias", "alias")
+	if !ok {
+		return
+	}
+
+	if err := h.payeeService.deleteAlias(payeeID, aliasID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Merge is an HTTP handler for merging a payee into another one
+func (h *payeeHandler) merge(w http.ResponseWriter, r *http.Request) {
+	payeeID, ok := h.pathID(w, r, "id", "payee")
+	if !ok {
+		return
+	}
+
+	var req MergeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	payee, err := h.payeeService.merge(payeeID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, payee)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *payeeHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses an ID path parameter, writing a 400 response on failure
+func (h *payeeHandler) pathID(w http.ResponseWriter, r *http.Request, param, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(param))
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid " + name + " ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// householdID parses the household ID from the URL, writing a 400 response on failure
+func (h *payeeHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *payeeHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrMergeIntoSelf):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrPayeeNotFound), errors.Is(err, ErrAliasNotFound),
+		errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrPayeeExists), errors.Is(err, ErrAliasExists):
+		utils.WriteJson(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling payee request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
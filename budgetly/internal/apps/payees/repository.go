@@ -0,0 +1,206 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package payees
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code for unique constraint violations
+const uniqueViolation = "23505"
+
+// payeeColumns lists the columns selected for a Payee
+const payeeColumns = `id, household_id, name, category, created_at`
+
+// aliasColumns lists the columns selected for an Alias
+const aliasColumns = `id, household_id, payee_id, pattern, created_at`
+
+// payeeModel wraps the database connection pool using sqlx
+type payeeModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newPayeeModel(db *sqlx.DB, logger *slog.Logger) *payeeModel {
+	return &payeeModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new payee into the database and returns its ID
+func (m *payeeModel) create(p *Payee) (int, error) {
+	query := `INSERT INTO payees (household_id, name, category, created_at)
+	VALUES (:household_id, :name, :category, :created_at)
+	RETURNING id`
+
+	p.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, p)
+	if err != nil {
+		return 0, m.translate("Error inserting payee", err, ErrPayeeExists)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&p.ID); err != nil {
+			m.logger.Error("Error scanning payee ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Payee created successfully", "id", p.ID)
+	return p.ID, nil
+}
+
+// List returns a household's payees ordered by name
+func (m *payeeModel) list(householdID int64) ([]Payee, error) {
+	payees := []Payee{}
+	query := `SELECT ` + payeeColumns + ` FROM payees WHERE household_id = $1 ORDER BY lower(name)`
+	if err := m.DB.Select(&payees, query, householdID); err != nil {
+		m.logger.Error("Error listing payees", "error", err)
+		return nil, ErrInternalServer
+	}
+	return payees, nil
+}
+
+// GetByID returns a payee by ID
+func (m *payeeModel) getByID(id int) (*Payee, error) {
+	p := &Payee{}
+	err := m.DB.Get(p, `SELECT `+payeeColumns+` FROM payees WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPayeeNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting payee by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return p, nil
+}
+
+// Update renames a payee or changes its default category
+func (m *payeeModel) update(p *Payee) error {
+	result, err := m.DB.NamedExec(`UPDATE payees SET name = :name, category = :category WHERE id = :id`, p)
+	if err != nil {
+		return m.translate("Error updating payee", err, ErrPayeeExists)
+	}
+	return m.expectRow(result, ErrPayeeNotFound)
+}
+
+// Delete removes a payee and its aliases by ID
+func (m *payeeModel) delete(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM payees WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting payee", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result, ErrPayeeNotFound)
+}
+
+// ListAliases returns the aliases of the given payees
+func (m *payeeModel) listAliases(ids []int) ([]Alias, error) {
+	aliases := []Alias{}
+	query := `SELECT ` + aliasColumns + ` FROM payee_aliases WHERE payee_id = ANY($1) ORDER BY pattern`
+	if err := m.DB.Select(&aliases, query, pq.Array(ids)); err != nil {
+		m.logger.Error("Error listing payee aliases", "error", err)
+		return nil, ErrInternalServer
+	}
+	return aliases, nil
+}
+
+// CreateAlias adds an alias pattern to a payee
+func (m *payeeModel) createAlias(a *Alias) error {
+	query := `INSERT INTO payee_aliases (household_id, payee_id, pattern, created_at)
+	VALUES ($1, $2, $3, $4) RETURNING id`
+
+	a.CreatedAt = time.Now()
+	if err := m.DB.Get(&a.ID, query, a.HouseholdID, a.PayeeID, a.Pattern, a.CreatedAt); err != nil {
+		return m.translate("Error inserting payee alias", err, ErrAliasExists)
+	}
+	return nil
+}
+
+// DeleteAlias removes one of a payee's aliases
+func (m *payeeModel) deleteAlias(payeeID, id int) error {
+	result, err := m.DB.Exec(`DELETE FROM payee_aliases WHERE payee_id = $1 AND id = $2`, payeeID, id)
+	if err != nil {
+		m.logger.Error("Error deleting payee alias", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result, ErrAliasNotFound)
+}
+
+// Merge moves source's aliases to target, keeps source's name as an alias of target so
+// that future imports resolve to it, fills in target's category from source when target
+// has none, and deletes source, in a single transaction
+func (m *payeeModel) merge(source, target *Payee) error {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting payee merge transaction", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE payee_aliases SET payee_id = $2 WHERE payee_id = $1`, source.ID, target.ID); err != nil {
+		m.logger.Error("Error moving payee aliases", "error", err)
+		return ErrInternalServer
+	}
+
+	_, err = tx.Exec(`INSERT INTO payee_aliases (household_id, payee_id, pattern, created_at) VALUES ($1, $2, $3, $4)
+	ON CONFLICT DO NOTHING`, target.HouseholdID, target.ID, source.Name, time.Now())
+	if err != nil {
+		m.logger.Error("Error keeping merged payee name as alias", "error", err)
+		return ErrInternalServer
+	}
+
+	if _, err := tx.Exec(`UPDATE payees SET category = COALESCE(category, $2) WHERE id = $1`, target.ID, source.Category); err != nil {
+		m.logger.Error("Error updating merged payee category", "error", err)
+		return ErrInternalServer
+	}
+
+	result, err := tx.Exec(`DELETE FROM payees WHERE id = $1`, source.ID)
+	if err != nil {
+		m.logger.Error("Error deleting merged payee", "error", err)
+		return ErrInternalServer
+	}
+	if err := m.expectRow(result, ErrPayeeNotFound); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing payee merge", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Payee merged successfully", "source", source.ID, "target", target.ID)
+	return nil
+}
+
+// expectRow returns notFound when a statement affected no rows
+func (m *payeeModel) expectRow(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected row count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// translate maps unique violations to exists and logs any other database error
+func (m *payeeModel) translate(message string, err error, exists error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return exists
+	}
+	m.logger.Error(message, "error", err)
+	return ErrInternalServer
+}
@@ -0,0 +1,214 @@
+package payees
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/payees"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type payeeService struct {
+	payeeRepo *payeeModel
+	logger    *slog.Logger
+}
+
+func newPayeeService(payeeRepo *payeeModel, logger *slog.Logger) *payeeService {
+	return &payeeService{
+		payeeRepo: payeeRepo,
+		logger:    logger,
+	}
+}
+
+// create validates and stores a new payee of a household
+func (s *payeeService) create(householdID int64, input PayeeRequest) (*PayeeResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Payee validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.payeeRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	payee := input.toPayee(householdID)
+	if _, err := s.payeeRepo.create(payee); err != nil {
+		return nil, err
+	}
+	return payee.ToResponse(nil), nil
+}
+
+// list returns a household's payees with their aliases
+func (s *payeeService) list(householdID int64) ([]*PayeeResponse, error) {
+	if _, err := households.Get(s.payeeRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	payees, aliases, err := s.load(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	byPayee := map[int][]Alias{}
+	for _, alias := range aliases {
+		byPayee[alias.PayeeID] = append(byPayee[alias.PayeeID], alias)
+	}
+	responses := make([]*PayeeResponse, 0, len(payees))
+	for i := range payees {
+		responses = append(responses, payees[i].ToResponse(byPayee[payees[i].ID]))
+	}
+	return responses, nil
+}
+
+// get returns a payee with its aliases
+func (s *payeeService) get(id int) (*PayeeResponse, error) {
+	payee, err := s.payeeRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := s.payeeRepo.listAliases([]int{id})
+	if err != nil {
+		return nil, err
+	}
+	return payee.ToResponse(aliases), nil
+}
+
+// update renames a payee or changes its default category
+func (s *payeeService) update(id int, input PayeeRequest) (*PayeeResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Payee validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	existing, err := s.payeeRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	payee := input.toPayee(existing.HouseholdID)
+	payee.ID = id
+	if err := s.payeeRepo.update(payee); err != nil {
+		return nil, err
+	}
+	return s.get(id)
+}
+
+// delete removes a payee by ID
+func (s *payeeService) delete(id int) error {
+	return s.payeeRepo.delete(id)
+}
+
+// addAlias validates and adds an alias pattern to a payee
+func (s *payeeService) addAlias(payeeID int, input AliasRequest) (*PayeeResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Payee alias validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	payee, err := s.payeeRepo.getByID(payeeID)
+	if err != nil {
+		return nil, err
+	}
+	alias := &Alias{HouseholdID: payee.HouseholdID, PayeeID: payeeID, Pattern: input.Pattern}
+	if err := s.payeeRepo.createAlias(alias); err != nil {
+		return nil, err
+	}
+	return s.get(payeeID)
+}
+
+// deleteAlias removes one of a payee's aliases
+func (s *payeeService) deleteAlias(payeeID, id int) error {
+	return s.payeeRepo.deleteAlias(payeeID, id)
+}
+
+// merge folds the source payee into the target payee and returns the target
+func (s *payeeService) merge(sourceID int, input MergeRequest) (*PayeeResponse, error) {
+	if sourceID == input.Into {
+		return nil, ErrMergeIntoSelf
+	}
+
+	source, err := s.payeeRepo.getByID(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.payeeRepo.getByID(input.Into)
+	if err != nil {
+		return nil, err
+	}
+	if source.HouseholdID != target.HouseholdID {
+		return nil, &validate.ValidationError{Errors: map[string]string{"Into": "Into must be a payee of the same household"}}
+	}
+	if err := s.payeeRepo.merge(source, target); err != nil {
+		return nil, err
+	}
+	return s.get(target.ID)
+}
+
+// load returns a household's payees and all of their aliases
+func (s *payeeService) load(householdID int64) ([]Payee, []Alias, error) {
+	stored, err := s.payeeRepo.list(householdID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]int, 0, len(stored))
+	for _, p := range stored {
+		ids = append(ids, p.ID)
+	}
+	aliases, err := s.payeeRepo.listAliases(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stored, aliases, nil
+}
+
+// directory loads a household's payees and aliases into a resolver
+func (s *payeeService) directory(householdID int64) (*payees.Directory, error) {
+	stored, storedAliases, err := s.load(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]payees.Payee, 0, len(stored))
+	for _, p := range stored {
+		list = append(list, payees.Payee{ID: p.ID, Name: p.Name, Category: p.Category.String})
+	}
+	aliases := make([]payees.Alias, 0, len(storedAliases))
+	for _, a := range storedAliases {
+		aliases = append(aliases, payees.Alias{PayeeID: a.PayeeID, Pattern: a.Pattern})
+	}
+
+	directory, err := payees.NewDirectory(list, aliases)
+	if err != nil {
+		s.logger.Error("Error building payee directory", "error", err)
+		return nil, ErrInternalServer
+	}
+	return directory, nil
+}
+
+// resolve normalizes raw payee names and maps them to a household's canonical payees
+func (s *payeeService) resolve(householdID int64, input ResolveRequest) ([]*ResolveResponse, error) {
+	if len(input.Names) == 0 || len(input.Names) > maxResolveNames {
+		return nil, &validate.ValidationError{Errors: map[string]string{
+			"Names": fmt.Sprintf("Between 1 and %d names are required", maxResolveNames),
+		}}
+	}
+
+	if _, err := households.Get(s.payeeRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	directory, err := s.directory(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*ResolveResponse, 0, len(input.Names))
+	for _, raw := range input.Names {
+		match := directory.Resolve(raw)
+		response := &ResolveResponse{Raw: raw, Name: match.Normalized, Pattern: match.Pattern}
+		if match.Payee != nil {
+			response.Name = match.Payee.Name
+			response.PayeeID = match.Payee.ID
+			response.Category = match.Payee.Category
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
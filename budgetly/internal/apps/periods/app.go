@@ -0,0 +1,30 @@
+package periods
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewPeriodsApp creates a new fiscal periods application with the provided database connection
+func NewPeriodsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	periodModel := newPeriodModel(db, logger)
+	periodService := newPeriodService(periodModel, logger)
+	newPeriodHandler(periodService, logger, router)
+}
+
+// Periods returns a household's budget periods overlapping from..to, for budgets and
+// reports to bucket by instead of calendar months
+func Periods(db *sqlx.DB, logger *slog.Logger, householdID int64, from, to time.Time) ([]report.Period, error) {
+	return newPeriodService(newPeriodModel(db, logger), logger).periods(householdID, from, to)
+}
+
+// CheckChange returns an error wrapping fiscal.ErrPeriodLocked when a transaction of the
+// household may not be created on current, moved from previous to current, or deleted
+// from previous because a date falls in a closed period. Either date may be zero.
+func CheckChange(db *sqlx.DB, logger *slog.Logger, householdID int64, previous, current time.Time) error {
+	return newPeriodService(newPeriodModel(db, logger), logger).checkChange(householdID, previous, current)
+}
@@ -0,0 +1,155 @@
+package periods
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/fiscal"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+)
+
+// Calendar is a household's period definition.
+type Calendar struct {
+	HouseholdID int64         `db:"household_id"`
+	Kind        fiscal.Kind   `db:"kind"`
+	StartDay    sql.NullInt32 `db:"start_day"`
+	Anchor      sql.NullTime  `db:"anchor"`
+	UpdatedAt   time.Time     `db:"updated_at"`
+}
+
+// toDefinition converts a stored calendar into the form used by the fiscal package.
+func (c *Calendar) toDefinition() fiscal.Definition {
+	return fiscal.Definition{Kind: c.Kind, StartDay: int(c.StartDay.Int32), Anchor: c.Anchor.Time}
+}
+
+// CalendarRequest represents the input data for setting a household's period definition.
+type CalendarRequest struct {
+	Kind     fiscal.Kind `json:"kind"`
+	StartDay int         `json:"start_day"`
+	Anchor   string      `json:"anchor"`
+}
+
+// Validate validates the CalendarRequest struct.
+func (input *CalendarRequest) Validate() map[string]string {
+	errors := map[string]string{}
+	if input.Anchor != "" {
+		if _, err := time.Parse(time.DateOnly, input.Anchor); err != nil {
+			errors["Anchor"] = "Anchor must be in YYYY-MM-DD format"
+			return errors
+		}
+	}
+	if err := input.toCalendar(0).toDefinition().Validate(); err != nil {
+		errors["Calendar"] = err.Error()
+	}
+	return errors
+}
+
+// toCalendar converts a CalendarRequest into a Calendar, keeping only the fields its kind uses.
+func (input *CalendarRequest) toCalendar(householdID int64) *Calendar {
+	calendar := &Calendar{HouseholdID: householdID, Kind: input.Kind}
+	switch input.Kind {
+	case fiscal.CustomStart:
+		calendar.StartDay = sql.NullInt32{Int32: int32(input.StartDay), Valid: true}
+	case fiscal.FourFourFive, fiscal.Biweekly:
+		if anchor, err := time.Parse(time.DateOnly, input.Anchor); err == nil {
+			calendar.Anchor = sql.NullTime{Time: anchor, Valid: true}
+		}
+	}
+	return calendar
+}
+
+// CalendarResponse represents a household's period definition to return in responses.
+type CalendarResponse struct {
+	HouseholdID int64       `json:"household_id"`
+	Kind        fiscal.Kind `json:"kind"`
+	StartDay    int         `json:"start_day,omitempty"`
+	Anchor      string      `json:"anchor,omitempty"`
+}
+
+// ToResponse converts a Calendar (from database) to a CalendarResponse (for API responses).
+func (c *Calendar) ToResponse() *CalendarResponse {
+	response := &CalendarResponse{HouseholdID: c.HouseholdID, Kind: c.Kind, StartDay: int(c.StartDay.Int32)}
+	if c.Anchor.Valid {
+		response.Anchor = c.Anchor.Time.Format(time.DateOnly)
+	}
+	return response
+}
+
+// ClosedPeriod is a period in which transactions can no longer be created or changed.
+type ClosedPeriod struct {
+	ID          int       `db:"id"`
+	HouseholdID int64     `db:"household_id"`
+	StartDate   time.Time `db:"start_date"`
+	// EndDate is exclusive: the first day after the period.
+	EndDate  time.Time `db:"end_date"`
+	ClosedAt time.Time `db:"closed_at"`
+}
+
+// toPeriod converts a stored closed period into a report period.
+func (p *ClosedPeriod) toPeriod() report.Period {
+	return report.Period{Start: p.StartDate, End: p.EndDate}
+}
+
+// DateRequest names the period containing a date.
+type DateRequest struct {
+	Date string `json:"date"`
+}
+
+// Validate validates the DateRequest struct.
+func (input *DateRequest) Validate() map[string]string {
+	errors := map[string]string{}
+	if _, err := time.Parse(time.DateOnly, input.Date); err != nil {
+		errors["Date"] = "Date must be in YYYY-MM-DD format"
+	}
+	return errors
+}
+
+// CheckRequest represents a transaction being created (only date), moved (both) or
+// deleted (only previous_date).
+type CheckRequest struct {
+	PreviousDate string `json:"previous_date"`
+	Date         string `json:"date"`
+}
+
+// Validate validates the CheckRequest struct.
+func (input *CheckRequest) Validate() map[string]string {
+	errors := map[string]string{}
+	if input.PreviousDate == "" && input.Date == "" {
+		errors["Date"] = "At least one of previous_date or date is required"
+	}
+	for field, value := range map[string]string{"PreviousDate": input.PreviousDate, "Date": input.Date} {
+		if _, err := time.Parse(time.DateOnly, value); value != "" && err != nil {
+			errors[field] = field + " must be in YYYY-MM-DD format"
+		}
+	}
+	return errors
+}
+
+// dates returns the validated dates, zero when absent.
+func (input *CheckRequest) dates() (previous, current time.Time) {
+	previous, _ = time.Parse(time.DateOnly, input.PreviousDate)
+	current, _ = time.Parse(time.DateOnly, input.Date)
+	return previous, current
+}
+
+// PeriodResponse represents a budget period to return in responses.
+type PeriodResponse struct {
+	Start string `json:"start"`
+	// End is the last day of the period.
+	End      string     `json:"end"`
+	Closed   bool       `json:"closed"`
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+}
+
+// newPeriodResponse builds a PeriodResponse, marking it closed when closed is not nil.
+func newPeriodResponse(period report.Period, closed *ClosedPeriod) *PeriodResponse {
+	response := &PeriodResponse{
+		Start: period.Start.Format(time.DateOnly),
+		End:   period.End.AddDate(0, 0, -1).Format(time.DateOnly),
+	}
+	if closed != nil {
+		response.Closed = true
+		response.ClosedAt = &closed.ClosedAt
+	}
+	return response
+}
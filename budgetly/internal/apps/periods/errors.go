@@ -0,0 +1,8 @@
+package periods
+
+import "errors"
+
+var (
+	ErrInternalServer  error = errors.New("internal server error")
+	ErrPeriodNotClosed error = errors.New("the period is not closed")
+)
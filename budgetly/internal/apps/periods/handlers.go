@@ -0,0 +1,266 @@
+package periods
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/fiscal"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// periodHandler is an HTTP handler for fiscal period operations
+// (e.g., setting a household's calendar, listing periods, closing periods, etc.)
+type periodHandler struct {
+	periodService *periodService
+	logger        *slog.Logger
+	router        *http.ServeMux
+}
+
+// newPeriodHandler creates a new period handler with the provided period service and logger
+func newPeriodHandler(periodService *periodService, logger *slog.Logger, router *http.ServeMux) *periodHandler {
+	periodHandler := &periodHandler{
+		periodService: periodService,
+		logger:        logger,
+		router:        router,
+	}
+	periodHandler.registerRoutes()
+	return periodHandler
+}
+
+// Register routes for period-related actions
+func (h *periodHandler) registerRoutes() {
+	h.router.HandleFunc("GET /households/{household}/calendar", h.getCalendar)
+	h.router.HandleFunc("PUT /households/{household}/calendar", h.setCalendar)
+	h.router.HandleFunc("GET /households/{household}/periods", h.list)
+	h.router.HandleFunc("GET /households/{household}/periods/current", h.current)
+	h.router.HandleFunc("POST /households/{household}/periods/close", h.close)
+	h.router.HandleFunc("POST /households/{household}/periods/reopen", h.reopen)
+	h.router.HandleFunc("POST /households/{household}/periods/check", h.check)
+}
+
+// GetCalendar is an HTTP handler for retrieving a household's period definition
+func (h *periodHandler) getCalendar(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.periodService.getCalendar(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, calendar)
+}
+
+// SetCalendar is an HTTP handler for setting a household's period definition
+func (h *periodHandler) setCalendar(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CalendarRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	calendar, err := h.periodService.setCalendar(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, calendar)
+}
+
+// List is an HTTP handler for the household's periods between from (default a year ago)
+// and to (default today)
+func (h *periodHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	errs := map[string]string{}
+	query := r.URL.Query()
+	from, to := queryDate(query, "from", errs), queryDate(query, "to", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+	if !to.Valid {
+		to.Time = today()
+	}
+	if !from.Valid {
+		from.Time = to.Time.AddDate(-1, 0, 0)
+	}
+
+	periods, err := h.periodService.list(householdID, from.Time, to.Time)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, periods)
+}
+
+// Current is an HTTP handler for the household's period containing a date (default today)
+func (h *periodHandler) current(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	errs := map[string]string{}
+	date := queryDate(r.URL.Query(), "date", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+	if !date.Valid {
+		date.Time = today()
+	}
+
+	period, err := h.periodService.current(householdID, date.Time)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, period)
+}
+
+// Close is an HTTP handler for closing the household's period containing a date
+func (h *periodHandler) close(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req DateRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	period, err := h.periodService.close(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, period)
+}
+
+// Reopen is an HTTP handler for reopening the household's closed period containing a date
+func (h *periodHandler) reopen(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req DateRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := h.periodService.reopen(householdID, req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Check is an HTTP handler for checking whether a transaction may be created, moved or
+// deleted, answering 409 when a date falls in a closed period
+func (h *periodHandler) check(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CheckRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := h.periodService.check(householdID, req); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *periodHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *periodHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// today returns the current UTC date
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// queryDate parses an optional YYYY-MM-DD query parameter, recording an error when it is invalid
+func queryDate(query url.Values, name string, errs map[string]string) sql.NullTime {
+	value := query.Get(name)
+	if value == "" {
+		return sql.NullTime{}
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		errs[name] = name + " must be in YYYY-MM-DD format"
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: date, Valid: true}
+}
+
+// writeError maps service errors to HTTP responses
+func (h *periodHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrPeriodNotClosed), errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, fiscal.ErrPeriodLocked):
+		utils.WriteJson(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling period request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
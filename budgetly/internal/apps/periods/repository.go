@@ -0,0 +1,105 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package periods
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// calendarColumns lists the columns selected for a Calendar
+const calendarColumns = `household_id, kind, start_day, anchor, updated_at`
+
+// closedColumns lists the columns selected for a ClosedPeriod
+const closedColumns = `id, household_id, start_date, end_date, closed_at`
+
+// periodModel wraps the database connection pool using sqlx
+type periodModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newPeriodModel(db *sqlx.DB, logger *slog.Logger) *periodModel {
+	return &periodModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// GetCalendar returns a household's period definition, or nil when it has not set one
+func (m *periodModel) getCalendar(householdID int64) (*Calendar, error) {
+	c := &Calendar{}
+	err := m.DB.Get(c, `SELECT `+calendarColumns+` FROM fiscal_calendars WHERE household_id = $1`, householdID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		m.logger.Error("Error getting fiscal calendar", "error", err)
+		return nil, ErrInternalServer
+	}
+	return c, nil
+}
+
+// UpsertCalendar stores a household's period definition, replacing any existing one
+func (m *periodModel) upsertCalendar(c *Calendar) error {
+	query := `INSERT INTO fiscal_calendars (household_id, kind, start_day, anchor, updated_at)
+	VALUES (:household_id, :kind, :start_day, :anchor, :updated_at)
+	ON CONFLICT (household_id) DO UPDATE
+	SET kind = EXCLUDED.kind, start_day = EXCLUDED.start_day, anchor = EXCLUDED.anchor, updated_at = EXCLUDED.updated_at`
+
+	c.UpdatedAt = time.Now()
+
+	if _, err := m.DB.NamedExec(query, c); err != nil {
+		m.logger.Error("Error storing fiscal calendar", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Fiscal calendar stored successfully", "household_id", c.HouseholdID)
+	return nil
+}
+
+// ListClosed returns a household's closed periods in date order
+func (m *periodModel) listClosed(householdID int64) ([]ClosedPeriod, error) {
+	closed := []ClosedPeriod{}
+	query := `SELECT ` + closedColumns + ` FROM closed_periods WHERE household_id = $1 ORDER BY start_date`
+	if err := m.DB.Select(&closed, query, householdID); err != nil {
+		m.logger.Error("Error listing closed periods", "error", err)
+		return nil, ErrInternalServer
+	}
+	return closed, nil
+}
+
+// Close records a period as closed. Closing an already closed period keeps its original closing time.
+func (m *periodModel) close(p *ClosedPeriod) error {
+	query := `INSERT INTO closed_periods (household_id, start_date, end_date, closed_at)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (household_id, start_date) DO UPDATE SET end_date = EXCLUDED.end_date
+	RETURNING id, closed_at`
+
+	if err := m.DB.QueryRowx(query, p.HouseholdID, p.StartDate, p.EndDate, time.Now()).Scan(&p.ID, &p.ClosedAt); err != nil {
+		m.logger.Error("Error closing period", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Period closed successfully", "household_id", p.HouseholdID, "start", p.StartDate)
+	return nil
+}
+
+// Reopen deletes the closed periods containing a date
+func (m *periodModel) reopen(householdID int64, date time.Time) error {
+	result, err := m.DB.Exec(`DELETE FROM closed_periods WHERE household_id = $1 AND start_date <= $2 AND end_date > $2`, householdID, date)
+	if err != nil {
+		m.logger.Error("Error reopening period", "error", err)
+		return ErrInternalServer
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrPeriodNotClosed
+	}
+
+	m.logger.Debug("Period reopened successfully", "household_id", householdID, "date", date)
+	return nil
+}
@@ -0,0 +1,185 @@
+package periods
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/fiscal"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type periodService struct {
+	periodRepo *periodModel
+	logger     *slog.Logger
+}
+
+func newPeriodService(periodRepo *periodModel, logger *slog.Logger) *periodService {
+	return &periodService{
+		periodRepo: periodRepo,
+		logger:     logger,
+	}
+}
+
+// getCalendar returns a household's period definition, which is calendar months until it sets one
+func (s *periodService) getCalendar(householdID int64) (*CalendarResponse, error) {
+	calendar, err := s.calendar(householdID)
+	if err != nil {
+		return nil, err
+	}
+	return calendar.ToResponse(), nil
+}
+
+// setCalendar validates and stores a household's period definition. Closed periods keep
+// their dates when the definition changes.
+func (s *periodService) setCalendar(householdID int64, input CalendarRequest) (*CalendarResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Fiscal calendar validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	if _, err := households.Get(s.periodRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	calendar := input.toCalendar(householdID)
+	if err := s.periodRepo.upsertCalendar(calendar); err != nil {
+		return nil, err
+	}
+	return calendar.ToResponse(), nil
+}
+
+// list returns the household's periods overlapping from..to with their closed state
+func (s *periodService) list(householdID int64, from, to time.Time) ([]*PeriodResponse, error) {
+	periods, err := s.periods(householdID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := s.periodRepo.listClosed(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*PeriodResponse, 0, len(periods))
+	for _, period := range periods {
+		responses = append(responses, newPeriodResponse(period, findClosed(closed, period.Start)))
+	}
+	return responses, nil
+}
+
+// current returns the household's period containing a date
+func (s *periodService) current(householdID int64, date time.Time) (*PeriodResponse, error) {
+	calendar, err := s.calendar(householdID)
+	if err != nil {
+		return nil, err
+	}
+	closed, err := s.periodRepo.listClosed(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	period := calendar.toDefinition().PeriodOf(date)
+	return newPeriodResponse(period, findClosed(closed, period.Start)), nil
+}
+
+// close closes the household's period containing a date
+func (s *periodService) close(householdID int64, input DateRequest) (*PeriodResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	date, _ := time.Parse(time.DateOnly, input.Date)
+
+	calendar, err := s.calendar(householdID)
+	if err != nil {
+		return nil, err
+	}
+	period := calendar.toDefinition().PeriodOf(date)
+
+	closed := &ClosedPeriod{HouseholdID: householdID, StartDate: period.Start, EndDate: period.End}
+	if err := s.periodRepo.close(closed); err != nil {
+		return nil, err
+	}
+	return newPeriodResponse(period, closed), nil
+}
+
+// reopen reopens the household's closed periods containing a date
+func (s *periodService) reopen(householdID int64, input DateRequest) error {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		return &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.periodRepo.DB, s.logger, householdID); err != nil {
+		return err
+	}
+	date, _ := time.Parse(time.DateOnly, input.Date)
+	return s.periodRepo.reopen(householdID, date)
+}
+
+// check validates a transaction change and reports whether it touches a closed period
+func (s *periodService) check(householdID int64, input CheckRequest) error {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		return &validate.ValidationError{Errors: validationErrors}
+	}
+	previous, current := input.dates()
+	return s.checkChange(householdID, previous, current)
+}
+
+// checkChange returns fiscal.ErrPeriodLocked when either date falls in a closed period
+func (s *periodService) checkChange(householdID int64, previous, current time.Time) error {
+	if _, err := households.Get(s.periodRepo.DB, s.logger, householdID); err != nil {
+		return err
+	}
+	stored, err := s.periodRepo.listClosed(householdID)
+	if err != nil {
+		return err
+	}
+	closed := make([]report.Period, 0, len(stored))
+	for i := range stored {
+		closed = append(closed, stored[i].toPeriod())
+	}
+
+	if err := fiscal.CheckChange(previous, current, closed); err != nil {
+		s.logger.Warn("Change to a closed period rejected", "household_id", householdID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// periods returns the household's periods overlapping from..to
+func (s *periodService) periods(householdID int64, from, to time.Time) ([]report.Period, error) {
+	calendar, err := s.calendar(householdID)
+	if err != nil {
+		return nil, err
+	}
+	periods, err := calendar.toDefinition().Periods(from, to)
+	if errors.Is(err, report.ErrInvalidRange) {
+		return nil, &validate.ValidationError{Errors: map[string]string{"to": err.Error()}}
+	}
+	return periods, err
+}
+
+// calendar returns the household's stored calendar, or calendar months when it has none
+func (s *periodService) calendar(householdID int64) (*Calendar, error) {
+	if _, err := households.Get(s.periodRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	calendar, err := s.periodRepo.getCalendar(householdID)
+	if err != nil {
+		return nil, err
+	}
+	if calendar == nil {
+		calendar = &Calendar{HouseholdID: householdID, Kind: fiscal.DefaultDefinition().Kind}
+	}
+	return calendar, nil
+}
+
+// findClosed returns the closed period containing start, or nil. Closed periods keep their
+// dates when the definition changes, so they need not line up with the current periods.
+func findClosed(closed []ClosedPeriod, start time.Time) *ClosedPeriod {
+	for i := range closed {
+		if closed[i].toPeriod().Contains(start) {
+			return &closed[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package rates
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewRatesApp creates a new exchange rates application with the provided database connection
+func NewRatesApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	rateModel := newRateModel(db, logger)
+	rateService := newRateService(rateModel, logger)
+	newRateHandler(rateService, logger, router)
+}
+
+// ImportRates stores already parsed exchange rates, replacing existing rates for the same date and pair
+func ImportRates(db *sqlx.DB, logger *slog.Logger, rates []fx.Rate) error {
+	rateModel := newRateModel(db, logger)
+	rateService := newRateService(rateModel, logger)
+	return rateService.store(rates)
+}
+
+// LoadTable loads the stored exchange rates between the currencies, directly or through
+// EUR, that are in effect from..to into a rate source for converting amounts, such as
+// report totals, on those dates
+func LoadTable(db *sqlx.DB, logger *slog.Logger, currencies []string, from, to time.Time) (*fx.Table, error) {
+	return newRateService(newRateModel(db, logger), logger).table(currencies, from, to)
+}
@@ -0,0 +1,102 @@
+package rates
+
+import (
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type ExchangeRate struct {
+	Date      time.Time `db:"date"`
+	Base      string    `db:"base"`
+	Quote     string    `db:"quote"`
+	Rate      string    `db:"rate"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// RateRequest represents the input data for entering an exchange rate manually.
+type RateRequest struct {
+	Date  string `json:"date"`
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	Rate  string `json:"rate"`
+}
+
+// Validate validates the RateRequest struct.
+func (input *RateRequest) Validate() map[string]string {
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Date": validate.Rules(
+			validate.Required,
+			validate.ErrorMessage("Date is required in YYYY-MM-DD format"),
+		),
+		"Base": validate.Rules(
+			validate.Required,
+			validate.Min(3),
+			validate.Max(3),
+			validate.ErrorMessage("Base must be a 3-letter ISO 4217 currency code"),
+		),
+		"Quote": validate.Rules(
+			validate.Required,
+			validate.Min(3),
+			validate.Max(3),
+			validate.ErrorMessage("Quote must be a 3-letter ISO 4217 currency code"),
+		),
+		"Rate": validate.Rules(
+			validate.Required,
+			validate.ErrorMessage("Rate is required"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	return validate.Validate(*input, validationFields)
+}
+
+// RateResponse represents the exchange rate data to return in responses.
+type RateResponse struct {
+	Date  string `json:"date"`
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	Rate  string `json:"rate"`
+}
+
+// ToResponse converts an ExchangeRate (from database) to a RateResponse (for API responses).
+func (r *ExchangeRate) ToResponse() *RateResponse {
+	return &RateResponse{
+		Date:  r.Date.Format(time.DateOnly),
+		Base:  r.Base,
+		Quote: r.Quote,
+		Rate:  r.Rate,
+	}
+}
+
+// toRate converts an ExchangeRate (from database) into an fx.Rate.
+func (r *ExchangeRate) toRate() (fx.Rate, error) {
+	return fx.NewRate(r.Date, r.Base, r.Quote, r.Rate)
+}
+
+// fromRate converts an fx.Rate into an ExchangeRate for storage.
+func fromRate(rate fx.Rate) ExchangeRate {
+	return ExchangeRate{
+		Date:  rate.Date,
+		Base:  rate.Base,
+		Quote: rate.Quote,
+		Rate:  rate.Rate.FloatString(10),
+	}
+}
+
+// UploadResponse reports how many rates a bulk upload stored.
+type UploadResponse struct {
+	Imported int `json:"imported"`
+}
+
+// ConversionResponse represents the result of converting an amount between currencies.
+// Amounts are expressed in minor units of their currency.
+type ConversionResponse struct {
+	Amount    int64  `json:"amount"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Date      string `json:"date"`
+	Converted int64  `json:"converted"`
+}
@@ -0,0 +1,8 @@
+package rates
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+	ErrRateNotFound   error = errors.New("exchange rate not found")
+)
@@ -0,0 +1,171 @@
+package rates
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// maxUploadSize limits the size of bulk exchange rate uploads
+const maxUploadSize = 10 << 20
+
+// rateHandler is an HTTP handler for exchange rate operations
+// (e.g., manual entry, bulk upload, conversion, etc.)
+type rateHandler struct {
+	rateService *rateService
+	logger      *slog.Logger
+	router      *http.ServeMux
+}
+
+// newRateHandler creates a new rate handler with the provided rate service and logger
+func newRateHandler(rateService *rateService, logger *slog.Logger, router *http.ServeMux) *rateHandler {
+	rateHandler := &rateHandler{
+		rateService: rateService,
+		logger:      logger,
+		router:      router,
+	}
+	rateHandler.registerRoutes()
+	return rateHandler
+}
+
+// Register routes for exchange rate actions
+func (h *rateHandler) registerRoutes() {
+	h.router.HandleFunc("POST /rates", h.create)
+	h.router.HandleFunc("POST /rates/upload", h.upload)
+	h.router.HandleFunc("GET /rates", h.list)
+	h.router.HandleFunc("GET /rates/convert", h.convert)
+}
+
+// Create is an HTTP handler for entering an exchange rate manually
+func (h *rateHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req RateRequest
+
+	// Decode the JSON request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return
+	}
+
+	rate, err := h.rateService.create(req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, rate)
+}
+
+// Upload is an HTTP handler for bulk uploading exchange rates as ECB XML or CSV,
+// either as a multipart "file" field or as the raw request body
+func (h *rateHandler) upload(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		formFile, _, err := r.FormFile("file")
+		if err != nil {
+			h.logger.Warn("Invalid upload", "error", err)
+			utils.WriteJson(
+				w,
+				http.StatusBadRequest,
+				map[string]string{"error": "A rate file is required in the \"file\" field"},
+			)
+			return
+		}
+		defer formFile.Close()
+		file = formFile
+	}
+
+	result, err := h.rateService.upload(file)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, result)
+}
+
+// List is an HTTP handler for listing exchange rates, optionally filtered by base and quote
+func (h *rateHandler) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	rates, err := h.rateService.list(query.Get("base"), query.Get("quote"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, rates)
+}
+
+// Convert is an HTTP handler for converting an amount in minor units between currencies
+func (h *rateHandler) convert(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	amount, err := strconv.ParseInt(query.Get("amount"), 10, 64)
+	if err != nil {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid amount, expected an integer in minor units"},
+		)
+		return
+	}
+
+	date := time.Now()
+	if value := query.Get("date"); value != "" {
+		date, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			utils.WriteJson(
+				w,
+				http.StatusBadRequest,
+				map[string]string{"error": "Invalid date, expected YYYY-MM-DD"},
+			)
+			return
+		}
+	}
+
+	from, to := query.Get("from"), query.Get("to")
+	if len(from) != 3 || len(to) != 3 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Both from and to must be 3-letter currency codes"},
+		)
+		return
+	}
+
+	result, err := h.rateService.convert(amount, from, to, date)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, result)
+}
+
+// writeError maps service errors to HTTP responses
+func (h *rateHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrRateNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling exchange rate request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,111 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package rates
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// rateModel wraps the database connection pool using sqlx
+type rateModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newRateModel(db *sqlx.DB, logger *slog.Logger) *rateModel {
+	return &rateModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Upsert inserts the rates in a single transaction, replacing any existing rate
+// for the same date and currency pair
+func (m *rateModel) upsert(rates []ExchangeRate) error {
+	query := `INSERT INTO exchange_rates (date, base, quote, rate, created_at)
+	VALUES (:date, :base, :quote, :rate, :created_at)
+	ON CONFLICT (date, base, quote) DO UPDATE SET rate = EXCLUDED.rate`
+
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting exchange rate transaction", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for i := range rates {
+		rates[i].CreatedAt = now
+		if _, err := tx.NamedExec(query, rates[i]); err != nil {
+			m.logger.Error("Error upserting exchange rate", "error", err)
+			return ErrInternalServer
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing exchange rates", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Exchange rates stored successfully", "count", len(rates))
+	return nil
+}
+
+// List returns the rates for a currency pair, or all rates when the pair is empty, newest first
+func (m *rateModel) list(base, quote string) ([]ExchangeRate, error) {
+	query := `SELECT date, base, quote, rate, created_at
+	FROM exchange_rates
+	WHERE ($1 = '' OR base = $1) AND ($2 = '' OR quote = $2)
+	ORDER BY date DESC, base, quote`
+
+	rates := []ExchangeRate{}
+	if err := m.DB.Select(&rates, query, base, quote); err != nil {
+		m.logger.Error("Error listing exchange rates", "error", err)
+		return nil, ErrInternalServer
+	}
+	return rates, nil
+}
+
+// Between returns the rates among the currencies needed for lookups from..to: each
+// pair's latest rate on or before from and every rate after it up to to
+func (m *rateModel) between(currencies []string, from, to time.Time) ([]ExchangeRate, error) {
+	query := `SELECT r.date, r.base, r.quote, r.rate, r.created_at
+	FROM exchange_rates r
+	WHERE r.base = ANY($1) AND r.quote = ANY($1) AND r.date <= $3
+		AND r.date >= COALESCE((SELECT max(p.date) FROM exchange_rates p
+			WHERE p.base = r.base AND p.quote = r.quote AND p.date <= $2), '-infinity')
+	ORDER BY r.date, r.base, r.quote`
+
+	rates := []ExchangeRate{}
+	if err := m.DB.Select(&rates, query, pq.Array(currencies), from, to); err != nil {
+		m.logger.Error("Error listing exchange rates between dates", "error", err)
+		return nil, ErrInternalServer
+	}
+	return rates, nil
+}
+
+// Latest returns the most recent rate for a currency pair on or before the date
+func (m *rateModel) latest(base, quote string, date time.Time) (*ExchangeRate, error) {
+	query := `SELECT date, base, quote, rate, created_at
+	FROM exchange_rates
+	WHERE base = $1 AND quote = $2 AND date <= $3
+	ORDER BY date DESC
+	LIMIT 1`
+
+	rate := &ExchangeRate{}
+	err := m.DB.Get(rate, query, base, quote, date)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRateNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting latest exchange rate", "error", err)
+		return nil, ErrInternalServer
+	}
+	return rate, nil
+}
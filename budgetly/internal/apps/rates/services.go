@@ -0,0 +1,161 @@
+package rates
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type rateService struct {
+	rateRepo *rateModel
+	logger   *slog.Logger
+}
+
+func newRateService(rateRepo *rateModel, logger *slog.Logger) *rateService {
+	return &rateService{
+		rateRepo: rateRepo,
+		logger:   logger,
+	}
+}
+
+// create validates and stores a manually entered exchange rate
+func (s *rateService) create(input RateRequest) (*RateResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Exchange rate validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	date, err := time.Parse(time.DateOnly, input.Date)
+	if err != nil {
+		return nil, &validate.ValidationError{Errors: map[string]string{"Date": "Date must be in YYYY-MM-DD format"}}
+	}
+
+	rate, err := fx.NewRate(date, input.Base, input.Quote, input.Rate)
+	if err != nil {
+		return nil, &validate.ValidationError{Errors: map[string]string{"Rate": err.Error()}}
+	}
+
+	stored := fromRate(rate)
+	if err := s.rateRepo.upsert([]ExchangeRate{stored}); err != nil {
+		return nil, err
+	}
+	return stored.ToResponse(), nil
+}
+
+// upload parses an ECB XML or CSV file of exchange rates and stores them all
+func (s *rateService) upload(r io.Reader) (*UploadResponse, error) {
+	parsed, err := fx.Parse(r)
+	if err != nil {
+		s.logger.Warn("Exchange rate upload rejected", "error", err)
+		return nil, &validate.ValidationError{Errors: map[string]string{"file": err.Error()}}
+	}
+
+	if err := s.store(parsed); err != nil {
+		return nil, err
+	}
+	return &UploadResponse{Imported: len(parsed)}, nil
+}
+
+// store saves parsed rates, replacing existing rates for the same date and pair
+func (s *rateService) store(parsed []fx.Rate) error {
+	rates := make([]ExchangeRate, 0, len(parsed))
+	for _, rate := range parsed {
+		rates = append(rates, fromRate(rate))
+	}
+	return s.rateRepo.upsert(rates)
+}
+
+// list returns the stored rates, optionally filtered by currency pair
+func (s *rateService) list(base, quote string) ([]*RateResponse, error) {
+	rates, err := s.rateRepo.list(strings.ToUpper(base), strings.ToUpper(quote))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*RateResponse, 0, len(rates))
+	for i := range rates {
+		responses = append(responses, rates[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// convert converts an amount in minor units between currencies at the rate in effect on the date
+func (s *rateService) convert(amount int64, from, to string, date time.Time) (*ConversionResponse, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+
+	table, err := s.rateTable(from, to, date)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := fx.Convert(table, amount, from, to, date)
+	if errors.Is(err, fx.ErrRateNotFound) {
+		return nil, ErrRateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConversionResponse{
+		Amount:    amount,
+		From:      from,
+		To:        to,
+		Date:      date.Format(time.DateOnly),
+		Converted: converted,
+	}, nil
+}
+
+// rateTable loads the latest rates for a pair in both directions into an fx.Table
+func (s *rateService) rateTable(from, to string, date time.Time) (*fx.Table, error) {
+	table := fx.NewTable()
+	for _, pair := range [][2]string{{from, to}, {to, from}} {
+		stored, err := s.rateRepo.latest(pair[0], pair[1], date)
+		if errors.Is(err, ErrRateNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rate, err := stored.toRate()
+		if err != nil {
+			s.logger.Error("Invalid stored exchange rate", "error", err)
+			return nil, fmt.Errorf("%w: %v", ErrInternalServer, err)
+		}
+		table.Add(rate)
+	}
+	return table, nil
+}
+
+// table loads the rates between the currencies, and against EUR for cross rates, that
+// are in effect from..to into an fx.Table, for converting many amounts at once
+func (s *rateService) table(currencies []string, from, to time.Time) (*fx.Table, error) {
+	wanted := []string{fx.ECBBase}
+	for _, currency := range currencies {
+		if currency = strings.ToUpper(currency); currency != fx.ECBBase {
+			wanted = append(wanted, currency)
+		}
+	}
+
+	stored, err := s.rateRepo.between(wanted, fx.Day(from), fx.Day(to))
+	if err != nil {
+		return nil, err
+	}
+
+	table := fx.NewTable()
+	for i := range stored {
+		rate, err := stored[i].toRate()
+		if err != nil {
+			s.logger.Error("Invalid stored exchange rate", "error", err)
+			return nil, fmt.Errorf("%w: %v", ErrInternalServer, err)
+		}
+		table.Add(rate)
+	}
+	return table, nil
+}
@@ -0,0 +1,15 @@
+package reconciliations
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewReconciliationsApp creates a new reconciliations application with the provided database connection
+func NewReconciliationsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	reconciliationModel := newReconciliationModel(db, logger)
+	reconciliationService := newReconciliationService(reconciliationModel, logger)
+	newReconciliationHandler(reconciliationService, logger, router)
+}
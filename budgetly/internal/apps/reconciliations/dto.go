@@ -0,0 +1,113 @@
+package reconciliations
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/reconcile"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// adjustmentPayee is the payee of the transactions posting a leftover difference.
+const adjustmentPayee = "Reconciliation adjustment"
+
+// Reconciliation is a session reconciling an account against one bank statement. It is
+// open until FinishedAt is set. The cleared state it toggles is stored on the
+// transactions themselves, so an open session survives restarts.
+type Reconciliation struct {
+	ID               int64         `db:"id"`
+	AccountID        int64         `db:"account_id"`
+	StatementDate    time.Time     `db:"statement_date"`
+	StatementBalance money.Money   `db:"statement_balance"`
+	AdjustmentID     sql.NullInt64 `db:"adjustment_id"`
+	CreatedAt        time.Time     `db:"created_at"`
+	FinishedAt       sql.NullTime  `db:"finished_at"`
+}
+
+// Transaction is the subset of an account's transaction a reconciliation works on.
+type Transaction struct {
+	ID      int64       `db:"id"`
+	Date    time.Time   `db:"date"`
+	Payee   string      `db:"payee"`
+	Amount  money.Money `db:"amount"`
+	Cleared bool        `db:"cleared"`
+}
+
+// toReconcile converts a stored transaction into the form used by the reconcile package.
+func (t *Transaction) toReconcile() reconcile.Transaction {
+	status := reconcile.StatusUncleared
+	if t.Cleared {
+		status = reconcile.StatusCleared
+	}
+	return reconcile.Transaction{
+		ID:     strconv.FormatInt(t.ID, 10),
+		Date:   t.Date,
+		Payee:  t.Payee,
+		Amount: t.Amount,
+		Status: status,
+	}
+}
+
+// ReconciliationRequest represents the input data for starting a reconciliation.
+type ReconciliationRequest struct {
+	StatementDate    string      `json:"statement_date"`
+	StatementBalance money.Money `json:"statement_balance"`
+}
+
+// Validate validates the ReconciliationRequest struct.
+func (input *ReconciliationRequest) Validate() map[string]string {
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"StatementBalance": validate.Rules(
+			money.Valid,
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if _, err := time.Parse(time.DateOnly, input.StatementDate); err != nil {
+		errors["StatementDate"] = "StatementDate is required and must be in YYYY-MM-DD format"
+	}
+	return errors
+}
+
+// FinishRequest represents how to finish a reconciliation: a leftover difference is
+// rejected unless Adjust posts it as an adjustment transaction.
+type FinishRequest struct {
+	Adjust bool `json:"adjust"`
+}
+
+// ReconciliationResponse represents a reconciliation to return in responses. The
+// balances and transactions are only set while it is open.
+type ReconciliationResponse struct {
+	ID               int64                   `json:"id"`
+	AccountID        int64                   `json:"account_id"`
+	StatementDate    string                  `json:"statement_date"`
+	StatementBalance money.Money             `json:"statement_balance"`
+	ClearedBalance   money.Money             `json:"cleared_balance"`
+	Difference       money.Money             `json:"difference"`
+	Transactions     []reconcile.Transaction `json:"transactions,omitempty"`
+	Finished         bool                    `json:"finished"`
+	FinishedAt       *time.Time              `json:"finished_at,omitempty"`
+	AdjustmentID     int64                   `json:"adjustment_id,omitempty"`
+	// Reconciled holds the IDs of the transactions locked when the reconciliation finished.
+	Reconciled []string `json:"reconciled,omitempty"`
+}
+
+// ToResponse converts a Reconciliation (from database) to a ReconciliationResponse (for API responses).
+func (r *Reconciliation) ToResponse() *ReconciliationResponse {
+	response := &ReconciliationResponse{
+		ID:               r.ID,
+		AccountID:        r.AccountID,
+		StatementDate:    r.StatementDate.Format(time.DateOnly),
+		StatementBalance: r.StatementBalance,
+		Finished:         r.FinishedAt.Valid,
+		AdjustmentID:     r.AdjustmentID.Int64,
+	}
+	if r.FinishedAt.Valid {
+		response.FinishedAt = &r.FinishedAt.Time
+	}
+	return response
+}
@@ -0,0 +1,9 @@
+package reconciliations
+
+import "errors"
+
+var (
+	ErrInternalServer         error = errors.New("internal server error")
+	ErrReconciliationNotFound error = errors.New("reconciliation not found")
+	ErrReconciliationOpen     error = errors.New("the account already has an open reconciliation")
+)
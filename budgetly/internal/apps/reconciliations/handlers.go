@@ -0,0 +1,203 @@
+package reconciliations
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
+	"github.com/ZiadMansourM/budgetly/pkg/fiscal"
+	"github.com/ZiadMansourM/budgetly/pkg/reconcile"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// reconciliationHandler is an HTTP handler for reconciliation operations
+// (e.g., starting a reconciliation, toggling cleared transactions, finishing, etc.)
+type reconciliationHandler struct {
+	reconciliationService *reconciliationService
+	logger                *slog.Logger
+	router                *http.ServeMux
+}
+
+// newReconciliationHandler creates a new reconciliation handler with the provided reconciliation service and logger
+func newReconciliationHandler(reconciliationService *reconciliationService, logger *slog.Logger, router *http.ServeMux) *reconciliationHandler {
+	reconciliationHandler := &reconciliationHandler{
+		reconciliationService: reconciliationService,
+		logger:                logger,
+		router:                router,
+	}
+	reconciliationHandler.registerRoutes()
+	return reconciliationHandler
+}
+
+// Register routes for reconciliation-related actions
+func (h *reconciliationHandler) registerRoutes() {
+	h.router.HandleFunc("POST /accounts/{id}/reconciliations", h.start)
+	h.router.HandleFunc("GET /accounts/{id}/reconciliations", h.list)
+	h.router.HandleFunc("GET /reconciliations/{id}", h.get)
+	h.router.HandleFunc("POST /reconciliations/{id}/transactions/{transaction}/toggle", h.toggle)
+	h.router.HandleFunc("POST /reconciliations/{id}/finish", h.finish)
+	h.router.HandleFunc("DELETE /reconciliations/{id}", h.delete)
+}
+
+// Start is an HTTP handler for reconciling an account against a statement
+func (h *reconciliationHandler) start(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r, "id", "account")
+	if !ok {
+		return
+	}
+
+	var req ReconciliationRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	reconciliation, err := h.reconciliationService.start(accountID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, reconciliation)
+}
+
+// List is an HTTP handler for listing an account's reconciliations
+func (h *reconciliationHandler) list(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r, "id", "account")
+	if !ok {
+		return
+	}
+
+	reconciliations, err := h.reconciliationService.list(accountID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, reconciliations)
+}
+
+// Get is an HTTP handler for retrieving a reconciliation with its running difference
+func (h *reconciliationHandler) get(w http.ResponseWriter, r *http.Request) {
+	reconciliationID, ok := h.pathID(w, r, "id", "reconciliation")
+	if !ok {
+		return
+	}
+
+	reconciliation, err := h.reconciliationService.get(reconciliationID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, reconciliation)
+}
+
+// Toggle is an HTTP handler for clearing or unclearing a transaction in a reconciliation
+func (h *reconciliationHandler) toggle(w http.ResponseWriter, r *http.Request) {
+	reconciliationID, ok := h.pathID(w, r, "id", "reconciliation")
+	if !ok {
+		return
+	}
+	transactionID, ok := h.pathID(w, r, "transaction", "transaction")
+	if !ok {
+		return
+	}
+
+	reconciliation, err := h.reconciliationService.toggle(reconciliationID, transactionID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, reconciliation)
+}
+
+// Finish is an HTTP handler for finishing a reconciliation, locking its cleared transactions
+func (h *reconciliationHandler) finish(w http.ResponseWriter, r *http.Request) {
+	reconciliationID, ok := h.pathID(w, r, "id", "reconciliation")
+	if !ok {
+		return
+	}
+
+	var req FinishRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	reconciliation, err := h.reconciliationService.finish(reconciliationID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, reconciliation)
+}
+
+// Delete is an HTTP handler for abandoning an open reconciliation
+func (h *reconciliationHandler) delete(w http.ResponseWriter, r *http.Request) {
+	reconciliationID, ok := h.pathID(w, r, "id", "reconciliation")
+	if !ok {
+		return
+	}
+
+	if err := h.reconciliationService.delete(reconciliationID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *reconciliationHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses an ID path parameter of the named resource, writing a 400 response on failure
+func (h *reconciliationHandler) pathID(w http.ResponseWriter, r *http.Request, name, resource string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid " + resource + " ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *reconciliationHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrReconciliationNotFound), errors.Is(err, accounts.ErrAccountNotFound),
+		errors.Is(err, households.ErrHouseholdNotFound), errors.Is(err, reconcile.ErrUnknownTransaction),
+		errors.Is(err, transactions.ErrTransactionNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrReconciliationOpen), errors.Is(err, reconcile.ErrSessionFinished),
+		errors.Is(err, reconcile.ErrAfterStatementDate), errors.Is(err, reconcile.ErrUnbalanced),
+		errors.Is(err, reconcile.ErrStatementBeforeLast), errors.Is(err, fiscal.ErrPeriodLocked):
+		utils.WriteJson(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling reconciliation request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,189 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package reconciliations
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code for unique constraint violations
+const uniqueViolation = "23505"
+
+// reconciliationColumns lists the columns selected for a Reconciliation
+const reconciliationColumns = `id, account_id, statement_date, statement_balance, adjustment_id, created_at, finished_at`
+
+// reconciliationModel wraps the database connection pool using sqlx
+type reconciliationModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newReconciliationModel(db *sqlx.DB, logger *slog.Logger) *reconciliationModel {
+	return &reconciliationModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new open reconciliation. An account has at most one open reconciliation.
+func (m *reconciliationModel) create(r *Reconciliation) error {
+	query := `INSERT INTO reconciliations (account_id, statement_date, statement_balance, created_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id`
+
+	r.CreatedAt = time.Now()
+
+	err := m.DB.QueryRowx(query, r.AccountID, r.StatementDate, r.StatementBalance, r.CreatedAt).Scan(&r.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return ErrReconciliationOpen
+	}
+	if err != nil {
+		m.logger.Error("Error inserting reconciliation", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Reconciliation started successfully", "id", r.ID, "account_id", r.AccountID)
+	return nil
+}
+
+// List returns an account's reconciliations, the most recent statement first
+func (m *reconciliationModel) list(accountID int64) ([]Reconciliation, error) {
+	reconciliations := []Reconciliation{}
+	query := `SELECT ` + reconciliationColumns + ` FROM reconciliations
+	WHERE account_id = $1
+	ORDER BY statement_date DESC, id DESC`
+	if err := m.DB.Select(&reconciliations, query, accountID); err != nil {
+		m.logger.Error("Error listing reconciliations", "error", err)
+		return nil, ErrInternalServer
+	}
+	return reconciliations, nil
+}
+
+// GetByID returns a reconciliation by ID
+func (m *reconciliationModel) getByID(id int64) (*Reconciliation, error) {
+	r := &Reconciliation{}
+	err := m.DB.Get(r, `SELECT `+reconciliationColumns+` FROM reconciliations WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrReconciliationNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting reconciliation by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return r, nil
+}
+
+// LastFinished returns an account's most recently finished reconciliation, or nil when
+// it was never reconciled
+func (m *reconciliationModel) lastFinished(accountID int64) (*Reconciliation, error) {
+	r := &Reconciliation{}
+	query := `SELECT ` + reconciliationColumns + ` FROM reconciliations
+	WHERE account_id = $1 AND finished_at IS NOT NULL
+	ORDER BY statement_date DESC, finished_at DESC
+	LIMIT 1`
+	err := m.DB.Get(r, query, accountID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		m.logger.Error("Error getting last reconciliation", "error", err)
+		return nil, ErrInternalServer
+	}
+	return r, nil
+}
+
+// ListUnreconciled returns an account's transactions that are not reconciled yet
+func (m *reconciliationModel) listUnreconciled(accountID int64) ([]Transaction, error) {
+	transactions := []Transaction{}
+	query := `SELECT id, date, payee, amount, cleared FROM transactions
+	WHERE account_id = $1 AND NOT reconciled
+	ORDER BY date, id`
+	if err := m.DB.Select(&transactions, query, accountID); err != nil {
+		m.logger.Error("Error listing unreconciled transactions", "error", err)
+		return nil, ErrInternalServer
+	}
+	return transactions, nil
+}
+
+// SetCleared stores whether an unreconciled transaction of the account is cleared
+func (m *reconciliationModel) setCleared(accountID, transactionID int64, cleared bool) error {
+	query := `UPDATE transactions SET cleared = $3 WHERE id = $2 AND account_id = $1 AND NOT reconciled`
+	if _, err := m.DB.Exec(query, accountID, transactionID, cleared); err != nil {
+		m.logger.Error("Error clearing transaction", "error", err)
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// Finish closes the reconciliation and locks the account's reconciled transactions in a
+// single transaction. When adjust is set, it creates the adjustment within that transaction
+// once the reconciliation is claimed, so only one finish can ever store an adjustment.
+func (m *reconciliationModel) finish(r *Reconciliation, reconciled []int64, adjust func(tx *sqlx.Tx) (int64, error)) error {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting reconciliation transaction", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	// Claiming the open reconciliation first makes a concurrent finish wait, then find it finished.
+	query := `UPDATE reconciliations SET finished_at = $2
+	WHERE id = $1 AND finished_at IS NULL
+	RETURNING finished_at`
+	if err := tx.Get(&r.FinishedAt, query, r.ID, time.Now()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrReconciliationNotFound
+		}
+		m.logger.Error("Error finishing reconciliation", "error", err)
+		return ErrInternalServer
+	}
+
+	if adjust != nil {
+		adjustmentID, err := adjust(tx)
+		if err != nil {
+			return err
+		}
+		r.AdjustmentID = sql.NullInt64{Int64: adjustmentID, Valid: true}
+		reconciled = append(reconciled, adjustmentID)
+		if _, err := tx.Exec(`UPDATE reconciliations SET adjustment_id = $2 WHERE id = $1`, r.ID, adjustmentID); err != nil {
+			m.logger.Error("Error recording reconciliation adjustment", "error", err)
+			return ErrInternalServer
+		}
+	}
+
+	query = `UPDATE transactions SET cleared = TRUE, reconciled = TRUE WHERE account_id = $1 AND id = ANY($2)`
+	if _, err := tx.Exec(query, r.AccountID, pq.Array(reconciled)); err != nil {
+		m.logger.Error("Error locking reconciled transactions", "error", err)
+		return ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing reconciliation", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Reconciliation finished successfully", "id", r.ID, "reconciled", len(reconciled))
+	return nil
+}
+
+// Delete abandons an open reconciliation. Cleared transactions stay cleared.
+func (m *reconciliationModel) delete(id int64) error {
+	result, err := m.DB.Exec(`DELETE FROM reconciliations WHERE id = $1 AND finished_at IS NULL`, id)
+	if err != nil {
+		m.logger.Error("Error deleting reconciliation", "error", err)
+		return ErrInternalServer
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrReconciliationNotFound
+	}
+
+	m.logger.Debug("Reconciliation deleted successfully", "id", id)
+	return nil
+}
@@ -0,0 +1,232 @@
+package reconciliations
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/transactions"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/reconcile"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/jmoiron/sqlx"
+)
+
+type reconciliationService struct {
+	reconciliationRepo *reconciliationModel
+	logger             *slog.Logger
+}
+
+func newReconciliationService(reconciliationRepo *reconciliationModel, logger *slog.Logger) *reconciliationService {
+	return &reconciliationService{
+		reconciliationRepo: reconciliationRepo,
+		logger:             logger,
+	}
+}
+
+// start validates a statement and opens a reconciliation of the account against it
+func (s *reconciliationService) start(accountID int64, input ReconciliationRequest) (*ReconciliationResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Reconciliation validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	account, err := accounts.Get(s.reconciliationRepo.DB, s.logger, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if input.StatementBalance.Currency() != account.Currency {
+		return nil, &validate.ValidationError{Errors: map[string]string{
+			"StatementBalance": "StatementBalance must be in the account's currency " + account.Currency,
+		}}
+	}
+
+	statementDate, _ := time.Parse(time.DateOnly, input.StatementDate)
+	reconciliation := &Reconciliation{AccountID: accountID, StatementDate: statementDate, StatementBalance: input.StatementBalance}
+	// Building the session checks the statement against the last reconciliation before it is stored.
+	if _, err := s.session(account, reconciliation); err != nil {
+		return nil, err
+	}
+	if err := s.reconciliationRepo.create(reconciliation); err != nil {
+		return nil, err
+	}
+	return s.get(reconciliation.ID)
+}
+
+// list returns an account's reconciliations, the most recent statement first
+func (s *reconciliationService) list(accountID int64) ([]*ReconciliationResponse, error) {
+	if _, err := accounts.Get(s.reconciliationRepo.DB, s.logger, accountID); err != nil {
+		return nil, err
+	}
+	reconciliations, err := s.reconciliationRepo.list(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*ReconciliationResponse, 0, len(reconciliations))
+	for i := range reconciliations {
+		responses = append(responses, reconciliations[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// get returns a reconciliation, with its running balances and transactions while it is open
+func (s *reconciliationService) get(id int64) (*ReconciliationResponse, error) {
+	reconciliation, _, session, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.respond(reconciliation, session)
+}
+
+// toggle flips a transaction of an open reconciliation between cleared and uncleared
+func (s *reconciliationService) toggle(id, transactionID int64) (*ReconciliationResponse, error) {
+	reconciliation, _, session, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, reconcile.ErrSessionFinished
+	}
+
+	status, err := session.Toggle(strconv.FormatInt(transactionID, 10))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reconciliationRepo.setCleared(reconciliation.AccountID, transactionID, status == reconcile.StatusCleared); err != nil {
+		return nil, err
+	}
+	return s.respond(reconciliation, session)
+}
+
+// finish locks the cleared transactions of an open reconciliation as reconciled. A
+// leftover difference is posted as an adjustment transaction when the input asks for it.
+func (s *reconciliationService) finish(id int64, input FinishRequest) (*ReconciliationResponse, error) {
+	reconciliation, account, session, err := s.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, reconcile.ErrSessionFinished
+	}
+
+	result, err := session.Finish(input.Adjust)
+	if err != nil {
+		return nil, err
+	}
+	reconciled := make([]int64, 0, len(result.Reconciled)+1)
+	for _, transactionID := range result.Reconciled {
+		parsed, _ := strconv.ParseInt(transactionID, 10, 64)
+		reconciled = append(reconciled, parsed)
+	}
+
+	var adjust func(tx *sqlx.Tx) (int64, error)
+	committed := func() {}
+	if adjustment := result.Adjustment; adjustment != nil {
+		adjust = func(tx *sqlx.Tx) (int64, error) {
+			created, createdCommitted, err := transactions.CreateTx(s.reconciliationRepo.DB, tx, s.logger, account.HouseholdID, transactions.TransactionRequest{
+				AccountID: account.ID,
+				Date:      adjustment.Date.Format(time.DateOnly),
+				Amount:    adjustment.Amount,
+				Payee:     adjustmentPayee,
+				Memo:      adjustment.Memo,
+				Cleared:   true,
+			})
+			if err != nil {
+				return 0, err
+			}
+			committed = createdCommitted
+			return created.ID, nil
+		}
+	}
+
+	if err := s.reconciliationRepo.finish(reconciliation, reconciled, adjust); err != nil {
+		return nil, err
+	}
+	committed()
+	if reconciliation.AdjustmentID.Valid {
+		result.Reconciled = append(result.Reconciled, strconv.FormatInt(reconciliation.AdjustmentID.Int64, 10))
+	}
+	response := reconciliation.ToResponse()
+	response.Reconciled = result.Reconciled
+	return response, nil
+}
+
+// delete abandons an open reconciliation. Finished reconciliations are kept, as the
+// next statement is reconciled against them.
+func (s *reconciliationService) delete(id int64) error {
+	reconciliation, err := s.reconciliationRepo.getByID(id)
+	if err != nil {
+		return err
+	}
+	if reconciliation.FinishedAt.Valid {
+		return reconcile.ErrSessionFinished
+	}
+	return s.reconciliationRepo.delete(id)
+}
+
+// load returns a reconciliation with its account and, while it is open, its session
+func (s *reconciliationService) load(id int64) (*Reconciliation, *accounts.Account, *reconcile.Session, error) {
+	reconciliation, err := s.reconciliationRepo.getByID(id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	account, err := accounts.Get(s.reconciliationRepo.DB, s.logger, reconciliation.AccountID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if reconciliation.FinishedAt.Valid {
+		return reconciliation, account, nil, nil
+	}
+	session, err := s.session(account, reconciliation)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return reconciliation, account, session, nil
+}
+
+// session rebuilds the reconciliation session from the account's last finished
+// reconciliation and its unreconciled transactions
+func (s *reconciliationService) session(account *accounts.Account, reconciliation *Reconciliation) (*reconcile.Session, error) {
+	opening, err := money.Zero(account.Currency)
+	if err != nil {
+		s.logger.Error("Invalid account currency", "account_id", account.ID, "error", err)
+		return nil, ErrInternalServer
+	}
+	state := reconcile.Account{ID: strconv.FormatInt(account.ID, 10), ReconciledBalance: opening}
+	last, err := s.reconciliationRepo.lastFinished(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		state.ReconciledBalance, state.LastReconciledDate = last.StatementBalance, last.StatementDate
+	}
+
+	stored, err := s.reconciliationRepo.listUnreconciled(account.ID)
+	if err != nil {
+		return nil, err
+	}
+	unreconciled := make([]reconcile.Transaction, 0, len(stored))
+	for i := range stored {
+		unreconciled = append(unreconciled, stored[i].toReconcile())
+	}
+	return reconcile.NewSession(state, reconciliation.StatementDate, reconciliation.StatementBalance, unreconciled)
+}
+
+// respond builds the response of a reconciliation, with the session's balances when it is open
+func (s *reconciliationService) respond(reconciliation *Reconciliation, session *reconcile.Session) (*ReconciliationResponse, error) {
+	response := reconciliation.ToResponse()
+	if session == nil {
+		return response, nil
+	}
+
+	var err error
+	if response.ClearedBalance, err = session.ClearedBalance(); err != nil {
+		return nil, err
+	}
+	if response.Difference, err = session.Difference(); err != nil {
+		return nil, err
+	}
+	response.Transactions = session.Transactions()
+	return response, nil
+}
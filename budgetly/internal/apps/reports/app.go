@@ -0,0 +1,15 @@
+package reports
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewReportsApp creates a new reports application with the provided database connection
+func NewReportsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	reportModel := newReportModel(db, logger)
+	reportService := newReportService(reportModel, logger)
+	newReportHandler(reportService, logger, router)
+}
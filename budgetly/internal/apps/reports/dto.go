@@ -0,0 +1,343 @@
+package reports
+
+import (
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/pkg/export"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+)
+
+// Query holds the parameters shared by the reports.
+type Query struct {
+	From time.Time
+	To   time.Time
+	// Granularity buckets the range into calendar periods; without it the periods are the
+	// household's fiscal periods.
+	Granularity string
+	// TagID limits the report to transactions with the tag, when set.
+	TagID int64
+	// Compare adds a comparison against the range of the same length before From..To.
+	Compare bool
+	// Limit is the number of top payees reported per currency.
+	Limit int
+}
+
+// CategoryTotal is a category's spending in one period and currency, as aggregated by the database.
+type CategoryTotal struct {
+	Period   time.Time `db:"period"`
+	Category string    `db:"category"`
+	Currency string    `db:"currency"`
+	Total    string    `db:"total"`
+	Count    int       `db:"count"`
+}
+
+// CashflowTotal is the income and expense of one period in one currency, as aggregated by the database.
+type CashflowTotal struct {
+	Period   time.Time `db:"period"`
+	Currency string    `db:"currency"`
+	Income   string    `db:"income"`
+	Expense  string    `db:"expense"`
+}
+
+// PayeeTotal is the spending at a payee in one currency, as aggregated by the database.
+type PayeeTotal struct {
+	Payee    string `db:"payee"`
+	Currency string `db:"currency"`
+	Total    string `db:"total"`
+	Count    int    `db:"count"`
+}
+
+// PeriodResponse represents a report period.
+type PeriodResponse struct {
+	Start string `json:"start"`
+	// End is the last day of the period.
+	End string `json:"end"`
+
+	// start and end are the dates of Start and End, for exports.
+	start, end time.Time
+}
+
+// newPeriodResponse builds the response of a half-open report period.
+func newPeriodResponse(period report.Period) PeriodResponse {
+	end := period.End.AddDate(0, 0, -1)
+	return PeriodResponse{
+		Start: period.Start.Format(time.DateOnly),
+		End:   end.Format(time.DateOnly),
+		start: period.Start,
+		end:   end,
+	}
+}
+
+// SpendingResponse represents spending per category in each period. Spending is the sum of
+// outflows, shown as positive amounts; transfers between accounts are left out.
+type SpendingResponse struct {
+	HouseholdID int64                     `json:"household_id"`
+	Granularity report.Granularity        `json:"granularity,omitempty"`
+	TagID       int64                     `json:"tag_id,omitempty"`
+	Periods     []*SpendingPeriodResponse `json:"periods"`
+	// Comparison holds each category's total over the whole range against the previous range.
+	Comparison []*CategoryChangeResponse `json:"comparison,omitempty"`
+	Previous   *PeriodResponse           `json:"previous,omitempty"`
+}
+
+// SpendingPeriodResponse represents the spending of one period.
+type SpendingPeriodResponse struct {
+	PeriodResponse
+	Categories []*CategoryResponse `json:"categories"`
+	Totals     report.Totals       `json:"totals"`
+}
+
+// CategoryResponse represents a category's spending per currency.
+type CategoryResponse struct {
+	Category string        `json:"category"`
+	Count    int           `json:"count"`
+	Totals   report.Totals `json:"totals"`
+}
+
+// CategoryChangeResponse represents a category's spending against the previous range.
+type CategoryChangeResponse struct {
+	Category string          `json:"category"`
+	Changes  []report.Change `json:"changes"`
+}
+
+// CashflowResponse represents income against expense in each period; transfers between
+// accounts are left out.
+type CashflowResponse struct {
+	HouseholdID int64                     `json:"household_id"`
+	Granularity report.Granularity        `json:"granularity,omitempty"`
+	TagID       int64                     `json:"tag_id,omitempty"`
+	Periods     []*CashflowPeriodResponse `json:"periods"`
+	Comparison  *CashflowChangeResponse   `json:"comparison,omitempty"`
+	Previous    *PeriodResponse           `json:"previous,omitempty"`
+}
+
+// CashflowPeriodResponse represents the income, expense and their difference in one period.
+type CashflowPeriodResponse struct {
+	PeriodResponse
+	Income  report.Totals `json:"income"`
+	Expense report.Totals `json:"expense"`
+	Net     report.Totals `json:"net"`
+}
+
+// CashflowChangeResponse represents the income and expense of the whole range against the previous range.
+type CashflowChangeResponse struct {
+	Income  []report.Change `json:"income"`
+	Expense []report.Change `json:"expense"`
+}
+
+// PayeesResponse represents the payees spent the most at, per currency.
+type PayeesResponse struct {
+	HouseholdID int64            `json:"household_id"`
+	From        string           `json:"from"`
+	To          string           `json:"to"`
+	TagID       int64            `json:"tag_id,omitempty"`
+	Payees      []*PayeeResponse `json:"payees"`
+	Previous    *PeriodResponse  `json:"previous,omitempty"`
+}
+
+// PayeeResponse represents the spending at a payee in one currency.
+type PayeeResponse struct {
+	Payee string      `json:"payee"`
+	Count int         `json:"count"`
+	Total money.Money `json:"total"`
+	// Previous is the spending at the payee in the previous range, when comparing.
+	Previous *money.Money `json:"previous,omitempty"`
+}
+
+// category returns the period's entry for a category, adding it on first use
+func (p *SpendingPeriodResponse) category(name string) *CategoryResponse {
+	for _, category := range p.Categories {
+		if category.Category == name {
+			return category
+		}
+	}
+	category := &CategoryResponse{Category: name, Totals: report.Totals{}}
+	p.Categories = append(p.Categories, category)
+	return category
+}
+
+// changeColumns are the columns of a comparison sheet after its label column
+var changeColumns = []export.Column{
+	{Title: "Currency", Kind: export.Text},
+	{Title: "Current", Kind: export.Currency},
+	{Title: "Previous", Kind: export.Currency},
+	{Title: "Change", Kind: export.Currency},
+	{Title: "Change %", Kind: export.Number},
+}
+
+// changeRows returns a comparison sheet's rows for the changes of one label
+func changeRows(label string, changes []report.Change) [][]any {
+	rows := make([][]any, 0, len(changes))
+	for _, change := range changes {
+		var percent any
+		if change.Percent != nil {
+			percent = *change.Percent
+		}
+		rows = append(rows, []any{label, change.Currency, change.Current, change.Previous, change.Delta, percent})
+	}
+	return rows
+}
+
+// toExport converts the spending report into a spreadsheet with a row per period,
+// category and currency, and a sheet for the comparison if there is one
+func (s *SpendingResponse) toExport() export.Report {
+	var rows [][]any
+	for _, period := range s.Periods {
+		for _, category := range period.Categories {
+			for _, currency := range category.Totals.Currencies() {
+				rows = append(rows, []any{period.start, period.end, category.Category, currency, category.Count, category.Totals[currency]})
+			}
+		}
+	}
+
+	spending := export.Report{
+		Title: "Spending by category",
+		Sheets: []export.Sheet{{
+			Name: "Spending",
+			Columns: []export.Column{
+				{Title: "Start", Kind: export.Date},
+				{Title: "End", Kind: export.Date},
+				{Title: "Category", Kind: export.Text},
+				{Title: "Currency", Kind: export.Text},
+				{Title: "Transactions", Kind: export.Number},
+				{Title: "Total", Kind: export.Currency},
+			},
+			Rows:  export.SliceRows(rows),
+			Chart: &export.Chart{Type: export.BarChart, Label: 2, Value: 5},
+		}},
+	}
+	if s.Comparison != nil {
+		var comparison [][]any
+		for _, category := range s.Comparison {
+			comparison = append(comparison, changeRows(category.Category, category.Changes)...)
+		}
+		spending.Sheets = append(spending.Sheets, export.Sheet{
+			Name:    "Comparison",
+			Columns: append([]export.Column{{Title: "Category", Kind: export.Text}}, changeColumns...),
+			Rows:    export.SliceRows(comparison),
+		})
+	}
+	return spending
+}
+
+// toExport converts the cashflow report into a spreadsheet with a row per period and
+// currency, and a sheet for the comparison if there is one
+func (c *CashflowResponse) toExport() export.Report {
+	var rows [][]any
+	for _, period := range c.Periods {
+		for _, currency := range period.Net.Currencies() {
+			rows = append(rows, []any{period.start, period.end, currency, period.Income[currency], period.Expense[currency], period.Net[currency]})
+		}
+	}
+
+	cashflow := export.Report{
+		Title: "Income and expense",
+		Sheets: []export.Sheet{{
+			Name: "Cashflow",
+			Columns: []export.Column{
+				{Title: "Start", Kind: export.Date},
+				{Title: "End", Kind: export.Date},
+				{Title: "Currency", Kind: export.Text},
+				{Title: "Income", Kind: export.Currency},
+				{Title: "Expense", Kind: export.Currency},
+				{Title: "Net", Kind: export.Currency},
+			},
+			Rows:  export.SliceRows(rows),
+			Chart: &export.Chart{Type: export.LineChart, Label: 0, Value: 5},
+		}},
+	}
+	if c.Comparison != nil {
+		comparison := append(changeRows("Income", c.Comparison.Income), changeRows("Expense", c.Comparison.Expense)...)
+		cashflow.Sheets = append(cashflow.Sheets, export.Sheet{
+			Name:    "Comparison",
+			Columns: append([]export.Column{{Title: "Flow", Kind: export.Text}}, changeColumns...),
+			Rows:    export.SliceRows(comparison),
+		})
+	}
+	return cashflow
+}
+
+// toExport converts the top payees into a spreadsheet with a row per payee and currency,
+// with the previous range's spending when comparing
+func (p *PayeesResponse) toExport() export.Report {
+	columns := []export.Column{
+		{Title: "Payee", Kind: export.Text},
+		{Title: "Currency", Kind: export.Text},
+		{Title: "Transactions", Kind: export.Number},
+		{Title: "Total", Kind: export.Currency},
+	}
+	if p.Previous != nil {
+		columns = append(columns, export.Column{Title: "Previous", Kind: export.Currency})
+	}
+
+	rows := make([][]any, 0, len(p.Payees))
+	for _, payee := range p.Payees {
+		row := []any{payee.Payee, payee.Total.Currency(), payee.Count, payee.Total}
+		if payee.Previous != nil {
+			row = append(row, *payee.Previous)
+		}
+		rows = append(rows, row)
+	}
+
+	return export.Report{
+		Title: "Top payees",
+		Sheets: []export.Sheet{{
+			Name:    "Payees",
+			Columns: columns,
+			Rows:    export.SliceRows(rows),
+			Chart:   &export.Chart{Type: export.BarChart, Label: 0, Value: 3},
+		}},
+	}
+}
+
+// netWorthExport converts a net worth timeline into a spreadsheet with a row per date and
+// currency, and a sheet for the converted total if one was requested
+func netWorthExport(timeline *balances.TimelineResponse) export.Report {
+	var rows, converted [][]any
+	for _, point := range timeline.Points {
+		date, _ := time.Parse(time.DateOnly, point.Date)
+		for _, currency := range point.NetWorth.Currencies() {
+			rows = append(rows, []any{date, currency, amountCell(point.Accounts, currency), amountCell(point.Investments, currency), point.NetWorth[currency]})
+		}
+		if point.Total != nil {
+			converted = append(converted, []any{date, *point.Total})
+		}
+	}
+
+	netWorth := export.Report{
+		Title: "Net worth",
+		Sheets: []export.Sheet{{
+			Name: "Net worth",
+			Columns: []export.Column{
+				{Title: "Date", Kind: export.Date},
+				{Title: "Currency", Kind: export.Text},
+				{Title: "Accounts", Kind: export.Currency},
+				{Title: "Investments", Kind: export.Currency},
+				{Title: "Net worth", Kind: export.Currency},
+			},
+			Rows: export.SliceRows(rows),
+		}},
+	}
+	if timeline.Currency != "" {
+		netWorth.Sheets = append(netWorth.Sheets, export.Sheet{
+			Name: "Total in " + timeline.Currency,
+			Columns: []export.Column{
+				{Title: "Date", Kind: export.Date},
+				{Title: "Net worth", Kind: export.Currency},
+			},
+			Rows:  export.SliceRows(converted),
+			Chart: &export.Chart{Type: export.LineChart, Label: 0, Value: 1},
+		})
+	}
+	return netWorth
+}
+
+// amountCell returns the total in a currency, or an empty cell when there is none
+func amountCell(totals report.Totals, currency string) any {
+	if amount, ok := totals[currency]; ok {
+		return amount
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package reports
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+)
@@ -0,0 +1,257 @@
+package reports
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/export"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+const (
+	// defaultPayeeLimit is how many top payees are reported per currency by default
+	defaultPayeeLimit = 10
+	// maxPayeeLimit caps the number of top payees reported per currency
+	maxPayeeLimit = 100
+)
+
+// reportHandler is an HTTP handler for household reports
+// (e.g., spending by category, income vs expense, top payees, net worth)
+type reportHandler struct {
+	reportService *reportService
+	logger        *slog.Logger
+	router        *http.ServeMux
+}
+
+// newReportHandler creates a new report handler with the provided report service and logger
+func newReportHandler(reportService *reportService, logger *slog.Logger, router *http.ServeMux) *reportHandler {
+	reportHandler := &reportHandler{
+		reportService: reportService,
+		logger:        logger,
+		router:        router,
+	}
+	reportHandler.registerRoutes()
+	return reportHandler
+}
+
+// Register routes for report actions
+func (h *reportHandler) registerRoutes() {
+	h.router.HandleFunc("GET /households/{household}/reports/spending", h.spending)
+	h.router.HandleFunc("GET /households/{household}/reports/cashflow", h.cashflow)
+	h.router.HandleFunc("GET /households/{household}/reports/payees", h.payees)
+	h.router.HandleFunc("GET /households/{household}/reports/networth", h.netWorth)
+}
+
+// Spending is an HTTP handler for a household's spending per category in each period
+func (h *reportHandler) spending(w http.ResponseWriter, r *http.Request) {
+	householdID, query, ok := h.query(w, r)
+	if !ok {
+		return
+	}
+	format, ok := h.format(w, r)
+	if !ok {
+		return
+	}
+
+	spending, err := h.reportService.spending(householdID, query)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.write(w, format, "spending", spending, spending.toExport)
+}
+
+// Cashflow is an HTTP handler for a household's income against expense in each period
+func (h *reportHandler) cashflow(w http.ResponseWriter, r *http.Request) {
+	householdID, query, ok := h.query(w, r)
+	if !ok {
+		return
+	}
+	format, ok := h.format(w, r)
+	if !ok {
+		return
+	}
+
+	cashflow, err := h.reportService.cashflow(householdID, query)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.write(w, format, "cashflow", cashflow, cashflow.toExport)
+}
+
+// Payees is an HTTP handler for the payees a household spent the most at
+func (h *reportHandler) payees(w http.ResponseWriter, r *http.Request) {
+	householdID, query, ok := h.query(w, r)
+	if !ok {
+		return
+	}
+	format, ok := h.format(w, r)
+	if !ok {
+		return
+	}
+
+	payees, err := h.reportService.payees(householdID, query)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.write(w, format, "payees", payees, payees.toExport)
+}
+
+// NetWorth is an HTTP handler for a household's net worth at the end of each period
+func (h *reportHandler) netWorth(w http.ResponseWriter, r *http.Request) {
+	householdID, query, ok := h.query(w, r)
+	if !ok {
+		return
+	}
+	format, ok := h.format(w, r)
+	if !ok {
+		return
+	}
+
+	timeline, err := h.reportService.netWorth(householdID, query, strings.ToUpper(r.URL.Query().Get("currency")))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	h.write(w, format, "networth", timeline, func() export.Report { return netWorthExport(timeline) })
+}
+
+// query parses the household path parameter and the report query parameters, writing a
+// 400 response on failure. to defaults to today and from to a year before it.
+func (h *reportHandler) query(w http.ResponseWriter, r *http.Request) (int64, Query, bool) {
+	householdID, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || householdID <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, Query{}, false
+	}
+
+	errs := map[string]string{}
+	values := r.URL.Query()
+	from, to := queryDate(values, "from", errs), queryDate(values, "to", errs)
+	query := Query{
+		Granularity: values.Get("granularity"),
+		TagID:       queryID(values, "tag", errs),
+		Limit:       defaultPayeeLimit,
+	}
+	if value := values.Get("compare"); value != "" {
+		if query.Compare, err = strconv.ParseBool(value); err != nil {
+			errs["compare"] = "compare must be true or false"
+		}
+	}
+	if value := values.Get("limit"); value != "" {
+		if query.Limit, err = strconv.Atoi(value); err != nil || query.Limit < 1 || query.Limit > maxPayeeLimit {
+			errs["limit"] = "limit must be between 1 and " + strconv.Itoa(maxPayeeLimit)
+		}
+	}
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return 0, Query{}, false
+	}
+
+	query.To = today()
+	if to.Valid {
+		query.To = to.Time
+	}
+	query.From = query.To.AddDate(-1, 0, 0)
+	if from.Valid {
+		query.From = from.Time
+	}
+	return householdID, query, true
+}
+
+// format negotiates the format of a report through ?format= or the Accept header, writing
+// a 400 response for an unknown one. An empty format means JSON.
+func (h *reportHandler) format(w http.ResponseWriter, r *http.Request) (export.Format, bool) {
+	format, exported, err := utils.NegotiateFormat(r)
+	if err != nil {
+		h.writeError(w, &validate.ValidationError{Errors: map[string]string{"format": "format must be one of json, csv, xlsx or pdf"}})
+		return "", false
+	}
+	if !exported {
+		return "", true
+	}
+	return format, true
+}
+
+// write sends a report as JSON, or as a file download in the negotiated format. CSV holds
+// a single sheet, so comparison sheets are only part of XLSX and PDF downloads.
+func (h *reportHandler) write(w http.ResponseWriter, format export.Format, filename string, response any, toExport func() export.Report) {
+	if format == "" {
+		utils.WriteJson(w, http.StatusOK, response)
+		return
+	}
+
+	exported := toExport()
+	if format == export.CSV {
+		exported.Sheets = exported.Sheets[:1]
+	}
+	if err := utils.WriteExport(w, format, filename, exported); err != nil {
+		h.logger.Error("Error writing report export", "format", format, "error", err)
+	}
+}
+
+// writeError maps service errors to HTTP responses
+func (h *reportHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, households.ErrHouseholdNotFound), errors.Is(err, balances.ErrRateNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling report request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
+
+// today returns the current UTC date
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// queryID parses an optional positive ID query parameter, recording an error when it is invalid
+func queryID(query url.Values, name string, errs map[string]string) int64 {
+	value := query.Get(name)
+	if value == "" {
+		return 0
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		errs[name] = name + " must be a positive integer"
+		return 0
+	}
+	return id
+}
+
+// queryDate parses an optional YYYY-MM-DD query parameter, recording an error when it is invalid
+func queryDate(query url.Values, name string, errs map[string]string) sql.NullTime {
+	value := query.Get(name)
+	if value == "" {
+		return sql.NullTime{}
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		errs[name] = name + " must be in YYYY-MM-DD format"
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: date, Valid: true}
+}
@@ -0,0 +1,195 @@
+package reports
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/pkg/export"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+)
+
+func newTestHandler() (*reportHandler, *http.ServeMux) {
+	router := http.NewServeMux()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return newReportHandler(nil, logger, router), router
+}
+
+func sampleSpending(compare bool) *SpendingResponse {
+	march := report.Period{Start: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)}
+	response := &SpendingResponse{
+		HouseholdID: 1,
+		Granularity: report.Monthly,
+		Periods: []*SpendingPeriodResponse{{
+			PeriodResponse: newPeriodResponse(march),
+			Categories: []*CategoryResponse{
+				{Category: "Groceries", Count: 3, Totals: report.Totals{"EUR": money.MustNew(12050, "EUR")}},
+			},
+			Totals: report.Totals{"EUR": money.MustNew(12050, "EUR")},
+		}},
+	}
+	if compare {
+		changes, _ := report.Compare(report.Totals{"EUR": money.MustNew(12050, "EUR")}, report.Totals{"EUR": money.MustNew(10000, "EUR")})
+		previous := newPeriodResponse(march.Previous())
+		response.Previous = &previous
+		response.Comparison = []*CategoryChangeResponse{{Category: "Groceries", Changes: changes}}
+	}
+	return response
+}
+
+func TestInvalidQuery(t *testing.T) {
+	_, router := newTestHandler()
+
+	tests := map[string]string{
+		"/households/1/reports/spending?format=docx":   "format",
+		"/households/1/reports/cashflow?from=March":    "from",
+		"/households/1/reports/payees?limit=0":         "limit",
+		"/households/1/reports/networth?compare=maybe": "compare",
+		"/households/1/reports/spending?tag=-2":        "tag",
+	}
+	for target, field := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", target, w.Code)
+			continue
+		}
+		var body struct {
+			Errors map[string]string `json:"errors"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil || body.Errors[field] == "" {
+			t.Errorf("%s: expected an error for %s, got %v (%v)", target, field, body.Errors, err)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	h, _ := newTestHandler()
+
+	tests := []struct {
+		target string
+		accept string
+		want   export.Format
+	}{
+		{"/households/1/reports/spending", "", ""},
+		{"/households/1/reports/spending?format=json", "text/csv", ""},
+		{"/households/1/reports/spending?format=xlsx", "", export.XLSX},
+		{"/households/1/reports/spending", "application/json;q=0.5, application/pdf", export.PDF},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, test.target, nil)
+		if test.accept != "" {
+			r.Header.Set("Accept", test.accept)
+		}
+		format, ok := h.format(httptest.NewRecorder(), r)
+		if !ok || format != test.want {
+			t.Errorf("%s (Accept %q): expected %q, got %q (%v)", test.target, test.accept, test.want, format, ok)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	h, _ := newTestHandler()
+	spending := sampleSpending(false)
+
+	w := httptest.NewRecorder()
+	h.write(w, "", "spending", spending, spending.toExport)
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected JSON, got %q", got)
+	}
+	if !strings.Contains(w.Body.String(), `"end":"2026-03-31"`) {
+		t.Errorf("Expected the period's last day as its end, got %s", w.Body.String())
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	h, _ := newTestHandler()
+	spending := sampleSpending(true)
+
+	w := httptest.NewRecorder()
+	h.write(w, export.CSV, "spending", spending, spending.toExport)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "attachment; filename=spending.csv" {
+		t.Errorf("Unexpected Content-Disposition %q", got)
+	}
+	// The comparison sheet does not fit in a CSV file and is left out.
+	want := "Start,End,Category,Currency,Transactions,Total\n2026-03-01,2026-03-31,Groceries,EUR,3,120.50\n"
+	if w.Body.String() != want {
+		t.Errorf("Unexpected CSV:\n%s", w.Body.String())
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	h, _ := newTestHandler()
+	spending := sampleSpending(true)
+
+	w := httptest.NewRecorder()
+	h.write(w, export.XLSX, "spending", spending, spending.toExport)
+	if got := w.Header().Get("Content-Type"); got != export.XLSX.ContentType() {
+		t.Errorf("Unexpected Content-Type %q", got)
+	}
+	if !strings.HasPrefix(w.Body.String(), "PK") {
+		t.Errorf("Expected a zip archive")
+	}
+}
+
+func TestExports(t *testing.T) {
+	march := newPeriodResponse(report.Period{Start: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)})
+	cashflow := &CashflowResponse{
+		Periods: []*CashflowPeriodResponse{{
+			PeriodResponse: march,
+			Income:         report.Totals{"EUR": money.MustNew(300000, "EUR")},
+			Expense:        report.Totals{"EUR": money.MustNew(120000, "EUR")},
+			Net:            report.Totals{"EUR": money.MustNew(180000, "EUR")},
+		}},
+	}
+	previous := money.MustNew(4000, "USD")
+	payees := &PayeesResponse{
+		Payees:   []*PayeeResponse{{Payee: "Netflix", Count: 1, Total: money.MustNew(1599, "USD"), Previous: &previous}},
+		Previous: &march,
+	}
+	total := money.MustNew(500000, "EUR")
+	timeline := &balances.TimelineResponse{
+		Currency: "EUR",
+		Points: []balances.PointResponse{{
+			Date:        "2026-03-31",
+			Accounts:    report.Totals{"EUR": money.MustNew(500000, "EUR")},
+			Investments: report.Totals{},
+			NetWorth:    report.Totals{"EUR": money.MustNew(500000, "EUR")},
+			Total:       &total,
+		}},
+	}
+
+	tests := map[string]struct {
+		toExport func() export.Report
+		want     string
+	}{
+		"cashflow": {cashflow.toExport, "Start,End,Currency,Income,Expense,Net\n2026-03-01,2026-03-31,EUR,3000.00,1200.00,1800.00\n"},
+		"payees":   {payees.toExport, "Payee,Currency,Transactions,Total,Previous\nNetflix,USD,1,15.99,40.00\n"},
+		"networth": {func() export.Report { return netWorthExport(timeline) }, "Date,Currency,Accounts,Investments,Net worth\n2026-03-31,EUR,5000.00,,5000.00\n"},
+	}
+	for name, test := range tests {
+		// Charts are checked against the columns before anything is written.
+		if err := export.Write(io.Discard, export.PDF, test.toExport()); err != nil {
+			t.Errorf("%s: unexpected PDF error: %v", name, err)
+		}
+		exported := test.toExport()
+		exported.Sheets = exported.Sheets[:1]
+		var b strings.Builder
+		if err := export.Write(&b, export.CSV, exported); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if b.String() != test.want {
+			t.Errorf("%s: unexpected CSV:\n%s", name, b.String())
+		}
+	}
+}
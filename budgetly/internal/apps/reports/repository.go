@@ -0,0 +1,112 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package reports
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// reportedTransactions joins a household's transactions to the report periods passed as
+// arrays of starts ($2) and ends ($3), leaving out transfers between accounts, except
+// categorized ones that left the budget, scheduled transactions and, with a tag ($4),
+// untagged ones. Amounts are stored as "<amount> <currency>".
+// It reads from transactions or, to count split transactions per split line, from the
+// transaction_lines view.
+const reportedTransactions = `FROM %s t
+	JOIN unnest($2::date[], $3::date[]) AS p (start, finish) ON t.date >= p.start AND t.date < p.finish
+	CROSS JOIN LATERAL (SELECT split_part(t.amount, ' ', 1)::numeric AS n, split_part(t.amount, ' ', 2) AS currency) AS a
+	WHERE t.household_id = $1
+		AND (t.transfer_id IS NULL OR t.category <> '')
+		AND NOT t.scheduled
+		AND ($4 = 0 OR EXISTS (SELECT 1 FROM transaction_tags WHERE transaction_id = t.id AND tag_id = $4))`
+
+// reportModel wraps the database connection pool using sqlx
+type reportModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newReportModel(db *sqlx.DB, logger *slog.Logger) *reportModel {
+	return &reportModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Spending sums a household's outflows per period, category and currency, as positive
+// amounts, counting each line of a split transaction towards its own category
+func (m *reportModel) spending(householdID int64, periods []report.Period, tagID int64) ([]CategoryTotal, error) {
+	query := `SELECT p.start AS period, t.category, a.currency,
+		(-SUM(a.n))::text AS total,
+		COUNT(DISTINCT t.id) AS count
+	` + fmt.Sprintf(reportedTransactions, "transaction_lines") + `
+		AND a.n < 0
+	GROUP BY p.start, t.category, a.currency
+	ORDER BY p.start, SUM(a.n), t.category, a.currency`
+
+	starts, ends := bounds(periods)
+	totals := []CategoryTotal{}
+	if err := m.DB.Select(&totals, query, householdID, starts, ends, tagID); err != nil {
+		m.logger.Error("Error summing spending by category", "error", err)
+		return nil, ErrInternalServer
+	}
+	return totals, nil
+}
+
+// Cashflow sums a household's inflows and outflows per period and currency, outflows as positive amounts
+func (m *reportModel) cashflow(householdID int64, periods []report.Period, tagID int64) ([]CashflowTotal, error) {
+	query := `SELECT p.start AS period, a.currency,
+		COALESCE(SUM(a.n) FILTER (WHERE a.n > 0), 0)::text AS income,
+		COALESCE(-SUM(a.n) FILTER (WHERE a.n < 0), 0)::text AS expense
+	` + fmt.Sprintf(reportedTransactions, "transactions") + `
+	GROUP BY p.start, a.currency
+	ORDER BY p.start, a.currency`
+
+	starts, ends := bounds(periods)
+	totals := []CashflowTotal{}
+	if err := m.DB.Select(&totals, query, householdID, starts, ends, tagID); err != nil {
+		m.logger.Error("Error summing cashflow", "error", err)
+		return nil, ErrInternalServer
+	}
+	return totals, nil
+}
+
+// Payees sums a household's outflows per payee and currency within a period, as positive
+// amounts, keeping the limit payees spent the most at in each currency, or all with limit 0
+func (m *reportModel) payees(householdID int64, period report.Period, tagID int64, limit int) ([]PayeeTotal, error) {
+	query := `SELECT payee, currency, total::text AS total, count
+	FROM (
+		SELECT t.payee, a.currency, -SUM(a.n) AS total, COUNT(*) AS count,
+			ROW_NUMBER() OVER (PARTITION BY a.currency ORDER BY SUM(a.n), t.payee) AS rank
+		` + fmt.Sprintf(reportedTransactions, "transactions") + `
+			AND a.n < 0
+		GROUP BY t.payee, a.currency
+	) AS ranked
+	WHERE ($5 = 0 OR rank <= $5)
+	ORDER BY currency, rank`
+
+	starts, ends := bounds([]report.Period{period})
+	totals := []PayeeTotal{}
+	if err := m.DB.Select(&totals, query, householdID, starts, ends, tagID, limit); err != nil {
+		m.logger.Error("Error summing spending by payee", "error", err)
+		return nil, ErrInternalServer
+	}
+	return totals, nil
+}
+
+// bounds returns the starts and ends of periods as date arrays for the database
+func bounds(periods []report.Period) (any, any) {
+	starts := make([]string, 0, len(periods))
+	ends := make([]string, 0, len(periods))
+	for _, period := range periods {
+		starts = append(starts, period.Start.Format(time.DateOnly))
+		ends = append(ends, period.End.Format(time.DateOnly))
+	}
+	return pq.Array(starts), pq.Array(ends)
+}
@@ -0,0 +1,321 @@
+package reports
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/internal/apps/periods"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type reportService struct {
+	reportRepo *reportModel
+	logger     *slog.Logger
+}
+
+func newReportService(reportRepo *reportModel, logger *slog.Logger) *reportService {
+	return &reportService{
+		reportRepo: reportRepo,
+		logger:     logger,
+	}
+}
+
+// spending returns a household's spending per category in each period, and with compare
+// each category's total over the whole range against the range before it
+func (s *reportService) spending(householdID int64, query Query) (*SpendingResponse, error) {
+	reportPeriods, g, err := s.periods(householdID, query)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.reportRepo.spending(householdID, reportPeriods, query.TagID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SpendingResponse{HouseholdID: householdID, Granularity: g, TagID: query.TagID}
+	byStart := map[string]*SpendingPeriodResponse{}
+	for _, period := range reportPeriods {
+		entry := &SpendingPeriodResponse{PeriodResponse: newPeriodResponse(period), Categories: []*CategoryResponse{}, Totals: report.Totals{}}
+		byStart[entry.Start] = entry
+		response.Periods = append(response.Periods, entry)
+	}
+
+	current := map[string]report.Totals{}
+	var categories []string
+	for i := range totals {
+		amount, err := s.amount(totals[i].Total, totals[i].Currency)
+		if err != nil {
+			return nil, err
+		}
+		entry := byStart[totals[i].Period.Format(time.DateOnly)]
+		if entry == nil {
+			continue
+		}
+		category := entry.category(totals[i].Category)
+		category.Count += totals[i].Count
+		if err := s.add(amount, category.Totals, entry.Totals); err != nil {
+			return nil, err
+		}
+
+		if _, ok := current[totals[i].Category]; !ok {
+			current[totals[i].Category] = report.Totals{}
+			categories = append(categories, totals[i].Category)
+		}
+		if err := s.add(amount, current[totals[i].Category]); err != nil {
+			return nil, err
+		}
+	}
+
+	if !query.Compare {
+		return response, nil
+	}
+	previousPeriod := span(reportPeriods).Previous()
+	previousTotals, err := s.reportRepo.spending(householdID, []report.Period{previousPeriod}, query.TagID)
+	if err != nil {
+		return nil, err
+	}
+	previous := map[string]report.Totals{}
+	for i := range previousTotals {
+		amount, err := s.amount(previousTotals[i].Total, previousTotals[i].Currency)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := previous[previousTotals[i].Category]; !ok {
+			previous[previousTotals[i].Category] = report.Totals{}
+			if _, ok := current[previousTotals[i].Category]; !ok {
+				categories = append(categories, previousTotals[i].Category)
+			}
+		}
+		if err := s.add(amount, previous[previousTotals[i].Category]); err != nil {
+			return nil, err
+		}
+	}
+
+	previousResponse := newPeriodResponse(previousPeriod)
+	response.Previous = &previousResponse
+	response.Comparison = make([]*CategoryChangeResponse, 0, len(categories))
+	for _, category := range categories {
+		changes, err := report.Compare(current[category], previous[category])
+		if err != nil {
+			return nil, err
+		}
+		response.Comparison = append(response.Comparison, &CategoryChangeResponse{Category: category, Changes: changes})
+	}
+	return response, nil
+}
+
+// cashflow returns a household's income and expense in each period, and with compare the
+// totals of the whole range against the range before it
+func (s *reportService) cashflow(householdID int64, query Query) (*CashflowResponse, error) {
+	reportPeriods, g, err := s.periods(householdID, query)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := s.reportRepo.cashflow(householdID, reportPeriods, query.TagID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CashflowResponse{HouseholdID: householdID, Granularity: g, TagID: query.TagID}
+	byStart := map[string]*CashflowPeriodResponse{}
+	for _, period := range reportPeriods {
+		entry := &CashflowPeriodResponse{PeriodResponse: newPeriodResponse(period), Income: report.Totals{}, Expense: report.Totals{}, Net: report.Totals{}}
+		byStart[entry.Start] = entry
+		response.Periods = append(response.Periods, entry)
+	}
+
+	income, expense := report.Totals{}, report.Totals{}
+	for i := range totals {
+		entry := byStart[totals[i].Period.Format(time.DateOnly)]
+		if entry == nil {
+			continue
+		}
+		in, out, err := s.flows(totals[i])
+		if err != nil {
+			return nil, err
+		}
+		if err := s.add(in, entry.Income, entry.Net, income); err != nil {
+			return nil, err
+		}
+		if err := s.add(out, entry.Expense, expense); err != nil {
+			return nil, err
+		}
+		if err := s.add(out.Negate(), entry.Net); err != nil {
+			return nil, err
+		}
+	}
+
+	if !query.Compare {
+		return response, nil
+	}
+	previousPeriod := span(reportPeriods).Previous()
+	previousTotals, err := s.reportRepo.cashflow(householdID, []report.Period{previousPeriod}, query.TagID)
+	if err != nil {
+		return nil, err
+	}
+	previousIncome, previousExpense := report.Totals{}, report.Totals{}
+	for i := range previousTotals {
+		in, out, err := s.flows(previousTotals[i])
+		if err != nil {
+			return nil, err
+		}
+		if err := s.add(in, previousIncome); err != nil {
+			return nil, err
+		}
+		if err := s.add(out, previousExpense); err != nil {
+			return nil, err
+		}
+	}
+
+	comparison := &CashflowChangeResponse{}
+	if comparison.Income, err = report.Compare(income, previousIncome); err != nil {
+		return nil, err
+	}
+	if comparison.Expense, err = report.Compare(expense, previousExpense); err != nil {
+		return nil, err
+	}
+	previousResponse := newPeriodResponse(previousPeriod)
+	response.Previous, response.Comparison = &previousResponse, comparison
+	return response, nil
+}
+
+// payees returns the payees a household spent the most at between from and to, per
+// currency, and with compare what was spent at them in the range before it
+func (s *reportService) payees(householdID int64, query Query) (*PayeesResponse, error) {
+	if _, err := households.Get(s.reportRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	if query.To.Before(query.From) {
+		return nil, &validate.ValidationError{Errors: map[string]string{"to": "to must not be before from"}}
+	}
+
+	period := report.Period{Start: query.From, End: query.To.AddDate(0, 0, 1)}
+	totals, err := s.reportRepo.payees(householdID, period, query.TagID, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PayeesResponse{
+		HouseholdID: householdID,
+		From:        query.From.Format(time.DateOnly),
+		To:          query.To.Format(time.DateOnly),
+		TagID:       query.TagID,
+		Payees:      make([]*PayeeResponse, 0, len(totals)),
+	}
+	for i := range totals {
+		amount, err := s.amount(totals[i].Total, totals[i].Currency)
+		if err != nil {
+			return nil, err
+		}
+		response.Payees = append(response.Payees, &PayeeResponse{Payee: totals[i].Payee, Count: totals[i].Count, Total: amount})
+	}
+
+	if !query.Compare {
+		return response, nil
+	}
+	previousPeriod := period.Previous()
+	previousTotals, err := s.reportRepo.payees(householdID, previousPeriod, query.TagID, 0)
+	if err != nil {
+		return nil, err
+	}
+	previous := map[string]money.Money{}
+	for i := range previousTotals {
+		amount, err := s.amount(previousTotals[i].Total, previousTotals[i].Currency)
+		if err != nil {
+			return nil, err
+		}
+		previous[previousTotals[i].Payee+"|"+previousTotals[i].Currency] = amount
+	}
+	for _, payee := range response.Payees {
+		amount, ok := previous[payee.Payee+"|"+payee.Total.Currency()]
+		if !ok {
+			if amount, err = money.Zero(payee.Total.Currency()); err != nil {
+				return nil, err
+			}
+		}
+		payee.Previous = &amount
+	}
+	previousResponse := newPeriodResponse(previousPeriod)
+	response.Previous = &previousResponse
+	return response, nil
+}
+
+// netWorth returns a household's net worth at the end of each period, converted to its
+// reporting currency or another one
+func (s *reportService) netWorth(householdID int64, query Query, currency string) (*balances.TimelineResponse, error) {
+	return balances.Timeline(s.reportRepo.DB, s.logger, householdID, query.From, query.To, query.Granularity, currency)
+}
+
+// periods returns the periods a report of the household is bucketed into: calendar
+// periods of the query's granularity, or the household's fiscal periods without one
+func (s *reportService) periods(householdID int64, query Query) ([]report.Period, report.Granularity, error) {
+	if _, err := households.Get(s.reportRepo.DB, s.logger, householdID); err != nil {
+		return nil, "", err
+	}
+	if query.To.Before(query.From) {
+		return nil, "", &validate.ValidationError{Errors: map[string]string{"to": "to must not be before from"}}
+	}
+
+	if query.Granularity == "" {
+		fiscalPeriods, err := periods.Periods(s.reportRepo.DB, s.logger, householdID, query.From, query.To)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(fiscalPeriods) == 0 {
+			return nil, "", &validate.ValidationError{Errors: map[string]string{"from": "no fiscal periods between from and to"}}
+		}
+		return fiscalPeriods, "", nil
+	}
+
+	g, err := report.ParseGranularity(query.Granularity)
+	if err != nil {
+		return nil, "", &validate.ValidationError{Errors: map[string]string{"granularity": "granularity must be one of monthly, quarterly or yearly"}}
+	}
+	buckets, err := report.Buckets(query.From, query.To, g)
+	if err != nil {
+		return nil, "", &validate.ValidationError{Errors: map[string]string{"to": err.Error()}}
+	}
+	return buckets, g, nil
+}
+
+// amount parses a total aggregated by the database
+func (s *reportService) amount(total, currency string) (money.Money, error) {
+	amount, err := money.Parse(total, currency)
+	if err != nil {
+		s.logger.Error("Invalid aggregated amount", "total", total, "currency", currency, "error", err)
+		return money.Money{}, ErrInternalServer
+	}
+	return amount, nil
+}
+
+// flows parses the income and expense of a cashflow total
+func (s *reportService) flows(total CashflowTotal) (money.Money, money.Money, error) {
+	income, err := s.amount(total.Income, total.Currency)
+	if err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	expense, err := s.amount(total.Expense, total.Currency)
+	if err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	return income, expense, nil
+}
+
+// add adds an amount to each of the totals
+func (s *reportService) add(amount money.Money, totals ...report.Totals) error {
+	for _, t := range totals {
+		if err := t.Add(amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// span returns the period from the start of the first period to the end of the last
+func span(reportPeriods []report.Period) report.Period {
+	return report.Period{Start: reportPeriods[0].Start, End: reportPeriods[len(reportPeriods)-1].End}
+}
@@ -0,0 +1,28 @@
+package rules
+
+import (
+	"log/slog"
+	"net/http"
+
+	engine "github.com/ZiadMansourM/budgetly/pkg/rules"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewRulesApp creates a new categorization rules application with the provided database connection
+func NewRulesApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	ruleModel := newRuleModel(db, logger)
+	ruleService := newRuleService(ruleModel, logger)
+	newRuleHandler(ruleService, logger, router)
+}
+
+// Apply runs a household's rules over new transactions, e.g. entered by hand or imported
+// from a bank statement, and returns them as changed by the matching rules
+func Apply(db *sqlx.DB, logger *slog.Logger, householdID int64, transactions []engine.Transaction) ([]engine.Transaction, error) {
+	return newRuleService(newRuleModel(db, logger), logger).apply(householdID, transactions)
+}
+
+// Observe records that the user recategorized a payee's transaction by hand, which rule
+// suggestions are based on
+func Observe(db *sqlx.DB, logger *slog.Logger, householdID int64, payee, category string) error {
+	return newRuleService(newRuleModel(db, logger), logger).observe(householdID, payee, category)
+}
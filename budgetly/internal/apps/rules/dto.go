@@ -0,0 +1,171 @@
+package rules
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	engine "github.com/ZiadMansourM/budgetly/pkg/rules"
+	"github.com/lib/pq"
+)
+
+type Rule struct {
+	ID          int           `db:"id"`
+	HouseholdID int64         `db:"household_id"`
+	Name        string        `db:"name"`
+	Priority    int           `db:"priority"`
+	Conditions  conditionList `db:"conditions"`
+	Actions     actionList    `db:"actions"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
+
+// conditionList stores rule conditions in a JSONB column.
+type conditionList []engine.Condition
+
+// Value implements driver.Valuer for conditionList.
+func (c conditionList) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner for conditionList.
+func (c *conditionList) Scan(src any) error {
+	return scanJSON(src, c)
+}
+
+// actionList stores rule actions in a JSONB column.
+type actionList []engine.Action
+
+// Value implements driver.Valuer for actionList.
+func (a actionList) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+// Scan implements sql.Scanner for actionList.
+func (a *actionList) Scan(src any) error {
+	return scanJSON(src, a)
+}
+
+// scanJSON decodes a JSON column value into dest.
+func scanJSON(src any, dest any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dest)
+	}
+}
+
+// RuleRequest represents the input data for creating a new rule.
+type RuleRequest struct {
+	Name       string             `json:"name"`
+	Priority   int                `json:"priority"`
+	Conditions []engine.Condition `json:"conditions"`
+	Actions    []engine.Action    `json:"actions"`
+}
+
+// Validate validates the RuleRequest struct.
+func (input *RuleRequest) Validate() map[string]string {
+	return input.toEngineRule().Validate()
+}
+
+// toEngineRule converts the request into a rule the engine understands.
+func (input *RuleRequest) toEngineRule() *engine.Rule {
+	return &engine.Rule{
+		Name:       input.Name,
+		Priority:   input.Priority,
+		Conditions: input.Conditions,
+		Actions:    input.Actions,
+	}
+}
+
+// Recategorization records a user moving a payee's transaction to a category by hand.
+type Recategorization struct {
+	ID          int64     `db:"id"`
+	HouseholdID int64     `db:"household_id"`
+	Payee       string    `db:"payee"`
+	Category    string    `db:"category"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// RuleResponse represents the rule data to return in responses.
+type RuleResponse struct {
+	ID          int                `json:"id"`
+	HouseholdID int64              `json:"household_id"`
+	Name        string             `json:"name"`
+	Priority    int                `json:"priority"`
+	Conditions  []engine.Condition `json:"conditions"`
+	Actions     []engine.Action    `json:"actions"`
+}
+
+// ToResponse converts a Rule (from database) to a RuleResponse (for API responses).
+func (r *Rule) ToResponse() *RuleResponse {
+	return &RuleResponse{
+		ID:          r.ID,
+		HouseholdID: r.HouseholdID,
+		Name:        r.Name,
+		Priority:    r.Priority,
+		Conditions:  r.Conditions,
+		Actions:     r.Actions,
+	}
+}
+
+// toEngineRule converts a Rule (from database) into a rule the engine understands.
+func (r *Rule) toEngineRule() engine.Rule {
+	return engine.Rule{
+		ID:         r.ID,
+		Name:       r.Name,
+		Priority:   r.Priority,
+		Conditions: r.Conditions,
+		Actions:    r.Actions,
+	}
+}
+
+// DryRunFilter narrows the stored transactions a dry run replays the rules over to an
+// account and a date range. Zero values do not filter.
+type DryRunFilter struct {
+	AccountID int64
+	From      sql.NullTime
+	To        sql.NullTime
+}
+
+// StoredTransaction is a household's stored transaction as the rules see it.
+type StoredTransaction struct {
+	ID        int64          `db:"id"`
+	AccountID int64          `db:"account_id"`
+	Payee     string         `db:"payee"`
+	Memo      string         `db:"memo"`
+	Amount    money.Money    `db:"amount"`
+	Category  string         `db:"category"`
+	Cleared   bool           `db:"cleared"`
+	Tags      pq.StringArray `db:"tags"`
+}
+
+// toEngine converts a StoredTransaction into a transaction the engine understands.
+func (t *StoredTransaction) toEngine() engine.Transaction {
+	return engine.Transaction{
+		ID:        strconv.FormatInt(t.ID, 10),
+		AccountID: strconv.FormatInt(t.AccountID, 10),
+		Payee:     t.Payee,
+		Memo:      t.Memo,
+		Amount:    t.Amount.Amount(),
+		Category:  t.Category,
+		Tags:      t.Tags,
+		Cleared:   t.Cleared,
+	}
+}
+
+// DryRunResponse represents how a household's rules would change its stored transactions.
+type DryRunResponse struct {
+	HouseholdID int64 `json:"household_id"`
+	// Checked is the number of stored transactions the rules were replayed over.
+	Checked int             `json:"checked"`
+	Changes []engine.Change `json:"changes"`
+}
@@ -0,0 +1,8 @@
+package rules
+
+import "errors"
+
+var (
+	ErrInternalServer error = errors.New("internal server error")
+	ErrRuleNotFound   error = errors.New("rule not found")
+)
@@ -0,0 +1,210 @@
+package rules
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// ruleHandler is an HTTP handler for categorization rule operations
+// (e.g., creating, listing, dry-running rules, etc.)
+type ruleHandler struct {
+	ruleService *ruleService
+	logger      *slog.Logger
+	router      *http.ServeMux
+}
+
+// newRuleHandler creates a new rule handler with the provided rule service and logger
+func newRuleHandler(ruleService *ruleService, logger *slog.Logger, router *http.ServeMux) *ruleHandler {
+	ruleHandler := &ruleHandler{
+		ruleService: ruleService,
+		logger:      logger,
+		router:      router,
+	}
+	ruleHandler.registerRoutes()
+	return ruleHandler
+}
+
+// Register routes for rule-related actions
+func (h *ruleHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/rules", h.create)
+	h.router.HandleFunc("GET /households/{household}/rules", h.list)
+	h.router.HandleFunc("DELETE /rules/{id}", h.delete)
+	h.router.HandleFunc("GET /households/{household}/rules/dry-run", h.dryRun)
+	h.router.HandleFunc("GET /households/{household}/rules/suggestions", h.suggestions)
+}
+
+// Create is an HTTP handler for creating a new rule in a household
+func (h *ruleHandler) create(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req RuleRequest
+
+	// Decode the JSON request body
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return
+	}
+
+	rule, err := h.ruleService.create(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, rule)
+}
+
+// List is an HTTP handler for listing a household's rules in the order they are applied
+func (h *ruleHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	rules, err := h.ruleService.list(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, rules)
+}
+
+// Delete is an HTTP handler for deleting a rule by ID
+func (h *ruleHandler) delete(w http.ResponseWriter, r *http.Request) {
+	ruleID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || ruleID <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid rule ID"},
+		)
+		return
+	}
+
+	if err := h.ruleService.delete(ruleID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DryRun is an HTTP handler that shows how a household's rules would change its stored
+// transactions, optionally for one account_id and between from and to
+func (h *ruleHandler) dryRun(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	errs := map[string]string{}
+	query := r.URL.Query()
+	filter := DryRunFilter{
+		AccountID: queryID(query, "account_id", errs),
+		From:      queryDate(query, "from", errs),
+		To:        queryDate(query, "to", errs),
+	}
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+
+	dryRun, err := h.ruleService.dryRun(householdID, filter)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, dryRun)
+}
+
+// Suggestions is an HTTP handler for rules proposed from a household's manual recategorizations
+func (h *ruleHandler) suggestions(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	suggestions, err := h.ruleService.suggestions(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, suggestions)
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *ruleHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *ruleHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrRuleNotFound), errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling rule request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
+
+// queryID parses an optional positive ID query parameter, recording an error when it is invalid
+func queryID(query url.Values, name string, errs map[string]string) int64 {
+	value := query.Get(name)
+	if value == "" {
+		return 0
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		errs[name] = name + " must be a positive integer"
+		return 0
+	}
+	return id
+}
+
+// queryDate parses an optional YYYY-MM-DD query parameter, recording an error when it is invalid
+func queryDate(query url.Values, name string, errs map[string]string) sql.NullTime {
+	value := query.Get(name)
+	if value == "" {
+		return sql.NullTime{}
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		errs[name] = name + " must be in YYYY-MM-DD format"
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: date, Valid: true}
+}
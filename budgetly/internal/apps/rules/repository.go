@@ -0,0 +1,133 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package rules
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ruleModel wraps the database connection pool using sqlx
+type ruleModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newRuleModel(db *sqlx.DB, logger *slog.Logger) *ruleModel {
+	return &ruleModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new rule into the database and returns the inserted rule's ID
+func (m *ruleModel) create(r *Rule) (int, error) {
+	query := `INSERT INTO rules (household_id, name, priority, conditions, actions, created_at)
+	VALUES (:household_id, :name, :priority, :conditions, :actions, :created_at)
+	RETURNING id`
+
+	r.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, r)
+	if err != nil {
+		m.logger.Error("Error inserting rule", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&r.ID); err != nil {
+			m.logger.Error("Error scanning rule ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Rule created successfully", "id", r.ID)
+	return r.ID, nil
+}
+
+// List returns a household's rules in the order they are applied
+func (m *ruleModel) list(householdID int64) ([]Rule, error) {
+	query := `SELECT id, household_id, name, priority, conditions, actions, created_at
+	FROM rules WHERE household_id = $1 ORDER BY priority, id`
+
+	rules := []Rule{}
+	if err := m.DB.Select(&rules, query, householdID); err != nil {
+		m.logger.Error("Error listing rules", "error", err)
+		return nil, ErrInternalServer
+	}
+	return rules, nil
+}
+
+// Delete removes a rule by ID
+func (m *ruleModel) delete(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM rules WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting rule", "error", err)
+		return ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading deleted rule count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrRuleNotFound
+	}
+
+	m.logger.Debug("Rule deleted successfully", "id", id)
+	return nil
+}
+
+// Transactions returns a household's stored transactions matching the filter, oldest
+// first, with the names of their tags. Transfers and scheduled transactions are left out.
+func (m *ruleModel) transactions(householdID int64, filter DryRunFilter) ([]StoredTransaction, error) {
+	query := `SELECT t.id, t.account_id, t.payee, t.memo, t.amount, t.category, t.cleared,
+		COALESCE(array_agg(g.name ORDER BY g.name) FILTER (WHERE g.name IS NOT NULL), '{}') AS tags
+	FROM transactions t
+	LEFT JOIN transaction_tags tt ON tt.transaction_id = t.id
+	LEFT JOIN tags g ON g.id = tt.tag_id
+	WHERE t.household_id = $1
+		AND t.transfer_id IS NULL AND NOT t.scheduled
+		AND ($2 = 0 OR t.account_id = $2)
+		AND ($3::date IS NULL OR t.date >= $3)
+		AND ($4::date IS NULL OR t.date <= $4)
+	GROUP BY t.id
+	ORDER BY t.date, t.id`
+
+	transactions := []StoredTransaction{}
+	if err := m.DB.Select(&transactions, query, householdID, filter.AccountID, filter.From, filter.To); err != nil {
+		m.logger.Error("Error listing transactions for a rules dry run", "error", err)
+		return nil, ErrInternalServer
+	}
+	return transactions, nil
+}
+
+// RecordRecategorization stores a manual recategorization
+func (m *ruleModel) recordRecategorization(r *Recategorization) error {
+	query := `INSERT INTO recategorizations (household_id, payee, category, created_at)
+	VALUES (:household_id, :payee, :category, :created_at)`
+
+	r.CreatedAt = time.Now()
+	if _, err := m.DB.NamedExec(query, r); err != nil {
+		m.logger.Error("Error inserting recategorization", "error", err)
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// ListRecategorizations returns a household's manual recategorizations, oldest first
+func (m *ruleModel) listRecategorizations(householdID int64) ([]Recategorization, error) {
+	query := `SELECT id, household_id, payee, category, created_at
+	FROM recategorizations WHERE household_id = $1 ORDER BY id`
+
+	recategorizations := []Recategorization{}
+	if err := m.DB.Select(&recategorizations, query, householdID); err != nil {
+		m.logger.Error("Error listing recategorizations", "error", err)
+		return nil, ErrInternalServer
+	}
+	return recategorizations, nil
+}
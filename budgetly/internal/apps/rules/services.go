@@ -0,0 +1,166 @@
+package rules
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	engine "github.com/ZiadMansourM/budgetly/pkg/rules"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// suggestionThreshold is how many times a payee must be moved to the same category by hand
+// before a rule is suggested
+const suggestionThreshold = 3
+
+type ruleService struct {
+	ruleRepo *ruleModel
+	logger   *slog.Logger
+}
+
+func newRuleService(ruleRepo *ruleModel, logger *slog.Logger) *ruleService {
+	return &ruleService{
+		ruleRepo: ruleRepo,
+		logger:   logger,
+	}
+}
+
+// create validates and stores a new rule of a household
+func (s *ruleService) create(householdID int64, input RuleRequest) (*RuleResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Rule validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.ruleRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	rule := &Rule{
+		HouseholdID: householdID,
+		Name:        input.Name,
+		Priority:    input.Priority,
+		Conditions:  input.Conditions,
+		Actions:     input.Actions,
+	}
+
+	if _, err := s.ruleRepo.create(rule); err != nil {
+		return nil, err
+	}
+	return rule.ToResponse(), nil
+}
+
+// list returns a household's rules in the order they are applied
+func (s *ruleService) list(householdID int64) ([]*RuleResponse, error) {
+	if _, err := households.Get(s.ruleRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	rules, err := s.ruleRepo.list(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*RuleResponse, 0, len(rules))
+	for i := range rules {
+		responses = append(responses, rules[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// delete removes a rule by ID
+func (s *ruleService) delete(id int) error {
+	return s.ruleRepo.delete(id)
+}
+
+// dryRun replays a household's rules over its stored transactions matching the filter and
+// reports the transactions they would change, without saving anything
+func (s *ruleService) dryRun(householdID int64, filter DryRunFilter) (*DryRunResponse, error) {
+	if filter.From.Valid && filter.To.Valid && filter.To.Time.Before(filter.From.Time) {
+		return nil, &validate.ValidationError{Errors: map[string]string{"to": "to must not be before from"}}
+	}
+	if _, err := households.Get(s.ruleRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	e, err := s.engine(householdID)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.ruleRepo.transactions(householdID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]engine.Transaction, 0, len(stored))
+	for i := range stored {
+		transactions = append(transactions, stored[i].toEngine())
+	}
+	return &DryRunResponse{HouseholdID: householdID, Checked: len(stored), Changes: e.DryRun(transactions)}, nil
+}
+
+// apply runs a household's rules over the transactions and returns the changed transactions
+func (s *ruleService) apply(householdID int64, transactions []engine.Transaction) ([]engine.Transaction, error) {
+	e, err := s.engine(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]engine.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		after, _ := e.Apply(transaction)
+		applied = append(applied, after)
+	}
+	return applied, nil
+}
+
+// observe records that the user moved a payee's transaction to a category by hand
+func (s *ruleService) observe(householdID int64, payee, category string) error {
+	if strings.TrimSpace(payee) == "" || category == "" {
+		return nil
+	}
+	return s.ruleRepo.recordRecategorization(&Recategorization{HouseholdID: householdID, Payee: payee, Category: category})
+}
+
+// suggestions proposes rules for payees the household keeps recategorizing to the same
+// category, by replaying its recorded recategorizations into a suggester. Payees its rules
+// already categorize that way are left out.
+func (s *ruleService) suggestions(householdID int64) ([]*engine.Suggestion, error) {
+	if _, err := households.Get(s.ruleRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	e, err := s.engine(householdID)
+	if err != nil {
+		return nil, err
+	}
+	recategorizations, err := s.ruleRepo.listRecategorizations(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggester := engine.NewSuggester(suggestionThreshold)
+	suggestions := []*engine.Suggestion{}
+	for _, r := range recategorizations {
+		if suggestion, ok := suggester.Observe(e, r.Payee, r.Category); ok {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return suggestions, nil
+}
+
+// engine builds a rules engine from a household's stored rules
+func (s *ruleService) engine(householdID int64) (*engine.Engine, error) {
+	rules, err := s.ruleRepo.list(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	engineRules := make([]engine.Rule, 0, len(rules))
+	for i := range rules {
+		engineRules = append(engineRules, rules[i].toEngineRule())
+	}
+
+	e, err := engine.NewEngine(engineRules)
+	if err != nil {
+		s.logger.Error("Error compiling stored rules", "error", err)
+		return nil, ErrInternalServer
+	}
+	return e, nil
+}
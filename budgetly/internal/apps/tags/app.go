@@ -0,0 +1,24 @@
+package tags
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// NewTagsApp creates a new tags application with the provided database connection
+func NewTagsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	tagModel := newTagModel(db, logger)
+	tagService := newTagService(tagModel, logger)
+	newTagHandler(tagService, logger, router)
+}
+
+// AddByName tags a transaction of a household with the household's tags of the given names,
+// creating missing tags, e.g. for the add_tag action of categorization rules
+func AddByName(db *sqlx.DB, logger *slog.Logger, householdID, transactionID int64, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	return newTagModel(db, logger).addByName(householdID, transactionID, names)
+}
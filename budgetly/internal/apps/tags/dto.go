@@ -0,0 +1,108 @@
+package tags
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// defaultColor is used when a tag is created without a color.
+const defaultColor = "#808080"
+
+// Tag is a label a household attaches to its transactions. Names are unique per household.
+type Tag struct {
+	ID          int       `db:"id"`
+	HouseholdID int64     `db:"household_id"`
+	Name        string    `db:"name"`
+	Color       string    `db:"color"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// TagRequest represents the input data for creating or renaming a tag.
+type TagRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Validate validates the TagRequest struct.
+func (input *TagRequest) Validate() map[string]string {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Color == "" {
+		input.Color = defaultColor
+	}
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Name": validate.Rules(
+			validate.Required,
+			validate.Max(50),
+			validate.ErrorMessage("Name is required and must be at most 50 characters long"),
+		),
+		"Color": validate.Rules(
+			validate.Regex(`^#[0-9a-fA-F]{6}$`),
+			validate.ErrorMessage("Color must be a hex color such as #FF8800"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	return validate.Validate(*input, validationFields)
+}
+
+// MergeRequest represents the tag another tag should be merged into.
+type MergeRequest struct {
+	Into int `json:"into"`
+}
+
+// TagResponse represents the tag data to return in responses.
+type TagResponse struct {
+	ID          int    `json:"id"`
+	HouseholdID int64  `json:"household_id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+}
+
+// ToResponse converts a Tag (from database) to a TagResponse (for API responses).
+func (t *Tag) ToResponse() *TagResponse {
+	return &TagResponse{
+		ID:          t.ID,
+		HouseholdID: t.HouseholdID,
+		Name:        t.Name,
+		Color:       t.Color,
+	}
+}
+
+// TagTotal is the sum of a tag's transactions in one currency, as aggregated by the database.
+type TagTotal struct {
+	TagID    int    `db:"tag_id"`
+	Name     string `db:"name"`
+	Color    string `db:"color"`
+	Currency string `db:"currency"`
+	Total    string `db:"total"`
+	Count    int    `db:"count"`
+}
+
+// amount returns the total as Money.
+func (t *TagTotal) amount() (money.Money, error) {
+	return money.Parse(t.Total, t.Currency)
+}
+
+// ReportResponse represents the totals of a household's tagged transactions between two
+// dates. Transfers between accounts are left out.
+type ReportResponse struct {
+	HouseholdID int64               `json:"household_id"`
+	From        string              `json:"from,omitempty"`
+	To          string              `json:"to,omitempty"`
+	Tags        []*TagTotalResponse `json:"tags"`
+}
+
+// TagTotalResponse represents a tag's totals per currency in a report.
+type TagTotalResponse struct {
+	ID     int           `json:"id"`
+	Name   string        `json:"name"`
+	Color  string        `json:"color"`
+	Count  int           `json:"count"`
+	Totals report.Totals `json:"totals"`
+}
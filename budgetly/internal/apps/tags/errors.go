@@ -0,0 +1,12 @@
+package tags
+
+import "errors"
+
+var (
+	ErrInternalServer      error = errors.New("internal server error")
+	ErrTagNotFound         error = errors.New("tag not found")
+	ErrTagExists           error = errors.New("a tag with this name already exists")
+	ErrMergeIntoSelf       error = errors.New("a tag cannot be merged into itself")
+	ErrNotTagged           error = errors.New("the transaction does not have this tag")
+	ErrTransactionNotFound error = errors.New("transaction not found")
+)
@@ -0,0 +1,294 @@
+package tags
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// tagHandler is an HTTP handler for tag-related operations
+// (e.g., creating, renaming, merging tags, tagging transactions, etc.)
+type tagHandler struct {
+	tagService *tagService
+	logger     *slog.Logger
+	router     *http.ServeMux
+}
+
+// newTagHandler creates a new tag handler with the provided tag service and logger
+func newTagHandler(tagService *tagService, logger *slog.Logger, router *http.ServeMux) *tagHandler {
+	tagHandler := &tagHandler{
+		tagService: tagService,
+		logger:     logger,
+		router:     router,
+	}
+	tagHandler.registerRoutes()
+	return tagHandler
+}
+
+// Register routes for tag-related actions
+func (h *tagHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/tags", h.create)
+	h.router.HandleFunc("GET /households/{household}/tags", h.list)
+	h.router.HandleFunc("GET /households/{household}/tags/report", h.report)
+	h.router.HandleFunc("PUT /tags/{id}", h.update)
+	h.router.HandleFunc("POST /tags/{id}/merge", h.merge)
+	h.router.HandleFunc("DELETE /tags/{id}", h.delete)
+	h.router.HandleFunc("POST /transactions/{transaction}/tags/{tag}", h.attach)
+	h.router.HandleFunc("DELETE /transactions/{transaction}/tags/{tag}", h.detach)
+}
+
+// Create is an HTTP handler for creating a new tag in a household
+func (h *tagHandler) create(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req TagRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	tag, err := h.tagService.create(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, tag)
+}
+
+// List is an HTTP handler for listing a household's tags
+func (h *tagHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	tags, err := h.tagService.list(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, tags)
+}
+
+// Report is an HTTP handler for the totals of a household's tagged transactions per tag,
+// optionally between from and to
+func (h *tagHandler) report(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	errs := map[string]string{}
+	query := r.URL.Query()
+	from, to := queryDate(query, "from", errs), queryDate(query, "to", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+
+	report, err := h.tagService.report(householdID, from, to)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, report)
+}
+
+// Update is an HTTP handler for renaming or recoloring a tag
+func (h *tagHandler) update(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req TagRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	tag, err := h.tagService.update(tagID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, tag)
+}
+
+// Merge is an HTTP handler for merging a tag into another one
+func (h *tagHandler) merge(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	var req MergeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	tag, err := h.tagService.merge(tagID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, tag)
+}
+
+// Delete is an HTTP handler for deleting a tag
+func (h *tagHandler) delete(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := h.pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.delete(tagID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Attach is an HTTP handler for tagging a transaction
+func (h *tagHandler) attach(w http.ResponseWriter, r *http.Request) {
+	transactionID, tagID, ok := h.transactionTag(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.attach(transactionID, tagID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Detach is an HTTP handler for removing a tag from a transaction
+func (h *tagHandler) detach(w http.ResponseWriter, r *http.Request) {
+	transactionID, tagID, ok := h.transactionTag(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.tagService.detach(transactionID, tagID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *tagHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses the tag ID from the URL, writing a 400 response on failure
+func (h *tagHandler) pathID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	tagID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || tagID <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid tag ID"},
+		)
+		return 0, false
+	}
+	return tagID, true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *tagHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// transactionTag parses the transaction and tag path parameters, writing a 400 response on failure
+func (h *tagHandler) transactionTag(w http.ResponseWriter, r *http.Request) (int64, int, bool) {
+	transactionID, err := strconv.ParseInt(r.PathValue("transaction"), 10, 64)
+	if err != nil || transactionID <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid transaction ID"},
+		)
+		return 0, 0, false
+	}
+	tagID, err := strconv.Atoi(r.PathValue("tag"))
+	if err != nil || tagID <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid tag ID"},
+		)
+		return 0, 0, false
+	}
+	return transactionID, tagID, true
+}
+
+// queryDate parses an optional YYYY-MM-DD query parameter, recording an error when it is invalid
+func queryDate(query url.Values, name string, errs map[string]string) sql.NullTime {
+	value := query.Get(name)
+	if value == "" {
+		return sql.NullTime{}
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		errs[name] = name + " must be in YYYY-MM-DD format"
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: date, Valid: true}
+}
+
+// writeError maps service errors to HTTP responses
+func (h *tagHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrMergeIntoSelf):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrTagNotFound), errors.Is(err, ErrNotTagged), errors.Is(err, ErrTransactionNotFound),
+		errors.Is(err, households.ErrHouseholdNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrTagExists):
+		utils.WriteJson(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling tag request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
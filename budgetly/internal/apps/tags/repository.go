@@ -0,0 +1,263 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package tags
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code for unique constraint violations
+const uniqueViolation = "23505"
+
+// tagColumns lists the columns selected for a Tag
+const tagColumns = `id, household_id, name, color, created_at`
+
+// tagModel wraps the database connection pool using sqlx
+type tagModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newTagModel(db *sqlx.DB, logger *slog.Logger) *tagModel {
+	return &tagModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// Create inserts a new tag into the database and returns the inserted tag's ID
+func (m *tagModel) create(t *Tag) (int, error) {
+	query := `INSERT INTO tags (household_id, name, color, created_at)
+	VALUES (:household_id, :name, :color, :created_at)
+	RETURNING id`
+
+	t.CreatedAt = time.Now()
+
+	rows, err := m.DB.NamedQuery(query, t)
+	if err != nil {
+		return 0, m.translate("Error inserting tag", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&t.ID); err != nil {
+			m.logger.Error("Error scanning tag ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Tag created successfully", "id", t.ID)
+	return t.ID, nil
+}
+
+// List returns a household's tags ordered by name
+func (m *tagModel) list(householdID int64) ([]Tag, error) {
+	tags := []Tag{}
+	if err := m.DB.Select(&tags, `SELECT `+tagColumns+` FROM tags WHERE household_id = $1 ORDER BY name`, householdID); err != nil {
+		m.logger.Error("Error listing tags", "error", err)
+		return nil, ErrInternalServer
+	}
+	return tags, nil
+}
+
+// GetByID returns a tag by ID
+func (m *tagModel) getByID(id int) (*Tag, error) {
+	t := &Tag{}
+	err := m.DB.Get(t, `SELECT `+tagColumns+` FROM tags WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting tag by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return t, nil
+}
+
+// Update renames or recolors a tag
+func (m *tagModel) update(t *Tag) error {
+	result, err := m.DB.NamedExec(`UPDATE tags SET name = :name, color = :color WHERE id = :id`, t)
+	if err != nil {
+		return m.translate("Error updating tag", err)
+	}
+	return m.expectRow(result)
+}
+
+// Merge moves every transaction tagged with source to target and deletes source,
+// in a single transaction
+func (m *tagModel) merge(sourceID, targetID int) error {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting tag merge transaction", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO transaction_tags (transaction_id, tag_id)
+	SELECT transaction_id, $2 FROM transaction_tags WHERE tag_id = $1
+	ON CONFLICT DO NOTHING`, sourceID, targetID)
+	if err != nil {
+		m.logger.Error("Error moving tagged transactions", "error", err)
+		return ErrInternalServer
+	}
+
+	result, err := tx.Exec(`DELETE FROM tags WHERE id = $1`, sourceID)
+	if err != nil {
+		m.logger.Error("Error deleting merged tag", "error", err)
+		return ErrInternalServer
+	}
+	if err := m.expectRow(result); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing tag merge", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Tag merged successfully", "source", sourceID, "target", targetID)
+	return nil
+}
+
+// Delete removes a tag by ID, untagging its transactions
+func (m *tagModel) delete(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting tag", "error", err)
+		return ErrInternalServer
+	}
+	return m.expectRow(result)
+}
+
+// TransactionHousehold returns the household a transaction belongs to
+func (m *tagModel) transactionHousehold(transactionID int64) (int64, error) {
+	var householdID int64
+	err := m.DB.Get(&householdID, `SELECT household_id FROM transactions WHERE id = $1`, transactionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrTransactionNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting transaction household", "error", err)
+		return 0, ErrInternalServer
+	}
+	return householdID, nil
+}
+
+// Attach tags a transaction. Tagging it again has no effect.
+func (m *tagModel) attach(transactionID int64, tagID int) error {
+	query := `INSERT INTO transaction_tags (transaction_id, tag_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING`
+
+	if _, err := m.DB.Exec(query, transactionID, tagID); err != nil {
+		m.logger.Error("Error tagging transaction", "error", err)
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// Detach removes a tag from a transaction
+func (m *tagModel) detach(transactionID int64, tagID int) error {
+	result, err := m.DB.Exec(`DELETE FROM transaction_tags WHERE transaction_id = $1 AND tag_id = $2`, transactionID, tagID)
+	if err != nil {
+		m.logger.Error("Error untagging transaction", "error", err)
+		return ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected transaction tag count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrNotTagged
+	}
+	return nil
+}
+
+// AddByName tags a transaction with the household's tags of the given names, creating the
+// tags that do not exist yet with the default color, in a single transaction
+func (m *tagModel) addByName(householdID, transactionID int64, names []string) error {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting tag transaction", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO tags (household_id, name, color, created_at)
+	SELECT $1, name, $3, $4 FROM UNNEST($2::text[]) AS name
+	ON CONFLICT (household_id, name) DO NOTHING`, householdID, pq.Array(names), defaultColor, time.Now())
+	if err != nil {
+		m.logger.Error("Error creating tags by name", "error", err)
+		return ErrInternalServer
+	}
+
+	_, err = tx.Exec(`INSERT INTO transaction_tags (transaction_id, tag_id)
+	SELECT $1, id FROM tags WHERE household_id = $2 AND name = ANY($3)
+	ON CONFLICT DO NOTHING`, transactionID, householdID, pq.Array(names))
+	if err != nil {
+		m.logger.Error("Error tagging transaction by name", "error", err)
+		return ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing tags", "error", err)
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// Totals sums a household's tagged transactions per tag and currency between two dates,
+// leaving out transfers between accounts. Amounts are stored as "<amount> <currency>".
+func (m *tagModel) totals(householdID int64, from, to sql.NullTime) ([]TagTotal, error) {
+	query := `SELECT t.id AS tag_id, t.name, t.color,
+		split_part(x.amount, ' ', 2) AS currency,
+		SUM(split_part(x.amount, ' ', 1)::numeric)::text AS total,
+		COUNT(*) AS count
+	FROM tags t
+	JOIN transaction_tags tt ON tt.tag_id = t.id
+	JOIN transactions x ON x.id = tt.transaction_id
+	WHERE t.household_id = $1
+		AND x.transfer_id IS NULL
+		AND ($2::date IS NULL OR x.date >= $2)
+		AND ($3::date IS NULL OR x.date <= $3)
+	GROUP BY t.id, t.name, t.color, currency
+	ORDER BY t.name, t.id, currency`
+
+	totals := []TagTotal{}
+	if err := m.DB.Select(&totals, query, householdID, from, to); err != nil {
+		m.logger.Error("Error summing tagged transactions", "error", err)
+		return nil, ErrInternalServer
+	}
+	return totals, nil
+}
+
+// expectRow returns ErrTagNotFound when a statement affected no rows
+func (m *tagModel) expectRow(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected tag count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// translate maps database errors to tag errors, hiding internal details from clients
+func (m *tagModel) translate(message string, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return ErrTagExists
+	}
+	m.logger.Error(message, "error", err)
+	return ErrInternalServer
+}
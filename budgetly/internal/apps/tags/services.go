@@ -0,0 +1,174 @@
+package tags
+
+import (
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+type tagService struct {
+	tagRepo *tagModel
+	logger  *slog.Logger
+}
+
+func newTagService(tagRepo *tagModel, logger *slog.Logger) *tagService {
+	return &tagService{
+		tagRepo: tagRepo,
+		logger:  logger,
+	}
+}
+
+// create validates and stores a new tag of a household
+func (s *tagService) create(householdID int64, input TagRequest) (*TagResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Tag validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.tagRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	tag := &Tag{HouseholdID: householdID, Name: input.Name, Color: input.Color}
+	if _, err := s.tagRepo.create(tag); err != nil {
+		return nil, err
+	}
+	return tag.ToResponse(), nil
+}
+
+// list returns a household's tags ordered by name
+func (s *tagService) list(householdID int64) ([]*TagResponse, error) {
+	if _, err := households.Get(s.tagRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.list(householdID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*TagResponse, 0, len(tags))
+	for i := range tags {
+		responses = append(responses, tags[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// update renames or recolors a tag
+func (s *tagService) update(id int, input TagRequest) (*TagResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Tag validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	tag, err := s.tagRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	tag.Name, tag.Color = input.Name, input.Color
+	if err := s.tagRepo.update(tag); err != nil {
+		return nil, err
+	}
+	return tag.ToResponse(), nil
+}
+
+// merge folds the source tag into another tag of the same household and returns the target
+func (s *tagService) merge(sourceID int, input MergeRequest) (*TagResponse, error) {
+	if sourceID == input.Into {
+		return nil, ErrMergeIntoSelf
+	}
+
+	source, err := s.tagRepo.getByID(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.tagRepo.getByID(input.Into)
+	if err != nil {
+		return nil, err
+	}
+	if target.HouseholdID != source.HouseholdID {
+		return nil, &validate.ValidationError{Errors: map[string]string{"Into": "Into must be a tag of the same household"}}
+	}
+	if err := s.tagRepo.merge(sourceID, target.ID); err != nil {
+		return nil, err
+	}
+	return target.ToResponse(), nil
+}
+
+// delete removes a tag by ID
+func (s *tagService) delete(id int) error {
+	return s.tagRepo.delete(id)
+}
+
+// attach tags a transaction with a tag of its household
+func (s *tagService) attach(transactionID int64, tagID int) error {
+	if err := s.checkSameHousehold(transactionID, tagID); err != nil {
+		return err
+	}
+	return s.tagRepo.attach(transactionID, tagID)
+}
+
+// detach removes a tag from a transaction
+func (s *tagService) detach(transactionID int64, tagID int) error {
+	if err := s.checkSameHousehold(transactionID, tagID); err != nil {
+		return err
+	}
+	return s.tagRepo.detach(transactionID, tagID)
+}
+
+// checkSameHousehold ensures the transaction and the tag exist and belong to the same
+// household. A tag of another household is reported as not found.
+func (s *tagService) checkSameHousehold(transactionID int64, tagID int) error {
+	householdID, err := s.tagRepo.transactionHousehold(transactionID)
+	if err != nil {
+		return err
+	}
+	tag, err := s.tagRepo.getByID(tagID)
+	if err != nil {
+		return err
+	}
+	if tag.HouseholdID != householdID {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// report sums a household's tagged transactions per tag between from and to
+func (s *tagService) report(householdID int64, from, to sql.NullTime) (*ReportResponse, error) {
+	if _, err := households.Get(s.tagRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	totals, err := s.tagRepo.totals(householdID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReportResponse{HouseholdID: householdID, Tags: []*TagTotalResponse{}}
+	if from.Valid {
+		response.From = from.Time.Format(time.DateOnly)
+	}
+	if to.Valid {
+		response.To = to.Time.Format(time.DateOnly)
+	}
+
+	var current *TagTotalResponse
+	for i := range totals {
+		total := &totals[i]
+		if current == nil || current.ID != total.TagID {
+			current = &TagTotalResponse{ID: total.TagID, Name: total.Name, Color: total.Color, Totals: report.Totals{}}
+			response.Tags = append(response.Tags, current)
+		}
+		amount, err := total.amount()
+		if err != nil {
+			s.logger.Error("Error parsing tag total", "tag_id", total.TagID, "error", err)
+			return nil, ErrInternalServer
+		}
+		if err := current.Totals.Add(amount); err != nil {
+			return nil, err
+		}
+		current.Count += total.Count
+	}
+	return response, nil
+}
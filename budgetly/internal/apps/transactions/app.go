@@ -0,0 +1,80 @@
+package transactions
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/jmoiron/sqlx"
+)
+
+// NewTransactionsApp creates a new transactions application with the provided database connection
+func NewTransactionsApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	transactionModel := newTransactionModel(db, logger)
+	transactionService := newTransactionService(transactionModel, logger)
+	newTransactionHandler(transactionService, logger, router)
+}
+
+// List returns a household's transactions matching the account_id, tag, from and to
+// parameters of a transaction listing query, such as a saved filter's
+func List(db *sqlx.DB, logger *slog.Logger, householdID int64, query url.Values) ([]*TransactionResponse, error) {
+	filter, err := parseFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	return newTransactionService(newTransactionModel(db, logger), logger).list(householdID, filter)
+}
+
+// ValidateQuery checks the parameters of a transaction listing query, such as a saved
+// filter's, returning a validation error for unknown parameters or malformed values
+func ValidateQuery(query url.Values) error {
+	errs := map[string]string{}
+	for name := range query {
+		if !filterParameters[name] {
+			errs[name] = name + " is not a transaction filter parameter"
+		}
+	}
+	var validationErr *validate.ValidationError
+	if _, err := parseFilter(query); errors.As(err, &validationErr) {
+		for name, message := range validationErr.Errors {
+			errs[name] = message
+		}
+	}
+	if len(errs) > 0 {
+		return &validate.ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// Get returns a transaction by ID, e.g. to check which household it belongs to
+func Get(db *sqlx.DB, logger *slog.Logger, id int64) (*TransactionResponse, error) {
+	return newTransactionService(newTransactionModel(db, logger), logger).get(id)
+}
+
+// Subscriptions returns the recurring payments detected in a household's transactions up
+// to a date, like GET /households/{household}/subscriptions
+func Subscriptions(db *sqlx.DB, logger *slog.Logger, householdID int64, asOf time.Time) ([]*SubscriptionResponse, error) {
+	return newTransactionService(newTransactionModel(db, logger), logger).subscriptions(householdID, asOf)
+}
+
+// ReplaceSplits replaces a transaction's split lines under the same checks as
+// PUT /transactions/{id}/splits, e.g. to split a loan payment into interest and principal
+func ReplaceSplits(db *sqlx.DB, logger *slog.Logger, id int64, input SplitsRequest, force bool) (*TransactionResponse, error) {
+	return newTransactionService(newTransactionModel(db, logger), logger).replaceSplits(id, input, force)
+}
+
+// CreateTx validates a transaction of the household and stores it with its balance change
+// within the caller's database transaction, with the same checks and categorization as the
+// transactions API, e.g. for reconciliation adjustments. Once tx commits, call committed to
+// run the side effects of the new transaction, such as alerts and webhooks.
+func CreateTx(db *sqlx.DB, tx *sqlx.Tx, logger *slog.Logger, householdID int64, input TransactionRequest) (created *TransactionResponse, committed func(), err error) {
+	service := newTransactionService(newTransactionModel(db, logger), logger)
+	transaction, err := service.createTx(tx, householdID, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	return transaction.ToResponse(), func() { service.committed(transaction) }, nil
+}
@@ -0,0 +1,577 @@
+package transactions
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZiadMansourM/budgetly/pkg/alerts"
+	"github.com/ZiadMansourM/budgetly/pkg/balances"
+	"github.com/ZiadMansourM/budgetly/pkg/classify"
+	"github.com/ZiadMansourM/budgetly/pkg/dedupe"
+	"github.com/ZiadMansourM/budgetly/pkg/export"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/reconcile"
+	"github.com/ZiadMansourM/budgetly/pkg/recurring"
+	engine "github.com/ZiadMansourM/budgetly/pkg/rules"
+	"github.com/ZiadMansourM/budgetly/pkg/splits"
+	"github.com/ZiadMansourM/budgetly/pkg/statement"
+	"github.com/ZiadMansourM/budgetly/pkg/transfers"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/jmoiron/sqlx"
+)
+
+// Transaction is a booking on one of a household's accounts, in the account's currency.
+// Outflows are negative. ExternalID is the bank's reference for imported transactions, and
+// Scheduled marks a transaction entered ahead of the bank from a schedule, which an
+// import merges its booking into. TransferID links the two legs of a transfer between
+// accounts to each other. Reconciled transactions were locked by a finished reconciliation.
+// Splits divide the amount across several categories, replacing the transaction's own.
+type Transaction struct {
+	ID          int64          `db:"id"`
+	HouseholdID int64          `db:"household_id"`
+	AccountID   int64          `db:"account_id"`
+	Date        time.Time      `db:"date"`
+	Amount      money.Money    `db:"amount"`
+	Payee       string         `db:"payee"`
+	Memo        string         `db:"memo"`
+	Category    string         `db:"category"`
+	Cleared     bool           `db:"cleared"`
+	Scheduled   bool           `db:"scheduled"`
+	Reconciled  bool           `db:"reconciled"`
+	ExternalID  sql.NullString `db:"external_id"`
+	TransferID  sql.NullInt64  `db:"transfer_id"`
+	CreatedAt   time.Time      `db:"created_at"`
+	Splits      []splits.Split `db:"-"`
+
+	// ruleTags are the tags categorization rules added, attached once the transaction is stored.
+	ruleTags []string
+	// payeeCategory is the default category of the payee an imported line resolved to,
+	// used when no categorization rule sets a category.
+	payeeCategory string
+}
+
+// fromLine converts a statement line into a cleared transaction of the account. The
+// counterparty is the payee and the remittance information the memo.
+func fromLine(householdID, accountID int64, line statement.Line, currency string) (*Transaction, error) {
+	amount, err := money.New(line.Amount, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{
+		HouseholdID: householdID,
+		AccountID:   accountID,
+		Date:        line.BookingDate,
+		Amount:      amount,
+		Payee:       truncate(line.CounterpartyName, 200),
+		Memo:        line.RemittanceInfo,
+		Cleared:     true,
+		ExternalID:  sql.NullString{String: line.Reference, Valid: line.Reference != ""},
+	}, nil
+}
+
+// change returns the balance change the transaction posts to its account, or nil for
+// no transaction.
+func (t *Transaction) change() *balances.Change {
+	if t == nil {
+		return nil
+	}
+	return &balances.Change{AccountID: t.AccountID, Date: t.Date, Amount: t.Amount}
+}
+
+// toDedupe returns the transaction as the duplicate matcher sees it, identified by id.
+func (t *Transaction) toDedupe(id string) dedupe.Transaction {
+	return dedupe.Transaction{
+		ID:         id,
+		ExternalID: t.ExternalID.String,
+		Date:       t.Date,
+		Amount:     t.Amount.Amount(),
+		Payee:      t.Payee,
+		Scheduled:  t.Scheduled,
+	}
+}
+
+// merge returns the transaction with an imported duplicate folded into it: it takes the
+// bank's reference and booking date, and is cleared and no longer scheduled.
+func (t *Transaction) merge(imported *Transaction) *Transaction {
+	fields := dedupe.Merge(t.toDedupe(""), imported.toDedupe(""))
+	merged := *t
+	merged.ExternalID = sql.NullString{String: fields.ExternalID, Valid: fields.ExternalID != ""}
+	merged.Date = fields.Date
+	merged.Payee = fields.Payee
+	merged.Scheduled = fields.Scheduled
+	merged.Cleared = true
+	return &merged
+}
+
+// toExample returns the transaction as the category classifier sees it.
+func (t *Transaction) toExample() classify.Example {
+	return classify.Example{
+		Payee:    t.Payee,
+		Memo:     t.Memo,
+		Amount:   t.Amount.Amount(),
+		Currency: t.Amount.Currency(),
+		Category: t.Category,
+	}
+}
+
+// toRule returns the transaction as categorization rules see it.
+func (t *Transaction) toRule() engine.Transaction {
+	return engine.Transaction{
+		ID:        strconv.FormatInt(t.ID, 10),
+		AccountID: strconv.FormatInt(t.AccountID, 10),
+		Payee:     t.Payee,
+		Memo:      t.Memo,
+		Amount:    t.Amount.Amount(),
+		Category:  t.Category,
+		Cleared:   t.Cleared,
+	}
+}
+
+// applyRules takes the payee, category, cleared state and tags set by the matching rules.
+// A category already chosen by the user is kept, and the payee's default category is
+// taken when no rule sets one.
+func (t *Transaction) applyRules(applied engine.Transaction) {
+	t.Payee = truncate(applied.Payee, 200)
+	t.Cleared = applied.Cleared
+	if t.Category == "" {
+		t.Category = truncate(applied.Category, 100)
+	}
+	if t.Category == "" {
+		t.Category = truncate(t.payeeCategory, 100)
+	}
+	t.ruleTags = t.ruleTags[:0]
+	for _, tag := range applied.Tags {
+		if tag = truncate(strings.TrimSpace(tag), 50); tag != "" {
+			t.ruleTags = append(t.ruleTags, tag)
+		}
+	}
+}
+
+// toAlert returns the transaction as alert rules see it.
+func (t *Transaction) toAlert() *alerts.Transaction {
+	return &alerts.Transaction{
+		ID:        t.ID,
+		AccountID: t.AccountID,
+		Category:  t.Category,
+		Payee:     t.Payee,
+		Amount:    t.Amount,
+		Date:      t.Date,
+	}
+}
+
+// TransactionRequest represents the input data for creating or updating a transaction.
+type TransactionRequest struct {
+	AccountID int64       `json:"account_id"`
+	Date      string      `json:"date"`
+	Amount    money.Money `json:"amount"`
+	Payee     string      `json:"payee"`
+	Memo      string      `json:"memo"`
+	Category  string      `json:"category"`
+	Cleared   bool        `json:"cleared"`
+	Scheduled bool        `json:"scheduled"`
+	// Splits divide the amount across categories; leave empty for a single category.
+	Splits []splits.Split `json:"splits"`
+}
+
+// Validate validates the TransactionRequest struct.
+func (input *TransactionRequest) Validate() map[string]string {
+	input.Payee = strings.TrimSpace(input.Payee)
+	input.Memo = strings.TrimSpace(input.Memo)
+	input.Category = strings.TrimSpace(input.Category)
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Amount": validate.Rules(
+			money.Valid,
+		),
+		"Payee": validate.Rules(
+			validate.Max(200),
+			validate.ErrorMessage("Payee must be at most 200 characters long"),
+		),
+		"Category": validate.Rules(
+			validate.Max(100),
+			validate.ErrorMessage("Category must be at most 100 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if input.AccountID <= 0 {
+		errors["AccountID"] = "AccountID is required"
+	}
+	if _, err := time.Parse(time.DateOnly, input.Date); err != nil {
+		errors["Date"] = "Date is required and must be in YYYY-MM-DD format"
+	}
+	for field, message := range validateSplits(input.Amount, input.Splits) {
+		errors[field] = message
+	}
+	return errors
+}
+
+// validateSplits trims the split lines and checks them against the parent amount.
+func validateSplits(parent money.Money, lines []splits.Split) map[string]string {
+	for i := range lines {
+		lines[i].Category = strings.TrimSpace(lines[i].Category)
+		lines[i].Memo = strings.TrimSpace(lines[i].Memo)
+	}
+	errors := splits.Validate(parent, lines)
+	for i, line := range lines {
+		if utf8.RuneCountInString(line.Category) > 100 {
+			errors[fmt.Sprintf("Splits[%d]", i)] = "Category must be at most 100 characters long"
+		}
+	}
+	return errors
+}
+
+// toTransaction converts a validated TransactionRequest into a Transaction of the household.
+func (input *TransactionRequest) toTransaction(householdID int64) *Transaction {
+	date, _ := time.Parse(time.DateOnly, input.Date)
+	return &Transaction{
+		HouseholdID: householdID,
+		AccountID:   input.AccountID,
+		Date:        date,
+		Amount:      input.Amount,
+		Payee:       input.Payee,
+		Memo:        input.Memo,
+		Category:    input.Category,
+		Cleared:     input.Cleared,
+		Scheduled:   input.Scheduled,
+		Splits:      input.Splits,
+	}
+}
+
+// SplitsRequest represents the split lines to replace a transaction's with. Either list the
+// Splits, or name the Categories and the Ratios to divide the amount by, e.g. 50/30/20.
+// An empty request removes the splits.
+type SplitsRequest struct {
+	Splits     []splits.Split `json:"splits"`
+	Categories []string       `json:"categories"`
+	Ratios     []int64        `json:"ratios"`
+}
+
+// toSplits returns the split lines of a transaction of the parent amount, validated.
+func (input *SplitsRequest) toSplits(parent money.Money) ([]splits.Split, map[string]string) {
+	lines := input.Splits
+	if len(input.Ratios) > 0 || len(input.Categories) > 0 {
+		if len(lines) > 0 {
+			return nil, map[string]string{"Splits": "Give either splits or categories and ratios, not both"}
+		}
+		even, err := splits.Even(parent, input.Categories, input.Ratios)
+		if err != nil {
+			return nil, map[string]string{"Ratios": err.Error()}
+		}
+		lines = even
+	}
+	if errors := validateSplits(parent, lines); len(errors) > 0 {
+		return nil, errors
+	}
+	return lines, nil
+}
+
+// TransactionResponse represents the transaction data to return in responses.
+type TransactionResponse struct {
+	ID          int64       `json:"id"`
+	HouseholdID int64       `json:"household_id"`
+	AccountID   int64       `json:"account_id"`
+	Date        string      `json:"date"`
+	Amount      money.Money `json:"amount"`
+	Payee       string      `json:"payee"`
+	Memo        string      `json:"memo,omitempty"`
+	Category    string      `json:"category,omitempty"`
+	Cleared     bool        `json:"cleared"`
+	Scheduled   bool        `json:"scheduled,omitempty"`
+	Reconciled  bool        `json:"reconciled,omitempty"`
+	ExternalID  string      `json:"external_id,omitempty"`
+	TransferID  int64       `json:"transfer_id,omitempty"`
+	// Splits are set when getting a single transaction that is split.
+	Splits []splits.Split `json:"splits,omitempty"`
+	// Converted is the amount in the household's reporting currency at the transaction
+	// date, set when listing a household's transactions and a rate is known.
+	Converted *money.Money `json:"converted,omitempty"`
+}
+
+// ToResponse converts a Transaction (from database) to a TransactionResponse (for API responses).
+func (t *Transaction) ToResponse() *TransactionResponse {
+	return &TransactionResponse{
+		ID:          t.ID,
+		HouseholdID: t.HouseholdID,
+		AccountID:   t.AccountID,
+		Date:        t.Date.Format(time.DateOnly),
+		Amount:      t.Amount,
+		Payee:       t.Payee,
+		Memo:        t.Memo,
+		Category:    t.Category,
+		Cleared:     t.Cleared,
+		Scheduled:   t.Scheduled,
+		Reconciled:  t.Reconciled,
+		ExternalID:  t.ExternalID.String,
+		TransferID:  t.TransferID.Int64,
+		Splits:      t.Splits,
+	}
+}
+
+// exportColumns are the columns of a transaction list export
+var exportColumns = []export.Column{
+	{Title: "Date", Kind: export.Date},
+	{Title: "Account", Kind: export.Number},
+	{Title: "Payee", Kind: export.Text},
+	{Title: "Category", Kind: export.Text},
+	{Title: "Memo", Kind: export.Text},
+	{Title: "Amount", Kind: export.Currency},
+}
+
+// transactionRows streams listed transactions into an export, one database row at a time
+type transactionRows struct {
+	rows *sqlx.Rows
+}
+
+// Next scans the next transaction into an export row, returning io.EOF after the last one
+func (t *transactionRows) Next() ([]any, error) {
+	if !t.rows.Next() {
+		if err := t.rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	var transaction Transaction
+	if err := t.rows.StructScan(&transaction); err != nil {
+		return nil, err
+	}
+	return []any{transaction.Date, transaction.AccountID, transaction.Payee, transaction.Category, transaction.Memo, transaction.Amount}, nil
+}
+
+// Close releases the database rows
+func (t *transactionRows) Close() error {
+	return t.rows.Close()
+}
+
+// toReconcile returns the transaction as the reconciliation guard sees it.
+func (t *Transaction) toReconcile() reconcile.Transaction {
+	status := reconcile.StatusUncleared
+	switch {
+	case t.Reconciled:
+		status = reconcile.StatusReconciled
+	case t.Cleared:
+		status = reconcile.StatusCleared
+	}
+	return reconcile.Transaction{
+		ID:     strconv.FormatInt(t.ID, 10),
+		Date:   t.Date,
+		Payee:  t.Payee,
+		Amount: t.Amount,
+		Status: status,
+	}
+}
+
+// Candidate is an imported transaction held for review because it looks like a duplicate
+// of an existing one.
+type Candidate struct {
+	ID          int64          `db:"id"`
+	HouseholdID int64          `db:"household_id"`
+	AccountID   int64          `db:"account_id"`
+	ExistingID  int64          `db:"existing_id"`
+	Date        time.Time      `db:"date"`
+	Amount      money.Money    `db:"amount"`
+	Payee       string         `db:"payee"`
+	Memo        string         `db:"memo"`
+	ExternalID  sql.NullString `db:"external_id"`
+	Score       float64        `db:"score"`
+	Status      dedupe.Status  `db:"status"`
+	CreatedAt   time.Time      `db:"created_at"`
+	ResolvedAt  sql.NullTime   `db:"resolved_at"`
+}
+
+// newCandidate holds an imported transaction for review against an existing one.
+func newCandidate(imported *Transaction, existingID int64, score float64) *Candidate {
+	return &Candidate{
+		HouseholdID: imported.HouseholdID,
+		AccountID:   imported.AccountID,
+		ExistingID:  existingID,
+		Date:        imported.Date,
+		Amount:      imported.Amount,
+		Payee:       imported.Payee,
+		Memo:        imported.Memo,
+		ExternalID:  imported.ExternalID,
+		Score:       score,
+		Status:      dedupe.StatusPending,
+	}
+}
+
+// toTransaction returns the held imported transaction.
+func (c *Candidate) toTransaction() *Transaction {
+	return &Transaction{
+		HouseholdID: c.HouseholdID,
+		AccountID:   c.AccountID,
+		Date:        c.Date,
+		Amount:      c.Amount,
+		Payee:       c.Payee,
+		Memo:        c.Memo,
+		Cleared:     true,
+		ExternalID:  c.ExternalID,
+	}
+}
+
+// CandidateResponse represents a suspected duplicate to return in responses.
+type CandidateResponse struct {
+	ID         int64                `json:"id"`
+	Imported   *TransactionResponse `json:"imported"`
+	ExistingID int64                `json:"existing_id"`
+	Score      float64              `json:"score"`
+	Status     dedupe.Status        `json:"status"`
+	// Transaction is the transaction the candidate was accepted as or merged into.
+	Transaction *TransactionResponse `json:"transaction,omitempty"`
+}
+
+// ToResponse converts a Candidate (from database) to a CandidateResponse (for API responses).
+func (c *Candidate) ToResponse() *CandidateResponse {
+	return &CandidateResponse{
+		ID:         c.ID,
+		Imported:   c.toTransaction().ToResponse(),
+		ExistingID: c.ExistingID,
+		Score:      c.Score,
+		Status:     c.Status,
+	}
+}
+
+// ImportResponse reports the outcome of a statement import: the transactions created,
+// how many lines were merged into scheduled transactions or skipped as already imported,
+// and the suspected duplicates held for review.
+type ImportResponse struct {
+	AccountID    int64                  `json:"account_id"`
+	Statements   int                    `json:"statements"`
+	Imported     int                    `json:"imported"`
+	Merged       int                    `json:"merged"`
+	Skipped      int                    `json:"skipped"`
+	Transactions []*TransactionResponse `json:"transactions"`
+	Review       []*CandidateResponse   `json:"review"`
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 sequence.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// PreviewTransactionResponse is a transaction an import would create, with category
+// suggestions when no rule categorized it.
+type PreviewTransactionResponse struct {
+	*TransactionResponse
+	Suggestions []classify.Suggestion `json:"suggestions,omitempty"`
+}
+
+// PreviewResponse shows what importing a statement would do without storing anything.
+type PreviewResponse struct {
+	AccountID    int64                         `json:"account_id"`
+	Statements   int                           `json:"statements"`
+	Merged       int                           `json:"merged"`
+	Skipped      int                           `json:"skipped"`
+	Transactions []*PreviewTransactionResponse `json:"transactions"`
+	Review       []*CandidateResponse          `json:"review"`
+}
+
+// TransferRequest represents the input data for moving money between two accounts of a
+// household. Amount is what leaves the sending account, in its currency; between accounts
+// in different currencies Received is what arrives in the receiving account. Category is
+// what a transfer from an on-budget to an off-budget account is spent from.
+type TransferRequest struct {
+	FromAccountID int64       `json:"from_account_id"`
+	ToAccountID   int64       `json:"to_account_id"`
+	Date          string      `json:"date"`
+	Amount        money.Money `json:"amount"`
+	Received      money.Money `json:"received"`
+	Memo          string      `json:"memo"`
+	Category      string      `json:"category"`
+	Cleared       bool        `json:"cleared"`
+}
+
+// Validate validates the TransferRequest struct.
+func (input *TransferRequest) Validate() map[string]string {
+	input.Memo = strings.TrimSpace(input.Memo)
+	input.Category = strings.TrimSpace(input.Category)
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"Amount": validate.Rules(
+			money.Positive,
+		),
+		"Category": validate.Rules(
+			validate.Max(100),
+			validate.ErrorMessage("Category must be at most 100 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if input.FromAccountID <= 0 {
+		errors["FromAccountID"] = "FromAccountID is required"
+	}
+	if input.ToAccountID <= 0 {
+		errors["ToAccountID"] = "ToAccountID is required"
+	}
+	if _, err := time.Parse(time.DateOnly, input.Date); err != nil {
+		errors["Date"] = "Date is required and must be in YYYY-MM-DD format"
+	}
+	return errors
+}
+
+// TransferResponse represents both legs of a transfer, the exchange rate they imply
+// (received per unit sent) and how the transfer affects the budget.
+type TransferResponse struct {
+	From   *TransactionResponse `json:"from"`
+	To     *TransactionResponse `json:"to"`
+	Rate   string               `json:"rate"`
+	Effect transfers.Effect     `json:"effect"`
+}
+
+// GainsResponse reports a household's realized and unrealized foreign exchange gains up to
+// a date, in its reporting currency. Losses are negative.
+type GainsResponse struct {
+	HouseholdID       int64               `json:"household_id"`
+	ReportingCurrency string              `json:"reporting_currency"`
+	AsOf              string              `json:"as_of"`
+	Realized          money.Money         `json:"realized"`
+	Unrealized        money.Money         `json:"unrealized"`
+	Positions         []*PositionResponse `json:"positions"`
+}
+
+// PositionResponse represents the household's holding of one foreign currency: its
+// balance, what the balance cost and is worth in the reporting currency, and the gains.
+type PositionResponse struct {
+	Currency   string      `json:"currency"`
+	Balance    money.Money `json:"balance"`
+	Cost       money.Money `json:"cost"`
+	Value      money.Money `json:"value"`
+	Realized   money.Money `json:"realized"`
+	Unrealized money.Money `json:"unrealized"`
+}
+
+// SubscriptionResponse represents a recurring payment detected in a household's transactions.
+type SubscriptionResponse struct {
+	recurring.Subscription
+	// AccountID is the account of the latest payment, which a scheduled charge is booked on.
+	AccountID int64 `json:"account_id"`
+	// ScheduledID is the scheduled transaction awaiting the next charge, if there is one.
+	ScheduledID int64 `json:"scheduled_id,omitempty"`
+}
+
+// OverspentResponse is the payload of a budget.overspent webhook event: the budget's
+// spending in its period after the transaction took it over the budgeted amount.
+type OverspentResponse struct {
+	alerts.Budget
+	TransactionID int64 `json:"transaction_id"`
+}
+
+// ScheduleRequest names a recurring payment to schedule the next charge of by one of its transactions.
+type ScheduleRequest struct {
+	TransactionID int64 `json:"transaction_id"`
+}
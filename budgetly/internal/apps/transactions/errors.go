@@ -0,0 +1,11 @@
+package transactions
+
+import "errors"
+
+var (
+	ErrInternalServer      error = errors.New("internal server error")
+	ErrTransactionNotFound error = errors.New("transaction not found")
+	ErrRateNotFound        error = errors.New("no exchange rate found to convert to the reporting currency")
+	ErrNotRecurring        error = errors.New("the transaction is not part of a recurring payment")
+	ErrAlreadyScheduled    error = errors.New("the next charge of the recurring payment is already scheduled")
+)
@@ -0,0 +1,603 @@
+package transactions
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/dedupe"
+	"github.com/ZiadMansourM/budgetly/pkg/export"
+	"github.com/ZiadMansourM/budgetly/pkg/fiscal"
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/reconcile"
+	"github.com/ZiadMansourM/budgetly/pkg/statement"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// maxStatementSize limits the size of uploaded bank statement files
+const maxStatementSize = 10 << 20
+
+// transactionHandler is an HTTP handler for transaction operations
+// (e.g., entering, editing, importing bank statements, etc.)
+type transactionHandler struct {
+	transactionService *transactionService
+	logger             *slog.Logger
+	router             *http.ServeMux
+}
+
+// newTransactionHandler creates a new transaction handler with the provided transaction service and logger
+func newTransactionHandler(transactionService *transactionService, logger *slog.Logger, router *http.ServeMux) *transactionHandler {
+	transactionHandler := &transactionHandler{
+		transactionService: transactionService,
+		logger:             logger,
+		router:             router,
+	}
+	transactionHandler.registerRoutes()
+	return transactionHandler
+}
+
+// Register routes for transaction-related actions
+func (h *transactionHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/transactions", h.create)
+	h.router.HandleFunc("GET /households/{household}/transactions", h.list)
+	h.router.HandleFunc("GET /transactions/{id}", h.get)
+	h.router.HandleFunc("PUT /transactions/{id}", h.update)
+	h.router.HandleFunc("DELETE /transactions/{id}", h.delete)
+	h.router.HandleFunc("PUT /transactions/{id}/splits", h.replaceSplits)
+	h.router.HandleFunc("GET /transactions/{id}/suggestions", h.suggestions)
+	h.router.HandleFunc("POST /accounts/{id}/statements", h.importStatement)
+	h.router.HandleFunc("POST /accounts/{id}/statements/preview", h.previewStatement)
+	h.router.HandleFunc("POST /households/{household}/transfers", h.createTransfer)
+	h.router.HandleFunc("GET /households/{household}/fx/gains", h.gains)
+	h.router.HandleFunc("GET /households/{household}/subscriptions", h.subscriptions)
+	h.router.HandleFunc("POST /households/{household}/subscriptions/schedule", h.schedule)
+	h.router.HandleFunc("GET /households/{household}/duplicates", h.listDuplicates)
+	h.router.HandleFunc("POST /duplicates/{id}/accept", h.acceptDuplicate)
+	h.router.HandleFunc("POST /duplicates/{id}/merge", h.mergeDuplicate)
+	h.router.HandleFunc("POST /duplicates/{id}/ignore", h.ignoreDuplicate)
+}
+
+// Create is an HTTP handler for entering a new transaction in a household
+func (h *transactionHandler) create(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req TransactionRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	transaction, err := h.transactionService.create(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, transaction)
+}
+
+// List is an HTTP handler for listing a household's transactions, optionally for one
+// account_id, with one tag and between from and to. With ?format= or an Accept header
+// for CSV, XLSX or PDF they are streamed as a file download instead of JSON.
+func (h *transactionHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	filter, err := parseFilter(r.URL.Query())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	format, exported, err := utils.NegotiateFormat(r)
+	if err != nil {
+		h.writeError(w, &validate.ValidationError{Errors: map[string]string{"format": "format must be one of json, csv, xlsx or pdf"}})
+		return
+	}
+	if exported {
+		h.export(w, householdID, filter, format)
+		return
+	}
+
+	transactions, err := h.transactionService.list(householdID, filter)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, transactions)
+}
+
+// export streams the listed transactions as a file download, scanning them from the
+// database as the file is written
+func (h *transactionHandler) export(w http.ResponseWriter, householdID int64, filter listFilter, format export.Format) {
+	report, rows, err := h.transactionService.export(householdID, filter)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	defer rows.Close()
+
+	if err := utils.WriteExport(w, format, "transactions", report); err != nil {
+		h.logger.Error("Error writing transaction export", "format", format, "error", err)
+	}
+}
+
+// Get is an HTTP handler for retrieving a transaction by ID
+func (h *transactionHandler) get(w http.ResponseWriter, r *http.Request) {
+	transactionID, ok := h.pathID(w, r, "transaction")
+	if !ok {
+		return
+	}
+
+	transaction, err := h.transactionService.get(transactionID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, transaction)
+}
+
+// Update is an HTTP handler for editing a transaction; reconciled ones need ?force=true
+func (h *transactionHandler) update(w http.ResponseWriter, r *http.Request) {
+	transactionID, ok := h.pathID(w, r, "transaction")
+	if !ok {
+		return
+	}
+
+	force, ok := h.force(w, r)
+	if !ok {
+		return
+	}
+
+	var req TransactionRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	transaction, err := h.transactionService.update(transactionID, req, force)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, transaction)
+}
+
+// ReplaceSplits is an HTTP handler for dividing a transaction across categories, or
+// undoing that with no splits; reconciled ones need ?force=true
+func (h *transactionHandler) replaceSplits(w http.ResponseWriter, r *http.Request) {
+	transactionID, ok := h.pathID(w, r, "transaction")
+	if !ok {
+		return
+	}
+
+	force, ok := h.force(w, r)
+	if !ok {
+		return
+	}
+
+	var req SplitsRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	transaction, err := h.transactionService.replaceSplits(transactionID, req, force)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, transaction)
+}
+
+// Delete is an HTTP handler for deleting a transaction; reconciled ones need ?force=true
+func (h *transactionHandler) delete(w http.ResponseWriter, r *http.Request) {
+	transactionID, ok := h.pathID(w, r, "transaction")
+	if !ok {
+		return
+	}
+
+	force, ok := h.force(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.transactionService.delete(transactionID, force); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateTransfer is an HTTP handler for moving money between two accounts of a household,
+// recording a transaction on each
+func (h *transactionHandler) createTransfer(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req TransferRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	transfer, err := h.transactionService.createTransfer(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, transfer)
+}
+
+// Gains is an HTTP handler for a household's realized and unrealized foreign exchange
+// gains in its reporting currency, up to as_of (default today)
+func (h *transactionHandler) gains(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	errs := map[string]string{}
+	asOf := queryDate(r.URL.Query(), "as_of", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return
+	}
+	if !asOf.Valid {
+		asOf.Time = fx.Day(time.Now())
+	}
+
+	gains, err := h.transactionService.gains(householdID, asOf.Time)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, gains)
+}
+
+// Subscriptions is an HTTP handler for the recurring payments detected in a household's
+// transactions up to a date (default today), flagging price increases and missed charges
+func (h *transactionHandler) subscriptions(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+	asOf, ok := h.asOf(w, r)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.transactionService.subscriptions(householdID, asOf)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, subscriptions)
+}
+
+// Schedule is an HTTP handler for turning a detected recurring payment into a scheduled
+// transaction for its next charge
+func (h *transactionHandler) schedule(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+	asOf, ok := h.asOf(w, r)
+	if !ok {
+		return
+	}
+
+	var req ScheduleRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	transaction, err := h.transactionService.schedule(householdID, req, asOf)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, transaction)
+}
+
+// Suggestions is an HTTP handler for the most likely categories of a transaction, learned
+// from its household's categorized transactions
+func (h *transactionHandler) suggestions(w http.ResponseWriter, r *http.Request) {
+	transactionID, ok := h.pathID(w, r, "transaction")
+	if !ok {
+		return
+	}
+
+	suggestions, err := h.transactionService.suggest(transactionID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, suggestions)
+}
+
+// ImportStatement is an HTTP handler for importing a camt.053 or MT940 bank statement
+// (selected by the format query parameter) into an account, either as a multipart "file"
+// field or as the raw request body
+func (h *transactionHandler) importStatement(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r, "account")
+	if !ok {
+		return
+	}
+	file, ok := h.statementFile(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.transactionService.importStatement(accountID, statement.Format(r.URL.Query().Get("format")), file)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, result)
+}
+
+// PreviewStatement is an HTTP handler for showing what importing a bank statement would
+// do, with category suggestions, without storing anything
+func (h *transactionHandler) previewStatement(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := h.pathID(w, r, "account")
+	if !ok {
+		return
+	}
+	file, ok := h.statementFile(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	preview, err := h.transactionService.previewStatement(accountID, statement.Format(r.URL.Query().Get("format")), file)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, preview)
+}
+
+// statementFile returns the uploaded statement, either a multipart "file" field or the raw
+// request body, writing a 400 response when the multipart field is missing
+func (h *transactionHandler) statementFile(w http.ResponseWriter, r *http.Request) (io.ReadCloser, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxStatementSize)
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.Body, true
+	}
+
+	formFile, _, err := r.FormFile("file")
+	if err != nil {
+		h.logger.Warn("Invalid upload", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "A statement file is required in the \"file\" field"},
+		)
+		return nil, false
+	}
+	return formFile, true
+}
+
+// ListDuplicates is an HTTP handler for listing a household's suspected duplicates with a
+// status (default pending)
+func (h *transactionHandler) listDuplicates(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	status := dedupe.Status(r.URL.Query().Get("status"))
+	switch status {
+	case "":
+		status = dedupe.StatusPending
+	case dedupe.StatusPending, dedupe.StatusAccepted, dedupe.StatusMerged, dedupe.StatusIgnored:
+	default:
+		h.writeError(w, &validate.ValidationError{Errors: map[string]string{
+			"status": "status must be one of pending, accepted, merged or ignored",
+		}})
+		return
+	}
+
+	candidates, err := h.transactionService.listDuplicates(householdID, status)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, candidates)
+}
+
+// AcceptDuplicate is an HTTP handler for keeping a suspected duplicate as a new transaction
+func (h *transactionHandler) acceptDuplicate(w http.ResponseWriter, r *http.Request) {
+	h.resolveDuplicate(w, r, dedupe.StatusAccepted)
+}
+
+// MergeDuplicate is an HTTP handler for folding a suspected duplicate into the existing transaction
+func (h *transactionHandler) mergeDuplicate(w http.ResponseWriter, r *http.Request) {
+	h.resolveDuplicate(w, r, dedupe.StatusMerged)
+}
+
+// IgnoreDuplicate is an HTTP handler for discarding a suspected duplicate
+func (h *transactionHandler) ignoreDuplicate(w http.ResponseWriter, r *http.Request) {
+	h.resolveDuplicate(w, r, dedupe.StatusIgnored)
+}
+
+// resolveDuplicate records the decision for the suspected duplicate in the path
+func (h *transactionHandler) resolveDuplicate(w http.ResponseWriter, r *http.Request, action dedupe.Status) {
+	candidateID, ok := h.pathID(w, r, "duplicate")
+	if !ok {
+		return
+	}
+
+	candidate, err := h.transactionService.resolveDuplicate(candidateID, action)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, candidate)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *transactionHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses the ID path parameter of the named resource, writing a 400 response on failure
+func (h *transactionHandler) pathID(w http.ResponseWriter, r *http.Request, resource string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid " + resource + " ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// asOf parses the optional as_of query parameter, defaulting to today, writing a 400
+// response on failure
+func (h *transactionHandler) asOf(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	errs := map[string]string{}
+	asOf := queryDate(r.URL.Query(), "as_of", errs)
+	if len(errs) > 0 {
+		h.writeError(w, &validate.ValidationError{Errors: errs})
+		return time.Time{}, false
+	}
+	if !asOf.Valid {
+		asOf.Time = fx.Day(time.Now())
+	}
+	return asOf.Time, true
+}
+
+// force parses the optional force query parameter, which allows changing reconciled
+// transactions, writing a 400 response on failure
+func (h *transactionHandler) force(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	value := r.URL.Query().Get("force")
+	if value == "" {
+		return false, true
+	}
+	force, err := strconv.ParseBool(value)
+	if err != nil {
+		h.writeError(w, &validate.ValidationError{Errors: map[string]string{"force": "force must be true or false"}})
+		return false, false
+	}
+	return force, true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *transactionHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// filterParameters are the query parameters parseFilter reads
+var filterParameters = map[string]bool{"account_id": true, "tag": true, "from": true, "to": true}
+
+// parseFilter parses the account_id, tag, from and to query parameters of a transaction
+// listing, returning a validation error for invalid ones
+func parseFilter(query url.Values) (listFilter, error) {
+	errs := map[string]string{}
+	filter := listFilter{
+		AccountID: queryID(query, "account_id", errs),
+		TagID:     queryID(query, "tag", errs),
+		From:      queryDate(query, "from", errs),
+		To:        queryDate(query, "to", errs),
+	}
+	if len(errs) > 0 {
+		return listFilter{}, &validate.ValidationError{Errors: errs}
+	}
+	return filter, nil
+}
+
+// queryID parses an optional positive ID query parameter, recording an error when it is invalid
+func queryID(query url.Values, name string, errs map[string]string) int64 {
+	value := query.Get(name)
+	if value == "" {
+		return 0
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		errs[name] = name + " must be a positive integer"
+		return 0
+	}
+	return id
+}
+
+// queryDate parses an optional YYYY-MM-DD query parameter, recording an error when it is invalid
+func queryDate(query url.Values, name string, errs map[string]string) sql.NullTime {
+	value := query.Get(name)
+	if value == "" {
+		return sql.NullTime{}
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		errs[name] = name + " must be in YYYY-MM-DD format"
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: date, Valid: true}
+}
+
+// writeError maps service errors to HTTP responses
+func (h *transactionHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, ErrTransactionNotFound), errors.Is(err, accounts.ErrAccountNotFound),
+		errors.Is(err, households.ErrHouseholdNotFound), errors.Is(err, dedupe.ErrCandidateNotFound),
+		errors.Is(err, ErrRateNotFound), errors.Is(err, ErrNotRecurring):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, fiscal.ErrPeriodLocked), errors.Is(err, dedupe.ErrAlreadyResolved),
+		errors.Is(err, reconcile.ErrReconciled), errors.Is(err, ErrAlreadyScheduled):
+		utils.WriteJson(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling transaction request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,27 @@
+package transactions
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListUnknownFormat(t *testing.T) {
+	router := http.NewServeMux()
+	newTransactionHandler(nil, slog.New(slog.NewTextHandler(io.Discard, nil)), router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/households/1/transactions?format=docx", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d", w.Code)
+	}
+	var body struct {
+		Errors map[string]string `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil || body.Errors["format"] == "" {
+		t.Errorf("Expected an error for format, got %v (%v)", body.Errors, err)
+	}
+}
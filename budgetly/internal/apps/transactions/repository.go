@@ -0,0 +1,407 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package transactions
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/dedupe"
+	"github.com/ZiadMansourM/budgetly/pkg/splits"
+	"github.com/jmoiron/sqlx"
+)
+
+// transactionColumns lists the columns selected for a Transaction
+const transactionColumns = `id, household_id, account_id, date, amount, payee, memo, category, cleared, scheduled, reconciled, external_id, transfer_id, created_at`
+
+// listQuery selects a household's transactions matching a listFilter, newest first
+const listQuery = `SELECT ` + transactionColumns + `
+	FROM transactions
+	WHERE household_id = $1
+		AND ($2 = 0 OR account_id = $2)
+		AND ($3::date IS NULL OR date >= $3)
+		AND ($4::date IS NULL OR date <= $4)
+		AND ($5 = 0 OR EXISTS (
+			SELECT 1 FROM transaction_tags WHERE transaction_id = transactions.id AND tag_id = $5
+		))
+	ORDER BY date DESC, id DESC`
+
+// candidateColumns lists the columns selected for a Candidate
+const candidateColumns = `id, household_id, account_id, existing_id, date, amount, payee, memo, external_id, score, status, created_at, resolved_at`
+
+// insertTransaction inserts a transaction and returns its ID
+const insertTransaction = `INSERT INTO transactions (household_id, account_id, date, amount, payee, memo, category, cleared, scheduled, external_id, transfer_id, created_at)
+	VALUES (:household_id, :account_id, :date, :amount, :payee, :memo, :category, :cleared, :scheduled, :external_id, :transfer_id, :created_at)
+	RETURNING id`
+
+// insertCandidate inserts a suspected duplicate and returns its ID
+const insertCandidate = `INSERT INTO duplicate_candidates (household_id, account_id, existing_id, date, amount, payee, memo, external_id, score, status, created_at)
+	VALUES (:household_id, :account_id, :existing_id, :date, :amount, :payee, :memo, :external_id, :score, :status, :created_at)
+	RETURNING id`
+
+// transactionModel wraps the database connection pool using sqlx
+type transactionModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newTransactionModel(db *sqlx.DB, logger *slog.Logger) *transactionModel {
+	return &transactionModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// listFilter narrows a household's transactions to an account, a tag and a date range.
+// Zero values do not filter.
+type listFilter struct {
+	AccountID int64
+	TagID     int64
+	From      sql.NullTime
+	To        sql.NullTime
+}
+
+// Create inserts a new transaction and its split lines in a single database transaction
+// and returns its ID
+func (m *transactionModel) create(t *Transaction) (int64, error) {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting transaction insert", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	if err := m.createTx(tx, t); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing transaction insert", "error", err)
+		return 0, ErrInternalServer
+	}
+
+	m.logger.Debug("Transaction created successfully", "id", t.ID)
+	return t.ID, nil
+}
+
+// CreateTx inserts a new transaction and its split lines within the caller's database
+// transaction, setting its ID
+func (m *transactionModel) createTx(tx *sqlx.Tx, t *Transaction) error {
+	t.CreatedAt = time.Now()
+	if err := insertReturningID(tx, insertTransaction, t, &t.ID); err != nil {
+		m.logger.Error("Error inserting transaction", "error", err)
+		return ErrInternalServer
+	}
+	return m.replaceSplits(tx, t.ID, t.Splits)
+}
+
+// SaveImport stores an import in a single database transaction: it inserts the imported
+// transactions and the suspected duplicates held for review, setting their IDs, merges
+// lines into scheduled transactions, replacing each merged transaction with the stored
+// one, and calls record for every transaction change so the balances commit with them
+func (m *transactionModel) saveImport(transactions []*Transaction, merges []importMerge, candidates []*Candidate, record func(tx *sqlx.Tx, previous, current *Transaction) error) error {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting transaction import", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, t := range transactions {
+		t.CreatedAt = now
+		if err := insertReturningID(tx, insertTransaction, t, &t.ID); err != nil {
+			m.logger.Error("Error inserting imported transaction", "error", err)
+			return ErrInternalServer
+		}
+		if err := record(tx, nil, t); err != nil {
+			return err
+		}
+	}
+	for _, merge := range merges {
+		updated, err := m.updateWith(tx, merge.merged)
+		if err != nil {
+			return err
+		}
+		*merge.merged = *updated
+		if err := record(tx, merge.existing, merge.merged); err != nil {
+			return err
+		}
+	}
+	for _, c := range candidates {
+		c.CreatedAt = now
+		if err := insertReturningID(tx, insertCandidate, c, &c.ID); err != nil {
+			m.logger.Error("Error inserting duplicate candidate", "error", err)
+			return ErrInternalServer
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing transaction import", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Transactions imported successfully", "count", len(transactions), "merged", len(merges), "review", len(candidates))
+	return nil
+}
+
+// CreateTransfer inserts both legs of a transfer in a single database transaction, linking
+// them to each other and setting their IDs
+func (m *transactionModel) createTransfer(sending, receiving *Transaction) error {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting transfer", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	sending.CreatedAt, receiving.CreatedAt = now, now
+	if err := insertReturningID(tx, insertTransaction, sending, &sending.ID); err != nil {
+		m.logger.Error("Error inserting sending transfer leg", "error", err)
+		return ErrInternalServer
+	}
+	receiving.TransferID = sql.NullInt64{Int64: sending.ID, Valid: true}
+	if err := insertReturningID(tx, insertTransaction, receiving, &receiving.ID); err != nil {
+		m.logger.Error("Error inserting receiving transfer leg", "error", err)
+		return ErrInternalServer
+	}
+	sending.TransferID = sql.NullInt64{Int64: receiving.ID, Valid: true}
+	if _, err := tx.Exec(`UPDATE transactions SET transfer_id = $2 WHERE id = $1`, sending.ID, receiving.ID); err != nil {
+		m.logger.Error("Error linking transfer legs", "error", err)
+		return ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing transfer", "error", err)
+		return ErrInternalServer
+	}
+
+	m.logger.Debug("Transfer created successfully", "from", sending.ID, "to", receiving.ID)
+	return nil
+}
+
+// insertReturningID runs a named insert returning an ID within a database transaction
+func insertReturningID(tx *sqlx.Tx, query string, arg any, id *int64) error {
+	rows, err := tx.NamedQuery(query, arg)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(id); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// List returns a household's transactions matching the filter, newest first
+func (m *transactionModel) list(householdID int64, filter listFilter) ([]Transaction, error) {
+	transactions := []Transaction{}
+	if err := m.DB.Select(&transactions, listQuery, householdID, filter.AccountID, filter.From, filter.To, filter.TagID); err != nil {
+		m.logger.Error("Error listing transactions", "error", err)
+		return nil, ErrInternalServer
+	}
+	return transactions, nil
+}
+
+// Stream runs the list query and returns the matching rows for the caller to scan one at a
+// time and close, so large exports are not held in memory
+func (m *transactionModel) stream(householdID int64, filter listFilter) (*sqlx.Rows, error) {
+	rows, err := m.DB.Queryx(listQuery, householdID, filter.AccountID, filter.From, filter.To, filter.TagID)
+	if err != nil {
+		m.logger.Error("Error streaming transactions", "error", err)
+		return nil, ErrInternalServer
+	}
+	return rows, nil
+}
+
+// ListCategorized returns up to limit of a household's most recent categorized transactions
+func (m *transactionModel) listCategorized(householdID int64, limit int) ([]Transaction, error) {
+	query := `SELECT ` + transactionColumns + `
+	FROM transactions
+	WHERE household_id = $1 AND category <> ''
+	ORDER BY date DESC, id DESC
+	LIMIT $2`
+
+	transactions := []Transaction{}
+	if err := m.DB.Select(&transactions, query, householdID, limit); err != nil {
+		m.logger.Error("Error listing categorized transactions", "error", err)
+		return nil, ErrInternalServer
+	}
+	return transactions, nil
+}
+
+// GetByID returns a transaction by ID with its split lines
+func (m *transactionModel) getByID(id int64) (*Transaction, error) {
+	t := &Transaction{}
+	err := m.DB.Get(t, `SELECT `+transactionColumns+` FROM transactions WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting transaction by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+
+	if err := m.DB.Select(&t.Splits, `SELECT category, memo, amount FROM transaction_splits WHERE transaction_id = $1 ORDER BY position`, id); err != nil {
+		m.logger.Error("Error listing transaction splits", "error", err)
+		return nil, ErrInternalServer
+	}
+	return t, nil
+}
+
+// Update replaces a transaction's editable fields and split lines in a single database
+// transaction and returns it
+func (m *transactionModel) update(t *Transaction) (*Transaction, error) {
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting transaction update", "error", err)
+		return nil, ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	updated, err := m.updateWith(tx, t)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.replaceSplits(tx, t.ID, t.Splits); err != nil {
+		return nil, err
+	}
+	updated.Splits = t.Splits
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing transaction update", "error", err)
+		return nil, ErrInternalServer
+	}
+	return updated, nil
+}
+
+// replaceSplits replaces a transaction's split lines within a database transaction
+func (m *transactionModel) replaceSplits(tx *sqlx.Tx, id int64, lines []splits.Split) error {
+	if _, err := tx.Exec(`DELETE FROM transaction_splits WHERE transaction_id = $1`, id); err != nil {
+		m.logger.Error("Error deleting transaction splits", "error", err)
+		return ErrInternalServer
+	}
+	for i, line := range lines {
+		if _, err := tx.Exec(`INSERT INTO transaction_splits (transaction_id, position, category, memo, amount) VALUES ($1, $2, $3, $4, $5)`,
+			id, i, line.Category, line.Memo, line.Amount,
+		); err != nil {
+			m.logger.Error("Error inserting transaction split", "error", err)
+			return ErrInternalServer
+		}
+	}
+	return nil
+}
+
+// updateWith runs update on the database or within a transaction
+func (m *transactionModel) updateWith(q sqlx.Queryer, t *Transaction) (*Transaction, error) {
+	query := `UPDATE transactions
+	SET account_id = $2, date = $3, amount = $4, payee = $5, memo = $6, category = $7, cleared = $8,
+		scheduled = $9, external_id = $10
+	WHERE id = $1
+	RETURNING ` + transactionColumns
+
+	updated := &Transaction{}
+	err := sqlx.Get(q, updated, query, t.ID, t.AccountID, t.Date, t.Amount, t.Payee, t.Memo, t.Category, t.Cleared,
+		t.Scheduled, t.ExternalID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error updating transaction", "error", err)
+		return nil, ErrInternalServer
+	}
+	return updated, nil
+}
+
+// Delete removes a transaction by ID, together with the other leg when it is a transfer
+func (m *transactionModel) delete(id int64) error {
+	result, err := m.DB.Exec(`DELETE FROM transactions WHERE id = $1 OR transfer_id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting transaction", "error", err)
+		return ErrInternalServer
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected transaction count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrTransactionNotFound
+	}
+	return nil
+}
+
+// ListCandidates returns a household's suspected duplicates with a status, oldest first
+func (m *transactionModel) listCandidates(householdID int64, status dedupe.Status) ([]Candidate, error) {
+	query := `SELECT ` + candidateColumns + `
+	FROM duplicate_candidates
+	WHERE household_id = $1 AND status = $2
+	ORDER BY id`
+
+	candidates := []Candidate{}
+	if err := m.DB.Select(&candidates, query, householdID, status); err != nil {
+		m.logger.Error("Error listing duplicate candidates", "error", err)
+		return nil, ErrInternalServer
+	}
+	return candidates, nil
+}
+
+// GetCandidate returns a suspected duplicate by ID
+func (m *transactionModel) getCandidate(id int64) (*Candidate, error) {
+	c := &Candidate{}
+	err := m.DB.Get(c, `SELECT `+candidateColumns+` FROM duplicate_candidates WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, dedupe.ErrCandidateNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting duplicate candidate by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return c, nil
+}
+
+// ResolveCandidate records the decision for a pending suspected duplicate and returns it
+func (m *transactionModel) resolveCandidate(id int64, status dedupe.Status) (*Candidate, error) {
+	query := `UPDATE duplicate_candidates
+	SET status = $2, resolved_at = $3
+	WHERE id = $1 AND status = $4
+	RETURNING ` + candidateColumns
+
+	c := &Candidate{}
+	err := m.DB.Get(c, query, id, status, time.Now(), dedupe.StatusPending)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, dedupe.ErrAlreadyResolved
+	}
+	if err != nil {
+		m.logger.Error("Error resolving duplicate candidate", "error", err)
+		return nil, ErrInternalServer
+	}
+	return c, nil
+}
+
+// PendingExternalIDs returns the bank references of an account's imported transactions
+// still held for review, so re-importing them does not queue them again
+func (m *transactionModel) pendingExternalIDs(accountID int64) (map[string]bool, error) {
+	query := `SELECT external_id
+	FROM duplicate_candidates
+	WHERE account_id = $1 AND status = $2 AND external_id IS NOT NULL`
+
+	var ids []string
+	if err := m.DB.Select(&ids, query, accountID, dedupe.StatusPending); err != nil {
+		m.logger.Error("Error listing pending duplicate references", "error", err)
+		return nil, ErrInternalServer
+	}
+
+	pending := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		pending[id] = true
+	}
+	return pending, nil
+}
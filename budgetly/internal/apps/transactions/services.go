@@ -0,0 +1,1294 @@
+package transactions
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/internal/apps/balances"
+	"github.com/ZiadMansourM/budgetly/internal/apps/budgets"
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/internal/apps/notifications"
+	"github.com/ZiadMansourM/budgetly/internal/apps/payees"
+	"github.com/ZiadMansourM/budgetly/internal/apps/periods"
+	"github.com/ZiadMansourM/budgetly/internal/apps/rates"
+	"github.com/ZiadMansourM/budgetly/internal/apps/rules"
+	"github.com/ZiadMansourM/budgetly/internal/apps/tags"
+	"github.com/ZiadMansourM/budgetly/internal/apps/webhooks"
+	"github.com/ZiadMansourM/budgetly/pkg/alerts"
+	"github.com/ZiadMansourM/budgetly/pkg/classify"
+	"github.com/ZiadMansourM/budgetly/pkg/dedupe"
+	"github.com/ZiadMansourM/budgetly/pkg/export"
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	resolver "github.com/ZiadMansourM/budgetly/pkg/payees"
+	"github.com/ZiadMansourM/budgetly/pkg/reconcile"
+	"github.com/ZiadMansourM/budgetly/pkg/recurring"
+	engine "github.com/ZiadMansourM/budgetly/pkg/rules"
+	"github.com/ZiadMansourM/budgetly/pkg/splits"
+	"github.com/ZiadMansourM/budgetly/pkg/statement"
+	"github.com/ZiadMansourM/budgetly/pkg/transfers"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/pkg/webhook"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// suggestionLimit is how many category suggestions are returned for a transaction
+	suggestionLimit = 3
+	// trainingLimit caps how many of a household's most recent categorized transactions
+	// its category classifier is trained on
+	trainingLimit = 5000
+)
+
+type transactionService struct {
+	transactionRepo *transactionModel
+	logger          *slog.Logger
+	classifiers     *classifiers
+}
+
+// classifiers holds each household's category classifier once trained.
+type classifiers struct {
+	mu          sync.Mutex
+	byHousehold map[int64]*classify.Classifier
+}
+
+func newTransactionService(transactionRepo *transactionModel, logger *slog.Logger) *transactionService {
+	return &transactionService{
+		transactionRepo: transactionRepo,
+		logger:          logger,
+		classifiers:     &classifiers{byHousehold: make(map[int64]*classify.Classifier)},
+	}
+}
+
+// create validates and stores a new transaction of a household
+func (s *transactionService) create(householdID int64, input TransactionRequest) (*TransactionResponse, error) {
+	transaction, err := s.prepare(householdID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.save(nil, transaction)
+	if err != nil {
+		return nil, err
+	}
+	if err := tags.AddByName(s.transactionRepo.DB, s.logger, householdID, created.ID, transaction.ruleTags); err != nil {
+		return nil, err
+	}
+	return created.ToResponse(), nil
+}
+
+// createTx validates a new transaction of a household and stores it with its balance
+// change within the caller's database transaction. Once that commits, committed must run
+// the side effects create has.
+func (s *transactionService) createTx(tx *sqlx.Tx, householdID int64, input TransactionRequest) (*Transaction, error) {
+	transaction, err := s.prepare(householdID, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.transactionRepo.createTx(tx, transaction); err != nil {
+		return nil, err
+	}
+	if err := balances.RecordChangeTx(tx, s.logger, nil, transaction.change()); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// committed runs the side effects of a transaction stored by createTx after its database
+// transaction committed. Failures are logged, as the transaction is stored either way.
+func (s *transactionService) committed(transaction *Transaction) {
+	s.notify(nil, transaction)
+	s.publish(transaction.HouseholdID, webhook.TransactionCreated, transaction.ToResponse())
+	if err := tags.AddByName(s.transactionRepo.DB, s.logger, transaction.HouseholdID, transaction.ID, transaction.ruleTags); err != nil {
+		s.logger.Error("Error tagging transaction", "transaction_id", transaction.ID, "error", err)
+	}
+}
+
+// prepare validates a new transaction of a household and categorizes it
+func (s *transactionService) prepare(householdID int64, input TransactionRequest) (*Transaction, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Transaction validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.transactionRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	transaction := input.toTransaction(householdID)
+	if err := s.checkAccount(transaction); err != nil {
+		return nil, err
+	}
+	if err := s.categorize(householdID, []*Transaction{transaction}); err != nil {
+		return nil, err
+	}
+	if err := periods.CheckChange(s.transactionRepo.DB, s.logger, householdID, time.Time{}, transaction.Date); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// list returns a household's transactions matching the filter, each converted to the
+// household's reporting currency at its date when a rate is known
+func (s *transactionService) list(householdID int64, filter listFilter) ([]*TransactionResponse, error) {
+	household, err := households.Get(s.transactionRepo.DB, s.logger, householdID)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.transactionRepo.list(householdID, filter)
+	if err != nil {
+		return nil, err
+	}
+	table, err := s.rateTable(transactions, household.ReportingCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*TransactionResponse, 0, len(transactions))
+	for i := range transactions {
+		response := transactions[i].ToResponse()
+		converted, err := convert(table, transactions[i].Amount, household.ReportingCurrency, transactions[i].Date)
+		if err == nil {
+			response.Converted = &converted
+		} else if !errors.Is(err, fx.ErrRateNotFound) {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// rateTable loads the exchange rates needed to convert the transactions into the
+// reporting currency on their dates
+func (s *transactionService) rateTable(transactions []Transaction, reporting string) (*fx.Table, error) {
+	if len(transactions) == 0 {
+		return fx.NewTable(), nil
+	}
+
+	currencies := []string{reporting}
+	from, to := transactions[0].Date, transactions[0].Date
+	for i := range transactions {
+		currencies = append(currencies, transactions[i].Amount.Currency())
+		if transactions[i].Date.Before(from) {
+			from = transactions[i].Date
+		}
+		if transactions[i].Date.After(to) {
+			to = transactions[i].Date
+		}
+	}
+	return rates.LoadTable(s.transactionRepo.DB, s.logger, currencies, from, to)
+}
+
+// export streams a household's transactions matching the filter as a spreadsheet. The
+// caller closes the returned rows once the export is written.
+func (s *transactionService) export(householdID int64, filter listFilter) (export.Report, *transactionRows, error) {
+	if _, err := households.Get(s.transactionRepo.DB, s.logger, householdID); err != nil {
+		return export.Report{}, nil, err
+	}
+	rows, err := s.transactionRepo.stream(householdID, filter)
+	if err != nil {
+		return export.Report{}, nil, err
+	}
+
+	streamed := &transactionRows{rows: rows}
+	return export.Report{
+		Title:  "Transactions",
+		Sheets: []export.Sheet{{Name: "Transactions", Columns: exportColumns, Rows: streamed}},
+	}, streamed, nil
+}
+
+// get returns a transaction by ID
+func (s *transactionService) get(id int64) (*TransactionResponse, error) {
+	transaction, err := s.transactionRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return transaction.ToResponse(), nil
+}
+
+// update validates and replaces a transaction. It may move to another account of the
+// same household, but neither its old nor its new date may be in a closed period, and a
+// reconciled transaction is only changed when forced.
+func (s *transactionService) update(id int64, input TransactionRequest, force bool) (*TransactionResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Transaction validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	existing, err := s.transactionRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := reconcile.CheckEdit(existing.toReconcile(), force); err != nil {
+		return nil, err
+	}
+	transaction := input.toTransaction(existing.HouseholdID)
+	transaction.ID, transaction.ExternalID = id, existing.ExternalID
+	if err := s.checkAccount(transaction); err != nil {
+		return nil, err
+	}
+	if err := periods.CheckChange(s.transactionRepo.DB, s.logger, existing.HouseholdID, existing.Date, transaction.Date); err != nil {
+		return nil, err
+	}
+	if existing.TransferID.Valid {
+		if len(transaction.Splits) > 0 {
+			return nil, &validate.ValidationError{Errors: map[string]string{"Splits": "A transfer cannot be split"}}
+		}
+		if err := s.updateTransfer(existing, transaction, force); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.save(existing, transaction)
+	if err != nil {
+		return nil, err
+	}
+	if updated.Category != existing.Category && updated.Category != "" {
+		if err := rules.Observe(s.transactionRepo.DB, s.logger, updated.HouseholdID, updated.Payee, updated.Category); err != nil {
+			s.logger.Error("Error recording recategorization", "transaction_id", id, "error", err)
+		}
+	}
+	return updated.ToResponse(), nil
+}
+
+// replaceSplits replaces a transaction's split lines, together with the transaction, under
+// the same checks as update. Splits only move the amount between categories.
+func (s *transactionService) replaceSplits(id int64, input SplitsRequest, force bool) (*TransactionResponse, error) {
+	existing, err := s.transactionRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	lines, validationErrors := input.toSplits(existing.Amount)
+	if len(validationErrors) > 0 {
+		s.logger.Warn("Transaction split validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if existing.TransferID.Valid && len(lines) > 0 {
+		return nil, &validate.ValidationError{Errors: map[string]string{"Splits": "A transfer cannot be split"}}
+	}
+	if err := reconcile.CheckEdit(existing.toReconcile(), force); err != nil {
+		return nil, err
+	}
+	if err := periods.CheckChange(s.transactionRepo.DB, s.logger, existing.HouseholdID, existing.Date, existing.Date); err != nil {
+		return nil, err
+	}
+
+	transaction := *existing
+	transaction.Splits = lines
+	updated, err := s.save(existing, &transaction)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToResponse(), nil
+}
+
+// delete removes a transaction, and the other leg of a transfer with it, unless a date
+// is in a closed period or a leg is reconciled and the deletion is not forced
+func (s *transactionService) delete(id int64, force bool) error {
+	existing, err := s.transactionRepo.getByID(id)
+	if err != nil {
+		return err
+	}
+	deleted := []*Transaction{existing}
+	if existing.TransferID.Valid {
+		counterpart, err := s.transactionRepo.getByID(existing.TransferID.Int64)
+		if err != nil {
+			return err
+		}
+		deleted = append(deleted, counterpart)
+	}
+	for _, transaction := range deleted {
+		if err := reconcile.CheckEdit(transaction.toReconcile(), force); err != nil {
+			return err
+		}
+		if err := periods.CheckChange(s.transactionRepo.DB, s.logger, transaction.HouseholdID, transaction.Date, time.Time{}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.transactionRepo.delete(id); err != nil {
+		return err
+	}
+	for _, transaction := range deleted {
+		if err := s.changed(transaction, nil); err != nil {
+			return err
+		}
+		s.publish(transaction.HouseholdID, webhook.TransactionDeleted, transaction.ToResponse())
+	}
+	return nil
+}
+
+// createTransfer validates a transfer between two accounts of a household and stores both
+// legs, linked to each other: a negative transaction on the sending account and a positive
+// one on the receiving account, in their own currencies
+func (s *transactionService) createTransfer(householdID int64, input TransferRequest) (*TransferResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Transfer validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.transactionRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	from, err := s.householdAccount(householdID, input.FromAccountID, "FromAccountID")
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.householdAccount(householdID, input.ToAccountID, "ToAccountID")
+	if err != nil {
+		return nil, err
+	}
+
+	date, _ := time.Parse(time.DateOnly, input.Date)
+	transfer, err := transfers.New(date, transferAccount(from), transferAccount(to), input.Amount, input.Received, input.Category)
+	if err != nil {
+		return nil, transferError(err)
+	}
+	if err := periods.CheckChange(s.transactionRepo.DB, s.logger, householdID, time.Time{}, date); err != nil {
+		return nil, err
+	}
+
+	sending := &Transaction{
+		HouseholdID: householdID,
+		AccountID:   from.ID,
+		Date:        date,
+		Amount:      transfer.From.Amount,
+		Payee:       truncate(to.Name, 200),
+		Memo:        input.Memo,
+		Category:    transfer.From.Category,
+		Cleared:     input.Cleared,
+	}
+	receiving := &Transaction{
+		HouseholdID: householdID,
+		AccountID:   to.ID,
+		Date:        date,
+		Amount:      transfer.To.Amount,
+		Payee:       truncate(from.Name, 200),
+		Memo:        input.Memo,
+		Cleared:     input.Cleared,
+	}
+	if err := s.transactionRepo.createTransfer(sending, receiving); err != nil {
+		return nil, err
+	}
+	for _, transaction := range []*Transaction{sending, receiving} {
+		if err := s.changed(nil, transaction); err != nil {
+			return nil, err
+		}
+		s.publish(householdID, webhook.TransactionCreated, transaction.ToResponse())
+	}
+
+	return &TransferResponse{
+		From:   sending.ToResponse(),
+		To:     receiving.ToResponse(),
+		Rate:   transfer.Rate().FloatString(6),
+		Effect: transfer.Effect,
+	}, nil
+}
+
+// updateTransfer checks an edit of one leg of a transfer and saves the other leg to match:
+// it follows the date and memo, and its amount follows a changed amount, at the original
+// exchange rate between accounts in different currencies. A reconciled other leg is only
+// changed when forced.
+func (s *transactionService) updateTransfer(existing, transaction *Transaction, force bool) error {
+	counterpart, err := s.transactionRepo.getByID(existing.TransferID.Int64)
+	if err != nil {
+		return err
+	}
+	if err := reconcile.CheckEdit(counterpart.toReconcile(), force); err != nil {
+		return err
+	}
+	if transaction.AccountID == counterpart.AccountID {
+		return transferError(transfers.ErrSameAccount)
+	}
+	if transaction.Amount.IsNegative() != existing.Amount.IsNegative() || transaction.Amount.IsZero() {
+		return &validate.ValidationError{Errors: map[string]string{"Amount": "Amount must keep the direction of the transfer"}}
+	}
+	if transaction.Amount.IsNegative() && transaction.Category == "" {
+		if err := s.checkTransferCategory(transaction.HouseholdID, transaction.AccountID, counterpart.AccountID); err != nil {
+			return err
+		}
+	}
+
+	transfer := transfers.Transfer{
+		From: transfers.Leg{Amount: existing.Amount},
+		To:   transfers.Leg{Amount: counterpart.Amount},
+	}
+	if existing.Amount.IsPositive() {
+		transfer.From.Amount, transfer.To.Amount = counterpart.Amount, existing.Amount
+		err = transfer.SetReceived(transaction.Amount)
+	} else {
+		err = transfer.SetSent(transaction.Amount.Negate())
+	}
+	if err != nil {
+		return transferError(err)
+	}
+
+	mirrored := *counterpart
+	mirrored.Date, mirrored.Memo = transaction.Date, transaction.Memo
+	mirrored.Amount = transfer.To.Amount
+	if existing.Amount.IsPositive() {
+		mirrored.Amount = transfer.From.Amount
+	}
+	if err := periods.CheckChange(s.transactionRepo.DB, s.logger, counterpart.HouseholdID, counterpart.Date, mirrored.Date); err != nil {
+		return err
+	}
+
+	_, err = s.save(counterpart, &mirrored)
+	return err
+}
+
+// gains replays a household's transactions up to a date to report the realized and
+// unrealized gains on each foreign currency it holds, using the average cost method in
+// its reporting currency. Amounts are valued at the rate of their date, except that a
+// transfer to or from an account in the reporting currency is valued at what it actually
+// sent or received. Transfers between accounts in the same currency are not valued at all.
+func (s *transactionService) gains(householdID int64, asOf time.Time) (*GainsResponse, error) {
+	household, err := households.Get(s.transactionRepo.DB, s.logger, householdID)
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.transactionRepo.list(householdID, listFilter{To: sql.NullTime{Time: asOf, Valid: true}})
+	if err != nil {
+		return nil, err
+	}
+	table, err := s.rateTable(transactions, household.ReportingCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	reporting := household.ReportingCurrency
+	byID := make(map[int64]*Transaction, len(transactions))
+	for i := range transactions {
+		byID[transactions[i].ID] = &transactions[i]
+	}
+
+	positions := map[string]*fx.Position{}
+	realized := map[string]int64{}
+	// The list is newest first; positions need the oldest first.
+	for i := len(transactions) - 1; i >= 0; i-- {
+		transaction := &transactions[i]
+		currency := transaction.Amount.Currency()
+		if currency == reporting || transaction.Amount.IsZero() {
+			continue
+		}
+
+		var rate *big.Rat
+		if counterpart, ok := byID[transaction.TransferID.Int64]; ok {
+			switch counterpart.Amount.Currency() {
+			case currency:
+				continue
+			case reporting:
+				rate = new(big.Rat).Quo(counterpart.Amount.Abs().Rat(), transaction.Amount.Abs().Rat())
+			}
+		}
+		if rate == nil {
+			if rate, err = table.Rate(currency, reporting, transaction.Date); err != nil {
+				return nil, gainsError(err)
+			}
+		}
+
+		position, ok := positions[currency]
+		if !ok {
+			position = &fx.Position{Currency: currency, ReportingCurrency: reporting}
+			positions[currency] = position
+		}
+		amount := transaction.Amount.Abs().Amount()
+		value, err := fx.ConvertAt(amount, currency, reporting, rate)
+		if err != nil {
+			return nil, err
+		}
+		if transaction.Amount.IsPositive() {
+			position.Acquire(amount, value)
+			continue
+		}
+
+		// Spending more than was held, e.g. an account opened with a balance, has no known
+		// cost; treat the shortfall as acquired at the day's rate so it has no gain.
+		if shortfall := amount - position.Balance; shortfall > 0 {
+			cost, err := fx.ConvertAt(shortfall, currency, reporting, rate)
+			if err != nil {
+				return nil, err
+			}
+			position.Acquire(shortfall, cost)
+		}
+		gain, err := position.Dispose(amount, value)
+		if err != nil {
+			return nil, err
+		}
+		realized[currency] += gain
+	}
+
+	currencies := make([]string, 0, len(positions))
+	for currency := range positions {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	response := &GainsResponse{
+		HouseholdID:       householdID,
+		ReportingCurrency: reporting,
+		AsOf:              asOf.Format(time.DateOnly),
+		Positions:         make([]*PositionResponse, 0, len(positions)),
+	}
+	var totalRealized, totalUnrealized int64
+	for _, currency := range currencies {
+		position := positions[currency]
+		rate, err := table.Rate(currency, reporting, asOf)
+		if err != nil {
+			return nil, gainsError(err)
+		}
+		unrealized, err := position.UnrealizedGain(rate)
+		if err != nil {
+			return nil, err
+		}
+		totalRealized += realized[currency]
+		totalUnrealized += unrealized
+
+		response.Positions = append(response.Positions, &PositionResponse{
+			Currency:   currency,
+			Balance:    money.MustNew(position.Balance, currency),
+			Cost:       money.MustNew(position.Cost, reporting),
+			Value:      money.MustNew(position.Cost+unrealized, reporting),
+			Realized:   money.MustNew(realized[currency], reporting),
+			Unrealized: money.MustNew(unrealized, reporting),
+		})
+	}
+	response.Realized = money.MustNew(totalRealized, reporting)
+	response.Unrealized = money.MustNew(totalUnrealized, reporting)
+	return response, nil
+}
+
+// importStatement parses a bank statement file and stores its lines as cleared transactions
+// of the account. Every statement must be for the account, in its currency, and pass the
+// opening/closing balance check; otherwise nothing is imported. The new transactions,
+// merges, review candidates and balance changes are stored in one database transaction.
+//
+// Lines run through the household's categorization rules, then are matched against the
+// account's transactions around the same dates: lines the bank already reported are
+// skipped, lines matching a scheduled transaction entered by hand are merged into it, and
+// other suspected duplicates are held for review.
+func (s *transactionService) importStatement(accountID int64, format statement.Format, r io.Reader) (*ImportResponse, error) {
+	account, statements, err := s.parseStatement(accountID, format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.planImport(account, statements)
+	if err != nil {
+		return nil, err
+	}
+	err = s.transactionRepo.saveImport(plan.create, plan.merge, plan.review, func(tx *sqlx.Tx, previous, current *Transaction) error {
+		return balances.RecordChangeTx(tx, s.logger, previous.change(), current.change())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ImportResponse{
+		AccountID:    accountID,
+		Statements:   len(statements),
+		Imported:     len(plan.create),
+		Merged:       len(plan.merge),
+		Skipped:      plan.skipped,
+		Transactions: make([]*TransactionResponse, 0, len(plan.create)),
+		Review:       make([]*CandidateResponse, 0, len(plan.review)),
+	}
+	for _, transaction := range plan.create {
+		s.notify(nil, transaction)
+		if err := tags.AddByName(s.transactionRepo.DB, s.logger, account.HouseholdID, transaction.ID, transaction.ruleTags); err != nil {
+			return nil, err
+		}
+		response.Transactions = append(response.Transactions, transaction.ToResponse())
+	}
+	for _, merge := range plan.merge {
+		s.notify(merge.existing, merge.merged)
+		s.publish(account.HouseholdID, webhook.TransactionUpdated, merge.merged.ToResponse())
+	}
+	for _, candidate := range plan.review {
+		response.Review = append(response.Review, candidate.ToResponse())
+	}
+
+	s.publish(account.HouseholdID, webhook.ImportCompleted, response)
+	return response, nil
+}
+
+// previewStatement shows what importing a bank statement file into the account would do
+// without storing anything, with category suggestions for the lines no rule categorized
+func (s *transactionService) previewStatement(accountID int64, format statement.Format, r io.Reader) (*PreviewResponse, error) {
+	account, statements, err := s.parseStatement(accountID, format, r)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.planImport(account, statements)
+	if err != nil {
+		return nil, err
+	}
+	classifier, err := s.classifier(account.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PreviewResponse{
+		AccountID:    accountID,
+		Statements:   len(statements),
+		Merged:       len(plan.merge),
+		Skipped:      plan.skipped,
+		Transactions: make([]*PreviewTransactionResponse, 0, len(plan.create)),
+		Review:       make([]*CandidateResponse, 0, len(plan.review)),
+	}
+	for _, transaction := range plan.create {
+		preview := &PreviewTransactionResponse{TransactionResponse: transaction.ToResponse()}
+		if transaction.Category == "" {
+			preview.Suggestions = classifier.Suggest(transaction.toExample(), suggestionLimit)
+		}
+		response.Transactions = append(response.Transactions, preview)
+	}
+	for _, candidate := range plan.review {
+		response.Review = append(response.Review, candidate.ToResponse())
+	}
+	return response, nil
+}
+
+// parseStatement parses a bank statement file for the account
+func (s *transactionService) parseStatement(accountID int64, format statement.Format, r io.Reader) (*accounts.Account, []statement.Statement, error) {
+	account, err := accounts.Get(s.transactionRepo.DB, s.logger, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	parser, err := statement.NewParser(format)
+	if err != nil {
+		return nil, nil, &validate.ValidationError{Errors: map[string]string{"format": "format must be camt.053 or mt940"}}
+	}
+	statements, err := parser.Parse(r)
+	if err != nil {
+		s.logger.Warn("Statement import rejected", "account_id", accountID, "error", err)
+		return nil, nil, &validate.ValidationError{Errors: map[string]string{"file": err.Error()}}
+	}
+	return account, statements, nil
+}
+
+// planImport checks parsed statements against the account, resolves their payees through
+// the household's payee directory, runs their lines through the household's rules and
+// matches them against its transactions
+func (s *transactionService) planImport(account *accounts.Account, statements []statement.Statement) (*importPlan, error) {
+	directory, err := payees.LoadDirectory(s.transactionRepo.DB, s.logger, account.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
+	imported, err := s.fromStatements(account, statements, directory)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.categorize(account.HouseholdID, imported); err != nil {
+		return nil, err
+	}
+	return s.match(account, imported)
+}
+
+// suggest returns the most likely categories for a transaction, learned from the
+// household's categorized transactions
+func (s *transactionService) suggest(id int64) ([]classify.Suggestion, error) {
+	transaction, err := s.transactionRepo.getByID(id)
+	if err != nil {
+		return nil, err
+	}
+	classifier, err := s.classifier(transaction.HouseholdID)
+	if err != nil {
+		return nil, err
+	}
+	return classifier.Suggest(transaction.toExample(), suggestionLimit), nil
+}
+
+// classifier returns the household's category classifier, training it from the
+// household's categorized transactions on first use
+func (s *transactionService) classifier(householdID int64) (*classify.Classifier, error) {
+	s.classifiers.mu.Lock()
+	defer s.classifiers.mu.Unlock()
+
+	if classifier, ok := s.classifiers.byHousehold[householdID]; ok {
+		return classifier, nil
+	}
+	categorized, err := s.transactionRepo.listCategorized(householdID, trainingLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	classifier := classify.New()
+	for i := range categorized {
+		classifier.Train(categorized[i].toExample())
+	}
+	s.classifiers.byHousehold[householdID] = classifier
+	return classifier, nil
+}
+
+// learn retrains a household's classifier incrementally after a transaction changed. A
+// classifier that is not loaded yet will see the change when it is trained.
+func (s *transactionService) learn(previous, current *Transaction) {
+	s.classifiers.mu.Lock()
+	defer s.classifiers.mu.Unlock()
+
+	if previous != nil {
+		if classifier, ok := s.classifiers.byHousehold[previous.HouseholdID]; ok {
+			classifier.Forget(previous.toExample())
+		}
+	}
+	if current != nil {
+		if classifier, ok := s.classifiers.byHousehold[current.HouseholdID]; ok {
+			classifier.Train(current.toExample())
+		}
+	}
+}
+
+// importPlan is what an import does with each statement line.
+type importPlan struct {
+	create []*Transaction
+	merge  []importMerge
+	review []*Candidate
+	// skipped counts lines already imported or already held for review.
+	skipped int
+}
+
+// importMerge is a scheduled transaction and the result of merging a line into it.
+type importMerge struct {
+	existing, merged *Transaction
+}
+
+// match sorts imported transactions into new ones, merges into scheduled transactions and
+// suspected duplicates, comparing them with the account's transactions within the
+// matcher's date window
+func (s *transactionService) match(account *accounts.Account, imported []*Transaction) (*importPlan, error) {
+	plan := &importPlan{}
+	if len(imported) == 0 {
+		return plan, nil
+	}
+
+	options := dedupe.DefaultOptions()
+	from, to := imported[0].Date, imported[0].Date
+	for _, transaction := range imported {
+		if transaction.Date.Before(from) {
+			from = transaction.Date
+		}
+		if transaction.Date.After(to) {
+			to = transaction.Date
+		}
+	}
+	existing, err := s.transactionRepo.list(account.HouseholdID, listFilter{
+		AccountID: account.ID,
+		From:      sql.NullTime{Time: from.AddDate(0, 0, -options.DateWindow), Valid: true},
+		To:        sql.NullTime{Time: to.AddDate(0, 0, options.DateWindow), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.transactionRepo.pendingExternalIDs(account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	importedByID := make(map[string]*Transaction, len(imported))
+	importedMatches := make([]dedupe.Transaction, 0, len(imported))
+	for i, transaction := range imported {
+		id := strconv.Itoa(i)
+		importedByID[id] = transaction
+		importedMatches = append(importedMatches, transaction.toDedupe(id))
+	}
+	existingByID := make(map[string]*Transaction, len(existing))
+	existingMatches := make([]dedupe.Transaction, 0, len(existing))
+	for i := range existing {
+		id := strconv.FormatInt(existing[i].ID, 10)
+		existingByID[id] = &existing[i]
+		existingMatches = append(existingMatches, existing[i].toDedupe(id))
+	}
+
+	result := dedupe.NewMatcher(options).Match(importedMatches, existingMatches)
+	for _, match := range result.New {
+		transaction := importedByID[match.ID]
+		if pending[transaction.ExternalID.String] {
+			plan.skipped++
+			continue
+		}
+		plan.create = append(plan.create, transaction)
+	}
+	for _, candidate := range result.Resolved {
+		if candidate.Status != dedupe.StatusMerged {
+			plan.skipped++
+			continue
+		}
+		scheduled := existingByID[candidate.Existing.ID]
+		merged := scheduled.merge(importedByID[candidate.Imported.ID])
+		if err := periods.CheckChange(s.transactionRepo.DB, s.logger, account.HouseholdID, scheduled.Date, merged.Date); err != nil {
+			return nil, err
+		}
+		plan.merge = append(plan.merge, importMerge{existing: scheduled, merged: merged})
+	}
+	for _, candidate := range result.Review {
+		transaction := importedByID[candidate.Imported.ID]
+		if pending[transaction.ExternalID.String] {
+			plan.skipped++
+			continue
+		}
+		plan.review = append(plan.review, newCandidate(transaction, existingByID[candidate.Existing.ID].ID, candidate.Score))
+	}
+	return plan, nil
+}
+
+// listDuplicates returns a household's suspected duplicates with a status
+func (s *transactionService) listDuplicates(householdID int64, status dedupe.Status) ([]*CandidateResponse, error) {
+	if _, err := households.Get(s.transactionRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	candidates, err := s.transactionRepo.listCandidates(householdID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*CandidateResponse, 0, len(candidates))
+	for i := range candidates {
+		responses = append(responses, candidates[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// resolveDuplicate records the decision for a suspected duplicate: accepting creates the
+// held transaction, merging folds it into the existing one and ignoring discards it
+func (s *transactionService) resolveDuplicate(id int64, action dedupe.Status) (*CandidateResponse, error) {
+	candidate, err := s.transactionRepo.getCandidate(id)
+	if err != nil {
+		return nil, err
+	}
+	if candidate.Status != dedupe.StatusPending {
+		return nil, fmt.Errorf("%w: candidate %d is %s", dedupe.ErrAlreadyResolved, id, candidate.Status)
+	}
+
+	// Work out the change before claiming the candidate, so a closed period leaves it pending.
+	var existing, current *Transaction
+	switch action {
+	case dedupe.StatusAccepted:
+		current = candidate.toTransaction()
+	case dedupe.StatusMerged:
+		if existing, err = s.transactionRepo.getByID(candidate.ExistingID); err != nil {
+			return nil, err
+		}
+		current = existing.merge(candidate.toTransaction())
+	case dedupe.StatusIgnored:
+	default:
+		return nil, fmt.Errorf("%w: %q", dedupe.ErrInvalidAction, action)
+	}
+	if current != nil {
+		previousDate := time.Time{}
+		if existing != nil {
+			previousDate = existing.Date
+		}
+		if err := periods.CheckChange(s.transactionRepo.DB, s.logger, candidate.HouseholdID, previousDate, current.Date); err != nil {
+			return nil, err
+		}
+	}
+
+	resolved, err := s.transactionRepo.resolveCandidate(id, action)
+	if err != nil {
+		return nil, err
+	}
+	response := resolved.ToResponse()
+	if current != nil {
+		saved, err := s.save(existing, current)
+		if err != nil {
+			return nil, err
+		}
+		response.Transaction = saved.ToResponse()
+	}
+	return response, nil
+}
+
+// save creates current when previous is nil or otherwise replaces previous with it,
+// then applies the change's side effects
+func (s *transactionService) save(previous, current *Transaction) (*Transaction, error) {
+	eventType := webhook.TransactionCreated
+	if previous == nil {
+		if _, err := s.transactionRepo.create(current); err != nil {
+			return nil, err
+		}
+	} else {
+		updated, err := s.transactionRepo.update(current)
+		if err != nil {
+			return nil, err
+		}
+		current, eventType = updated, webhook.TransactionUpdated
+	}
+
+	if err := s.changed(previous, current); err != nil {
+		return nil, err
+	}
+	s.publish(current.HouseholdID, eventType, current.ToResponse())
+	return current, nil
+}
+
+// fromStatements checks parsed statements against the account and converts their lines
+// into transactions, with each counterparty normalized to its payee in the directory
+func (s *transactionService) fromStatements(account *accounts.Account, statements []statement.Statement, directory *resolver.Directory) ([]*Transaction, error) {
+	checked := map[time.Time]bool{}
+	var transactions []*Transaction
+	for i := range statements {
+		st := &statements[i]
+		if !account.MatchesIBAN(st.AccountIBAN) {
+			return nil, &validate.ValidationError{Errors: map[string]string{
+				"file": fmt.Sprintf("statement %q is for %s, not account %d", st.ID, st.AccountIBAN, account.ID),
+			}}
+		}
+		if err := st.CheckBalance(); err != nil {
+			s.logger.Warn("Statement balance check failed", "account_id", account.ID, "error", err)
+			return nil, &validate.ValidationError{Errors: map[string]string{"file": err.Error()}}
+		}
+
+		matches := directory.NormalizeLines(st.Lines)
+		for j, line := range st.Lines {
+			if line.Currency != account.Currency {
+				return nil, &validate.ValidationError{Errors: map[string]string{
+					"file": fmt.Sprintf("statement %q has %s lines but the account is in %s", st.ID, line.Currency, account.Currency),
+				}}
+			}
+			transaction, err := fromLine(account.HouseholdID, account.ID, line, account.Currency)
+			if err != nil {
+				return nil, &validate.ValidationError{Errors: map[string]string{"file": err.Error()}}
+			}
+			if matches[j].Payee != nil {
+				transaction.payeeCategory = matches[j].Payee.Category
+			}
+
+			if !checked[transaction.Date] {
+				if err := periods.CheckChange(s.transactionRepo.DB, s.logger, account.HouseholdID, time.Time{}, transaction.Date); err != nil {
+					return nil, err
+				}
+				checked[transaction.Date] = true
+			}
+			transactions = append(transactions, transaction)
+		}
+	}
+	return transactions, nil
+}
+
+// categorize runs the household's categorization rules over new transactions
+func (s *transactionService) categorize(householdID int64, transactions []*Transaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	inputs := make([]engine.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		inputs = append(inputs, transaction.toRule())
+	}
+	applied, err := rules.Apply(s.transactionRepo.DB, s.logger, householdID, inputs)
+	if err != nil {
+		return err
+	}
+	for i, transaction := range transactions {
+		transaction.applyRules(applied[i])
+	}
+	return nil
+}
+
+// checkAccount ensures the transaction's account belongs to its household and is in the
+// currency of its amount
+func (s *transactionService) checkAccount(transaction *Transaction) error {
+	account, err := s.householdAccount(transaction.HouseholdID, transaction.AccountID, "AccountID")
+	if err != nil {
+		return err
+	}
+	if transaction.Amount.Currency() != account.Currency {
+		return &validate.ValidationError{Errors: map[string]string{"Amount": "Amount must be in " + account.Currency}}
+	}
+	return nil
+}
+
+// householdAccount returns an account of the household, reporting any other account as a
+// validation error for the field
+func (s *transactionService) householdAccount(householdID, accountID int64, field string) (*accounts.Account, error) {
+	account, err := accounts.Get(s.transactionRepo.DB, s.logger, accountID)
+	if errors.Is(err, accounts.ErrAccountNotFound) || (err == nil && account.HouseholdID != householdID) {
+		return nil, &validate.ValidationError{Errors: map[string]string{field: field + " must be an account of the household"}}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// changed keeps account balances up to date after a transaction was created (previous is
+// nil), edited or deleted (current is nil), and evaluates the household's alert rules
+// against the new state. Alert failures are logged rather than failing the change.
+func (s *transactionService) changed(previous, current *Transaction) error {
+	if err := balances.RecordChange(s.transactionRepo.DB, s.logger, previous.change(), current.change()); err != nil {
+		return err
+	}
+	s.notify(previous, current)
+	return nil
+}
+
+// notify retrains the household's classifier and evaluates its alert rules after a
+// transaction change was stored with its balances
+func (s *transactionService) notify(previous, current *Transaction) {
+	s.learn(previous, current)
+	if current == nil {
+		return
+	}
+
+	event, err := s.alertEvent(current)
+	if err != nil {
+		s.logger.Error("Error building alert event", "transaction_id", current.ID, "error", err)
+		return
+	}
+	s.publishOverspent(previous, current, event.Budgets)
+	if _, err := notifications.Evaluate(s.transactionRepo.DB, s.logger, current.HouseholdID, event); err != nil {
+		s.logger.Error("Error evaluating alert rules", "transaction_id", current.ID, "error", err)
+	}
+}
+
+// publishOverspent publishes a budget.overspent webhook event for each budget the change
+// took over its budgeted amount, so subscribers hear of an overspent budget once per
+// crossing rather than on every later change
+func (s *transactionService) publishOverspent(previous, current *Transaction, status []alerts.Budget) {
+	for _, budget := range status {
+		if cmp, err := budget.Spent.Cmp(budget.Budgeted); err != nil || cmp <= 0 {
+			continue
+		}
+		added, err := budgetSpending(current, budget)
+		if err != nil {
+			s.logger.Error("Error computing budget spending", "transaction_id", current.ID, "error", err)
+			continue
+		}
+		removed, err := budgetSpending(previous, budget)
+		if err != nil {
+			s.logger.Error("Error computing budget spending", "transaction_id", current.ID, "error", err)
+			continue
+		}
+		before, _ := budget.Spent.Sub(added)
+		before, _ = before.Add(removed)
+		if cmp, _ := before.Cmp(budget.Budgeted); cmp > 0 {
+			continue
+		}
+		s.publish(current.HouseholdID, webhook.BudgetOverspent, &OverspentResponse{Budget: budget, TransactionID: current.ID})
+	}
+}
+
+// budgetSpending returns what a transaction spends from a budget in its period: the
+// outflows less refunds of its lines in the budget's category and currency
+func budgetSpending(t *Transaction, budget alerts.Budget) (money.Money, error) {
+	spent, err := money.Zero(budget.Budgeted.Currency())
+	if err != nil || t == nil || t.Date.Before(budget.PeriodStart) || !t.Date.Before(budget.PeriodEnd) {
+		return spent, err
+	}
+	lines, err := splits.ByCategory(t.Category, t.Amount, t.Splits)
+	if err != nil {
+		return money.Money{}, err
+	}
+	for _, line := range lines {
+		if strings.EqualFold(line.Category, budget.Category) && line.Amount.Currency() == spent.Currency() {
+			if spent, err = spent.Sub(line.Amount); err != nil {
+				return money.Money{}, err
+			}
+		}
+	}
+	return spent, nil
+}
+
+// alertEvent returns the state after a transaction changed as the alert rules watch it:
+// the transaction, the spending of the budgets of its categories in its budget period, and
+// the current balance of its account, all loaded from the stored data
+func (s *transactionService) alertEvent(t *Transaction) (alerts.Event, error) {
+	event := alerts.Event{Transaction: t.toAlert()}
+
+	lines, err := splits.ByCategory(t.Category, t.Amount, t.Splits)
+	if err != nil {
+		return alerts.Event{}, err
+	}
+	var categories []string
+	for _, line := range lines {
+		if line.Category != "" {
+			categories = append(categories, line.Category)
+		}
+	}
+	if len(categories) > 0 {
+		status, err := budgets.Status(s.transactionRepo.DB, s.logger, t.HouseholdID, categories, t.Date)
+		if err != nil {
+			return alerts.Event{}, err
+		}
+		for _, budget := range status {
+			event.Budgets = append(event.Budgets, alerts.Budget{
+				Category:    budget.Category,
+				Budgeted:    budget.Budgeted,
+				Spent:       budget.Spent,
+				PeriodStart: budget.PeriodStart,
+				PeriodEnd:   budget.PeriodEnd,
+			})
+		}
+	}
+
+	account, err := accounts.Get(s.transactionRepo.DB, s.logger, t.AccountID)
+	if err != nil {
+		return alerts.Event{}, err
+	}
+	balance, ok, err := balances.Balance(s.transactionRepo.DB, s.logger, t.AccountID, time.Now())
+	if err != nil {
+		return alerts.Event{}, err
+	}
+	if ok {
+		event.Accounts = append(event.Accounts, alerts.Account{ID: account.ID, Name: account.Name, Balance: balance})
+	}
+	return event, nil
+}
+
+// publish queues a webhook event for the household, logging rather than failing on errors
+func (s *transactionService) publish(householdID int64, eventType string, data any) {
+	if _, err := webhooks.Publish(s.transactionRepo.DB, s.logger, householdID, eventType, data); err != nil {
+		s.logger.Error("Error publishing webhook event", "event", eventType, "error", err)
+	}
+}
+
+// checkTransferCategory returns ErrCategoryMissing as a validation error when an
+// uncategorized transfer between the accounts moves money out of the budget
+func (s *transactionService) checkTransferCategory(householdID, fromID, toID int64) error {
+	from, err := s.householdAccount(householdID, fromID, "AccountID")
+	if err != nil {
+		return err
+	}
+	to, err := s.householdAccount(householdID, toID, "AccountID")
+	if err != nil {
+		return err
+	}
+	if transfers.EffectOf(transferAccount(from), transferAccount(to)) == transfers.EffectOutflow {
+		return transferError(transfers.ErrCategoryMissing)
+	}
+	return nil
+}
+
+// transferAccount returns an account as a transfer sees it
+func transferAccount(account *accounts.Account) transfers.Account {
+	return transfers.Account{ID: strconv.FormatInt(account.ID, 10), Currency: account.Currency, OnBudget: account.OnBudget}
+}
+
+// transferError reports a transfer the transfers package rejected as a validation error
+func transferError(err error) error {
+	field := "Amount"
+	switch {
+	case errors.Is(err, transfers.ErrSameAccount):
+		field = "ToAccountID"
+	case errors.Is(err, transfers.ErrCurrencyMissing):
+		field = "Received"
+	case errors.Is(err, transfers.ErrCategoryMissing):
+		field = "Category"
+	}
+	return &validate.ValidationError{Errors: map[string]string{field: err.Error()}}
+}
+
+// gainsError reports a missing exchange rate as ErrRateNotFound
+func gainsError(err error) error {
+	if errors.Is(err, fx.ErrRateNotFound) {
+		return fmt.Errorf("%w: %v", ErrRateNotFound, err)
+	}
+	return err
+}
+
+// convert converts an amount to a currency at the rate in effect on the date
+func convert(source fx.RateSource, amount money.Money, currency string, date time.Time) (money.Money, error) {
+	converted, err := fx.Convert(source, amount.Amount(), amount.Currency(), currency, date)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return money.New(converted, currency)
+}
+
+// subscriptions detects the recurring payments in a household's transactions up to a
+// date. Transfers are left out, and scheduled transactions are matched to the payment
+// whose next charge they stand for rather than counted as charges.
+func (s *transactionService) subscriptions(householdID int64, asOf time.Time) ([]*SubscriptionResponse, error) {
+	if _, err := households.Get(s.transactionRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	transactions, err := s.transactionRepo.list(householdID, listFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*Transaction, len(transactions))
+	var history []recurring.Transaction
+	var scheduled []*Transaction
+	for i := range transactions {
+		transaction := &transactions[i]
+		switch {
+		case transaction.Scheduled:
+			scheduled = append(scheduled, transaction)
+		case transaction.TransferID.Valid, transaction.Date.After(asOf):
+		default:
+			id := strconv.FormatInt(transaction.ID, 10)
+			byID[id] = transaction
+			history = append(history, recurring.Transaction{
+				ID:     id,
+				Date:   transaction.Date,
+				Payee:  transaction.Payee,
+				Amount: transaction.Amount,
+			})
+		}
+	}
+
+	detected := recurring.Detect(history, asOf, recurring.DefaultOptions())
+	responses := make([]*SubscriptionResponse, 0, len(detected))
+	for _, subscription := range detected {
+		last := byID[subscription.TransactionIDs[len(subscription.TransactionIDs)-1]]
+		response := &SubscriptionResponse{Subscription: subscription, AccountID: last.AccountID}
+		for _, pending := range scheduled {
+			if pending.AccountID == last.AccountID && pending.Date.After(subscription.LastDate) &&
+				strings.EqualFold(pending.Payee, subscription.Payee) {
+				response.ScheduledID = pending.ID
+				break
+			}
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// schedule converts the recurring payment a transaction belongs to into a scheduled
+// transaction for its next charge, on the account and in the category of its latest payment
+func (s *transactionService) schedule(householdID int64, input ScheduleRequest, asOf time.Time) (*TransactionResponse, error) {
+	if input.TransactionID <= 0 {
+		return nil, &validate.ValidationError{Errors: map[string]string{"TransactionID": "TransactionID is required"}}
+	}
+	subscriptions, err := s.subscriptions(householdID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	id := strconv.FormatInt(input.TransactionID, 10)
+	for _, subscription := range subscriptions {
+		if !slices.Contains(subscription.TransactionIDs, id) {
+			continue
+		}
+		if subscription.ScheduledID != 0 {
+			return nil, ErrAlreadyScheduled
+		}
+
+		lastID, _ := strconv.ParseInt(subscription.TransactionIDs[len(subscription.TransactionIDs)-1], 10, 64)
+		last, err := s.transactionRepo.getByID(lastID)
+		if err != nil {
+			return nil, err
+		}
+		next := subscription.ToSchedule()
+		return s.create(householdID, TransactionRequest{
+			AccountID: subscription.AccountID,
+			Date:      next.NextDate.Format(time.DateOnly),
+			Amount:    next.Amount,
+			Payee:     next.Payee,
+			Category:  last.Category,
+			Scheduled: true,
+		})
+	}
+	return nil, ErrNotRecurring
+}
@@ -0,0 +1,87 @@
+package transactions
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/accounts"
+	"github.com/ZiadMansourM/budgetly/pkg/alerts"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	engine "github.com/ZiadMansourM/budgetly/pkg/rules"
+	"github.com/ZiadMansourM/budgetly/pkg/splits"
+	"github.com/ZiadMansourM/budgetly/pkg/transfers"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+func TestTransferOffBudget(t *testing.T) {
+	checking := transferAccount(&accounts.Account{ID: 1, Currency: "EUR", OnBudget: true})
+	mortgage := transferAccount(&accounts.Account{ID: 2, Currency: "EUR", OnBudget: false})
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	amount, _ := money.New(120000, "EUR")
+
+	_, err := transfers.New(date, checking, mortgage, amount, money.Money{}, "")
+	var validationErr *validate.ValidationError
+	if !errors.As(transferError(err), &validationErr) || validationErr.Errors["Category"] == "" {
+		t.Fatalf("Expected a Category validation error leaving the budget, got %v", err)
+	}
+
+	transfer, err := transfers.New(date, checking, mortgage, amount, money.Money{}, "Housing")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transfer.Effect != transfers.EffectOutflow || transfer.From.Category != "Housing" {
+		t.Errorf("Expected an outflow spent from Housing, got %s from %q", transfer.Effect, transfer.From.Category)
+	}
+
+	transfer, err = transfers.New(date, mortgage, checking, amount, money.Money{}, "")
+	if err != nil || transfer.Effect != transfers.EffectInflow {
+		t.Errorf("Expected an uncategorized inflow into the budget, got %v, %v", transfer.Effect, err)
+	}
+}
+
+func TestBudgetSpending(t *testing.T) {
+	amount, _ := money.New(-5000, "EUR")
+	groceries, _ := money.New(-3000, "EUR")
+	household, _ := money.New(-2000, "EUR")
+	budgeted, _ := money.New(20000, "EUR")
+	transaction := &Transaction{
+		Date:   time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		Amount: amount,
+		Splits: []splits.Split{{Category: "Groceries", Amount: groceries}, {Category: "Household", Amount: household}},
+	}
+	budget := alerts.Budget{
+		Category:    "groceries",
+		Budgeted:    budgeted,
+		PeriodStart: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	spent, err := budgetSpending(transaction, budget)
+	if err != nil || spent.Amount() != 3000 {
+		t.Fatalf("Expected the groceries split to spend 30.00, got %v, %v", spent, err)
+	}
+
+	transaction.Date = time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	if spent, err := budgetSpending(transaction, budget); err != nil || !spent.IsZero() {
+		t.Errorf("Expected nothing spent outside the period, got %v, %v", spent, err)
+	}
+	if spent, err := budgetSpending(nil, budget); err != nil || !spent.IsZero() {
+		t.Errorf("Expected nothing spent without a transaction, got %v, %v", spent, err)
+	}
+}
+
+func TestApplyRulesPayeeCategory(t *testing.T) {
+	transaction := &Transaction{Payee: "Amazon", payeeCategory: "Shopping"}
+
+	transaction.applyRules(engine.Transaction{Payee: "Amazon"})
+	if transaction.Category != "Shopping" {
+		t.Errorf("Expected the payee's default category, got %q", transaction.Category)
+	}
+
+	transaction.Category = ""
+	transaction.applyRules(engine.Transaction{Payee: "Amazon", Category: "Books"})
+	if transaction.Category != "Books" {
+		t.Errorf("Expected the rule's category over the payee's default, got %q", transaction.Category)
+	}
+}
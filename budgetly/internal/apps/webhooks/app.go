@@ -0,0 +1,34 @@
+package webhooks
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// pollInterval is how often the dispatcher looks for deliveries that are due.
+const pollInterval = 10 * time.Second
+
+// NewWebhooksApp creates a new outbound webhooks application with the provided database
+// connection, and starts the dispatcher that sends and retries due deliveries
+func NewWebhooksApp(db *sqlx.DB, logger *slog.Logger, router *http.ServeMux) {
+	webhookModel := newWebhookModel(db, logger)
+	webhookService := newWebhookService(webhookModel, logger)
+	newWebhookHandler(webhookService, logger, router)
+
+	if db != nil {
+		go webhookService.run(context.Background(), pollInterval)
+	}
+}
+
+// Publish queues an event for every enabled subscription of the household whose filter
+// matches its type, e.g. Publish(db, logger, householdID, webhook.TransactionCreated,
+// transaction). The dispatcher sends the deliveries shortly after. It returns the number
+// of deliveries queued.
+func Publish(db *sqlx.DB, logger *slog.Logger, householdID int64, eventType string, data any) (int, error) {
+	service := newWebhookService(newWebhookModel(db, logger), logger)
+	return service.publish(householdID, eventType, data, time.Now())
+}
@@ -0,0 +1,173 @@
+package webhooks
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/pkg/webhook"
+	"github.com/lib/pq"
+)
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Subscription is an endpoint that receives the events matching its filters.
+type Subscription struct {
+	ID                  int            `db:"id"`
+	HouseholdID         int64          `db:"household_id"`
+	URL                 string         `db:"url"`
+	Secret              string         `db:"secret"`
+	EventTypes          pq.StringArray `db:"event_types"`
+	Enabled             bool           `db:"enabled"`
+	ConsecutiveFailures int            `db:"consecutive_failures"`
+	DisabledAt          sql.NullTime   `db:"disabled_at"`
+	CreatedAt           time.Time      `db:"created_at"`
+}
+
+// SubscriptionRequest represents the input data for creating or updating a subscription.
+type SubscriptionRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+// Validate validates the SubscriptionRequest struct.
+func (input *SubscriptionRequest) Validate() map[string]string {
+	input.URL = strings.TrimSpace(input.URL)
+
+	// Define validation rules for each field.
+	validationFields := validate.ValidationFields{
+		"URL": validate.Rules(
+			validate.Required,
+			validate.Max(2048),
+			validate.ErrorMessage("URL is required and must be at most 2048 characters long"),
+		),
+	}
+
+	// Perform validation using the validate package.
+	errors := validate.Validate(*input, validationFields)
+	if _, ok := errors["URL"]; !ok {
+		if u, err := url.Parse(input.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			errors["URL"] = "URL must be an http or https URL"
+		}
+	}
+	if len(input.EventTypes) == 0 {
+		errors["EventTypes"] = "At least one event type is required"
+	}
+	for _, eventType := range input.EventTypes {
+		if !webhook.ValidFilter(eventType) {
+			errors["EventTypes"] = "Unknown event type " + eventType + "; expected one of " + strings.Join(webhook.EventTypes, ", ") + ", a wildcard such as transaction.* or *"
+			break
+		}
+	}
+	return errors
+}
+
+// toSubscription converts a validated SubscriptionRequest into a Subscription.
+func (input *SubscriptionRequest) toSubscription(householdID int64) *Subscription {
+	return &Subscription{
+		HouseholdID: householdID,
+		URL:         input.URL,
+		EventTypes:  input.EventTypes,
+		Enabled:     input.Enabled == nil || *input.Enabled,
+	}
+}
+
+// SubscriptionResponse represents the subscription data to return in responses. The
+// secret is only included when the subscription is created.
+type SubscriptionResponse struct {
+	ID                  int        `json:"id"`
+	HouseholdID         int64      `json:"household_id"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Enabled             bool       `json:"enabled"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	Secret              string     `json:"secret,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// ToResponse converts a Subscription (from database) to a SubscriptionResponse (for API responses).
+func (s *Subscription) ToResponse() *SubscriptionResponse {
+	response := &SubscriptionResponse{
+		ID:                  s.ID,
+		HouseholdID:         s.HouseholdID,
+		URL:                 s.URL,
+		EventTypes:          s.EventTypes,
+		Enabled:             s.Enabled,
+		ConsecutiveFailures: s.ConsecutiveFailures,
+		CreatedAt:           s.CreatedAt,
+	}
+	if s.DisabledAt.Valid {
+		response.DisabledAt = &s.DisabledAt.Time
+	}
+	return response
+}
+
+// Delivery is one event queued for one subscription, with the outcome of its latest attempt.
+type Delivery struct {
+	ID             int            `db:"id"`
+	SubscriptionID int            `db:"subscription_id"`
+	EventID        string         `db:"event_id"`
+	EventType      string         `db:"event_type"`
+	Payload        string         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  sql.NullTime   `db:"next_attempt_at"`
+	ResponseStatus sql.NullInt32  `db:"response_status"`
+	ResponseBody   sql.NullString `db:"response_body"`
+	Error          sql.NullString `db:"error"`
+	DurationMS     sql.NullInt64  `db:"duration_ms"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+// DeliveryResponse represents the delivery log entry to return in responses.
+type DeliveryResponse struct {
+	ID             int             `json:"id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	ResponseBody   string          `json:"response_body,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	DurationMS     int64           `json:"duration_ms,omitempty"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// ToResponse converts a Delivery (from database) to a DeliveryResponse (for API responses),
+// including the payload only when asked to.
+func (d *Delivery) ToResponse(withPayload bool) *DeliveryResponse {
+	response := &DeliveryResponse{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: int(d.ResponseStatus.Int32),
+		ResponseBody:   d.ResponseBody.String,
+		Error:          d.Error.String,
+		DurationMS:     d.DurationMS.Int64,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+	if d.NextAttemptAt.Valid {
+		response.NextAttemptAt = &d.NextAttemptAt.Time
+	}
+	if withPayload {
+		response.Payload = json.RawMessage(d.Payload)
+	}
+	return response
+}
@@ -0,0 +1,9 @@
+package webhooks
+
+import "errors"
+
+var (
+	ErrInternalServer       error = errors.New("internal server error")
+	ErrSubscriptionNotFound error = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     error = errors.New("webhook delivery not found")
+)
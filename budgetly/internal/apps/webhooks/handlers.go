@@ -0,0 +1,277 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/utils"
+)
+
+// Delivery log page sizes.
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// webhookHandler is an HTTP handler for webhook subscriptions and their delivery log
+type webhookHandler struct {
+	webhookService *webhookService
+	logger         *slog.Logger
+	router         *http.ServeMux
+}
+
+// newWebhookHandler creates a new webhook handler with the provided webhook service and logger
+func newWebhookHandler(webhookService *webhookService, logger *slog.Logger, router *http.ServeMux) *webhookHandler {
+	webhookHandler := &webhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+		router:         router,
+	}
+	webhookHandler.registerRoutes()
+	return webhookHandler
+}
+
+// Register routes for webhook actions
+func (h *webhookHandler) registerRoutes() {
+	h.router.HandleFunc("POST /households/{household}/webhooks", h.create)
+	h.router.HandleFunc("GET /households/{household}/webhooks", h.list)
+	h.router.HandleFunc("GET /webhooks/{id}", h.get)
+	h.router.HandleFunc("PUT /webhooks/{id}", h.update)
+	h.router.HandleFunc("DELETE /webhooks/{id}", h.delete)
+	h.router.HandleFunc("POST /webhooks/{id}/ping", h.ping)
+	h.router.HandleFunc("GET /webhooks/{id}/deliveries", h.listDeliveries)
+	h.router.HandleFunc("GET /webhooks/{id}/deliveries/{delivery}", h.getDelivery)
+	h.router.HandleFunc("POST /webhooks/{id}/deliveries/{delivery}/redeliver", h.redeliver)
+}
+
+// Create is an HTTP handler for creating a new subscription for a household
+func (h *webhookHandler) create(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	var req SubscriptionRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	subscription, err := h.webhookService.create(householdID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, subscription)
+}
+
+// List is an HTTP handler for listing a household's subscriptions
+func (h *webhookHandler) list(w http.ResponseWriter, r *http.Request) {
+	householdID, ok := h.householdID(w, r)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.webhookService.list(householdID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, subscriptions)
+}
+
+// Get is an HTTP handler for retrieving a subscription by ID
+func (h *webhookHandler) get(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.pathID(w, r, "id", "subscription")
+	if !ok {
+		return
+	}
+
+	subscription, err := h.webhookService.get(subscriptionID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, subscription)
+}
+
+// Update is an HTTP handler for updating a subscription
+func (h *webhookHandler) update(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.pathID(w, r, "id", "subscription")
+	if !ok {
+		return
+	}
+
+	var req SubscriptionRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	subscription, err := h.webhookService.update(subscriptionID, req)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, subscription)
+}
+
+// Delete is an HTTP handler for deleting a subscription
+func (h *webhookHandler) delete(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.pathID(w, r, "id", "subscription")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.delete(subscriptionID); err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Ping is an HTTP handler for sending a test event to a subscription
+func (h *webhookHandler) ping(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.pathID(w, r, "id", "subscription")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.ping(r.Context(), subscriptionID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, delivery)
+}
+
+// ListDeliveries is an HTTP handler for reading a subscription's delivery log, with an
+// optional ?limit= parameter
+func (h *webhookHandler) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.pathID(w, r, "id", "subscription")
+	if !ok {
+		return
+	}
+
+	limit := defaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 || limit > maxLimit {
+			h.writeError(w, &validate.ValidationError{Errors: map[string]string{"limit": "limit must be between 1 and 200"}})
+			return
+		}
+	}
+
+	deliveries, err := h.webhookService.listDeliveries(subscriptionID, limit)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, deliveries)
+}
+
+// GetDelivery is an HTTP handler for retrieving a delivery with its payload
+func (h *webhookHandler) getDelivery(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.pathID(w, r, "id", "subscription")
+	if !ok {
+		return
+	}
+	deliveryID, ok := h.pathID(w, r, "delivery", "delivery")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.getDelivery(subscriptionID, deliveryID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, delivery)
+}
+
+// Redeliver is an HTTP handler for sending a past delivery's event again
+func (h *webhookHandler) redeliver(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := h.pathID(w, r, "id", "subscription")
+	if !ok {
+		return
+	}
+	deliveryID, ok := h.pathID(w, r, "delivery", "delivery")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.redeliver(r.Context(), subscriptionID, deliveryID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, delivery)
+}
+
+// decode decodes the JSON request body, writing a 400 response on failure
+func (h *webhookHandler) decode(w http.ResponseWriter, r *http.Request, dest any) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		h.logger.Warn("Invalid request body", "error", err)
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid request body"},
+		)
+		return false
+	}
+	return true
+}
+
+// pathID parses an ID path parameter, writing a 400 response on failure
+func (h *webhookHandler) pathID(w http.ResponseWriter, r *http.Request, param, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(param))
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid " + name + " ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// householdID parses the household path parameter, writing a 400 response on failure
+func (h *webhookHandler) householdID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("household"), 10, 64)
+	if err != nil || id <= 0 {
+		utils.WriteJson(
+			w,
+			http.StatusBadRequest,
+			map[string]string{"error": "Invalid household ID"},
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError maps service errors to HTTP responses
+func (h *webhookHandler) writeError(w http.ResponseWriter, err error) {
+	var validationErr *validate.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.WriteJson(w, http.StatusBadRequest, map[string]any{"errors": validationErr.Errors})
+	case errors.Is(err, households.ErrHouseholdNotFound), errors.Is(err, ErrSubscriptionNotFound), errors.Is(err, ErrDeliveryNotFound):
+		utils.WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	default:
+		h.logger.Error("Error handling webhook request", "error", err)
+		utils.WriteJson(w, http.StatusInternalServerError, map[string]string{"error": ErrInternalServer.Error()})
+	}
+}
@@ -0,0 +1,243 @@
+// This is the model layer, responsible for interacting
+// with the database and returning data to the service layer
+package webhooks
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/webhook"
+	"github.com/jmoiron/sqlx"
+)
+
+// subscriptionColumns lists the columns selected for a Subscription
+const subscriptionColumns = `id, household_id, url, secret, event_types, enabled, consecutive_failures, disabled_at, created_at`
+
+// deliveryColumns lists the columns selected for a Delivery
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	response_status, response_body, error, duration_ms, created_at, updated_at`
+
+// webhookModel wraps the database connection pool using sqlx
+type webhookModel struct {
+	DB     *sqlx.DB
+	logger *slog.Logger
+}
+
+func newWebhookModel(db *sqlx.DB, logger *slog.Logger) *webhookModel {
+	return &webhookModel{
+		DB:     db,
+		logger: logger,
+	}
+}
+
+// CreateSubscription inserts a new subscription into the database and returns its ID
+func (m *webhookModel) createSubscription(s *Subscription) (int, error) {
+	query := `INSERT INTO webhook_subscriptions (household_id, url, secret, event_types, enabled, created_at)
+	VALUES (:household_id, :url, :secret, :event_types, :enabled, :created_at)
+	RETURNING id`
+
+	s.CreatedAt = time.Now()
+	rows, err := m.DB.NamedQuery(query, s)
+	if err != nil {
+		m.logger.Error("Error inserting webhook subscription", "error", err)
+		return 0, ErrInternalServer
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&s.ID); err != nil {
+			m.logger.Error("Error scanning webhook subscription ID", "error", err)
+			return 0, ErrInternalServer
+		}
+	}
+
+	m.logger.Debug("Webhook subscription created successfully", "id", s.ID)
+	return s.ID, nil
+}
+
+// ListSubscriptions returns a household's subscriptions, or only the enabled ones
+func (m *webhookModel) listSubscriptions(householdID int64, enabledOnly bool) ([]Subscription, error) {
+	subscriptions := []Subscription{}
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions
+	WHERE household_id = $1 AND (NOT $2 OR enabled) ORDER BY id`
+	if err := m.DB.Select(&subscriptions, query, householdID, enabledOnly); err != nil {
+		m.logger.Error("Error listing webhook subscriptions", "error", err)
+		return nil, ErrInternalServer
+	}
+	return subscriptions, nil
+}
+
+// GetSubscription returns a subscription by ID
+func (m *webhookModel) getSubscription(id int) (*Subscription, error) {
+	s := &Subscription{}
+	err := m.DB.Get(s, `SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSubscriptionNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting webhook subscription by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return s, nil
+}
+
+// UpdateSubscription replaces a subscription's URL, filters and enabled flag. Enabling a
+// subscription clears its failure count, so a fixed endpoint starts with a clean slate.
+func (m *webhookModel) updateSubscription(s *Subscription) (*Subscription, error) {
+	updated := &Subscription{}
+	query := `UPDATE webhook_subscriptions SET url = $2, event_types = $3, enabled = $4,
+	consecutive_failures = CASE WHEN $4 THEN 0 ELSE consecutive_failures END,
+	disabled_at = CASE WHEN $4 THEN NULL ELSE COALESCE(disabled_at, $5) END
+	WHERE id = $1
+	RETURNING ` + subscriptionColumns
+	err := m.DB.Get(updated, query, s.ID, s.URL, s.EventTypes, s.Enabled, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSubscriptionNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error updating webhook subscription", "error", err)
+		return nil, ErrInternalServer
+	}
+	return updated, nil
+}
+
+// DeleteSubscription removes a subscription and its delivery log by ID
+func (m *webhookModel) deleteSubscription(id int) error {
+	result, err := m.DB.Exec(`DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		m.logger.Error("Error deleting webhook subscription", "error", err)
+		return ErrInternalServer
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		m.logger.Error("Error reading affected webhook subscription count", "error", err)
+		return ErrInternalServer
+	}
+	if affected == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// CreateDelivery queues a delivery, due immediately
+func (m *webhookModel) createDelivery(d *Delivery) error {
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $6, $6)
+	RETURNING ` + deliveryColumns
+
+	now := time.Now()
+	if err := m.DB.Get(d, query, d.SubscriptionID, d.EventID, d.EventType, d.Payload, StatusPending, now); err != nil {
+		m.logger.Error("Error inserting webhook delivery", "error", err)
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// ListDeliveries returns a subscription's delivery log, newest first
+func (m *webhookModel) listDeliveries(subscriptionID, limit int) ([]Delivery, error) {
+	deliveries := []Delivery{}
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries
+	WHERE subscription_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2`
+	if err := m.DB.Select(&deliveries, query, subscriptionID, limit); err != nil {
+		m.logger.Error("Error listing webhook deliveries", "error", err)
+		return nil, ErrInternalServer
+	}
+	return deliveries, nil
+}
+
+// GetDelivery returns one of a subscription's deliveries by ID
+func (m *webhookModel) getDelivery(subscriptionID, id int) (*Delivery, error) {
+	d := &Delivery{}
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = $1 AND id = $2`
+	err := m.DB.Get(d, query, subscriptionID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		m.logger.Error("Error getting webhook delivery by ID", "error", err)
+		return nil, ErrInternalServer
+	}
+	return d, nil
+}
+
+// ClaimDue leases up to limit pending deliveries of enabled subscriptions that are due,
+// pushing their next attempt past the lease so that other dispatchers skip them
+func (m *webhookModel) claimDue(now time.Time, limit int, lease time.Duration) ([]Delivery, error) {
+	deliveries := []Delivery{}
+	query := `UPDATE webhook_deliveries SET next_attempt_at = $2
+	WHERE id IN (
+		SELECT d.id FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = '` + StatusPending + `' AND d.next_attempt_at <= $1 AND s.enabled
+		ORDER BY d.next_attempt_at
+		LIMIT $3
+		FOR UPDATE OF d SKIP LOCKED
+	)
+	RETURNING ` + deliveryColumns
+	if err := m.DB.Select(&deliveries, query, now, now.Add(lease), limit); err != nil {
+		m.logger.Error("Error claiming due webhook deliveries", "error", err)
+		return nil, ErrInternalServer
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt. A failure schedules the next
+// attempt with exponential backoff until the attempts run out, and disables the
+// subscription once it has failed too many times in a row.
+func (m *webhookModel) recordAttempt(d *Delivery, result webhook.Result, now time.Time) error {
+	d.Attempts++
+	d.UpdatedAt = now
+	d.ResponseStatus = sql.NullInt32{Int32: int32(result.StatusCode), Valid: result.StatusCode != 0}
+	d.ResponseBody = sql.NullString{String: result.Body, Valid: result.Body != ""}
+	d.DurationMS = sql.NullInt64{Int64: result.Duration.Milliseconds(), Valid: true}
+	d.Error = sql.NullString{}
+	d.NextAttemptAt = sql.NullTime{}
+	switch {
+	case result.OK():
+		d.Status = StatusSucceeded
+	case d.Attempts >= webhook.MaxAttempts:
+		d.Status = StatusFailed
+		d.Error = sql.NullString{String: result.Err.Error(), Valid: true}
+	default:
+		d.Status = StatusPending
+		d.Error = sql.NullString{String: result.Err.Error(), Valid: true}
+		d.NextAttemptAt = sql.NullTime{Time: now.Add(webhook.Backoff(d.Attempts)), Valid: true}
+	}
+
+	tx, err := m.DB.Beginx()
+	if err != nil {
+		m.logger.Error("Error starting webhook attempt transaction", "error", err)
+		return ErrInternalServer
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE webhook_deliveries SET status = :status, attempts = :attempts, next_attempt_at = :next_attempt_at,
+	response_status = :response_status, response_body = :response_body, error = :error,
+	duration_ms = :duration_ms, updated_at = :updated_at
+	WHERE id = :id`
+	if _, err := tx.NamedExec(query, d); err != nil {
+		m.logger.Error("Error recording webhook attempt", "error", err)
+		return ErrInternalServer
+	}
+
+	if result.OK() {
+		_, err = tx.Exec(`UPDATE webhook_subscriptions SET consecutive_failures = 0 WHERE id = $1`, d.SubscriptionID)
+	} else {
+		_, err = tx.Exec(`UPDATE webhook_subscriptions SET consecutive_failures = consecutive_failures + 1,
+		enabled = enabled AND consecutive_failures + 1 < $2,
+		disabled_at = CASE WHEN enabled AND consecutive_failures + 1 >= $2 THEN $3 ELSE disabled_at END
+		WHERE id = $1`, d.SubscriptionID, webhook.DisableAfter, now)
+	}
+	if err != nil {
+		m.logger.Error("Error updating webhook subscription health", "error", err)
+		return ErrInternalServer
+	}
+
+	if err := tx.Commit(); err != nil {
+		m.logger.Error("Error committing webhook attempt", "error", err)
+		return ErrInternalServer
+	}
+	return nil
+}
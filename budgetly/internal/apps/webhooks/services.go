@@ -0,0 +1,288 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/internal/apps/households"
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+	"github.com/ZiadMansourM/budgetly/pkg/webhook"
+)
+
+// Dispatcher tuning.
+const (
+	// deliveryTimeout bounds how long a receiver may take to respond.
+	deliveryTimeout = 15 * time.Second
+	// claimBatch is how many due deliveries a dispatcher claims at a time.
+	claimBatch = 50
+	// claimLease keeps claimed deliveries from other dispatchers while they are sent.
+	claimLease = 5 * time.Minute
+)
+
+type webhookService struct {
+	webhookRepo *webhookModel
+	client      *http.Client
+	logger      *slog.Logger
+}
+
+func newWebhookService(webhookRepo *webhookModel, logger *slog.Logger) *webhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		client:      &http.Client{Timeout: deliveryTimeout},
+		logger:      logger,
+	}
+}
+
+// create validates and stores a new subscription of a household with a fresh signing secret
+func (s *webhookService) create(householdID int64, input SubscriptionRequest) (*SubscriptionResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Webhook subscription validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+	if _, err := households.Get(s.webhookRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+
+	subscription := input.toSubscription(householdID)
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		s.logger.Error("Error generating webhook secret", "error", err)
+		return nil, ErrInternalServer
+	}
+	subscription.Secret = secret
+	if _, err := s.webhookRepo.createSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	// The secret is only ever shown once, when the subscription is created.
+	response := subscription.ToResponse()
+	response.Secret = subscription.Secret
+	return response, nil
+}
+
+// list returns a household's subscriptions
+func (s *webhookService) list(householdID int64) ([]*SubscriptionResponse, error) {
+	if _, err := households.Get(s.webhookRepo.DB, s.logger, householdID); err != nil {
+		return nil, err
+	}
+	subscriptions, err := s.webhookRepo.listSubscriptions(householdID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*SubscriptionResponse, 0, len(subscriptions))
+	for i := range subscriptions {
+		responses = append(responses, subscriptions[i].ToResponse())
+	}
+	return responses, nil
+}
+
+// get returns a subscription by ID
+func (s *webhookService) get(id int) (*SubscriptionResponse, error) {
+	subscription, err := s.webhookRepo.getSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+	return subscription.ToResponse(), nil
+}
+
+// update validates and replaces a subscription's configuration
+func (s *webhookService) update(id int, input SubscriptionRequest) (*SubscriptionResponse, error) {
+	if validationErrors := input.Validate(); len(validationErrors) > 0 {
+		s.logger.Warn("Webhook subscription validation failed", "errors", validationErrors)
+		return nil, &validate.ValidationError{Errors: validationErrors}
+	}
+
+	subscription := input.toSubscription(0)
+	subscription.ID = id
+	updated, err := s.webhookRepo.updateSubscription(subscription)
+	if err != nil {
+		return nil, err
+	}
+	return updated.ToResponse(), nil
+}
+
+// delete removes a subscription by ID
+func (s *webhookService) delete(id int) error {
+	return s.webhookRepo.deleteSubscription(id)
+}
+
+// publish queues an event for every enabled subscription of the household whose filters match it
+func (s *webhookService) publish(householdID int64, eventType string, data any, now time.Time) (int, error) {
+	subscriptions, err := s.webhookRepo.listSubscriptions(householdID, true)
+	if err != nil {
+		return 0, err
+	}
+
+	var envelope *webhook.Envelope
+	var payload []byte
+	queued := 0
+	for i := range subscriptions {
+		if !webhook.Matches(subscriptions[i].EventTypes, eventType) {
+			continue
+		}
+		// Build the envelope once, so every subscriber sees the same event ID.
+		if envelope == nil {
+			e, err := webhook.NewEnvelope(eventType, data, now)
+			if err != nil {
+				s.logger.Error("Error building webhook event", "type", eventType, "error", err)
+				return queued, ErrInternalServer
+			}
+			if payload, err = json.Marshal(e); err != nil {
+				s.logger.Error("Error encoding webhook event", "type", eventType, "error", err)
+				return queued, ErrInternalServer
+			}
+			envelope = &e
+		}
+
+		delivery := &Delivery{SubscriptionID: subscriptions[i].ID, EventID: envelope.ID, EventType: eventType, Payload: string(payload)}
+		if err := s.webhookRepo.createDelivery(delivery); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// ping sends a test event to a subscription straight away and returns the delivery
+func (s *webhookService) ping(ctx context.Context, id int) (*DeliveryResponse, error) {
+	subscription, err := s.webhookRepo.getSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, err := webhook.NewEnvelope(webhook.Ping, map[string]int{"subscription_id": id}, time.Now())
+	if err != nil {
+		s.logger.Error("Error building webhook ping", "error", err)
+		return nil, ErrInternalServer
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		s.logger.Error("Error encoding webhook ping", "error", err)
+		return nil, ErrInternalServer
+	}
+
+	delivery := &Delivery{SubscriptionID: id, EventID: envelope.ID, EventType: webhook.Ping, Payload: string(payload)}
+	if err := s.webhookRepo.createDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return s.attemptNow(ctx, subscription, delivery)
+}
+
+// listDeliveries returns a subscription's delivery log
+func (s *webhookService) listDeliveries(subscriptionID, limit int) ([]*DeliveryResponse, error) {
+	if _, err := s.webhookRepo.getSubscription(subscriptionID); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.webhookRepo.listDeliveries(subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*DeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		responses = append(responses, deliveries[i].ToResponse(false))
+	}
+	return responses, nil
+}
+
+// getDelivery returns a delivery with its payload
+func (s *webhookService) getDelivery(subscriptionID, id int) (*DeliveryResponse, error) {
+	delivery, err := s.webhookRepo.getDelivery(subscriptionID, id)
+	if err != nil {
+		return nil, err
+	}
+	return delivery.ToResponse(true), nil
+}
+
+// redeliver sends a past delivery's event again as a new delivery, straight away. It works
+// for disabled subscriptions too, so a fixed endpoint can be checked before re-enabling it.
+func (s *webhookService) redeliver(ctx context.Context, subscriptionID, id int) (*DeliveryResponse, error) {
+	subscription, err := s.webhookRepo.getSubscription(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	original, err := s.webhookRepo.getDelivery(subscriptionID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &Delivery{SubscriptionID: subscriptionID, EventID: original.EventID, EventType: original.EventType, Payload: original.Payload}
+	if err := s.webhookRepo.createDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return s.attemptNow(ctx, subscription, delivery)
+}
+
+// attemptNow sends a delivery once and records the outcome; failures are retried later
+// by the dispatcher like any other delivery
+func (s *webhookService) attemptNow(ctx context.Context, subscription *Subscription, delivery *Delivery) (*DeliveryResponse, error) {
+	if err := s.attempt(ctx, subscription, delivery); err != nil {
+		return nil, err
+	}
+	return delivery.ToResponse(false), nil
+}
+
+// attempt sends a delivery and records the result
+func (s *webhookService) attempt(ctx context.Context, subscription *Subscription, delivery *Delivery) error {
+	now := time.Now()
+	result := webhook.Send(ctx, s.client, subscription.URL, subscription.Secret,
+		strconv.Itoa(delivery.ID), delivery.EventType, []byte(delivery.Payload), now)
+	if !result.OK() {
+		s.logger.Warn("Webhook delivery failed", "subscription", subscription.ID, "delivery", delivery.ID,
+			"attempt", delivery.Attempts+1, "error", result.Err)
+	}
+	return s.webhookRepo.recordAttempt(delivery, result, now)
+}
+
+// deliverDue sends every delivery that is due, batch by batch, and returns how many were attempted
+func (s *webhookService) deliverDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for ctx.Err() == nil {
+		deliveries, err := s.webhookRepo.claimDue(time.Now(), claimBatch, claimLease)
+		if err != nil {
+			return attempted, err
+		}
+
+		subscriptions := map[int]*Subscription{}
+		for i := range deliveries {
+			subscription, ok := subscriptions[deliveries[i].SubscriptionID]
+			if !ok {
+				if subscription, err = s.webhookRepo.getSubscription(deliveries[i].SubscriptionID); err != nil {
+					return attempted, err
+				}
+				subscriptions[subscription.ID] = subscription
+			}
+			if err := s.attempt(ctx, subscription, &deliveries[i]); err != nil {
+				return attempted, err
+			}
+			attempted++
+		}
+
+		if len(deliveries) < claimBatch {
+			break
+		}
+	}
+	return attempted, nil
+}
+
+// run delivers due webhooks every interval until the context is cancelled
+func (s *webhookService) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.deliverDue(ctx); err != nil {
+			s.logger.Error("Error delivering webhooks", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Package alerts evaluates alert rules against budgets, transactions and account balances.
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var (
+	ErrUnknownKind  = errors.New("unknown alert kind")
+	ErrInvalidRule  = errors.New("invalid alert rule")
+	ErrMissingScope = errors.New("alert rule needs a category or account")
+)
+
+// Kind is the condition an alert rule watches for.
+type Kind string
+
+const (
+	// KindThreshold fires when a budget's spending reaches a percentage of the budgeted amount.
+	KindThreshold Kind = "threshold"
+	// KindOverspent fires when a budget's spending exceeds the budgeted amount.
+	KindOverspent Kind = "overspent"
+	// KindLargeTransaction fires for a single outflow at least as large as the rule's amount.
+	KindLargeTransaction Kind = "large_transaction"
+	// KindLowBalance fires when an account's balance falls below the rule's amount.
+	KindLowBalance Kind = "low_balance"
+)
+
+// Rule is a user-defined alert. Budget rules are scoped to a category; transaction and
+// balance rules may be scoped to an account, or apply to every account when AccountID is 0.
+type Rule struct {
+	ID        int
+	Kind      Kind
+	Category  string
+	AccountID int64
+	// Percent is the share of the budget that triggers a threshold rule.
+	Percent int
+	// Amount is the transaction size or balance floor of the other rules.
+	Amount money.Money
+}
+
+// Validate checks that the rule has the fields its kind needs.
+func (r Rule) Validate() error {
+	switch r.Kind {
+	case KindThreshold:
+		if r.Percent <= 0 || r.Percent > 1000 {
+			return fmt.Errorf("%w: percent must be between 1 and 1000", ErrInvalidRule)
+		}
+		fallthrough
+	case KindOverspent:
+		if r.Category == "" {
+			return fmt.Errorf("%w: %s rules need a category", ErrMissingScope, r.Kind)
+		}
+	case KindLargeTransaction, KindLowBalance:
+		if r.Amount.Currency() == "" || r.Amount.IsNegative() || (r.Kind == KindLargeTransaction && r.Amount.IsZero()) {
+			return fmt.Errorf("%w: %s rules need a positive amount", ErrInvalidRule, r.Kind)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownKind, r.Kind)
+	}
+	return nil
+}
+
+// Budget is the spending of a category in the current budget period [PeriodStart, PeriodEnd).
+type Budget struct {
+	Category    string      `json:"category"`
+	Budgeted    money.Money `json:"budgeted"`
+	Spent       money.Money `json:"spent"`
+	PeriodStart time.Time   `json:"period_start"`
+	PeriodEnd   time.Time   `json:"period_end"`
+}
+
+// Transaction is a transaction that was created or changed. Outflows are negative.
+type Transaction struct {
+	ID        int64       `json:"id"`
+	AccountID int64       `json:"account_id"`
+	Category  string      `json:"category"`
+	Payee     string      `json:"payee"`
+	Amount    money.Money `json:"amount"`
+	Date      time.Time   `json:"date"`
+}
+
+// Account is an account's current balance.
+type Account struct {
+	ID      int64       `json:"id"`
+	Name    string      `json:"name"`
+	Balance money.Money `json:"balance"`
+}
+
+// Event is the state after a change, e.g. a transaction being added. Only the parts the
+// change affected need to be set.
+type Event struct {
+	Budgets     []Budget     `json:"budgets"`
+	Transaction *Transaction `json:"transaction"`
+	Accounts    []Account    `json:"accounts"`
+}
+
+// Alert is a rule that fired. Key identifies the occurrence, so the same condition in the
+// same period is only reported once however often it is evaluated.
+type Alert struct {
+	RuleID  int
+	Key     string
+	Title   string
+	Message string
+}
+
+// Evaluate returns the alerts that fire for the event as of today. Rules whose currency
+// differs from the budget, transaction or account they watch are skipped.
+func Evaluate(rules []Rule, event Event, today time.Time) []Alert {
+	var alerts []Alert
+	for _, rule := range rules {
+		switch rule.Kind {
+		case KindThreshold, KindOverspent:
+			for _, budget := range event.Budgets {
+				if budget.Category == rule.Category {
+					if alert, ok := evaluateBudget(rule, budget, today); ok {
+						alerts = append(alerts, alert)
+					}
+				}
+			}
+		case KindLargeTransaction:
+			if tx := event.Transaction; tx != nil && matchesAccount(rule, tx.AccountID) {
+				if alert, ok := evaluateTransaction(rule, *tx); ok {
+					alerts = append(alerts, alert)
+				}
+			}
+		case KindLowBalance:
+			for _, account := range event.Accounts {
+				if matchesAccount(rule, account.ID) {
+					if alert, ok := evaluateBalance(rule, account, today); ok {
+						alerts = append(alerts, alert)
+					}
+				}
+			}
+		}
+	}
+	return alerts
+}
+
+func matchesAccount(rule Rule, accountID int64) bool {
+	return rule.AccountID == 0 || rule.AccountID == accountID
+}
+
+// evaluateBudget fires a threshold or overspent rule for a budget, keyed by budget period.
+func evaluateBudget(rule Rule, budget Budget, today time.Time) (Alert, bool) {
+	if !budget.Budgeted.IsPositive() {
+		return Alert{}, false
+	}
+	cmp, err := budget.Spent.Cmp(budget.Budgeted)
+	if err != nil {
+		return Alert{}, false
+	}
+
+	key := fmt.Sprintf("rule:%d:%s:%s", rule.ID, budget.Category, budget.PeriodStart.Format(time.DateOnly))
+	left := daysLeft(today, budget.PeriodEnd)
+	if rule.Kind == KindOverspent {
+		if cmp <= 0 {
+			return Alert{}, false
+		}
+		over, _ := budget.Spent.Sub(budget.Budgeted)
+		return Alert{
+			RuleID:  rule.ID,
+			Key:     key,
+			Title:   fmt.Sprintf("%s is overspent", budget.Category),
+			Message: fmt.Sprintf("%s is %s over budget with %s left", budget.Category, over, pluralDays(left)),
+		}, true
+	}
+
+	// spent/budgeted >= percent/100, compared exactly.
+	spent := new(big.Rat).Mul(budget.Spent.Rat(), big.NewRat(100, 1))
+	limit := new(big.Rat).Mul(budget.Budgeted.Rat(), big.NewRat(int64(rule.Percent), 1))
+	if spent.Cmp(limit) < 0 {
+		return Alert{}, false
+	}
+	share := new(big.Rat).Quo(spent, budget.Budgeted.Rat())
+	percent, _ := share.Float64()
+	return Alert{
+		RuleID:  rule.ID,
+		Key:     key,
+		Title:   fmt.Sprintf("%s is %d%% spent", budget.Category, rule.Percent),
+		Message: fmt.Sprintf("%s is %.0f%% spent with %s left", budget.Category, percent, pluralDays(left)),
+	}, true
+}
+
+// evaluateTransaction fires a large transaction rule, keyed by transaction.
+func evaluateTransaction(rule Rule, tx Transaction) (Alert, bool) {
+	if !tx.Amount.IsNegative() {
+		return Alert{}, false
+	}
+	if cmp, err := tx.Amount.Abs().Cmp(rule.Amount); err != nil || cmp < 0 {
+		return Alert{}, false
+	}
+	payee := tx.Payee
+	if payee == "" {
+		payee = "an unknown payee"
+	}
+	return Alert{
+		RuleID:  rule.ID,
+		Key:     fmt.Sprintf("rule:%d:transaction:%d", rule.ID, tx.ID),
+		Title:   "Large transaction",
+		Message: fmt.Sprintf("%s was paid to %s on %s", tx.Amount.Abs(), payee, tx.Date.Format(time.DateOnly)),
+	}, true
+}
+
+// evaluateBalance fires a low balance rule, at most once per account per month.
+func evaluateBalance(rule Rule, account Account, today time.Time) (Alert, bool) {
+	if cmp, err := account.Balance.Cmp(rule.Amount); err != nil || cmp >= 0 {
+		return Alert{}, false
+	}
+	name := account.Name
+	if name == "" {
+		name = fmt.Sprintf("Account %d", account.ID)
+	}
+	return Alert{
+		RuleID:  rule.ID,
+		Key:     fmt.Sprintf("rule:%d:account:%d:%s", rule.ID, account.ID, today.Format("2006-01")),
+		Title:   fmt.Sprintf("%s balance is low", name),
+		Message: fmt.Sprintf("%s balance is %s, below %s", name, account.Balance, rule.Amount),
+	}, true
+}
+
+// daysLeft counts the days from today until the exclusive end of a period.
+func daysLeft(today, end time.Time) int {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+	return max(0, int(end.Sub(today).Hours()/24))
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
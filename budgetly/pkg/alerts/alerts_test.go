@@ -0,0 +1,97 @@
+package alerts
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func eur(amount int64) money.Money {
+	return money.MustNew(amount, "EUR")
+}
+
+func day(d int) time.Time {
+	return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRuleValidate(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want error
+	}{
+		{Rule{Kind: KindThreshold, Category: "Dining", Percent: 90}, nil},
+		{Rule{Kind: KindThreshold, Category: "Dining"}, ErrInvalidRule},
+		{Rule{Kind: KindOverspent}, ErrMissingScope},
+		{Rule{Kind: KindLargeTransaction, Amount: eur(0)}, ErrInvalidRule},
+		{Rule{Kind: KindLowBalance, Amount: eur(0)}, nil},
+		{Rule{Kind: "weekly_digest"}, ErrUnknownKind},
+	}
+	for _, tt := range tests {
+		if err := tt.rule.Validate(); !errors.Is(err, tt.want) {
+			t.Errorf("%+v: expected %v, got %v", tt.rule, tt.want, err)
+		}
+	}
+}
+
+func TestEvaluateBudgets(t *testing.T) {
+	rules := []Rule{
+		{ID: 1, Kind: KindThreshold, Category: "Dining", Percent: 90},
+		{ID: 2, Kind: KindOverspent, Category: "Dining"},
+		{ID: 3, Kind: KindThreshold, Category: "Groceries", Percent: 50},
+	}
+	event := Event{Budgets: []Budget{
+		{Category: "Dining", Budgeted: eur(20000), Spent: eur(18000), PeriodStart: day(1), PeriodEnd: time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{Category: "Groceries", Budgeted: eur(40000), Spent: eur(19999), PeriodStart: day(1), PeriodEnd: time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)},
+	}}
+
+	alerts := Evaluate(rules, event, day(22))
+	if len(alerts) != 1 {
+		t.Fatalf("Expected only the dining threshold to fire, got %+v", alerts)
+	}
+	if alerts[0].Message != "Dining is 90% spent with 10 days left" {
+		t.Errorf("Unexpected message %q", alerts[0].Message)
+	}
+	if alerts[0].Key != "rule:1:Dining:2026-03-01" {
+		t.Errorf("Unexpected key %q", alerts[0].Key)
+	}
+
+	event.Budgets[0].Spent = eur(21050)
+	alerts = Evaluate(rules, event, day(31))
+	if len(alerts) != 2 || alerts[1].Message != "Dining is 10.50 EUR over budget with 1 day left" {
+		t.Errorf("Expected threshold and overspent alerts, got %+v", alerts)
+	}
+}
+
+func TestEvaluateTransactionAndBalance(t *testing.T) {
+	rules := []Rule{
+		{ID: 1, Kind: KindLargeTransaction, Amount: eur(50000)},
+		{ID: 2, Kind: KindLowBalance, AccountID: 7, Amount: eur(10000)},
+		{ID: 3, Kind: KindLowBalance, Amount: money.MustNew(10000, "USD")},
+	}
+	event := Event{
+		Transaction: &Transaction{ID: 42, AccountID: 7, Payee: "Landlord", Amount: eur(-95000), Date: day(1)},
+		Accounts: []Account{
+			{ID: 7, Name: "Checking", Balance: eur(5000)},
+			{ID: 8, Name: "Savings", Balance: eur(500)},
+		},
+	}
+
+	alerts := Evaluate(rules, event, day(1))
+	if len(alerts) != 2 {
+		t.Fatalf("Expected a large transaction and a low balance alert, got %+v", alerts)
+	}
+	if alerts[0].Key != "rule:1:transaction:42" || alerts[0].Message != "950.00 EUR was paid to Landlord on 2026-03-01" {
+		t.Errorf("Unexpected large transaction alert %+v", alerts[0])
+	}
+	if alerts[1].Key != "rule:2:account:7:2026-03" || alerts[1].Message != "Checking balance is 50.00 EUR, below 100.00 EUR" {
+		t.Errorf("Unexpected low balance alert %+v", alerts[1])
+	}
+
+	// Income never counts as a large transaction.
+	event.Transaction.Amount = eur(95000)
+	if alerts := Evaluate(rules[:1], event, day(1)); len(alerts) != 0 {
+		t.Errorf("Expected no alert for income, got %+v", alerts)
+	}
+}
@@ -0,0 +1,151 @@
+// Package balances answers balance-as-of questions from periodic snapshots and the daily
+// changes posted after them, and works out how edits to back-dated transactions shift
+// the snapshots that follow.
+package balances
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+)
+
+var ErrInvalidChange = errors.New("invalid balance change")
+
+// Snapshot is an account's balance at the end of a day, after every change on that day.
+type Snapshot struct {
+	AccountID int64
+	Date      time.Time
+	Balance   money.Money
+}
+
+// Change is an amount posted to an account on a day. A transaction is one change; the
+// daily total of an account's transactions is also one change.
+type Change struct {
+	AccountID int64
+	Date      time.Time
+	Amount    money.Money
+}
+
+// Delta returns the changes that move balances from a transaction's previous posting to
+// its current one. previous is nil for a new transaction and current is nil for a deleted
+// one. Every snapshot of the change's account dated on or after the change's date must be
+// adjusted by its amount; snapshots before it are unaffected.
+func Delta(previous, current *Change) ([]Change, error) {
+	var changes []Change
+	if previous != nil {
+		changes = append(changes, Change{AccountID: previous.AccountID, Date: fx.Day(previous.Date), Amount: previous.Amount.Negate()})
+	}
+	if current != nil {
+		changes = append(changes, Change{AccountID: current.AccountID, Date: fx.Day(current.Date), Amount: current.Amount})
+	}
+	for _, change := range changes {
+		if change.AccountID <= 0 || change.Amount.Currency() == "" {
+			return nil, fmt.Errorf("%w: needs an account and an amount", ErrInvalidChange)
+		}
+	}
+
+	// An edit that keeps the account and date only changes the amount, and one that
+	// changes nothing needs no work at all.
+	if len(changes) == 2 && changes[0].AccountID == changes[1].AccountID && changes[0].Date.Equal(changes[1].Date) {
+		sum, err := changes[0].Amount.Add(changes[1].Amount)
+		if err != nil {
+			return nil, err
+		}
+		if sum.IsZero() {
+			return nil, nil
+		}
+		return []Change{{AccountID: changes[0].AccountID, Date: changes[0].Date, Amount: sum}}, nil
+	}
+	return changes, nil
+}
+
+// At returns an account's balance at the end of each date. Each balance starts from the
+// latest snapshot on or before its date, or from zero in currency when there is none, and
+// adds the changes after that snapshot up to and including the date. Snapshots and
+// changes must belong to the same account and may be in any order.
+func At(currency string, snapshots []Snapshot, changes []Change, dates []time.Time) ([]money.Money, error) {
+	zero, err := money.Zero(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots = append([]Snapshot(nil), snapshots...)
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Date.Before(snapshots[j].Date) })
+	changes = append([]Change(nil), changes...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Date.Before(changes[j].Date) })
+
+	balances := make([]money.Money, 0, len(dates))
+	for _, date := range dates {
+		date = fx.Day(date)
+
+		balance, since := zero, time.Time{}
+		if i := sort.Search(len(snapshots), func(i int) bool { return snapshots[i].Date.After(date) }); i > 0 {
+			balance, since = snapshots[i-1].Balance, snapshots[i-1].Date
+		}
+
+		start := sort.Search(len(changes), func(i int) bool { return changes[i].Date.After(since) })
+		for _, change := range changes[start:] {
+			if change.Date.After(date) {
+				break
+			}
+			if balance, err = balance.Add(change.Amount); err != nil {
+				return nil, err
+			}
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
+
+// PeriodEnds returns the last day of each period of the given granularity from..to,
+// with the final one clamped to to, e.g. 31 January, 28 February and 10 March for a
+// monthly timeline ending on 10 March.
+func PeriodEnds(from, to time.Time, g report.Granularity) ([]time.Time, error) {
+	periods, err := report.Buckets(from, to, g)
+	if err != nil {
+		return nil, err
+	}
+	return Ends(periods, to), nil
+}
+
+// Ends returns the last day of each period, with any after to clamped to it.
+func Ends(periods []report.Period, to time.Time) []time.Time {
+	to = fx.Day(to)
+	ends := make([]time.Time, 0, len(periods))
+	for _, period := range periods {
+		end := period.End.AddDate(0, 0, -1)
+		if end.After(to) {
+			end = to
+		}
+		ends = append(ends, end)
+	}
+	return ends
+}
+
+// DueSnapshots returns the month ends after the last snapshot (or from the first change
+// when there is none) that have passed by today and are not yet snapshotted.
+func DueSnapshots(last, firstChange time.Time, today time.Time) []time.Time {
+	start := firstChange
+	if !last.IsZero() {
+		start = last.AddDate(0, 0, 1)
+	}
+	if start.IsZero() {
+		return nil
+	}
+
+	today = fx.Day(today)
+	var due []time.Time
+	for month := report.Monthly.Trunc(start); ; month = report.Monthly.Next(month) {
+		end := report.Monthly.Next(month).AddDate(0, 0, -1)
+		if !end.Before(today) {
+			break
+		}
+		due = append(due, end)
+	}
+	return due
+}
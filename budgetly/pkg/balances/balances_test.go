@@ -0,0 +1,121 @@
+package balances
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func eur(amount int64) money.Money {
+	return money.MustNew(amount, "EUR")
+}
+
+func TestDelta(t *testing.T) {
+	created, err := Delta(nil, &Change{AccountID: 1, Date: day(3, 5), Amount: eur(-2000)})
+	if err != nil || len(created) != 1 || created[0].Amount.Amount() != -2000 {
+		t.Errorf("Unexpected changes for a new transaction: %+v, %v", created, err)
+	}
+
+	deleted, err := Delta(&Change{AccountID: 1, Date: day(3, 5), Amount: eur(-2000)}, nil)
+	if err != nil || len(deleted) != 1 || deleted[0].Amount.Amount() != 2000 {
+		t.Errorf("Unexpected changes for a deleted transaction: %+v, %v", deleted, err)
+	}
+
+	amended, err := Delta(
+		&Change{AccountID: 1, Date: day(3, 5), Amount: eur(-2000)},
+		&Change{AccountID: 1, Date: day(3, 5), Amount: eur(-2500)},
+	)
+	if err != nil || len(amended) != 1 || amended[0].Amount.Amount() != -500 {
+		t.Errorf("Expected a single -5.00 change, got %+v, %v", amended, err)
+	}
+
+	moved, err := Delta(
+		&Change{AccountID: 1, Date: day(3, 5), Amount: eur(-2000)},
+		&Change{AccountID: 2, Date: day(1, 20), Amount: eur(-2000)},
+	)
+	if err != nil || len(moved) != 2 || moved[0].AccountID != 1 || moved[1].Date != day(1, 20) {
+		t.Errorf("Expected a reversal and a new posting, got %+v, %v", moved, err)
+	}
+
+	unchanged, err := Delta(
+		&Change{AccountID: 1, Date: day(3, 5), Amount: eur(-2000)},
+		&Change{AccountID: 1, Date: day(3, 5), Amount: eur(-2000)},
+	)
+	if err != nil || len(unchanged) != 0 {
+		t.Errorf("Expected no changes, got %+v, %v", unchanged, err)
+	}
+
+	if _, err := Delta(nil, &Change{Date: day(3, 5), Amount: eur(1)}); !errors.Is(err, ErrInvalidChange) {
+		t.Errorf("Expected ErrInvalidChange, got %v", err)
+	}
+	if _, err := Delta(
+		&Change{AccountID: 1, Date: day(3, 5), Amount: eur(1)},
+		&Change{AccountID: 1, Date: day(3, 5), Amount: money.MustNew(1, "USD")},
+	); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
+
+func TestAt(t *testing.T) {
+	snapshots := []Snapshot{
+		{AccountID: 1, Date: day(2, 28), Balance: eur(150000)},
+		{AccountID: 1, Date: day(1, 31), Balance: eur(100000)},
+	}
+	changes := []Change{
+		{AccountID: 1, Date: day(1, 10), Amount: eur(100000)},
+		{AccountID: 1, Date: day(2, 28), Amount: eur(50000)},
+		{AccountID: 1, Date: day(3, 2), Amount: eur(-2000)},
+		{AccountID: 1, Date: day(3, 9), Amount: eur(-3000)},
+	}
+
+	balances, err := At("EUR", snapshots, changes, []time.Time{day(1, 5), day(1, 15), day(2, 28), day(3, 5), day(3, 31)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := make([]int64, 0, len(balances))
+	for _, balance := range balances {
+		got = append(got, balance.Amount())
+	}
+	// Changes on a snapshot's own day are already included in it.
+	if !slices.Equal(got, []int64{0, 100000, 150000, 148000, 145000}) {
+		t.Errorf("Unexpected balances %v", got)
+	}
+
+	if _, err := At("EUR", nil, []Change{{AccountID: 1, Date: day(1, 1), Amount: money.MustNew(1, "USD")}}, []time.Time{day(1, 2)}); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
+
+func TestPeriodEnds(t *testing.T) {
+	ends, err := PeriodEnds(day(1, 15), day(3, 10), report.Monthly)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(ends, []time.Time{day(1, 31), day(2, 28), day(3, 10)}) {
+		t.Errorf("Unexpected period ends %v", ends)
+	}
+
+	if _, err := PeriodEnds(day(3, 1), day(1, 1), report.Monthly); !errors.Is(err, report.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+}
+
+func TestDueSnapshots(t *testing.T) {
+	if due := DueSnapshots(time.Time{}, day(1, 10), day(3, 31)); !slices.Equal(due, []time.Time{day(1, 31), day(2, 28)}) {
+		t.Errorf("Expected January and February, got %v", due)
+	}
+	if due := DueSnapshots(day(1, 31), day(1, 10), day(4, 1)); !slices.Equal(due, []time.Time{day(2, 28), day(3, 31)}) {
+		t.Errorf("Expected February and March, got %v", due)
+	}
+	if due := DueSnapshots(time.Time{}, time.Time{}, day(4, 1)); len(due) != 0 {
+		t.Errorf("Expected nothing without changes, got %v", due)
+	}
+}
@@ -0,0 +1,99 @@
+// Package blob stores binary objects such as receipts behind a pluggable Store.
+// Objects are content-addressed: an object's key is the SHA-256 of its bytes, so
+// identical uploads share storage and a key never changes meaning.
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+	ErrTooLarge   = errors.New("blob exceeds the size limit")
+	ErrEmpty      = errors.New("blob is empty")
+)
+
+// keyRegex matches a content address: a lowercase hex SHA-256 digest.
+var keyRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Store is a flat key-value store for immutable binary objects.
+type Store interface {
+	// Put stores size bytes read from r under key, replacing any existing object.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get opens the object stored under key. It returns ErrNotFound if there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// List returns every stored object.
+	List(ctx context.Context) ([]Info, error)
+}
+
+// Info describes a stored object.
+type Info struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Object describes an uploaded object.
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+}
+
+// Upload reads r, rejecting more than maxSize bytes, and stores it under its content address.
+// The content type is sniffed from the bytes rather than trusted from the client.
+// The bytes are returned too, so callers can derive thumbnails without reading the object back.
+func Upload(ctx context.Context, store Store, r io.Reader, maxSize int64) (Object, []byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return Object{}, nil, fmt.Errorf("error reading blob: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return Object{}, nil, fmt.Errorf("%w of %d bytes", ErrTooLarge, maxSize)
+	}
+	if len(data) == 0 {
+		return Object{}, nil, ErrEmpty
+	}
+
+	object := Object{
+		Key:         Key(data),
+		Size:        int64(len(data)),
+		ContentType: http.DetectContentType(data),
+	}
+	if err := store.Put(ctx, object.Key, bytes.NewReader(data), object.Size); err != nil {
+		return Object{}, nil, err
+	}
+	return object, data, nil
+}
+
+// Key returns the content address of data.
+func Key(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidKey reports whether key is a well-formed content address.
+func ValidKey(key string) bool {
+	return keyRegex.MatchString(key)
+}
+
+// checkKey returns ErrInvalidKey for anything but a content address, which also keeps
+// keys from escaping the store's directory or bucket.
+func checkKey(key string) error {
+	if !ValidKey(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
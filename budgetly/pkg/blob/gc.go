@@ -0,0 +1,47 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MinGrace is the shortest grace period CollectGarbage accepts. Uploads store their object
+// before the referencing record is committed, so younger objects may still be in use.
+const MinGrace = time.Hour
+
+var ErrGraceTooShort = errors.New("grace period is shorter than the minimum of one hour")
+
+// CollectGarbage deletes every object in the store that is older than grace and that the
+// referenced set does not contain. The store is listed before referenced is called, so a
+// record committed in between is still seen; an object uploaded after the listing is never
+// considered, and one uploaded just before it is protected by the grace period.
+// It returns the keys of the deleted objects.
+func CollectGarbage(ctx context.Context, store Store, referenced func(ctx context.Context) (map[string]bool, error), grace time.Duration) ([]string, error) {
+	if grace < MinGrace {
+		return nil, ErrGraceTooShort
+	}
+
+	cutoff := time.Now().Add(-grace)
+	infos, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inUse, err := referenced(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, info := range infos {
+		if inUse[info.Key] || info.ModTime.After(cutoff) {
+			continue
+		}
+		if err := store.Delete(ctx, info.Key); err != nil {
+			return deleted, fmt.Errorf("error deleting orphaned blob %s: %w", info.Key, err)
+		}
+		deleted = append(deleted, info.Key)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,91 @@
+package blob
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	// Register the decoders for the image formats receipts usually come in.
+	_ "image/gif"
+	_ "image/png"
+)
+
+// MaxPixels is the largest image Thumbnail decodes. A small compressed file can declare huge
+// dimensions, and decoding allocates width x height pixels up front.
+const MaxPixels = 50_000_000
+
+var (
+	ErrNotImage      = errors.New("not a supported image")
+	ErrImageTooLarge = errors.New("image exceeds the pixel limit")
+)
+
+// Thumbnail decodes a JPEG, PNG or GIF image and returns a JPEG scaled down to fit within
+// maxSize x maxSize pixels, keeping the aspect ratio. Smaller images are not enlarged.
+// Images of more than MaxPixels pixels are rejected from their header, before decoding.
+func Thumbnail(data []byte, maxSize int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotImage, err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("%w: empty image", ErrNotImage)
+	}
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			width, height = maxSize, max(1, height*maxSize/width)
+		} else {
+			width, height = max(1, width*maxSize/height), maxSize
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scale(src, width, height), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("error encoding thumbnail: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// scale resizes src to width x height by averaging the source pixels that fall into each
+// destination pixel (box filter), which avoids the aliasing of nearest-neighbour sampling.
+func scale(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,243 @@
+// Package classify suggests transaction categories with a naive Bayes classifier
+// trained on previously categorized transactions.
+package classify
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+// Example is a categorized transaction used for training, or an uncategorized one to classify.
+// Amount is expressed in minor units (e.g. cents) of Currency.
+type Example struct {
+	Payee    string
+	Memo     string
+	Amount   int64
+	Currency string
+	Category string
+}
+
+// Suggestion is a candidate category with the classifier's confidence, between 0 and 1.
+type Suggestion struct {
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Classifier is a multinomial naive Bayes model over payee and memo tokens and amount buckets.
+// It can be trained incrementally and is safe for concurrent use.
+type Classifier struct {
+	mu          sync.RWMutex
+	documents   int
+	categories  map[string]int
+	tokens      map[string]map[string]int
+	tokenTotals map[string]int
+	vocabulary  map[string]int
+}
+
+// New creates an untrained classifier.
+func New() *Classifier {
+	return &Classifier{
+		categories:  make(map[string]int),
+		tokens:      make(map[string]map[string]int),
+		tokenTotals: make(map[string]int),
+		vocabulary:  make(map[string]int),
+	}
+}
+
+// Train adds categorized examples to the model. Examples without a category are skipped.
+func (c *Classifier) Train(examples ...Example) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, example := range examples {
+		if example.Category == "" {
+			continue
+		}
+		c.update(example, 1)
+	}
+}
+
+// Forget removes a previously trained example, e.g. when a transaction is recategorized
+// or deleted. The model is left unchanged unless it holds the example's category and
+// every one of its features under that category, as it does for a trained example.
+func (c *Classifier) Forget(example Example) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.holds(example) {
+		return
+	}
+	c.update(example, -1)
+}
+
+// holds reports whether removing the example's counts would leave none of them negative.
+func (c *Classifier) holds(example Example) bool {
+	if c.categories[example.Category] == 0 {
+		return false
+	}
+
+	counts := make(map[string]int)
+	for _, feature := range Features(example) {
+		counts[feature]++
+	}
+	for feature, count := range counts {
+		if c.tokens[example.Category][feature] < count {
+			return false
+		}
+	}
+	return true
+}
+
+// Suggest returns up to limit categories for the example, most likely first.
+// Confidences are normalized across all known categories.
+func (c *Classifier) Suggest(example Example, limit int) []Suggestion {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.documents == 0 || limit <= 0 {
+		return []Suggestion{}
+	}
+
+	features := Features(example)
+	vocabularySize := float64(len(c.vocabulary))
+
+	scores := make(map[string]float64, len(c.categories))
+	best := math.Inf(-1)
+	for category, count := range c.categories {
+		// Log prior plus the Laplace-smoothed log likelihood of every feature.
+		score := math.Log(float64(count) / float64(c.documents))
+		denominator := float64(c.tokenTotals[category]) + vocabularySize
+		for _, feature := range features {
+			score += math.Log((float64(c.tokens[category][feature]) + 1) / denominator)
+		}
+		scores[category] = score
+		best = math.Max(best, score)
+	}
+
+	// Convert log scores into probabilities with a numerically stable softmax, summing in
+	// category order so that the same model always gives the same confidences.
+	suggestions := make([]Suggestion, 0, len(scores))
+	for category, score := range scores {
+		suggestions = append(suggestions, Suggestion{Category: category, Confidence: math.Exp(score - best)})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Category < suggestions[j].Category
+	})
+	var total float64
+	for _, suggestion := range suggestions {
+		total += suggestion.Confidence
+	}
+	for i := range suggestions {
+		suggestions[i].Confidence /= total
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].Category < suggestions[j].Category
+	})
+
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// update adds (delta 1) or removes (delta -1) an example's counts.
+func (c *Classifier) update(example Example, delta int) {
+	c.documents += delta
+	c.categories[example.Category] += delta
+	if c.categories[example.Category] == 0 {
+		delete(c.categories, example.Category)
+	}
+
+	if c.tokens[example.Category] == nil {
+		c.tokens[example.Category] = make(map[string]int)
+	}
+	for _, feature := range Features(example) {
+		c.tokens[example.Category][feature] += delta
+		c.tokenTotals[example.Category] += delta
+		c.vocabulary[feature] += delta
+
+		if c.tokens[example.Category][feature] <= 0 {
+			delete(c.tokens[example.Category], feature)
+		}
+		if c.vocabulary[feature] <= 0 {
+			delete(c.vocabulary, feature)
+		}
+	}
+
+	if c.tokenTotals[example.Category] <= 0 {
+		delete(c.tokenTotals, example.Category)
+		delete(c.tokens, example.Category)
+	}
+}
+
+// Features extracts the model features of an example: prefixed payee and memo tokens
+// and a bucket describing the direction and size of the amount.
+func Features(example Example) []string {
+	var features []string
+	for _, token := range tokenize(example.Payee) {
+		features = append(features, "payee:"+token)
+	}
+	for _, token := range tokenize(example.Memo) {
+		features = append(features, "memo:"+token)
+	}
+	return append(features, "amount:"+amountBucket(example.Amount, example.Currency))
+}
+
+// tokenize lowercases the text and splits it into alphabetic words of at least two
+// letters, dropping digits so that card numbers and dates do not become features.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	tokens := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) >= 2 {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// amountBucket groups amounts in minor units of the currency into coarse, sign-aware size
+// buckets in major units.
+func amountBucket(amount int64, currency string) string {
+	direction := "out"
+	if amount >= 0 {
+		direction = "in"
+	} else {
+		amount = -amount
+	}
+
+	unit := int64(1)
+	for i := 0; i < money.Exponent(currency); i++ {
+		unit *= 10
+	}
+
+	bounds := []struct {
+		limit int64
+		name  string
+	}{
+		{5, "0-5"},
+		{20, "5-20"},
+		{50, "20-50"},
+		{100, "50-100"},
+		{500, "100-500"},
+		{1000, "500-1000"},
+	}
+	for _, bound := range bounds {
+		if amount < bound.limit*unit {
+			return direction + ":" + bound.name
+		}
+	}
+	return direction + ":1000+"
+}
@@ -0,0 +1,128 @@
+package classify
+
+import (
+	"math"
+	"testing"
+)
+
+func trainedClassifier() *Classifier {
+	classifier := New()
+	classifier.Train(
+		Example{Payee: "REWE Markt 1234", Amount: -4210, Category: "Groceries"},
+		Example{Payee: "REWE City", Amount: -1899, Category: "Groceries"},
+		Example{Payee: "Lidl Berlin", Amount: -2750, Category: "Groceries"},
+		Example{Payee: "Shell Station 42", Amount: -6500, Category: "Fuel"},
+		Example{Payee: "Aral Tankstelle", Amount: -7200, Category: "Fuel"},
+		Example{Payee: "Employer GmbH", Memo: "Salary January", Amount: 350000, Category: "Income"},
+		Example{Payee: "Employer GmbH", Memo: "Salary February", Amount: 350000, Category: "Income"},
+	)
+	return classifier
+}
+
+func TestSuggest(t *testing.T) {
+	classifier := trainedClassifier()
+
+	suggestions := classifier.Suggest(Example{Payee: "REWE Markt 9876", Amount: -3120}, 3)
+	if len(suggestions) != 3 {
+		t.Fatalf("Expected 3 suggestions, got %+v", suggestions)
+	}
+	if suggestions[0].Category != "Groceries" {
+		t.Errorf("Expected Groceries first, got %+v", suggestions)
+	}
+
+	var total float64
+	for i, suggestion := range suggestions {
+		total += suggestion.Confidence
+		if i > 0 && suggestion.Confidence > suggestions[i-1].Confidence {
+			t.Errorf("Expected suggestions sorted by confidence, got %+v", suggestions)
+		}
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Expected confidences over all categories to sum to 1, got %v", total)
+	}
+
+	income := classifier.Suggest(Example{Payee: "Employer GmbH", Memo: "Salary March", Amount: 350000}, 1)
+	if len(income) != 1 || income[0].Category != "Income" || income[0].Confidence < 0.9 {
+		t.Errorf("Expected a confident Income suggestion, got %+v", income)
+	}
+}
+
+func TestIncrementalTraining(t *testing.T) {
+	classifier := trainedClassifier()
+	coffee := Example{Payee: "Corner Coffee", Amount: -450}
+
+	classifier.Train(
+		Example{Payee: "Corner Coffee", Amount: -380, Category: "Dining"},
+		Example{Payee: "Corner Coffee", Amount: -420, Category: "Dining"},
+	)
+	if got := classifier.Suggest(coffee, 1); got[0].Category != "Dining" {
+		t.Errorf("Expected Dining after training, got %+v", got)
+	}
+
+	classifier.Forget(Example{Payee: "Corner Coffee", Amount: -380, Category: "Dining"})
+	classifier.Forget(Example{Payee: "Corner Coffee", Amount: -420, Category: "Dining"})
+	for _, suggestion := range classifier.Suggest(coffee, 10) {
+		if suggestion.Category == "Dining" {
+			t.Errorf("Expected Dining to be forgotten, got %+v", suggestion)
+		}
+	}
+
+	// Forgetting an example of a known category that was never trained must not
+	// remove counts belonging to other examples.
+	before := classifier.Suggest(Example{Payee: "REWE Markt", Amount: -2000}, 2)
+	classifier.Forget(Example{Payee: "Corner Coffee", Amount: -380, Category: "Groceries"})
+	after := classifier.Suggest(Example{Payee: "REWE Markt", Amount: -2000}, 2)
+	for i := range before {
+		if before[i] != after[i] {
+			t.Errorf("Expected forgetting an untrained example to be a no-op, got %+v then %+v", before, after)
+			break
+		}
+	}
+
+	// Forgetting an unknown category must not corrupt the model.
+	classifier.Forget(Example{Payee: "Nowhere", Category: "Unknown"})
+	if got := classifier.Suggest(Example{Payee: "Shell"}, 1); got[0].Category != "Fuel" {
+		t.Errorf("Expected Fuel, got %+v", got)
+	}
+}
+
+func TestSuggestUntrained(t *testing.T) {
+	if got := New().Suggest(Example{Payee: "Anything"}, 3); len(got) != 0 {
+		t.Errorf("Expected no suggestions from an untrained classifier, got %+v", got)
+	}
+}
+
+func TestFeatures(t *testing.T) {
+	features := Features(Example{Payee: "AMZN Mktp US*2K4L", Memo: "Order 12", Amount: -2599})
+	expected := []string{"payee:amzn", "payee:mktp", "payee:us", "memo:order", "amount:out:20-50"}
+
+	if len(features) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, features)
+	}
+	for i := range expected {
+		if features[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, features)
+			break
+		}
+	}
+}
+
+func TestAmountBucketCurrencyExponent(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		expected string
+	}{
+		{-2599, "EUR", "out:20-50"},
+		{-2599, "JPY", "out:1000+"},
+		{-25990, "KWD", "out:20-50"},
+		{350000, "", "in:1000+"},
+		{40, "JPY", "in:20-50"},
+	}
+
+	for _, test := range tests {
+		if got := amountBucket(test.amount, test.currency); got != test.expected {
+			t.Errorf("amountBucket(%d, %q): expected %s, got %s", test.amount, test.currency, test.expected, got)
+		}
+	}
+}
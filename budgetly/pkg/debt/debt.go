@@ -0,0 +1,300 @@
+// Package debt simulates paying off debts month by month under different payoff strategies.
+package debt
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+// maxMonths stops simulations of plans that would take longer than a century.
+const maxMonths = 1200
+
+var (
+	ErrNoDebts         = errors.New("at least one debt is required")
+	ErrInvalidDebt     = errors.New("invalid debt")
+	ErrUnknownStrategy = errors.New("unknown payoff strategy")
+	ErrInvalidOrder    = errors.New("custom order must list every debt exactly once")
+	ErrNeverPaidOff    = errors.New("payments do not cover the interest, so the debts are never paid off")
+	ErrNegativeExtra   = errors.New("extra payment must not be negative")
+	ErrDuplicateDebtID = errors.New("debt IDs must be unique")
+	ErrMixedCurrencies = errors.New("all debts and the extra payment must be in the same currency")
+	ErrPlanTooLong     = fmt.Errorf("plan takes longer than %d months", maxMonths)
+)
+
+// Strategy decides which debt receives the money left over after minimum payments.
+type Strategy string
+
+const (
+	// StrategySnowball pays the smallest balance first for quick wins.
+	StrategySnowball Strategy = "snowball"
+	// StrategyAvalanche pays the highest interest rate first, which costs the least interest.
+	StrategyAvalanche Strategy = "avalanche"
+	// StrategyCustom pays debts in an order chosen by the user.
+	StrategyCustom Strategy = "custom"
+)
+
+// Debt is a loan or credit card balance to pay off.
+type Debt struct {
+	ID      string      `json:"id"`
+	Name    string      `json:"name"`
+	Balance money.Money `json:"balance"`
+	// APR is the annual percentage rate, e.g. 19.99 for 19.99%, compounded monthly.
+	APR            *big.Rat    `json:"-"`
+	MinimumPayment money.Money `json:"minimum_payment"`
+}
+
+// ParseAPR parses an annual percentage rate such as "19.99".
+func ParseAPR(value string) (*big.Rat, error) {
+	apr, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || apr.Sign() < 0 {
+		return nil, fmt.Errorf("%w: APR %q must be a non-negative number", ErrInvalidDebt, value)
+	}
+	return apr, nil
+}
+
+// Payment is one month of a debt's schedule.
+type Payment struct {
+	Month     time.Time   `json:"month"`
+	Payment   money.Money `json:"payment"`
+	Interest  money.Money `json:"interest"`
+	Principal money.Money `json:"principal"`
+	Balance   money.Money `json:"balance"`
+}
+
+// DebtPlan is the payoff schedule of a single debt.
+type DebtPlan struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	PayoffDate time.Time   `json:"payoff_date"`
+	Interest   money.Money `json:"interest"`
+	Schedule   []Payment   `json:"schedule"`
+}
+
+// Plan is the outcome of paying off all debts with one strategy.
+type Plan struct {
+	Strategy      Strategy    `json:"strategy"`
+	Order         []string    `json:"order"`
+	Months        int         `json:"months"`
+	PayoffDate    time.Time   `json:"payoff_date"`
+	TotalInterest money.Money `json:"total_interest"`
+	TotalPaid     money.Money `json:"total_paid"`
+	Debts         []DebtPlan  `json:"debts"`
+}
+
+// Simulate pays off the debts month by month, starting in start's month. Each month interest
+// accrues on every balance, every debt gets its minimum payment, and the rest of the monthly
+// budget (all minimum payments plus extra) goes to the debts in strategy order. Minimums freed
+// by paid-off debts roll over to the next debt. order is only used by StrategyCustom.
+func Simulate(debts []Debt, extra money.Money, strategy Strategy, order []string, start time.Time) (Plan, error) {
+	if err := validate(debts, extra); err != nil {
+		return Plan{}, err
+	}
+
+	ordered, err := sortDebts(debts, strategy, order)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	currency := extra.Currency()
+	zero, _ := money.Zero(currency)
+	budget := extra
+	for _, d := range ordered {
+		budget, _ = budget.Add(d.MinimumPayment)
+	}
+
+	month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	plan := Plan{Strategy: strategy, PayoffDate: month, TotalInterest: zero, TotalPaid: zero}
+	balances := make([]money.Money, len(ordered))
+	for i, d := range ordered {
+		balances[i] = d.Balance
+		plan.Order = append(plan.Order, d.ID)
+		// Debts that are already paid off count as paid off in the first month.
+		plan.Debts = append(plan.Debts, DebtPlan{ID: d.ID, Name: d.Name, PayoffDate: month, Interest: zero, Schedule: []Payment{}})
+	}
+
+	for remaining := total(balances); remaining.IsPositive(); month = month.AddDate(0, 1, 0) {
+		if plan.Months == maxMonths {
+			return Plan{}, ErrPlanTooLong
+		}
+		plan.Months++
+
+		interests := make([]money.Money, len(ordered))
+		payments := make([]money.Money, len(ordered))
+		available := budget
+		for i, d := range ordered {
+			interests[i], payments[i] = zero, zero
+			if !balances[i].IsPositive() {
+				continue
+			}
+			interests[i], err = balances[i].MulRat(new(big.Rat).Quo(d.APR, big.NewRat(1200, 1)))
+			if err != nil {
+				return Plan{}, err
+			}
+			balances[i], _ = balances[i].Add(interests[i])
+
+			payments[i] = minMoney(d.MinimumPayment, balances[i])
+			available, _ = available.Sub(payments[i])
+		}
+
+		// Snowball the rest into the debts in strategy order.
+		for i := range ordered {
+			if !available.IsPositive() {
+				break
+			}
+			owed, _ := balances[i].Sub(payments[i])
+			if !owed.IsPositive() {
+				continue
+			}
+			more := minMoney(available, owed)
+			payments[i], _ = payments[i].Add(more)
+			available, _ = available.Sub(more)
+		}
+
+		for i := range ordered {
+			if payments[i].IsZero() {
+				continue
+			}
+			balances[i], _ = balances[i].Sub(payments[i])
+			principal, _ := payments[i].Sub(interests[i])
+
+			debtPlan := &plan.Debts[i]
+			debtPlan.Interest, _ = debtPlan.Interest.Add(interests[i])
+			debtPlan.Schedule = append(debtPlan.Schedule, Payment{
+				Month:     month,
+				Payment:   payments[i],
+				Interest:  interests[i],
+				Principal: principal,
+				Balance:   balances[i],
+			})
+			if balances[i].IsZero() {
+				debtPlan.PayoffDate = month
+			}
+			plan.TotalInterest, _ = plan.TotalInterest.Add(interests[i])
+			plan.TotalPaid, _ = plan.TotalPaid.Add(payments[i])
+		}
+
+		next := total(balances)
+		if cmp, _ := next.Cmp(remaining); cmp >= 0 {
+			return Plan{}, ErrNeverPaidOff
+		}
+		remaining = next
+		plan.PayoffDate = month
+	}
+	return plan, nil
+}
+
+// Compare simulates the snowball and avalanche strategies, and the custom one when an order
+// is given, so they can be compared side by side.
+func Compare(debts []Debt, extra money.Money, order []string, start time.Time) ([]Plan, error) {
+	strategies := []Strategy{StrategySnowball, StrategyAvalanche}
+	if len(order) > 0 {
+		strategies = append(strategies, StrategyCustom)
+	}
+
+	plans := make([]Plan, 0, len(strategies))
+	for _, strategy := range strategies {
+		plan, err := Simulate(debts, extra, strategy, order, start)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// validate checks the debts and the extra payment before simulating.
+func validate(debts []Debt, extra money.Money) error {
+	if len(debts) == 0 {
+		return ErrNoDebts
+	}
+	if extra.IsNegative() {
+		return ErrNegativeExtra
+	}
+
+	seen := make(map[string]bool)
+	for _, d := range debts {
+		if seen[d.ID] {
+			return fmt.Errorf("%w: %q", ErrDuplicateDebtID, d.ID)
+		}
+		seen[d.ID] = true
+
+		if d.Balance.Currency() != extra.Currency() || d.MinimumPayment.Currency() != extra.Currency() {
+			return fmt.Errorf("%w: debt %q", ErrMixedCurrencies, d.ID)
+		}
+		if d.Balance.IsNegative() || d.MinimumPayment.IsNegative() {
+			return fmt.Errorf("%w: %q must not have a negative balance or minimum payment", ErrInvalidDebt, d.ID)
+		}
+		if d.APR == nil || d.APR.Sign() < 0 {
+			return fmt.Errorf("%w: %q needs a non-negative APR", ErrInvalidDebt, d.ID)
+		}
+	}
+	return nil
+}
+
+// sortDebts returns the debts in the order the strategy pays them off.
+func sortDebts(debts []Debt, strategy Strategy, order []string) ([]Debt, error) {
+	sorted := append([]Debt(nil), debts...)
+
+	switch strategy {
+	case StrategySnowball:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if cmp, _ := sorted[i].Balance.Cmp(sorted[j].Balance); cmp != 0 {
+				return cmp < 0
+			}
+			return sorted[i].APR.Cmp(sorted[j].APR) > 0
+		})
+	case StrategyAvalanche:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if cmp := sorted[i].APR.Cmp(sorted[j].APR); cmp != 0 {
+				return cmp > 0
+			}
+			cmp, _ := sorted[i].Balance.Cmp(sorted[j].Balance)
+			return cmp < 0
+		})
+	case StrategyCustom:
+		if len(order) != len(debts) {
+			return nil, ErrInvalidOrder
+		}
+		position := make(map[string]int, len(order))
+		for i, id := range order {
+			position[id] = i
+		}
+		for _, d := range debts {
+			if _, ok := position[d.ID]; !ok {
+				return nil, fmt.Errorf("%w: %q is missing", ErrInvalidOrder, d.ID)
+			}
+		}
+		if len(position) != len(order) {
+			return nil, ErrInvalidOrder
+		}
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return position[sorted[i].ID] < position[sorted[j].ID]
+		})
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, strategy)
+	}
+	return sorted, nil
+}
+
+// total sums the balances, which share a currency.
+func total(balances []money.Money) money.Money {
+	sum := balances[0]
+	for _, balance := range balances[1:] {
+		sum, _ = sum.Add(balance)
+	}
+	return sum
+}
+
+// minMoney returns the smaller of two amounts in the same currency.
+func minMoney(a, b money.Money) money.Money {
+	if cmp, _ := a.Cmp(b); cmp <= 0 {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,133 @@
+package debt
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var start = time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+func usd(amount int64) money.Money {
+	return money.MustNew(amount, "USD")
+}
+
+func month(n int) time.Time {
+	return time.Date(2026, time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func testDebts(aprA, aprB int64) []Debt {
+	return []Debt{
+		{ID: "loan", Name: "Car loan", Balance: usd(100000), APR: big.NewRat(aprA, 1), MinimumPayment: usd(10000)},
+		{ID: "card", Name: "Credit card", Balance: usd(30000), APR: big.NewRat(aprB, 1), MinimumPayment: usd(5000)},
+	}
+}
+
+func TestSimulateSnowball(t *testing.T) {
+	// Without interest the monthly budget of 200.00 pays the card off in March
+	// and the rolled-over payments clear the loan in July.
+	plan, err := Simulate(testDebts(0, 0), usd(5000), StrategySnowball, nil, start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !slices.Equal(plan.Order, []string{"card", "loan"}) {
+		t.Errorf("Expected the smallest balance first, got %v", plan.Order)
+	}
+	if plan.Months != 7 || !plan.PayoffDate.Equal(month(7)) {
+		t.Errorf("Expected payoff in July after 7 months, got %d months, %v", plan.Months, plan.PayoffDate)
+	}
+	if !plan.TotalInterest.IsZero() || plan.TotalPaid.Amount() != 130000 {
+		t.Errorf("Unexpected totals %s interest, %s paid", plan.TotalInterest, plan.TotalPaid)
+	}
+
+	card, loan := plan.Debts[0], plan.Debts[1]
+	if !card.PayoffDate.Equal(month(3)) || len(card.Schedule) != 3 || card.Schedule[0].Payment.Amount() != 10000 {
+		t.Errorf("Unexpected card plan %+v", card)
+	}
+	if last := loan.Schedule[len(loan.Schedule)-1]; last.Payment.Amount() != 10000 || !last.Balance.IsZero() {
+		t.Errorf("Expected a final loan payment of 100.00, got %+v", last)
+	}
+	if loan.Schedule[3].Payment.Amount() != 20000 {
+		t.Errorf("Expected the freed card payment to roll over in April, got %s", loan.Schedule[3].Payment)
+	}
+}
+
+func TestSimulateInterest(t *testing.T) {
+	plan, err := Simulate(testDebts(20, 10), usd(5000), StrategyAvalanche, nil, start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !slices.Equal(plan.Order, []string{"loan", "card"}) {
+		t.Errorf("Expected the highest APR first, got %v", plan.Order)
+	}
+	// 1000.00 at 20% / 12 is 16.666..., rounded to 16.67.
+	first := plan.Debts[0].Schedule[0]
+	if first.Interest.Amount() != 1667 || first.Principal.Amount() != 15000-1667 {
+		t.Errorf("Unexpected first loan payment %+v", first)
+	}
+
+	paid, _ := plan.TotalPaid.Sub(plan.TotalInterest)
+	if paid.Amount() != 130000 {
+		t.Errorf("Expected principal payments to equal the starting balances, got %s", paid)
+	}
+
+	snowball, err := Simulate(testDebts(20, 10), usd(5000), StrategySnowball, nil, start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cmp, _ := plan.TotalInterest.Cmp(snowball.TotalInterest); cmp > 0 {
+		t.Errorf("Expected avalanche (%s) to cost no more interest than snowball (%s)", plan.TotalInterest, snowball.TotalInterest)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	plans, err := Compare(testDebts(20, 10), usd(5000), []string{"loan", "card"}, start)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(plans) != 3 || plans[0].Strategy != StrategySnowball || plans[2].Strategy != StrategyCustom {
+		t.Fatalf("Unexpected plans %+v", plans)
+	}
+	if !plans[2].TotalInterest.Equal(plans[1].TotalInterest) {
+		t.Errorf("Expected the custom order matching avalanche to cost the same interest")
+	}
+
+	if plans, _ := Compare(testDebts(20, 10), usd(5000), nil, start); len(plans) != 2 {
+		t.Errorf("Expected no custom plan without an order, got %d plans", len(plans))
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		debts    []Debt
+		extra    money.Money
+		strategy Strategy
+		order    []string
+		expected error
+	}{
+		{"no debts", nil, usd(0), StrategySnowball, nil, ErrNoDebts},
+		{"unknown strategy", testDebts(0, 0), usd(0), "yolo", nil, ErrUnknownStrategy},
+		{"incomplete order", testDebts(0, 0), usd(0), StrategyCustom, []string{"loan"}, ErrInvalidOrder},
+		{"repeated order", testDebts(0, 0), usd(0), StrategyCustom, []string{"loan", "loan"}, ErrInvalidOrder},
+		{"mixed currencies", testDebts(0, 0), money.MustNew(0, "EUR"), StrategySnowball, nil, ErrMixedCurrencies},
+		{"negative extra", testDebts(0, 0), usd(-1), StrategySnowball, nil, ErrNegativeExtra},
+		{
+			"interest outgrows payments",
+			[]Debt{{ID: "card", Balance: usd(100000), APR: big.NewRat(24, 1), MinimumPayment: usd(1000)}},
+			usd(0), StrategySnowball, nil, ErrNeverPaidOff,
+		},
+	}
+
+	for _, tt := range tests {
+		if _, err := Simulate(tt.debts, tt.extra, tt.strategy, tt.order, start); !errors.Is(err, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, err)
+		}
+	}
+}
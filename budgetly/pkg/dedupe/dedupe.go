@@ -0,0 +1,235 @@
+// Package dedupe detects imported transactions that duplicate transactions already on record.
+package dedupe
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Transaction is the subset of a transaction the matcher needs.
+// Amount is expressed in minor units (e.g. cents).
+type Transaction struct {
+	ID         string
+	ExternalID string // The bank's unique transaction ID, if any.
+	Date       time.Time
+	Amount     int64
+	Payee      string
+	Scheduled  bool // Entered manually from a scheduled transaction.
+}
+
+// Status is the review state of a suspected duplicate.
+type Status string
+
+const (
+	// StatusPending awaits a decision.
+	StatusPending Status = "pending"
+	// StatusAccepted keeps the imported transaction as a new, separate transaction.
+	StatusAccepted Status = "accepted"
+	// StatusMerged folds the imported transaction into the existing one.
+	StatusMerged Status = "merged"
+	// StatusIgnored discards the imported transaction as a duplicate.
+	StatusIgnored Status = "ignored"
+)
+
+var (
+	ErrCandidateNotFound = errors.New("duplicate candidate not found")
+	ErrAlreadyResolved   = errors.New("duplicate candidate already resolved")
+	ErrInvalidAction     = errors.New("invalid review action")
+)
+
+// Candidate is an imported transaction suspected to duplicate an existing one.
+type Candidate struct {
+	Imported Transaction
+	Existing Transaction
+	Score    float64
+	Status   Status
+}
+
+// Options configures how imported and existing transactions are compared.
+type Options struct {
+	// DateWindow is the number of days two dates may differ by and still match.
+	DateWindow int
+	// MinScore is the minimum score for a pair to be treated as a suspected duplicate.
+	MinScore float64
+	// AutoMergeScore is the minimum score for a scheduled entry to be merged without review.
+	AutoMergeScore float64
+}
+
+// DefaultOptions returns the options used when none are configured.
+func DefaultOptions() Options {
+	return Options{
+		DateWindow:     3,
+		MinScore:       0.6,
+		AutoMergeScore: 0.75,
+	}
+}
+
+// Matcher scores imported transactions against existing ones.
+type Matcher struct {
+	options Options
+}
+
+// NewMatcher creates a new matcher with the given options.
+func NewMatcher(options Options) *Matcher {
+	return &Matcher{options: options}
+}
+
+// Score returns how likely imported duplicates existing, between 0 and 1.
+// When both carry a bank transaction ID the IDs decide; otherwise the amounts must be equal
+// and the score combines date proximity and payee similarity.
+func (m *Matcher) Score(imported, existing Transaction) float64 {
+	if imported.ExternalID != "" && existing.ExternalID != "" {
+		if imported.ExternalID == existing.ExternalID {
+			return 1
+		}
+		return 0
+	}
+
+	if imported.Amount != existing.Amount {
+		return 0
+	}
+
+	days := daysBetween(imported.Date, existing.Date)
+	if days > m.options.DateWindow {
+		return 0
+	}
+	dateScore := 1 - float64(days)/float64(m.options.DateWindow+1)
+
+	return 0.5*dateScore + 0.5*PayeeSimilarity(imported.Payee, existing.Payee)
+}
+
+// Result is the outcome of matching a batch of imported transactions.
+type Result struct {
+	// New holds imported transactions that matched nothing and can be created.
+	New []Transaction
+	// Resolved holds matches decided without review: re-imports of the same bank
+	// transaction (ignored) and manually entered scheduled transactions (merged).
+	Resolved []Candidate
+	// Review holds suspected duplicates awaiting a decision.
+	Review []Candidate
+}
+
+// Match pairs every imported transaction with at most one existing transaction,
+// best scores first, and sorts the pairs into new, resolved and review buckets.
+func (m *Matcher) Match(imported, existing []Transaction) Result {
+	type scoredPair struct {
+		imported, existing int
+		score              float64
+	}
+
+	var pairs []scoredPair
+	for i := range imported {
+		for j := range existing {
+			if score := m.Score(imported[i], existing[j]); score >= m.options.MinScore {
+				pairs = append(pairs, scoredPair{imported: i, existing: j, score: score})
+			}
+		}
+	}
+
+	// Prefer the strongest pairs, keeping the input order stable for ties.
+	sort.SliceStable(pairs, func(a, b int) bool {
+		return pairs[a].score > pairs[b].score
+	})
+
+	var result Result
+	usedImported := make(map[int]bool)
+	usedExisting := make(map[int]bool)
+	for _, pair := range pairs {
+		if usedImported[pair.imported] || usedExisting[pair.existing] {
+			continue
+		}
+		usedImported[pair.imported], usedExisting[pair.existing] = true, true
+
+		candidate := Candidate{
+			Imported: imported[pair.imported],
+			Existing: existing[pair.existing],
+			Score:    pair.score,
+		}
+
+		switch {
+		case candidate.Imported.ExternalID != "" && candidate.Imported.ExternalID == candidate.Existing.ExternalID:
+			candidate.Status = StatusIgnored
+			result.Resolved = append(result.Resolved, candidate)
+		case candidate.Existing.Scheduled && candidate.Score >= m.options.AutoMergeScore:
+			candidate.Status = StatusMerged
+			result.Resolved = append(result.Resolved, candidate)
+		default:
+			candidate.Status = StatusPending
+			result.Review = append(result.Review, candidate)
+		}
+	}
+
+	for i, tx := range imported {
+		if !usedImported[i] {
+			result.New = append(result.New, tx)
+		}
+	}
+	return result
+}
+
+// Merge combines an imported transaction into the existing one it duplicates.
+// The existing transaction keeps its identity and user-entered payee, and takes
+// the bank's transaction ID and booking date from the import.
+func Merge(existing, imported Transaction) Transaction {
+	merged := existing
+	merged.ExternalID = imported.ExternalID
+	merged.Date = imported.Date
+	merged.Scheduled = false
+	if merged.Payee == "" {
+		merged.Payee = imported.Payee
+	}
+	return merged
+}
+
+// PayeeSimilarity returns the Sørensen–Dice coefficient of the character bigrams
+// of both payees after normalization, between 0 and 1.
+func PayeeSimilarity(a, b string) float64 {
+	x, y := []rune(normalizePayee(a)), []rune(normalizePayee(b))
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+	if string(x) == string(y) {
+		return 1
+	}
+	if len(x) < 2 || len(y) < 2 {
+		return 0
+	}
+
+	bigrams := make(map[string]int)
+	for i := 0; i < len(x)-1; i++ {
+		bigrams[string(x[i:i+2])]++
+	}
+
+	shared := 0
+	for i := 0; i < len(y)-1; i++ {
+		if bigram := string(y[i : i+2]); bigrams[bigram] > 0 {
+			bigrams[bigram]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(x)-1+len(y)-1)
+}
+
+// normalizePayee lowercases the payee and keeps only letters and digits.
+func normalizePayee(payee string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, payee)
+}
+
+// daysBetween returns the absolute number of calendar days between two dates.
+func daysBetween(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(a.Sub(b).Hours() / 24)
+	if days < 0 {
+		return -days
+	}
+	return days
+}
@@ -0,0 +1,100 @@
+package dedupe
+
+import (
+	"testing"
+	"time"
+)
+
+func date(day int) time.Time {
+	return time.Date(2024, time.March, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestScore(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+
+	sameID := matcher.Score(
+		Transaction{ExternalID: "TX-1", Amount: 100, Date: date(1)},
+		Transaction{ExternalID: "TX-1", Amount: 999, Date: date(20)},
+	)
+	if sameID != 1 {
+		t.Errorf("Expected matching bank IDs to score 1, got %v", sameID)
+	}
+
+	differentID := matcher.Score(
+		Transaction{ExternalID: "TX-1", Amount: 100, Date: date(1), Payee: "Cafe"},
+		Transaction{ExternalID: "TX-2", Amount: 100, Date: date(1), Payee: "Cafe"},
+	)
+	if differentID != 0 {
+		t.Errorf("Expected different bank IDs to score 0, got %v", differentID)
+	}
+
+	differentAmount := matcher.Score(
+		Transaction{Amount: 100, Date: date(1), Payee: "Cafe"},
+		Transaction{Amount: 101, Date: date(1), Payee: "Cafe"},
+	)
+	if differentAmount != 0 {
+		t.Errorf("Expected different amounts to score 0, got %v", differentAmount)
+	}
+
+	outsideWindow := matcher.Score(
+		Transaction{Amount: 100, Date: date(1), Payee: "Cafe"},
+		Transaction{Amount: 100, Date: date(10), Payee: "Cafe"},
+	)
+	if outsideWindow != 0 {
+		t.Errorf("Expected dates outside the window to score 0, got %v", outsideWindow)
+	}
+
+	close := matcher.Score(
+		Transaction{Amount: 100, Date: date(2), Payee: "AMAZON.COM*AB12"},
+		Transaction{Amount: 100, Date: date(1), Payee: "Amazon.com"},
+	)
+	if close < DefaultOptions().MinScore {
+		t.Errorf("Expected fuzzy match to reach the minimum score, got %v", close)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	matcher := NewMatcher(DefaultOptions())
+
+	existing := []Transaction{
+		{ID: "e1", ExternalID: "BANK-1", Amount: -500, Date: date(1), Payee: "Bakery"},
+		{ID: "e2", Amount: -12000, Date: date(3), Payee: "Landlord", Scheduled: true},
+		{ID: "e3", Amount: -2599, Date: date(5), Payee: "Book Store"},
+	}
+	imported := []Transaction{
+		{ID: "i1", ExternalID: "BANK-1", Amount: -500, Date: date(1), Payee: "BAKERY 123"},
+		{ID: "i2", ExternalID: "BANK-2", Amount: -12000, Date: date(4), Payee: "Landlord Rent"},
+		{ID: "i3", ExternalID: "BANK-3", Amount: -2599, Date: date(6), Payee: "BOOK STORE BERLIN"},
+		{ID: "i4", ExternalID: "BANK-4", Amount: -750, Date: date(6), Payee: "Cinema"},
+	}
+
+	result := matcher.Match(imported, existing)
+
+	if len(result.New) != 1 || result.New[0].ID != "i4" {
+		t.Errorf("Expected only i4 to be new, got %+v", result.New)
+	}
+
+	if len(result.Resolved) != 2 {
+		t.Fatalf("Expected 2 resolved candidates, got %+v", result.Resolved)
+	}
+	if result.Resolved[0].Imported.ID != "i1" || result.Resolved[0].Status != StatusIgnored {
+		t.Errorf("Expected re-imported i1 to be ignored, got %+v", result.Resolved[0])
+	}
+	if result.Resolved[1].Imported.ID != "i2" || result.Resolved[1].Status != StatusMerged {
+		t.Errorf("Expected scheduled e2 to be merged with i2, got %+v", result.Resolved[1])
+	}
+
+	if len(result.Review) != 1 || result.Review[0].Existing.ID != "e3" || result.Review[0].Status != StatusPending {
+		t.Errorf("Expected i3/e3 to await review, got %+v", result.Review)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	existing := Transaction{ID: "e1", Amount: -100, Date: date(1), Payee: "Gym", Scheduled: true}
+	imported := Transaction{ID: "i1", ExternalID: "BANK-9", Amount: -100, Date: date(2), Payee: "GYM CLUB 42"}
+
+	merged := Merge(existing, imported)
+	if merged.ID != "e1" || merged.Payee != "Gym" || merged.ExternalID != "BANK-9" || !merged.Date.Equal(date(2)) || merged.Scheduled {
+		t.Errorf("Unexpected merge result %+v", merged)
+	}
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// writeCSV writes the report's single sheet as CSV with a header row, flushing as it goes.
+// Currency cells are written as plain decimals so spreadsheets read them as numbers.
+func writeCSV(w io.Writer, report Report) error {
+	if len(report.Sheets) != 1 {
+		return fmt.Errorf("%w: csv holds exactly one sheet, got %d", ErrInvalidReport, len(report.Sheets))
+	}
+	sheet := report.Sheets[0]
+
+	writer := csv.NewWriter(w)
+	header := make([]string, len(sheet.Columns))
+	for i, column := range sheet.Columns {
+		header[i] = column.Title
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(sheet.Columns))
+	for n := 0; ; n++ {
+		row, err := sheet.Rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for i := range record {
+			record[i] = ""
+			if i < len(row) {
+				if record[i], err = text(row[i]); err != nil {
+					return err
+				}
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+		// Flush periodically so large exports reach the client while they are produced.
+		if n%500 == 499 {
+			writer.Flush()
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,199 @@
+// Package export renders tabular reports as CSV, XLSX or PDF files, streaming rows as
+// they are produced.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown export format")
+	ErrInvalidReport = errors.New("invalid report")
+	ErrInvalidCell   = errors.New("invalid cell")
+	ErrInvalidChart  = errors.New("invalid chart")
+)
+
+// Format is an export file format.
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+	PDF  Format = "pdf"
+)
+
+// formats maps each format to its media type.
+var formats = map[Format]string{
+	CSV:  "text/csv; charset=utf-8",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	PDF:  "application/pdf",
+}
+
+// ParseFormat parses a format name such as "csv".
+func ParseFormat(name string) (Format, error) {
+	format := Format(name)
+	if _, ok := formats[format]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, name)
+	}
+	return format, nil
+}
+
+// FormatFor returns the format served under a media type such as "application/pdf".
+func FormatFor(mediaType string) (Format, bool) {
+	for format, contentType := range formats {
+		if base, _, _ := strings.Cut(contentType, ";"); base == mediaType {
+			return format, true
+		}
+	}
+	return "", false
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	return formats[f]
+}
+
+// Extension returns the file extension of the format, including the dot.
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Kind is how a column's cells are formatted.
+type Kind int
+
+const (
+	Text Kind = iota
+	Number
+	Currency
+	Date
+)
+
+// Column describes one column of a sheet.
+type Column struct {
+	Title string
+	Kind  Kind
+}
+
+// Rows yields the rows of a sheet one at a time. Next returns io.EOF after the last row.
+// Cells may be nil, string, int, int64, float64, money.Money or time.Time.
+type Rows interface {
+	Next() ([]any, error)
+}
+
+// sliceRows is Rows over rows already in memory.
+type sliceRows struct {
+	rows [][]any
+}
+
+// SliceRows returns Rows over rows already in memory.
+func SliceRows(rows [][]any) Rows {
+	return &sliceRows{rows: rows}
+}
+
+func (s *sliceRows) Next() ([]any, error) {
+	if len(s.rows) == 0 {
+		return nil, io.EOF
+	}
+	row := s.rows[0]
+	s.rows = s.rows[1:]
+	return row, nil
+}
+
+// ChartType is the kind of chart drawn for a sheet.
+type ChartType string
+
+const (
+	BarChart  ChartType = "bar"
+	LineChart ChartType = "line"
+)
+
+// Chart plots one column of a sheet against another. Charts are only drawn in PDF exports.
+type Chart struct {
+	Type ChartType
+	// Label and Value are the indexes of the label and value columns.
+	Label int
+	Value int
+}
+
+// Sheet is one table of a report. Each sheet becomes a worksheet in XLSX and a section in
+// PDF; CSV exports hold a single sheet.
+type Sheet struct {
+	Name    string
+	Columns []Column
+	Rows    Rows
+	Chart   *Chart
+}
+
+// Report is a titled set of sheets.
+type Report struct {
+	Title  string
+	Sheets []Sheet
+}
+
+// Write renders the report to w in the given format.
+func Write(w io.Writer, format Format, report Report) error {
+	for _, sheet := range report.Sheets {
+		if chart := sheet.Chart; chart != nil {
+			if chart.Label < 0 || chart.Label >= len(sheet.Columns) || chart.Value < 0 || chart.Value >= len(sheet.Columns) {
+				return fmt.Errorf("%w: column out of range in sheet %q", ErrInvalidChart, sheet.Name)
+			}
+		}
+	}
+
+	switch format {
+	case CSV:
+		return writeCSV(w, report)
+	case XLSX:
+		return writeXLSX(w, report)
+	case PDF:
+		return writePDF(w, report)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// text formats a cell as plain text.
+func text(cell any) (string, error) {
+	switch value := cell.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case int:
+		return strconv.Itoa(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case money.Money:
+		return value.Decimal(), nil
+	case time.Time:
+		return value.Format(time.DateOnly), nil
+	default:
+		return "", fmt.Errorf("%w: unsupported type %T", ErrInvalidCell, cell)
+	}
+}
+
+// number returns the numeric value of a cell, for charts.
+func number(cell any) (float64, bool) {
+	switch value := cell.(type) {
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case float64:
+		return value, true
+	case money.Money:
+		f, _ := value.Rat().Float64()
+		return f, true
+	default:
+		return 0, false
+	}
+}
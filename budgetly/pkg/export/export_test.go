@@ -0,0 +1,175 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func sampleReport(rows int) Report {
+	data := make([][]any, rows)
+	for i := range data {
+		data[i] = []any{
+			time.Date(2026, time.January, 1+i%28, 0, 0, 0, 0, time.UTC),
+			fmt.Sprintf("Payee (%d)", i),
+			money.MustNew(int64(-1000*(i+1)), "EUR"),
+		}
+	}
+	return Report{
+		Title: "Spending",
+		Sheets: []Sheet{{
+			Name: "By payee",
+			Columns: []Column{
+				{Title: "Date", Kind: Date},
+				{Title: "Payee", Kind: Text},
+				{Title: "Amount", Kind: Currency},
+			},
+			Rows:  SliceRows(data),
+			Chart: &Chart{Type: BarChart, Label: 1, Value: 2},
+		}},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("xlsx"); err != nil || format != XLSX {
+		t.Errorf("Expected xlsx, got %q, %v", format, err)
+	}
+	if _, err := ParseFormat("docx"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+	if format, ok := FormatFor("text/csv"); !ok || format != CSV {
+		t.Errorf("Expected csv for text/csv, got %q", format)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, CSV, sampleReport(2)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "Date,Payee,Amount\n2026-01-01,Payee (0),-10.00\n2026-01-02,Payee (1),-20.00\n"
+	if b.String() != want {
+		t.Errorf("Unexpected CSV:\n%s", b.String())
+	}
+
+	report := sampleReport(1)
+	report.Sheets = append(report.Sheets, report.Sheets[0])
+	if err := Write(io.Discard, CSV, report); !errors.Is(err, ErrInvalidReport) {
+		t.Errorf("Expected ErrInvalidReport for two sheets, got %v", err)
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	report := sampleReport(3)
+	second := sampleReport(1).Sheets[0]
+	second.Name = "by payee"
+	report.Sheets = append(report.Sheets, second)
+
+	var b bytes.Buffer
+	if err := Write(&b, XLSX, report); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatalf("Expected a zip archive: %v", err)
+	}
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		content, _ := io.ReadAll(r)
+		r.Close()
+		// Every part must be well-formed XML.
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("Malformed %s: %v", file.Name, err)
+			}
+		}
+		parts[file.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "xl/workbook.xml", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Missing part %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="by payee (2)"`) {
+		t.Errorf("Expected duplicate sheet names to be made unique:\n%s", parts["xl/workbook.xml"])
+	}
+	if !strings.Contains(parts["xl/styles.xml"], `formatCode="#,##0.00 &#34;EUR&#34;;[Red]-#,##0.00 &#34;EUR&#34;"`) {
+		t.Errorf("Expected a EUR currency format:\n%s", parts["xl/styles.xml"])
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(sheet, `<c r="C4" s="3"><v>-30.00</v></c>`) {
+		t.Errorf("Expected a numeric currency cell:\n%s", sheet)
+	}
+	if !strings.Contains(sheet, `<c r="A2" s="2"><v>46023</v></c>`) {
+		t.Errorf("Expected a serial date cell:\n%s", sheet)
+	}
+}
+
+func TestCellRef(t *testing.T) {
+	for column, want := range map[int]string{0: "A1", 25: "Z1", 26: "AA1", 701: "ZZ1", 702: "AAA1"} {
+		if got := cellRef(column, 1); got != want {
+			t.Errorf("cellRef(%d) = %s, want %s", column, got, want)
+		}
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, PDF, sampleReport(150)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pdf := b.String()
+
+	if !strings.HasPrefix(pdf, "%PDF-1.4\n") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatalf("Missing PDF header or trailer")
+	}
+	if !strings.Contains(pdf, `(Payee \(149\)) Tj`) {
+		t.Errorf("Expected escaped row text")
+	}
+	if !strings.Contains(pdf, " re f\n") {
+		t.Errorf("Expected chart bars")
+	}
+	if pages := strings.Count(pdf, "/Type /Page "); pages < 3 {
+		t.Errorf("Expected the table to span several pages, got %d", pages)
+	}
+
+	// Every xref entry must point at the start of its object.
+	start, err := strconv.Atoi(regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(pdf)[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[start:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := fmt.Sprintf("%d 0 obj", i+1); !strings.HasPrefix(pdf[offset:], want) {
+			t.Errorf("xref entry %d points at %q", i+1, pdf[offset:offset+10])
+		}
+	}
+}
+
+func TestWriteInvalidChart(t *testing.T) {
+	report := sampleReport(1)
+	report.Sheets[0].Chart.Value = 5
+	if err := Write(io.Discard, PDF, report); !errors.Is(err, ErrInvalidChart) {
+		t.Errorf("Expected ErrInvalidChart, got %v", err)
+	}
+}
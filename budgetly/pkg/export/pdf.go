@@ -0,0 +1,332 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Page geometry in points, for A4 portrait.
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	pageMargin   = 40.0
+	contentWidth = pageWidth - 2*pageMargin
+	rowHeight    = 14.0
+	fontSize     = 9.0
+	cellPadding  = 3.0
+	chartHeight  = 200.0
+	// maxChartPoints caps how many points are kept for a chart; later rows are not plotted.
+	maxChartPoints = 120
+)
+
+// Object numbers reserved up front; pages take the numbers after them.
+const (
+	objCatalog = iota + 1
+	objPages
+	objFont
+	objBoldFont
+	objFirstFree
+)
+
+// pdfWriter writes a PDF one page at a time, remembering only object offsets.
+type pdfWriter struct {
+	w       io.Writer
+	offset  int64
+	offsets map[int]int64
+	next    int
+	pages   []int
+	page    bytes.Buffer
+	y       float64
+}
+
+// writePDF renders every sheet as a table, followed by its chart if it has one.
+func writePDF(w io.Writer, report Report) error {
+	if len(report.Sheets) == 0 {
+		return fmt.Errorf("%w: document has no sheets", ErrInvalidReport)
+	}
+
+	p := &pdfWriter{w: w, offsets: map[int]int64{}, next: objFirstFree}
+	if err := p.write("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"); err != nil {
+		return err
+	}
+	p.y = pageHeight - pageMargin
+
+	if report.Title != "" {
+		p.text(pageMargin, p.y-16, 16, true, report.Title)
+		p.y -= 32
+	}
+	for i, sheet := range report.Sheets {
+		if i > 0 {
+			p.y -= rowHeight
+		}
+		if err := p.sheet(sheet); err != nil {
+			return err
+		}
+	}
+	if err := p.flushPage(); err != nil {
+		return err
+	}
+	return p.finish()
+}
+
+// sheet draws a sheet's heading, table and chart, breaking pages as needed.
+func (p *pdfWriter) sheet(sheet Sheet) error {
+	if err := p.reserve(3 * rowHeight); err != nil {
+		return err
+	}
+	if sheet.Name != "" {
+		p.text(pageMargin, p.y-12, 12, true, sheet.Name)
+		p.y -= 2 * rowHeight
+	}
+
+	width := contentWidth / float64(max(1, len(sheet.Columns)))
+	header := func() {
+		for i, column := range sheet.Columns {
+			p.cell(i, width, column.Kind, column.Title, true)
+		}
+		p.line(pageMargin, p.y-rowHeight, pageMargin+contentWidth, p.y-rowHeight, 0.5)
+		p.y -= rowHeight
+	}
+	header()
+
+	var labels []string
+	var values []float64
+	for {
+		row, err := sheet.Rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if p.y-rowHeight < pageMargin {
+			if err := p.flushPage(); err != nil {
+				return err
+			}
+			header()
+		}
+		for i := range sheet.Columns {
+			if i >= len(row) {
+				break
+			}
+			s, err := text(row[i])
+			if err != nil {
+				return err
+			}
+			p.cell(i, width, sheet.Columns[i].Kind, s, false)
+		}
+		p.y -= rowHeight
+
+		if chart := sheet.Chart; chart != nil && len(values) < maxChartPoints && chart.Value < len(row) && chart.Label < len(row) {
+			if value, ok := number(row[chart.Value]); ok {
+				label, _ := text(row[chart.Label])
+				labels = append(labels, label)
+				values = append(values, value)
+			}
+		}
+	}
+
+	if sheet.Chart != nil && len(values) > 0 {
+		if err := p.reserve(chartHeight + 3*rowHeight); err != nil {
+			return err
+		}
+		p.y -= rowHeight
+		p.chart(sheet.Chart.Type, labels, values)
+	}
+	return nil
+}
+
+// cell draws text in a table column, right-aligning numeric kinds and truncating to fit.
+func (p *pdfWriter) cell(column int, width float64, kind Kind, s string, bold bool) {
+	s = fit(s, width-2*cellPadding, fontSize)
+	x := pageMargin + float64(column)*width + cellPadding
+	if kind == Number || kind == Currency {
+		x = pageMargin + float64(column+1)*width - cellPadding - textWidth(s, fontSize)
+	}
+	p.text(x, p.y-rowHeight+4, fontSize, bold, s)
+}
+
+// chart draws a bar or line chart of the values below the current position.
+func (p *pdfWriter) chart(chartType ChartType, labels []string, values []float64) {
+	top, bottom := p.y, p.y-chartHeight
+	left, right := pageMargin+50, pageMargin+contentWidth
+
+	high, low := 0.0, 0.0
+	for _, v := range values {
+		high, low = math.Max(high, v), math.Min(low, v)
+	}
+	if high == low {
+		high = low + 1
+	}
+	scale := (top - bottom) / (high - low)
+	zero := bottom - low*scale
+
+	p.line(left, bottom, left, top, 0.5)
+	p.line(left, zero, right, zero, 0.5)
+	p.text(pageMargin, top-fontSize, fontSize, false, fit(num(high), 45, fontSize))
+	p.text(pageMargin, bottom, fontSize, false, fit(num(low), 45, fontSize))
+
+	step := (right - left) / float64(len(values))
+	switch chartType {
+	case LineChart:
+		for i := 1; i < len(values); i++ {
+			x0, x1 := left+step*(float64(i)-0.5), left+step*(float64(i)+0.5)
+			p.line(x0, zero+values[i-1]*scale, x1, zero+values[i]*scale, 1.5)
+		}
+	default:
+		p.page.WriteString("0.27 0.51 0.71 rg\n")
+		for i, v := range values {
+			y, h := zero, v*scale
+			if h < 0 {
+				y, h = zero+h, -h
+			}
+			fmt.Fprintf(&p.page, "%s %s %s %s re f\n", num(left+step*float64(i)+step*0.15), num(y), num(step*0.7), num(h))
+		}
+		p.page.WriteString("0 g\n")
+	}
+
+	// Label as many points as fit without overlapping.
+	every := int(math.Ceil(60 / step))
+	for i := 0; i < len(labels); i += max(1, every) {
+		label := fit(labels[i], math.Max(step*float64(max(1, every))-4, 10), 7)
+		p.text(left+step*float64(i)+2, bottom-rowHeight+4, 7, false, label)
+	}
+	p.y = bottom - rowHeight
+}
+
+// reserve starts a new page unless height points remain on the current one.
+func (p *pdfWriter) reserve(height float64) error {
+	if p.y-height >= pageMargin {
+		return nil
+	}
+	return p.flushPage()
+}
+
+func (p *pdfWriter) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.page, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, num(size), num(x), num(y), escape(s))
+}
+
+func (p *pdfWriter) line(x0, y0, x1, y1, width float64) {
+	fmt.Fprintf(&p.page, "%s w %s %s m %s %s l S\n", num(width), num(x0), num(y0), num(x1), num(y1))
+}
+
+// flushPage writes the current page's content and page objects and starts a fresh page.
+func (p *pdfWriter) flushPage() error {
+	content, page := p.next, p.next+1
+	p.next += 2
+
+	stream := fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", p.page.Len(), p.page.String())
+	if err := p.object(content, stream); err != nil {
+		return err
+	}
+	if err := p.object(page, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Contents %d 0 R /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> >>",
+		objPages, num(pageWidth), num(pageHeight), content, objFont, objBoldFont,
+	)); err != nil {
+		return err
+	}
+
+	p.pages = append(p.pages, page)
+	p.page.Reset()
+	p.y = pageHeight - pageMargin
+	return nil
+}
+
+// finish writes the page tree, fonts, catalog and cross-reference table.
+func (p *pdfWriter) finish() error {
+	kids := make([]string, len(p.pages))
+	for i, page := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+	objects := []struct {
+		id   int
+		body string
+	}{
+		{objPages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages))},
+		{objFont, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"},
+		{objBoldFont, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>"},
+		{objCatalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", objPages)},
+	}
+	for _, object := range objects {
+		if err := p.object(object.id, object.body); err != nil {
+			return err
+		}
+	}
+
+	xref := p.offset
+	var b strings.Builder
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", p.next)
+	for id := 1; id < p.next; id++ {
+		fmt.Fprintf(&b, "%010d 00000 n \n", p.offsets[id])
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", p.next, objCatalog, xref)
+	return p.write(b.String())
+}
+
+func (p *pdfWriter) object(id int, body string) error {
+	p.offsets[id] = p.offset
+	return p.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", id, body))
+}
+
+func (p *pdfWriter) write(s string) error {
+	n, err := io.WriteString(p.w, s)
+	p.offset += int64(n)
+	return err
+}
+
+// escape encodes a string as a WinAnsi PDF literal, replacing characters the standard
+// fonts cannot show with '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteString(`\200`)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, `\%03o`, r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// textWidth estimates the width of a string in Helvetica, which averages just over half
+// an em per character.
+func textWidth(s string, size float64) float64 {
+	return float64(utf8.RuneCountInString(s)) * size * 0.55
+}
+
+// fit truncates s with an ellipsis so that it fits in width.
+func fit(s string, width, size float64) string {
+	if textWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	n := int(width/(size*0.55)) - 3
+	if n <= 0 {
+		return ""
+	}
+	return string(runes[:min(n, len(runes))]) + "..."
+}
+
+// num formats a coordinate with two decimals and no trailing zeros.
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,301 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+// excelEpoch is day zero of the 1900 date system, accounting for Excel's phantom 1900-02-29.
+var excelEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// maxSheetName is the longest worksheet name Excel accepts.
+const maxSheetName = 31
+
+// Style indexes into the cellXfs table of styles.xml. Currency styles follow the fixed ones.
+const (
+	styleDefault = iota
+	styleHeader
+	styleDate
+	styleFixed
+)
+
+// xlsxStyles assigns a number format to every currency seen while streaming the sheets,
+// so styles.xml can be written once they are all known.
+type xlsxStyles struct {
+	currencies []string
+	index      map[string]int
+}
+
+func (s *xlsxStyles) currency(code string) int {
+	if i, ok := s.index[code]; ok {
+		return i
+	}
+	s.index[code] = styleFixed + len(s.currencies)
+	s.currencies = append(s.currencies, code)
+	return s.index[code]
+}
+
+// writeXLSX writes the report as an Office Open XML workbook with one worksheet per sheet.
+// Rows are streamed into the archive and strings are stored inline, so nothing but the
+// set of currencies is held in memory.
+func writeXLSX(w io.Writer, report Report) error {
+	if len(report.Sheets) == 0 {
+		return fmt.Errorf("%w: workbook has no sheets", ErrInvalidReport)
+	}
+
+	archive := zip.NewWriter(w)
+	styles := &xlsxStyles{index: map[string]int{}}
+	names := make([]string, len(report.Sheets))
+
+	if err := writeZipFile(archive, "[Content_Types].xml", contentTypesXML(len(report.Sheets))); err != nil {
+		return err
+	}
+	if err := writeZipFile(archive, "_rels/.rels", rootRelsXML); err != nil {
+		return err
+	}
+
+	for i, sheet := range report.Sheets {
+		names[i] = sheetName(sheet.Name, i, names[:i])
+		part, err := archive.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		if err != nil {
+			return err
+		}
+		if err := writeWorksheet(part, sheet, styles); err != nil {
+			return err
+		}
+	}
+
+	if err := writeZipFile(archive, "xl/workbook.xml", workbookXML(names)); err != nil {
+		return err
+	}
+	if err := writeZipFile(archive, "xl/_rels/workbook.xml.rels", workbookRelsXML(len(names))); err != nil {
+		return err
+	}
+	if err := writeZipFile(archive, "xl/styles.xml", stylesXML(styles.currencies)); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// writeWorksheet streams one sheet's header and rows as worksheet XML.
+func writeWorksheet(w io.Writer, sheet Sheet, styles *xlsxStyles) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	if len(sheet.Columns) > 0 {
+		b.WriteString(`<cols>`)
+		for i, column := range sheet.Columns {
+			width := max(12, len(column.Title)+2)
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString(`</cols>`)
+	}
+	b.WriteString(`<sheetData><row r="1">`)
+	for i, column := range sheet.Columns {
+		writeInlineString(&b, cellRef(i, 1), column.Title, styleHeader)
+	}
+	b.WriteString(`</row>`)
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return err
+	}
+
+	for r := 2; ; r++ {
+		row, err := sheet.Rows.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		b.Reset()
+		fmt.Fprintf(&b, `<row r="%d">`, r)
+		for i, cell := range row {
+			if err := writeCell(&b, cellRef(i, r), cell, styles); err != nil {
+				return err
+			}
+		}
+		b.WriteString(`</row>`)
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, `</sheetData></worksheet>`)
+	return err
+}
+
+// writeCell writes a single cell, storing money as a number formatted in its currency and
+// dates as serial day numbers.
+func writeCell(b *strings.Builder, ref string, cell any, styles *xlsxStyles) error {
+	switch value := cell.(type) {
+	case nil:
+		return nil
+	case string:
+		writeInlineString(b, ref, value, styleDefault)
+	case int, int64, float64:
+		s, err := text(value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(b, `<c r="%s"><v>%s</v></c>`, ref, s)
+	case money.Money:
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styles.currency(value.Currency()), value.Decimal())
+	case time.Time:
+		days := value.Sub(excelEpoch).Hours() / 24
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleDate, strconv.FormatFloat(days, 'f', -1, 64))
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrInvalidCell, cell)
+	}
+	return nil
+}
+
+func writeInlineString(b *strings.Builder, ref, value string, style int) {
+	fmt.Fprintf(b, `<c r="%s" t="inlineStr"`, ref)
+	if style != styleDefault {
+		fmt.Fprintf(b, ` s="%d"`, style)
+	}
+	b.WriteString(`><is><t xml:space="preserve">`)
+	xml.EscapeText(b, []byte(value))
+	b.WriteString(`</t></is></c>`)
+}
+
+// cellRef returns the A1-style reference of a zero-based column and one-based row.
+func cellRef(column, row int) string {
+	var name []byte
+	for column++; column > 0; column = (column - 1) / 26 {
+		name = append([]byte{byte('A' + (column-1)%26)}, name...)
+	}
+	return string(name) + strconv.Itoa(row)
+}
+
+// sheetName makes a name Excel accepts: no reserved characters, at most 31 characters,
+// and unique within the workbook.
+func sheetName(name string, index int, taken []string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", index+1)
+	}
+	if runes := []rune(name); len(runes) > maxSheetName {
+		name = string(runes[:maxSheetName])
+	}
+
+	unique := name
+	for n := 2; containsFold(taken, unique); n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		runes := []rune(name)
+		unique = string(runes[:min(len(runes), maxSheetName-len(suffix))]) + suffix
+	}
+	return unique
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeZipFile(archive *zip.Writer, name, content string) error {
+	part, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, content)
+	return err
+}
+
+const rootRelsXML = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func contentTypesXML(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func workbookXML(names []string) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range names {
+		b.WriteString(`<sheet name="`)
+		xml.EscapeText(&b, []byte(name))
+		fmt.Fprintf(&b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func workbookRelsXML(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// stylesXML declares the header, date and per-currency cell styles. Currency formats show
+// the currency's decimals and code, with negatives in red.
+func stylesXML(currencies []string) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(currencies) > 0 {
+		fmt.Fprintf(&b, `<numFmts count="%d">`, len(currencies))
+		for i, code := range currencies {
+			format := "#,##0"
+			if exponent := money.Exponent(code); exponent > 0 {
+				format += "." + strings.Repeat("0", exponent)
+			}
+			format = fmt.Sprintf(`%s "%s";[Red]-%s "%s"`, format, code, format, code)
+			fmt.Fprintf(&b, `<numFmt numFmtId="%d" formatCode="`, 164+i)
+			xml.EscapeText(&b, []byte(format))
+			b.WriteString(`"/>`)
+		}
+		b.WriteString(`</numFmts>`)
+	}
+	b.WriteString(`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>`)
+	b.WriteString(`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>`)
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&b, `<cellXfs count="%d">`, styleFixed+len(currencies))
+	b.WriteString(`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>`)
+	b.WriteString(`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>`)
+	b.WriteString(`<xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`)
+	for i := range currencies {
+		fmt.Fprintf(&b, `<xf numFmtId="%d" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, 164+i)
+	}
+	b.WriteString(`</cellXfs></styleSheet>`)
+	return b.String()
+}
@@ -0,0 +1,175 @@
+// Package fiscal divides time into budget periods other than calendar months, such as
+// months starting on payday, 4-4-5 retail calendars and bi-weekly pay periods, and
+// guards closed periods against changes.
+package fiscal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+)
+
+var (
+	ErrInvalidDefinition = errors.New("invalid period definition")
+	ErrPeriodLocked      = errors.New("the period is closed")
+)
+
+// maxPeriods caps how many periods a single listing may span.
+const maxPeriods = 1200
+
+// Kind is how a definition divides time into periods.
+type Kind string
+
+const (
+	// Calendar periods are calendar months.
+	Calendar Kind = "calendar"
+	// CustomStart periods are months that begin on StartDay, e.g. the 25th to the 24th.
+	CustomStart Kind = "custom_start"
+	// FourFourFive periods are 4, 4 and 5 week months in 13 week quarters, with years of
+	// 52 weeks beginning on Anchor.
+	FourFourFive Kind = "4-4-5"
+	// Biweekly periods are 14 days long, one of them beginning on Anchor.
+	Biweekly Kind = "biweekly"
+)
+
+// fourFourFive is the number of weeks in each month of a 4-4-5 quarter.
+var fourFourFive = [3]int{4, 4, 5}
+
+// Definition describes how a household's budget periods are laid out.
+type Definition struct {
+	Kind Kind
+	// StartDay is the day of the month custom periods begin on. In months that are too
+	// short they begin on the last day instead.
+	StartDay int
+	// Anchor is the first day of one period: the start of a fiscal year for 4-4-5 and a
+	// payday for bi-weekly periods.
+	Anchor time.Time
+}
+
+// DefaultDefinition returns the definition used when a household has not configured one.
+func DefaultDefinition() Definition {
+	return Definition{Kind: Calendar}
+}
+
+// Validate checks that the definition has the fields its kind needs.
+func (d Definition) Validate() error {
+	switch d.Kind {
+	case Calendar:
+	case CustomStart:
+		if d.StartDay < 1 || d.StartDay > 31 {
+			return fmt.Errorf("%w: start day must be between 1 and 31", ErrInvalidDefinition)
+		}
+	case FourFourFive, Biweekly:
+		if d.Anchor.IsZero() {
+			return fmt.Errorf("%w: %s periods need an anchor date", ErrInvalidDefinition, d.Kind)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidDefinition, d.Kind)
+	}
+	return nil
+}
+
+// PeriodOf returns the period containing t.
+func (d Definition) PeriodOf(t time.Time) report.Period {
+	t = fx.Day(t)
+	switch d.Kind {
+	case CustomStart:
+		start := d.monthStart(t.Year(), t.Month())
+		if t.Before(start) {
+			start = d.monthStart(t.Year(), t.Month()-1)
+		}
+		return report.Period{Start: start, End: d.monthStart(start.Year(), start.Month()+1)}
+	case FourFourFive:
+		anchor := fx.Day(d.Anchor)
+		year := floorDiv(days(anchor, t), 364)
+		yearStart := anchor.AddDate(0, 0, year*364)
+		offset := days(yearStart, t)
+
+		start := yearStart.AddDate(0, 0, offset/91*91)
+		for _, weeks := range fourFourFive[:2] {
+			end := start.AddDate(0, 0, weeks*7)
+			if t.Before(end) {
+				return report.Period{Start: start, End: end}
+			}
+			start = end
+		}
+		return report.Period{Start: start, End: start.AddDate(0, 0, fourFourFive[2]*7)}
+	case Biweekly:
+		anchor := fx.Day(d.Anchor)
+		start := anchor.AddDate(0, 0, floorDiv(days(anchor, t), 14)*14)
+		return report.Period{Start: start, End: start.AddDate(0, 0, 14)}
+	default:
+		start := report.Monthly.Trunc(t)
+		return report.Period{Start: start, End: report.Monthly.Next(start)}
+	}
+}
+
+// Periods returns the periods overlapping from..to (inclusive), in order.
+func (d Definition) Periods(from, to time.Time) ([]report.Period, error) {
+	from, to = fx.Day(from), fx.Day(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: %s is before %s", report.ErrInvalidRange, to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+
+	var periods []report.Period
+	for period := d.PeriodOf(from); !period.Start.After(to); period = d.PeriodOf(period.End) {
+		if len(periods) == maxPeriods {
+			return nil, fmt.Errorf("%w: more than %d periods", report.ErrInvalidRange, maxPeriods)
+		}
+		periods = append(periods, period)
+	}
+	return periods, nil
+}
+
+// monthStart returns the day custom periods begin on in a month, clamped to its last day.
+// Months outside January..December roll over into the neighbouring year.
+func (d Definition) monthStart(year int, month time.Month) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(d.StartDay, last)-1)
+}
+
+// Closed returns the closed period containing date, if any.
+func Closed(date time.Time, closed []report.Period) (report.Period, bool) {
+	date = fx.Day(date)
+	for _, period := range closed {
+		if period.Contains(date) {
+			return period, true
+		}
+	}
+	return report.Period{}, false
+}
+
+// CheckChange returns ErrPeriodLocked when a transaction may not move from the previous
+// date to the current one because either falls in a closed period. previous is zero for
+// a new transaction and current is zero for a deleted one.
+func CheckChange(previous, current time.Time, closed []report.Period) error {
+	for _, date := range []time.Time{previous, current} {
+		if date.IsZero() {
+			continue
+		}
+		if period, ok := Closed(date, closed); ok {
+			return fmt.Errorf("%w: %s falls in %s to %s", ErrPeriodLocked, date.Format(time.DateOnly),
+				period.Start.Format(time.DateOnly), period.End.AddDate(0, 0, -1).Format(time.DateOnly))
+		}
+	}
+	return nil
+}
+
+// days returns the number of whole days from a to b, negative when b is before a.
+func days(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// floorDiv divides rounding towards negative infinity, so days before the anchor fall in
+// the periods before it.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
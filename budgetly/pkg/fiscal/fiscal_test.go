@@ -0,0 +1,110 @@
+package fiscal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/report"
+)
+
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func period(start, end time.Time) report.Period {
+	return report.Period{Start: start, End: end}
+}
+
+func TestPeriodOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition Definition
+		date       time.Time
+		expected   report.Period
+	}{
+		{"calendar", DefaultDefinition(), date(2026, 3, 10), period(date(2026, 3, 1), date(2026, 4, 1))},
+		{"payday before the start day", Definition{Kind: CustomStart, StartDay: 25}, date(2026, 3, 24), period(date(2026, 2, 25), date(2026, 3, 25))},
+		{"payday on the start day", Definition{Kind: CustomStart, StartDay: 25}, date(2026, 3, 25), period(date(2026, 3, 25), date(2026, 4, 25))},
+		{"payday across the year end", Definition{Kind: CustomStart, StartDay: 25}, date(2026, 1, 3), period(date(2025, 12, 25), date(2026, 1, 25))},
+		{"start day clamped in february", Definition{Kind: CustomStart, StartDay: 31}, date(2026, 3, 15), period(date(2026, 2, 28), date(2026, 3, 31))},
+		{"4-4-5 first month", Definition{Kind: FourFourFive, Anchor: date(2026, 2, 1)}, date(2026, 2, 28), period(date(2026, 2, 1), date(2026, 3, 1))},
+		{"4-4-5 five week month", Definition{Kind: FourFourFive, Anchor: date(2026, 2, 1)}, date(2026, 4, 30), period(date(2026, 3, 29), date(2026, 5, 3))},
+		{"4-4-5 second quarter", Definition{Kind: FourFourFive, Anchor: date(2026, 2, 1)}, date(2026, 5, 3), period(date(2026, 5, 3), date(2026, 5, 31))},
+		{"4-4-5 before the anchor", Definition{Kind: FourFourFive, Anchor: date(2026, 2, 1)}, date(2026, 1, 31), period(date(2025, 12, 28), date(2026, 2, 1))},
+		{"biweekly", Definition{Kind: Biweekly, Anchor: date(2026, 1, 9)}, date(2026, 3, 10), period(date(2026, 3, 6), date(2026, 3, 20))},
+		{"biweekly before the anchor", Definition{Kind: Biweekly, Anchor: date(2026, 1, 9)}, date(2026, 1, 8), period(date(2025, 12, 26), date(2026, 1, 9))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.definition.PeriodOf(tt.date); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	periods, err := Definition{Kind: CustomStart, StartDay: 25}.Periods(date(2026, 1, 1), date(2026, 3, 1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []report.Period{
+		period(date(2025, 12, 25), date(2026, 1, 25)),
+		period(date(2026, 1, 25), date(2026, 2, 25)),
+		period(date(2026, 2, 25), date(2026, 3, 25)),
+	}
+	if len(periods) != len(expected) {
+		t.Fatalf("Expected %d periods, got %v", len(expected), periods)
+	}
+	for i := range expected {
+		if periods[i] != expected[i] {
+			t.Errorf("Period %d: expected %v, got %v", i, expected[i], periods[i])
+		}
+	}
+
+	// A 4-4-5 year is exactly 52 weeks.
+	year, err := Definition{Kind: FourFourFive, Anchor: date(2026, 2, 1)}.Periods(date(2026, 2, 1), date(2027, 1, 30))
+	if err != nil || len(year) != 12 || !year[11].End.Equal(date(2027, 1, 31)) {
+		t.Errorf("Expected 12 periods ending on 31 January 2027, got %v, %v", year, err)
+	}
+
+	if _, err := DefaultDefinition().Periods(date(2026, 3, 1), date(2026, 1, 1)); !errors.Is(err, report.ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	invalid := []Definition{
+		{Kind: "weekly"},
+		{Kind: CustomStart},
+		{Kind: CustomStart, StartDay: 32},
+		{Kind: Biweekly},
+	}
+	for _, definition := range invalid {
+		if err := definition.Validate(); !errors.Is(err, ErrInvalidDefinition) {
+			t.Errorf("Expected ErrInvalidDefinition for %+v, got %v", definition, err)
+		}
+	}
+	if err := (Definition{Kind: FourFourFive, Anchor: date(2026, 2, 1)}).Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestCheckChange(t *testing.T) {
+	closed := []report.Period{period(date(2026, 2, 25), date(2026, 3, 25))}
+
+	if err := CheckChange(time.Time{}, date(2026, 3, 24), closed); !errors.Is(err, ErrPeriodLocked) {
+		t.Errorf("Expected creating inside a closed period to fail, got %v", err)
+	}
+	if err := CheckChange(date(2026, 3, 1), date(2026, 4, 1), closed); !errors.Is(err, ErrPeriodLocked) {
+		t.Errorf("Expected moving out of a closed period to fail, got %v", err)
+	}
+	if err := CheckChange(date(2026, 3, 1), time.Time{}, closed); !errors.Is(err, ErrPeriodLocked) {
+		t.Errorf("Expected deleting from a closed period to fail, got %v", err)
+	}
+	if err := CheckChange(date(2026, 3, 25), date(2026, 4, 2), closed); err != nil {
+		t.Errorf("Unexpected error for an open period: %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+// Package forecast projects daily account balances from scheduled transactions,
+// recurring bills and average discretionary spending.
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/recurring"
+)
+
+// maxDays limits how far ahead a forecast may look.
+const maxDays = 3 * 366
+
+var (
+	ErrInvalidDays     = fmt.Errorf("forecast must cover between 1 and %d days", maxDays)
+	ErrUnknownAccount  = errors.New("forecast item refers to an unknown account")
+	ErrInvalidSpending = errors.New("invalid spending window")
+	ErrUnknownCadence  = errors.New("unknown forecast item frequency")
+)
+
+// Account is an account to forecast with its current balance. Thresholds are balances the
+// user wants to be warned about crossing, e.g. zero or a minimum balance.
+type Account struct {
+	ID         string        `json:"id"`
+	Balance    money.Money   `json:"balance"`
+	Thresholds []money.Money `json:"thresholds"`
+}
+
+// Item is an expected transaction: a scheduled transaction, a detected recurring bill, or
+// a one-off when Frequency is empty.
+type Item struct {
+	AccountID   string            `json:"account_id"`
+	Description string            `json:"description"`
+	Amount      money.Money       `json:"amount"`
+	NextDate    time.Time         `json:"next_date"`
+	Frequency   recurring.Cadence `json:"frequency,omitempty"`
+}
+
+// FromSubscription creates a forecast item from a detected recurring bill.
+func FromSubscription(accountID string, subscription recurring.Subscription) Item {
+	return Item{
+		AccountID:   accountID,
+		Description: subscription.Payee,
+		Amount:      subscription.Amount,
+		NextDate:    subscription.NextDate,
+		Frequency:   subscription.Cadence,
+	}
+}
+
+// Spending is a past discretionary transaction used to estimate future day-to-day spending.
+type Spending struct {
+	AccountID string
+	Category  string
+	Date      time.Time
+	Amount    money.Money
+}
+
+// Discretionary is the average daily spending of one category from one account.
+type Discretionary struct {
+	AccountID string   `json:"account_id"`
+	Category  string   `json:"category"`
+	Daily     *big.Rat `json:"-"`
+	Currency  string   `json:"currency"`
+}
+
+// AverageSpending returns the average daily spending per account and category over the
+// days from from to to (exclusive). Only outflows count; refunds do not offset spending.
+func AverageSpending(spending []Spending, from, to time.Time) ([]Discretionary, error) {
+	days := int64(to.Sub(from).Hours() / 24)
+	if days <= 0 {
+		return nil, ErrInvalidSpending
+	}
+
+	type key struct{ account, category, currency string }
+	totals := make(map[key]*big.Rat)
+	var keys []key
+	for _, s := range spending {
+		if !s.Amount.IsNegative() || s.Date.Before(from) || !s.Date.Before(to) {
+			continue
+		}
+		k := key{s.AccountID, s.Category, s.Amount.Currency()}
+		if _, ok := totals[k]; !ok {
+			totals[k] = new(big.Rat)
+			keys = append(keys, k)
+		}
+		totals[k].Add(totals[k], s.Amount.Rat())
+	}
+
+	result := make([]Discretionary, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, Discretionary{
+			AccountID: k.account,
+			Category:  k.category,
+			Daily:     new(big.Rat).Quo(totals[k], big.NewRat(days, 1)),
+			Currency:  k.currency,
+		})
+	}
+	return result, nil
+}
+
+// Day is an account's projected activity and closing balance on one day.
+type Day struct {
+	Date    time.Time   `json:"date"`
+	Balance money.Money `json:"balance"`
+	Change  money.Money `json:"change"`
+	Items   []string    `json:"items,omitempty"`
+}
+
+// Direction is which way a balance crossed a threshold.
+type Direction string
+
+const (
+	DirectionBelow Direction = "below"
+	DirectionAbove Direction = "above"
+)
+
+// Crossing is a day on which the balance crosses a threshold.
+type Crossing struct {
+	Date      time.Time   `json:"date"`
+	Threshold money.Money `json:"threshold"`
+	Balance   money.Money `json:"balance"`
+	Direction Direction   `json:"direction"`
+}
+
+// AccountForecast is the projection of a single account.
+type AccountForecast struct {
+	AccountID string     `json:"account_id"`
+	Days      []Day      `json:"days"`
+	Lowest    Day        `json:"lowest"`
+	Crossings []Crossing `json:"crossings"`
+}
+
+// Project forecasts the closing balance of every account for each of the days starting
+// with start. Items before start are ignored and recurring items repeat at their cadence.
+// Discretionary spending is spread evenly, rounding the running total rather than each day
+// so no minor units are lost over the period.
+func Project(accounts []Account, items []Item, discretionary []Discretionary, start time.Time, days int) ([]AccountForecast, error) {
+	if days < 1 || days > maxDays {
+		return nil, ErrInvalidDays
+	}
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, days)
+
+	index := make(map[string]int, len(accounts))
+	for i, account := range accounts {
+		index[account.ID] = i
+		for _, threshold := range account.Thresholds {
+			if threshold.Currency() != account.Balance.Currency() {
+				return nil, fmt.Errorf("%w: threshold %s for account %q", money.ErrCurrencyMismatch, threshold, account.ID)
+			}
+		}
+	}
+
+	// Expand every item into its occurrences within the forecast.
+	type occurrence struct {
+		day         int
+		amount      money.Money
+		description string
+	}
+	occurrences := make([][]occurrence, len(accounts))
+	for _, item := range items {
+		i, ok := index[item.AccountID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownAccount, item.AccountID)
+		}
+		switch item.Frequency {
+		case "", recurring.CadenceWeekly, recurring.CadenceBiweekly, recurring.CadenceMonthly, recurring.CadenceQuarterly, recurring.CadenceYearly:
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCadence, item.Frequency)
+		}
+		if item.Amount.Currency() != accounts[i].Balance.Currency() {
+			return nil, fmt.Errorf("%w: %q in account %q", money.ErrCurrencyMismatch, item.Description, item.AccountID)
+		}
+		for n, date := 0, item.NextDate; date.Before(end); n++ {
+			if !date.Before(start) {
+				occurrences[i] = append(occurrences[i], occurrence{
+					day:         int(date.Sub(start).Hours() / 24),
+					amount:      item.Amount,
+					description: item.Description,
+				})
+			}
+			if item.Frequency == "" {
+				break
+			}
+			date = nextOccurrence(item.NextDate, item.Frequency, n+1)
+		}
+	}
+
+	dailySpend := make([]*big.Rat, len(accounts))
+	for _, d := range discretionary {
+		i, ok := index[d.AccountID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownAccount, d.AccountID)
+		}
+		if d.Currency != accounts[i].Balance.Currency() {
+			return nil, fmt.Errorf("%w: %q spending in account %q", money.ErrCurrencyMismatch, d.Category, d.AccountID)
+		}
+		if dailySpend[i] == nil {
+			dailySpend[i] = new(big.Rat)
+		}
+		dailySpend[i].Add(dailySpend[i], d.Daily)
+	}
+
+	forecasts := make([]AccountForecast, 0, len(accounts))
+	for i, account := range accounts {
+		byDay := occurrences[i]
+		sort.SliceStable(byDay, func(a, b int) bool { return byDay[a].day < byDay[b].day })
+
+		forecast := AccountForecast{AccountID: account.ID, Days: make([]Day, 0, days), Crossings: []Crossing{}}
+		currency := account.Balance.Currency()
+		balance := account.Balance
+		spent, _ := money.Zero(currency)
+		next := 0
+
+		for d := 0; d < days; d++ {
+			day := Day{Date: start.AddDate(0, 0, d)}
+			change, _ := money.Zero(currency)
+
+			for ; next < len(byDay) && byDay[next].day == d; next++ {
+				change, _ = change.Add(byDay[next].amount)
+				day.Items = append(day.Items, byDay[next].description)
+			}
+
+			if dailySpend[i] != nil {
+				cumulative, err := money.FromRat(new(big.Rat).Mul(dailySpend[i], big.NewRat(int64(d+1), 1)), currency)
+				if err != nil {
+					return nil, err
+				}
+				today, _ := cumulative.Sub(spent)
+				change, _ = change.Add(today)
+				spent = cumulative
+			}
+
+			previous := balance
+			var err error
+			if balance, err = balance.Add(change); err != nil {
+				return nil, err
+			}
+			day.Balance, day.Change = balance, change
+			forecast.Days = append(forecast.Days, day)
+
+			if d == 0 || cmpMoney(balance, forecast.Lowest.Balance) < 0 {
+				forecast.Lowest = day
+			}
+			forecast.Crossings = append(forecast.Crossings, crossings(account.Thresholds, previous, balance, day.Date)...)
+		}
+		forecasts = append(forecasts, forecast)
+	}
+	return forecasts, nil
+}
+
+// crossings returns the thresholds crossed moving from previous to balance.
+func crossings(thresholds []money.Money, previous, balance money.Money, date time.Time) []Crossing {
+	var result []Crossing
+	for _, threshold := range thresholds {
+		wasBelow := cmpMoney(previous, threshold) < 0
+		isBelow := cmpMoney(balance, threshold) < 0
+		switch {
+		case !wasBelow && isBelow:
+			result = append(result, Crossing{Date: date, Threshold: threshold, Balance: balance, Direction: DirectionBelow})
+		case wasBelow && !isBelow:
+			result = append(result, Crossing{Date: date, Threshold: threshold, Balance: balance, Direction: DirectionAbove})
+		}
+	}
+	return result
+}
+
+// nextOccurrence returns the nth occurrence after first. Months are added to the first date
+// rather than chained, so a bill due on the 31st does not drift to the 28th after February.
+func nextOccurrence(first time.Time, cadence recurring.Cadence, n int) time.Time {
+	switch cadence {
+	case recurring.CadenceWeekly:
+		return first.AddDate(0, 0, 7*n)
+	case recurring.CadenceBiweekly:
+		return first.AddDate(0, 0, 14*n)
+	case recurring.CadenceMonthly:
+		return addMonths(first, n)
+	case recurring.CadenceQuarterly:
+		return addMonths(first, 3*n)
+	default:
+		return addMonths(first, 12*n)
+	}
+}
+
+// addMonths adds months to date, clamping the day to the end of shorter months.
+func addMonths(date time.Time, months int) time.Time {
+	firstOfMonth := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(date.Day(), lastDay)-1)
+}
+
+// cmpMoney compares two amounts in the same currency.
+func cmpMoney(a, b money.Money) int {
+	cmp, _ := a.Cmp(b)
+	return cmp
+}
@@ -0,0 +1,143 @@
+package forecast
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+	"github.com/ZiadMansourM/budgetly/pkg/recurring"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func eur(amount int64) money.Money {
+	return money.MustNew(amount, "EUR")
+}
+
+func TestAverageSpending(t *testing.T) {
+	spending := []Spending{
+		{AccountID: "checking", Category: "groceries", Date: day(3, 5), Amount: eur(-6000)},
+		{AccountID: "checking", Category: "groceries", Date: day(3, 20), Amount: eur(-3000)},
+		{AccountID: "checking", Category: "groceries", Date: day(3, 21), Amount: eur(1500)},
+		{AccountID: "checking", Category: "dining", Date: day(3, 12), Amount: eur(-3000)},
+		{AccountID: "checking", Category: "dining", Date: day(4, 2), Amount: eur(-9999)},
+	}
+
+	averages, err := AverageSpending(spending, day(3, 1), day(3, 31))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(averages) != 2 {
+		t.Fatalf("Expected 2 categories, got %+v", averages)
+	}
+	// 90.00 over 30 days, ignoring the refund.
+	if averages[0].Category != "groceries" || averages[0].Daily.FloatString(2) != "-3.00" {
+		t.Errorf("Unexpected groceries average %s", averages[0].Daily.FloatString(2))
+	}
+	if averages[1].Category != "dining" || averages[1].Daily.FloatString(2) != "-1.00" {
+		t.Errorf("Unexpected dining average %s", averages[1].Daily.FloatString(2))
+	}
+
+	if _, err := AverageSpending(spending, day(3, 31), day(3, 1)); !errors.Is(err, ErrInvalidSpending) {
+		t.Errorf("Expected ErrInvalidSpending, got %v", err)
+	}
+}
+
+func TestProject(t *testing.T) {
+	accounts := []Account{{ID: "checking", Balance: eur(50000), Thresholds: []money.Money{eur(0), eur(10000)}}}
+	items := []Item{
+		{AccountID: "checking", Description: "Rent", Amount: eur(-45000), NextDate: day(5, 1), Frequency: recurring.CadenceMonthly},
+		{AccountID: "checking", Description: "Salary", Amount: eur(200000), NextDate: day(4, 25), Frequency: recurring.CadenceMonthly},
+		{AccountID: "checking", Description: "Old one-off", Amount: eur(-99999), NextDate: day(4, 1)},
+	}
+	subscription := recurring.Subscription{Payee: "Streaming", Cadence: recurring.CadenceWeekly, Amount: eur(-1000), NextDate: day(4, 22)}
+	items = append(items, FromSubscription("checking", subscription))
+
+	spending, _ := AverageSpending([]Spending{
+		{AccountID: "checking", Category: "groceries", Date: day(3, 10), Amount: eur(-10000)},
+	}, day(3, 1), day(3, 31))
+
+	// 100.00 over 30 days is 3.333... a day.
+	forecasts, err := Project(accounts, items, spending, day(4, 20), 15)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	forecast := forecasts[0]
+	if len(forecast.Days) != 15 {
+		t.Fatalf("Expected 15 days, got %d", len(forecast.Days))
+	}
+
+	changes := make([]int64, 0, 3)
+	for _, d := range forecast.Days[:3] {
+		changes = append(changes, d.Change.Amount())
+	}
+	if !slices.Equal(changes, []int64{-333, -334, -1333}) {
+		t.Errorf("Expected spending to be spread without losing cents and the subscription on 22 April, got %v", changes)
+	}
+
+	// Before payday: 500.00 - 5 days of spending (16.67) - one subscription (10.00) = 473.33.
+	payday := forecast.Days[5]
+	if !payday.Date.Equal(day(4, 25)) || !slices.Equal(payday.Items, []string{"Salary"}) {
+		t.Errorf("Unexpected payday %+v", payday)
+	}
+	if forecast.Days[4].Balance.Amount() != 47333 {
+		t.Errorf("Expected 473.33 before payday, got %s", forecast.Days[4].Balance)
+	}
+
+	// Fifteen days of spending, two subscriptions, salary and rent: 500.00 - 50.00 - 20.00 + 2000.00 - 450.00.
+	if last := forecast.Days[14]; last.Balance.Amount() != 198000 {
+		t.Errorf("Expected 1980.00 on the last day, got %s", last.Balance)
+	}
+	if !forecast.Lowest.Date.Equal(day(4, 24)) {
+		t.Errorf("Expected the lowest balance the day before payday, got %v", forecast.Lowest.Date)
+	}
+	if len(forecast.Crossings) != 0 {
+		t.Errorf("Expected no crossings, got %+v", forecast.Crossings)
+	}
+}
+
+func TestProjectCrossings(t *testing.T) {
+	accounts := []Account{{ID: "checking", Balance: eur(20000), Thresholds: []money.Money{eur(0), eur(10000)}}}
+	items := []Item{
+		{AccountID: "checking", Description: "Insurance", Amount: eur(-25000), NextDate: day(1, 31), Frequency: recurring.CadenceMonthly},
+		{AccountID: "checking", Description: "Salary", Amount: eur(100000), NextDate: day(2, 3)},
+	}
+
+	forecasts, err := Project(accounts, items, nil, day(1, 30), 35)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Crossing{
+		{Date: day(1, 31), Threshold: eur(0), Balance: eur(-5000), Direction: DirectionBelow},
+		{Date: day(1, 31), Threshold: eur(10000), Balance: eur(-5000), Direction: DirectionBelow},
+		{Date: day(2, 3), Threshold: eur(0), Balance: eur(95000), Direction: DirectionAbove},
+		{Date: day(2, 3), Threshold: eur(10000), Balance: eur(95000), Direction: DirectionAbove},
+	}
+	if !slices.Equal(forecasts[0].Crossings, expected) {
+		t.Errorf("Unexpected crossings %+v", forecasts[0].Crossings)
+	}
+
+	// A bill due on the 31st is clamped to the end of February, not moved into March.
+	if items := forecasts[0].Days[29].Items; !forecasts[0].Days[29].Date.Equal(day(2, 28)) || !slices.Equal(items, []string{"Insurance"}) {
+		t.Errorf("Expected the insurance on 28 February, got %v on %v", items, forecasts[0].Days[29].Date)
+	}
+}
+
+func TestProjectErrors(t *testing.T) {
+	accounts := []Account{{ID: "checking", Balance: eur(0)}}
+
+	if _, err := Project(accounts, nil, nil, day(1, 1), 0); !errors.Is(err, ErrInvalidDays) {
+		t.Errorf("Expected ErrInvalidDays, got %v", err)
+	}
+	if _, err := Project(accounts, []Item{{AccountID: "savings", Amount: eur(1), NextDate: day(1, 1)}}, nil, day(1, 1), 5); !errors.Is(err, ErrUnknownAccount) {
+		t.Errorf("Expected ErrUnknownAccount, got %v", err)
+	}
+	if _, err := Project(accounts, []Item{{AccountID: "checking", Amount: money.MustNew(1, "USD"), NextDate: day(1, 1)}}, nil, day(1, 1), 5); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
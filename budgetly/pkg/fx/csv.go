@@ -0,0 +1,56 @@
+package fx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ParseCSV reads rates from a CSV file with a header row naming the date, base,
+// quote and rate columns in any order. Dates use the YYYY-MM-DD format.
+func ParseCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %q column", name)
+		}
+	}
+
+	var rates []Rate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV line %d: %w", line, err)
+		}
+
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(record[columns["date"]]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid date on CSV line %d: %w", line, err)
+		}
+
+		rate, err := NewRate(date, record[columns["base"]], record[columns["quote"]], record[columns["rate"]])
+		if err != nil {
+			return nil, fmt.Errorf("CSV line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
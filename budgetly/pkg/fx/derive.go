@@ -0,0 +1,54 @@
+package fx
+
+import (
+	"sort"
+	"time"
+)
+
+// FillGaps returns the rates with every missing calendar day filled with the last
+// known rate of the same pair, e.g. for weekends and bank holidays. Each pair is
+// filled from its first date through until, or through its last date when until is zero.
+func FillGaps(rates []Rate, until time.Time) []Rate {
+	series := make(map[string][]Rate)
+	for _, rate := range rates {
+		key := pairKey(rate.Base, rate.Quote)
+		series[key] = append(series[key], rate)
+	}
+
+	var filled []Rate
+	for _, pair := range series {
+		sortRates(pair)
+
+		last := pair[len(pair)-1].Date
+		if !until.IsZero() && Day(until).After(last) {
+			last = Day(until)
+		}
+
+		next := 0
+		current := pair[0]
+		for date := pair[0].Date; !date.After(last); date = date.AddDate(0, 0, 1) {
+			for next < len(pair) && !pair[next].Date.After(date) {
+				current = pair[next]
+				next++
+			}
+			current.Date = date
+			filled = append(filled, current)
+		}
+	}
+
+	sortRates(filled)
+	return filled
+}
+
+// sortRates orders rates by date, then base and quote.
+func sortRates(rates []Rate) {
+	sort.Slice(rates, func(i, j int) bool {
+		if !rates[i].Date.Equal(rates[j].Date) {
+			return rates[i].Date.Before(rates[j].Date)
+		}
+		if rates[i].Base != rates[j].Base {
+			return rates[i].Base < rates[j].Base
+		}
+		return rates[i].Quote < rates[j].Quote
+	})
+}
@@ -0,0 +1,77 @@
+package fx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ECBBase is the base currency of the ECB euro foreign exchange reference rates.
+const ECBBase = "EUR"
+
+// ecbEnvelope is the layout shared by the ECB eurofxref daily, 90-day and historical XML files.
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECB reads an ECB eurofxref XML file (daily or historical) and returns
+// one EUR-based rate per currency and day.
+func ParseECB(r io.Reader) ([]Rate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("error decoding ECB XML: %w", err)
+	}
+
+	var rates []Rate
+	for _, day := range envelope.Days {
+		date, err := time.Parse(time.DateOnly, day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ECB date %q: %w", day.Time, err)
+		}
+
+		for _, quote := range day.Rates {
+			rate, err := NewRate(date, ECBBase, quote.Currency, quote.Rate)
+			if err != nil {
+				return nil, fmt.Errorf("ECB rate on %s: %w", day.Time, err)
+			}
+			rates = append(rates, rate)
+		}
+	}
+
+	return rates, nil
+}
+
+// Parse reads a rate file, detecting whether it is ECB XML or CSV from its first
+// non-blank character.
+func Parse(r io.Reader) ([]Rate, error) {
+	reader := bufio.NewReader(r)
+
+	// Skip a UTF-8 byte order mark and leading whitespace.
+	if bom, _ := reader.Peek(3); bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		reader.Discard(3)
+	}
+	for {
+		b, err := reader.Peek(1)
+		if err != nil {
+			return nil, fmt.Errorf("error reading rate file: %w", err)
+		}
+		if b[0] != ' ' && b[0] != '\t' && b[0] != '\r' && b[0] != '\n' {
+			break
+		}
+		reader.Discard(1)
+	}
+
+	if b, _ := reader.Peek(1); b[0] == '<' {
+		return ParseECB(reader)
+	}
+	return ParseCSV(reader)
+}
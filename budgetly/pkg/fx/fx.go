@@ -0,0 +1,194 @@
+// Package fx converts amounts between currencies using dated exchange rates.
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var (
+	ErrRateNotFound = errors.New("exchange rate not found")
+	ErrInvalidRate  = errors.New("invalid exchange rate")
+)
+
+// Rate is the price of one unit of Base expressed in Quote on a given date,
+// e.g. Base EUR, Quote USD, Rate 1.0856.
+type Rate struct {
+	Date  time.Time
+	Base  string
+	Quote string
+	Rate  *big.Rat
+}
+
+// NewRate builds a rate from a decimal string such as "1.0856", normalizing the
+// currency codes to upper case and the date to midnight UTC.
+func NewRate(date time.Time, base, quote, rate string) (Rate, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || value.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, rate)
+	}
+
+	base, quote = strings.ToUpper(strings.TrimSpace(base)), strings.ToUpper(strings.TrimSpace(quote))
+	if len(base) != 3 || len(quote) != 3 || base == quote {
+		return Rate{}, fmt.Errorf("%w: currency pair %s/%s", ErrInvalidRate, base, quote)
+	}
+
+	return Rate{Date: Day(date), Base: base, Quote: quote, Rate: value}, nil
+}
+
+// RateSource returns the rate to convert one unit of base into quote on a date.
+type RateSource interface {
+	Rate(base, quote string, date time.Time) (*big.Rat, error)
+}
+
+// Table is an in-memory RateSource. A lookup uses the most recent rate on or before
+// the requested date and falls back to the inverse of the opposite pair, then to the
+// cross rate through EUR (ECBBase). It is safe for concurrent use.
+type Table struct {
+	mu    sync.RWMutex
+	rates map[string][]Rate
+}
+
+// NewTable creates a table holding the given rates.
+func NewTable(rates ...Rate) *Table {
+	t := &Table{rates: make(map[string][]Rate)}
+	t.Add(rates...)
+	return t
+}
+
+// Add stores rates, replacing any existing rate for the same pair and date.
+func (t *Table) Add(rates ...Rate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, rate := range rates {
+		key := pairKey(rate.Base, rate.Quote)
+		series := t.rates[key]
+
+		i := sort.Search(len(series), func(i int) bool {
+			return !series[i].Date.Before(rate.Date)
+		})
+		if i < len(series) && series[i].Date.Equal(rate.Date) {
+			series[i] = rate
+			continue
+		}
+
+		series = append(series, Rate{})
+		copy(series[i+1:], series[i:])
+		series[i] = rate
+		t.rates[key] = series
+	}
+}
+
+// Rate implements RateSource.
+func (t *Table) Rate(base, quote string, date time.Time) (*big.Rat, error) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	if base == quote {
+		return big.NewRat(1, 1), nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if rate, ok := t.direct(base, quote, date); ok {
+		return rate, nil
+	}
+	// Derive the cross rate from both currencies' rates against EUR, e.g. USD/EGP is
+	// (EUR/EGP) / (EUR/USD).
+	if base != ECBBase && quote != ECBBase {
+		toPivot, ok := t.direct(base, ECBBase, date)
+		if ok {
+			if fromPivot, ok := t.direct(ECBBase, quote, date); ok {
+				return new(big.Rat).Mul(toPivot, fromPivot), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s/%s on %s", ErrRateNotFound, base, quote, date.Format(time.DateOnly))
+}
+
+// direct returns the most recent rate for the pair on or before the date, or the
+// inverse of the opposite pair's.
+func (t *Table) direct(base, quote string, date time.Time) (*big.Rat, bool) {
+	if rate, ok := t.latest(base, quote, date); ok {
+		return rate, true
+	}
+	if rate, ok := t.latest(quote, base, date); ok {
+		return new(big.Rat).Inv(rate), true
+	}
+	return nil, false
+}
+
+// Rates returns every stored rate, ordered by pair and date.
+func (t *Table) Rates() []Rate {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	keys := make([]string, 0, len(t.rates))
+	for key := range t.rates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var rates []Rate
+	for _, key := range keys {
+		rates = append(rates, t.rates[key]...)
+	}
+	return rates
+}
+
+// latest returns the most recent rate for the pair on or before the date.
+func (t *Table) latest(base, quote string, date time.Time) (*big.Rat, bool) {
+	series := t.rates[pairKey(base, quote)]
+	i := sort.Search(len(series), func(i int) bool {
+		return series[i].Date.After(Day(date))
+	})
+	if i == 0 {
+		return nil, false
+	}
+	return series[i-1].Rate, true
+}
+
+// Convert converts an amount in minor units of one currency into minor units of
+// another, using the rate in effect on the date and rounding half to even.
+func Convert(source RateSource, amount int64, from, to string, date time.Time) (int64, error) {
+	rate, err := source.Rate(from, to, date)
+	if err != nil {
+		return 0, err
+	}
+	return ConvertAt(amount, from, to, rate)
+}
+
+// ConvertAt converts an amount in minor units of one currency into minor units of
+// another at the given rate, rounding half to even. It returns money.ErrOverflow when
+// the converted amount does not fit in an int64.
+func ConvertAt(amount int64, from, to string, rate *big.Rat) (int64, error) {
+	value := new(big.Rat).SetInt64(amount)
+	value.Mul(value, rate)
+	value.Mul(value, scale(money.Exponent(to)-money.Exponent(from)))
+	return money.RoundHalfEven(value)
+}
+
+// Day truncates a time to midnight UTC of its calendar date.
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// pairKey returns the map key for a currency pair.
+func pairKey(base, quote string) string {
+	return base + "/" + quote
+}
+
+// scale returns 10^n as a rational, for negative n as well.
+func scale(n int) *big.Rat {
+	if n < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-n)), nil))
+	}
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
@@ -0,0 +1,210 @@
+package fx
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func mustRate(t *testing.T, date time.Time, base, quote, rate string) Rate {
+	t.Helper()
+	r, err := NewRate(date, base, quote, rate)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return r
+}
+
+func TestTableRate(t *testing.T) {
+	table := NewTable(
+		mustRate(t, day(2024, 1, 2), "EUR", "USD", "1.10"),
+		mustRate(t, day(2024, 1, 5), "EUR", "USD", "1.08"),
+	)
+
+	rate, err := table.Rate("EUR", "USD", day(2024, 1, 4))
+	if err != nil || rate.Cmp(big.NewRat(110, 100)) != 0 {
+		t.Errorf("Expected the last known rate 1.10, got %v, %v", rate, err)
+	}
+
+	rate, err = table.Rate("usd", "eur", day(2024, 1, 6))
+	if err != nil || rate.Cmp(big.NewRat(100, 108)) != 0 {
+		t.Errorf("Expected the inverse rate 1/1.08, got %v, %v", rate, err)
+	}
+
+	if _, err := table.Rate("EUR", "USD", day(2024, 1, 1)); !errors.Is(err, ErrRateNotFound) {
+		t.Errorf("Expected ErrRateNotFound before the first rate, got %v", err)
+	}
+
+	table.Add(mustRate(t, day(2024, 1, 2), "EUR", "USD", "1.11"))
+	if rate, _ := table.Rate("EUR", "USD", day(2024, 1, 3)); rate.Cmp(big.NewRat(111, 100)) != 0 {
+		t.Errorf("Expected the replaced rate 1.11, got %v", rate)
+	}
+	if len(table.Rates()) != 2 {
+		t.Errorf("Expected 2 stored rates, got %d", len(table.Rates()))
+	}
+}
+
+func TestConvert(t *testing.T) {
+	table := NewTable(
+		mustRate(t, day(2024, 1, 2), "EUR", "USD", "1.0856"),
+		mustRate(t, day(2024, 1, 2), "USD", "JPY", "141.5"),
+		mustRate(t, day(2024, 1, 2), "KWD", "USD", "3.25"),
+	)
+
+	tests := []struct {
+		amount   int64
+		from, to string
+		expected int64
+	}{
+		{10000, "EUR", "USD", 10856},
+		{10856, "USD", "EUR", 10000},
+		{1000, "USD", "JPY", 1415},
+		{1000, "KWD", "USD", 325},
+		{-10000, "EUR", "USD", -10856},
+		{500, "EUR", "EUR", 500},
+	}
+	for _, tt := range tests {
+		got, err := Convert(table, tt.amount, tt.from, tt.to, day(2024, 1, 3))
+		if err != nil || got != tt.expected {
+			t.Errorf("Convert(%d %s -> %s) = %d, %v; expected %d", tt.amount, tt.from, tt.to, got, err, tt.expected)
+		}
+	}
+}
+
+func TestConvertAtOverflow(t *testing.T) {
+	if got, err := ConvertAt(10000, "EUR", "USD", big.NewRat(10856, 10000)); err != nil || got != 10856 {
+		t.Errorf("ConvertAt = %d, %v; expected 10856", got, err)
+	}
+	if _, err := ConvertAt(math.MaxInt64, "EUR", "USD", big.NewRat(2, 1)); !errors.Is(err, money.ErrOverflow) {
+		t.Errorf("Expected money.ErrOverflow, got %v", err)
+	}
+}
+
+func TestPositionGains(t *testing.T) {
+	position := &Position{Currency: "USD", ReportingCurrency: "EUR"}
+	position.Acquire(10000, 9000)
+	position.Acquire(10000, 9400)
+
+	realized, err := position.Dispose(5000, 4800)
+	if err != nil || realized != 200 {
+		t.Errorf("Expected a realized gain of 200, got %d, %v", realized, err)
+	}
+	if position.Balance != 15000 || position.Cost != 13800 {
+		t.Errorf("Unexpected position after disposal %+v", position)
+	}
+
+	if gain, err := position.UnrealizedGain(big.NewRat(9, 10)); err != nil || gain != -300 {
+		t.Errorf("Expected an unrealized loss of 300, got %d, %v", gain, err)
+	}
+
+	if _, err := position.Dispose(20000, 0); !errors.Is(err, ErrInsufficientBalance) {
+		t.Errorf("Expected ErrInsufficientBalance, got %v", err)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "rate,date,base,quote\n1.0856,2024-01-02,eur,usd\n30.9,2024-01-02,USD,EGP\n"
+	rates, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rates) != 2 || rates[0].Base != "EUR" || rates[1].Quote != "EGP" {
+		t.Errorf("Unexpected rates %+v", rates)
+	}
+
+	if _, err := ParseCSV(strings.NewReader("date,base,rate\n")); err == nil {
+		t.Errorf("Expected an error for a missing quote column")
+	}
+	if _, err := ParseCSV(strings.NewReader("date,base,quote,rate\n2024-01-02,EUR,USD,-1\n")); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Expected ErrInvalidRate for a negative rate, got %v", err)
+	}
+}
+
+const ecbSample = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender><gesmes:name>European Central Bank</gesmes:name></gesmes:Sender>
+	<Cube>
+		<Cube time="2024-01-08">
+			<Cube currency="USD" rate="1.0951"/>
+			<Cube currency="GBP" rate="0.8592"/>
+		</Cube>
+		<Cube time="2024-01-05">
+			<Cube currency="USD" rate="1.0921"/>
+			<Cube currency="GBP" rate="0.8608"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestParseECB(t *testing.T) {
+	rates, err := Parse(strings.NewReader("\n  " + ecbSample))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rates) != 4 {
+		t.Fatalf("Expected 4 rates, got %d", len(rates))
+	}
+
+	first := rates[0]
+	if !first.Date.Equal(day(2024, 1, 8)) || first.Base != "EUR" || first.Quote != "USD" ||
+		first.Rate.Cmp(big.NewRat(10951, 10000)) != 0 {
+		t.Errorf("Unexpected first rate %+v", first)
+	}
+
+	csvRates, err := Parse(strings.NewReader("date,base,quote,rate\n2024-01-05,EUR,USD,1.0921\n"))
+	if err != nil || len(csvRates) != 1 {
+		t.Errorf("Expected Parse to fall back to CSV, got %v, %v", csvRates, err)
+	}
+}
+
+func TestTableCrossRate(t *testing.T) {
+	table := NewTable(
+		mustRate(t, day(2024, 1, 4), "EUR", "USD", "1.10"),
+		mustRate(t, day(2024, 1, 5), "EUR", "EGP", "33.00"),
+		mustRate(t, day(2024, 1, 5), "GBP", "EUR", "1.25"),
+	)
+
+	rate, err := table.Rate("USD", "EGP", day(2024, 1, 6))
+	if err != nil || rate.Cmp(big.NewRat(30, 1)) != 0 {
+		t.Errorf("Expected USD/EGP 30 through EUR, got %v, %v", rate, err)
+	}
+	rate, err = table.Rate("GBP", "USD", day(2024, 1, 5))
+	if err != nil || rate.Cmp(big.NewRat(1375, 1000)) != 0 {
+		t.Errorf("Expected GBP/USD 1.375 through EUR, got %v, %v", rate, err)
+	}
+	if _, err := table.Rate("USD", "EGP", day(2024, 1, 4)); !errors.Is(err, ErrRateNotFound) {
+		t.Errorf("Expected ErrRateNotFound before the EGP leg exists, got %v", err)
+	}
+}
+
+func TestFillGaps(t *testing.T) {
+	rates, err := ParseECB(strings.NewReader(ecbSample))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	filled := FillGaps(rates, day(2024, 1, 9))
+	if len(filled) != 10 {
+		t.Fatalf("Expected 5 days for 2 pairs, got %d", len(filled))
+	}
+
+	table := NewTable(filled...)
+	saturday, err := table.Rate("EUR", "USD", day(2024, 1, 6))
+	if err != nil || saturday.Cmp(big.NewRat(10921, 10000)) != 0 {
+		t.Errorf("Expected Saturday to carry Friday's rate, got %v, %v", saturday, err)
+	}
+
+	tuesday, err := table.Rate("EUR", "GBP", day(2024, 1, 9))
+	if err != nil || tuesday.Cmp(big.NewRat(8592, 10000)) != 0 {
+		t.Errorf("Expected Tuesday to carry Monday's rate, got %v, %v", tuesday, err)
+	}
+}
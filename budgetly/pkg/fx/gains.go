@@ -0,0 +1,62 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var ErrInsufficientBalance = errors.New("insufficient foreign currency balance")
+
+// Position tracks a foreign currency holding and what it cost in the reporting
+// currency, using the average cost method to compute realized and unrealized gains.
+// Amounts are expressed in minor units of their currency.
+type Position struct {
+	Currency          string
+	ReportingCurrency string
+	Balance           int64
+	Cost              int64
+}
+
+// Acquire adds foreign currency bought or received for cost in the reporting currency.
+func (p *Position) Acquire(amount, cost int64) {
+	p.Balance += amount
+	p.Cost += cost
+}
+
+// Dispose removes foreign currency sold or spent for proceeds in the reporting currency
+// and returns the realized gain (negative for a loss).
+func (p *Position) Dispose(amount, proceeds int64) (int64, error) {
+	if amount > p.Balance {
+		return 0, fmt.Errorf("%w: disposing %d of %d %s", ErrInsufficientBalance, amount, p.Balance, p.Currency)
+	}
+	if amount == 0 {
+		return proceeds, nil
+	}
+
+	// The disposed share of the cost is proportional to the disposed share of the balance.
+	disposedCost := p.Cost
+	if amount < p.Balance {
+		share := new(big.Int).Mul(big.NewInt(p.Cost), big.NewInt(amount))
+		var err error
+		if disposedCost, err = money.RoundHalfEven(new(big.Rat).SetFrac(share, big.NewInt(p.Balance))); err != nil {
+			return 0, err
+		}
+	}
+
+	p.Balance -= amount
+	p.Cost -= disposedCost
+	return proceeds - disposedCost, nil
+}
+
+// UnrealizedGain returns the gain (negative for a loss) of revaluing the remaining
+// balance at the given rate from Currency to ReportingCurrency.
+func (p *Position) UnrealizedGain(rate *big.Rat) (int64, error) {
+	value, err := ConvertAt(p.Balance, p.Currency, p.ReportingCurrency, rate)
+	if err != nil {
+		return 0, err
+	}
+	return value - p.Cost, nil
+}
@@ -0,0 +1,172 @@
+// Package goals tracks progress towards savings goals and projects when they will be reached.
+package goals
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var ErrInvalidTarget = errors.New("goal target must be greater than zero")
+
+// State summarizes how a goal is doing.
+type State string
+
+const (
+	// StateComplete goals have reached their target.
+	StateComplete State = "complete"
+	// StateOnTrack goals will reach their target by the target date at the recent pace,
+	// or have no target date and are being contributed to.
+	StateOnTrack State = "on_track"
+	// StateBehind goals will miss their target date at the recent pace.
+	StateBehind State = "behind"
+	// StateStalled goals have had no net contributions recently.
+	StateStalled State = "stalled"
+)
+
+// Goal is a savings target, optionally due by a date.
+type Goal struct {
+	Target money.Money
+	// TargetDate is the date the goal should be reached by; the zero time means none.
+	TargetDate time.Time
+}
+
+// Contribution is money added to (or, when negative, taken from) a goal's category or account.
+type Contribution struct {
+	Date   time.Time
+	Amount money.Money
+}
+
+// Status is a goal's progress and projection on a given day.
+type Status struct {
+	State     State       `json:"state"`
+	Saved     money.Money `json:"saved"`
+	Remaining money.Money `json:"remaining"`
+	// Percent is the share of the target saved, rounded down and capped at 100.
+	Percent int `json:"percent"`
+	// MonthlyAverage is the average net contribution over the recent full months.
+	MonthlyAverage money.Money `json:"monthly_average"`
+	// ProjectedCompletion is the month the goal is reached at the recent pace, if it ever is.
+	ProjectedCompletion *time.Time `json:"projected_completion,omitempty"`
+	// RequiredMonthly is the contribution needed each month to reach the target by the target date.
+	RequiredMonthly *money.Money `json:"required_monthly,omitempty"`
+}
+
+// Progress computes the status of a goal on today, given the balance of its linked category
+// or account and its contribution history. The pace is the average over the last months full
+// calendar months before today's month, so a half-finished month does not skew it.
+func Progress(goal Goal, balance money.Money, history []Contribution, today time.Time, months int) (Status, error) {
+	if !goal.Target.IsPositive() {
+		return Status{}, ErrInvalidTarget
+	}
+	if months < 1 {
+		months = 1
+	}
+
+	remaining, err := goal.Target.Sub(balance)
+	if err != nil {
+		return Status{}, err
+	}
+	if remaining.IsNegative() {
+		remaining, _ = money.Zero(remaining.Currency())
+	}
+
+	average, err := monthlyAverage(history, goal.Target.Currency(), today, months)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{
+		Saved:          balance,
+		Remaining:      remaining,
+		Percent:        percent(balance, goal.Target),
+		MonthlyAverage: average,
+	}
+
+	thisMonth := startOfMonth(today)
+	if remaining.IsZero() {
+		status.State = StateComplete
+		status.ProjectedCompletion = &thisMonth
+		return status, nil
+	}
+
+	if !goal.TargetDate.IsZero() {
+		// Contributions this month still count, so a goal due this month has one month left.
+		monthsLeft := max(1, monthsBetween(thisMonth, startOfMonth(goal.TargetDate))+1)
+		required := divideUp(remaining, int64(monthsLeft))
+		status.RequiredMonthly = &required
+	}
+
+	if !average.IsPositive() {
+		status.State = StateStalled
+		return status, nil
+	}
+
+	completion := thisMonth.AddDate(0, int(ceilDiv(remaining.Amount(), average.Amount())), 0)
+	status.ProjectedCompletion = &completion
+
+	status.State = StateOnTrack
+	if !goal.TargetDate.IsZero() && completion.After(startOfMonth(goal.TargetDate)) {
+		status.State = StateBehind
+	}
+	return status, nil
+}
+
+// monthlyAverage averages the contributions in the full months before today's month.
+func monthlyAverage(history []Contribution, currency string, today time.Time, months int) (money.Money, error) {
+	end := startOfMonth(today)
+	start := end.AddDate(0, -months, 0)
+
+	total, err := money.Zero(currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	for _, contribution := range history {
+		if contribution.Date.Before(start) || !contribution.Date.Before(end) {
+			continue
+		}
+		if total, err = total.Add(contribution.Amount); err != nil {
+			return money.Money{}, fmt.Errorf("error adding contribution: %w", err)
+		}
+	}
+	return total.MulRat(big.NewRat(1, int64(months)))
+}
+
+// percent returns saved as a whole percentage of target, capped between 0 and 100.
+func percent(saved, target money.Money) int {
+	if !saved.IsPositive() {
+		return 0
+	}
+	if saved.Amount() >= target.Amount() {
+		return 100
+	}
+	return int(new(big.Int).Div(
+		new(big.Int).Mul(big.NewInt(saved.Amount()), big.NewInt(100)),
+		big.NewInt(target.Amount()),
+	).Int64())
+}
+
+// divideUp splits m into n equal parts, rounding up to the next minor unit so that n parts
+// always cover m.
+func divideUp(m money.Money, n int64) money.Money {
+	part, _ := money.New(ceilDiv(m.Amount(), n), m.Currency())
+	return part
+}
+
+// ceilDiv divides two positive integers, rounding up.
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
+
+// startOfMonth returns midnight UTC on the first day of t's month.
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// monthsBetween returns the number of calendar months from a to b.
+func monthsBetween(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
@@ -0,0 +1,99 @@
+package goals
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func eur(amount int64) money.Money {
+	return money.MustNew(amount, "EUR")
+}
+
+// history contributes 200.00 in each of January to March and 500.00 so far in April.
+var history = []Contribution{
+	{Date: day(2026, 1, 5), Amount: eur(20000)},
+	{Date: day(2026, 2, 5), Amount: eur(25000)},
+	{Date: day(2026, 2, 20), Amount: eur(-5000)},
+	{Date: day(2026, 3, 5), Amount: eur(20000)},
+	{Date: day(2026, 4, 2), Amount: eur(50000)},
+}
+
+func TestProgressOnTrack(t *testing.T) {
+	goal := Goal{Target: eur(200000), TargetDate: day(2026, 12, 31)}
+
+	status, err := Progress(goal, eur(110000), history, day(2026, 4, 10), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if status.MonthlyAverage.Amount() != 20000 {
+		t.Errorf("Expected the April contribution to be left out of the average, got %s", status.MonthlyAverage)
+	}
+	if status.Remaining.Amount() != 90000 || status.Percent != 55 {
+		t.Errorf("Unexpected progress %s remaining, %d%%", status.Remaining, status.Percent)
+	}
+	// 900.00 at 200.00 a month takes 5 months.
+	if status.ProjectedCompletion == nil || !status.ProjectedCompletion.Equal(day(2026, 9, 1)) {
+		t.Errorf("Expected completion in September, got %v", status.ProjectedCompletion)
+	}
+	// April to December is 9 months: 900.00 / 9.
+	if status.RequiredMonthly == nil || status.RequiredMonthly.Amount() != 10000 {
+		t.Errorf("Expected 100.00 a month to be required, got %v", status.RequiredMonthly)
+	}
+	if status.State != StateOnTrack {
+		t.Errorf("Expected on track, got %q", status.State)
+	}
+}
+
+func TestProgressBehind(t *testing.T) {
+	goal := Goal{Target: eur(200000), TargetDate: day(2026, 6, 30)}
+
+	status, err := Progress(goal, eur(110000), history, day(2026, 4, 10), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.State != StateBehind {
+		t.Errorf("Expected behind, got %q", status.State)
+	}
+	// 900.00 over April to June, rounded up to the cent.
+	if status.RequiredMonthly.Amount() != 30000 {
+		t.Errorf("Expected 300.00 a month to be required, got %s", status.RequiredMonthly)
+	}
+
+	// An overdue goal needs the whole remainder now.
+	status, _ = Progress(Goal{Target: eur(100000), TargetDate: day(2026, 1, 31)}, eur(99999), history, day(2026, 4, 10), 3)
+	if status.RequiredMonthly.Amount() != 1 || status.State != StateBehind {
+		t.Errorf("Unexpected overdue status %+v", status)
+	}
+}
+
+func TestProgressStalledAndComplete(t *testing.T) {
+	goal := Goal{Target: eur(100000)}
+
+	status, err := Progress(goal, eur(1000), nil, day(2026, 4, 10), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.State != StateStalled || status.ProjectedCompletion != nil || status.RequiredMonthly != nil {
+		t.Errorf("Unexpected stalled status %+v", status)
+	}
+
+	status, _ = Progress(goal, eur(120000), history, day(2026, 4, 10), 3)
+	if status.State != StateComplete || status.Percent != 100 || !status.Remaining.IsZero() {
+		t.Errorf("Unexpected complete status %+v", status)
+	}
+
+	if _, err := Progress(Goal{Target: eur(0)}, eur(0), nil, day(2026, 4, 10), 3); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("Expected ErrInvalidTarget, got %v", err)
+	}
+	if _, err := Progress(goal, money.MustNew(0, "USD"), nil, day(2026, 4, 10), 3); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
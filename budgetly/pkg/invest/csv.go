@@ -0,0 +1,75 @@
+package invest
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+// Price is a security's closing price on a day.
+type Price struct {
+	Symbol string
+	Date   time.Time
+	Close  money.Money
+}
+
+// ParsePricesCSV reads closing prices from a CSV file with a header row naming the
+// symbol, date, close and currency columns in any order. Dates use the YYYY-MM-DD
+// format and symbols are upper-cased.
+func ParsePricesCSV(r io.Reader) ([]Price, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"symbol", "date", "close", "currency"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %q column", name)
+		}
+	}
+
+	var prices []Price
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV line %d: %w", line, err)
+		}
+
+		symbol := strings.ToUpper(strings.TrimSpace(record[columns["symbol"]]))
+		if symbol == "" {
+			return nil, fmt.Errorf("missing symbol on CSV line %d", line)
+		}
+
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(record[columns["date"]]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid date on CSV line %d: %w", line, err)
+		}
+
+		currency := strings.ToUpper(strings.TrimSpace(record[columns["currency"]]))
+		price, err := money.Parse(strings.TrimSpace(record[columns["close"]]), currency)
+		if err != nil {
+			return nil, fmt.Errorf("CSV line %d: %w", line, err)
+		}
+		if price.IsNegative() {
+			return nil, fmt.Errorf("negative price on CSV line %d", line)
+		}
+		prices = append(prices, Price{Symbol: symbol, Date: date, Close: price})
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,328 @@
+// Package invest tracks investment lots and computes cost basis and gains from a
+// security's trade history.
+package invest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var (
+	ErrInvalidTrade    = errors.New("invalid trade")
+	ErrOversold        = errors.New("sell quantity exceeds holdings")
+	ErrUnknownMethod   = errors.New("unknown cost basis method")
+	ErrInvalidQuantity = errors.New("invalid quantity")
+)
+
+// quantityDecimals is the precision quantities are stored and formatted with.
+const quantityDecimals = 8
+
+// Method is how sold shares are matched to the lots they came from.
+type Method string
+
+const (
+	FIFO    Method = "fifo"
+	LIFO    Method = "lifo"
+	Average Method = "average"
+)
+
+// ParseMethod parses a cost basis method name, defaulting to FIFO when empty.
+func ParseMethod(name string) (Method, error) {
+	switch m := Method(strings.ToLower(name)); m {
+	case "":
+		return FIFO, nil
+	case FIFO, LIFO, Average:
+		return m, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownMethod, name)
+	}
+}
+
+// Kind is the type of an investment transaction.
+type Kind string
+
+const (
+	Buy      Kind = "buy"
+	Sell     Kind = "sell"
+	Dividend Kind = "dividend"
+	Split    Kind = "split"
+)
+
+// Trade is one investment transaction in a single security.
+//
+//   - Buy and Sell move Quantity shares at Price per share, with Fees added to the cost of
+//     a buy and deducted from the proceeds of a sell.
+//   - Dividend pays Amount in cash and does not change the lots.
+//   - Split multiplies every lot's quantity by SplitTo/SplitFrom, e.g. 2 for 1 is
+//     SplitTo 2, SplitFrom 1. Cost basis is unchanged.
+type Trade struct {
+	ID        int
+	Date      time.Time
+	Kind      Kind
+	Quantity  *big.Rat
+	Price     money.Money
+	Fees      money.Money
+	Amount    money.Money
+	SplitFrom int64
+	SplitTo   int64
+}
+
+// Lot is shares bought together, with their remaining quantity and cost basis.
+type Lot struct {
+	TradeID  int         `json:"trade_id"`
+	Date     time.Time   `json:"date"`
+	Quantity *big.Rat    `json:"-"`
+	Cost     money.Money `json:"cost"`
+}
+
+// Realization is the gain or loss of one sell.
+type Realization struct {
+	TradeID  int         `json:"trade_id"`
+	Date     time.Time   `json:"date"`
+	Quantity *big.Rat    `json:"-"`
+	Proceeds money.Money `json:"proceeds"`
+	Cost     money.Money `json:"cost"`
+	Gain     money.Money `json:"gain"`
+}
+
+// Position is the result of replaying a security's trades.
+type Position struct {
+	Lots      []Lot
+	Realized  []Realization
+	Dividends money.Money
+}
+
+// Replay applies trades in date order (ties in slice order) using the given method and
+// returns the open lots, realized gains and dividends. All amounts must be in currency.
+func Replay(trades []Trade, method Method, currency string) (*Position, error) {
+	zero, err := money.Zero(currency)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ParseMethod(string(method)); err != nil {
+		return nil, err
+	}
+
+	ordered := make([]Trade, len(trades))
+	copy(ordered, trades)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Date.Before(ordered[j].Date) })
+
+	position := &Position{Dividends: zero}
+	for _, trade := range ordered {
+		if err := validateTrade(trade, currency); err != nil {
+			return nil, err
+		}
+		switch trade.Kind {
+		case Buy:
+			cost, err := tradeValue(trade)
+			if err != nil {
+				return nil, err
+			}
+			if cost, err = cost.Add(fees(trade, zero)); err != nil {
+				return nil, err
+			}
+			position.Lots = append(position.Lots, Lot{TradeID: trade.ID, Date: trade.Date, Quantity: new(big.Rat).Set(trade.Quantity), Cost: cost})
+			if method == Average {
+				position.Lots = pool(position.Lots, zero)
+			}
+		case Sell:
+			realization, err := position.sell(trade, method, zero)
+			if err != nil {
+				return nil, err
+			}
+			position.Realized = append(position.Realized, realization)
+		case Dividend:
+			if position.Dividends, err = position.Dividends.Add(trade.Amount); err != nil {
+				return nil, err
+			}
+		case Split:
+			ratio := big.NewRat(trade.SplitTo, trade.SplitFrom)
+			for i := range position.Lots {
+				position.Lots[i].Quantity = new(big.Rat).Mul(position.Lots[i].Quantity, ratio)
+			}
+		}
+	}
+	return position, nil
+}
+
+// sell removes shares from the lots in method order and records the realized gain.
+func (p *Position) sell(trade Trade, method Method, zero money.Money) (Realization, error) {
+	if trade.Quantity.Cmp(p.Quantity()) > 0 {
+		return Realization{}, fmt.Errorf("%w: selling %s of %s on %s", ErrOversold,
+			FormatQuantity(trade.Quantity), FormatQuantity(p.Quantity()), trade.Date.Format(time.DateOnly))
+	}
+
+	proceeds, err := tradeValue(trade)
+	if err != nil {
+		return Realization{}, err
+	}
+	if proceeds, err = proceeds.Sub(fees(trade, zero)); err != nil {
+		return Realization{}, err
+	}
+
+	cost := zero
+	remaining := new(big.Rat).Set(trade.Quantity)
+	for remaining.Sign() > 0 {
+		i := 0
+		if method == LIFO {
+			i = len(p.Lots) - 1
+		}
+		lot := &p.Lots[i]
+
+		if remaining.Cmp(lot.Quantity) >= 0 {
+			// The whole lot goes, with exactly its remaining cost.
+			if cost, err = cost.Add(lot.Cost); err != nil {
+				return Realization{}, err
+			}
+			remaining.Sub(remaining, lot.Quantity)
+			p.Lots = append(p.Lots[:i], p.Lots[i+1:]...)
+			continue
+		}
+
+		share := new(big.Rat).Quo(remaining, lot.Quantity)
+		part, err := lot.Cost.MulRat(share)
+		if err != nil {
+			return Realization{}, err
+		}
+		if cost, err = cost.Add(part); err != nil {
+			return Realization{}, err
+		}
+		if lot.Cost, err = lot.Cost.Sub(part); err != nil {
+			return Realization{}, err
+		}
+		lot.Quantity = new(big.Rat).Sub(lot.Quantity, remaining)
+		remaining.SetInt64(0)
+	}
+
+	gain, err := proceeds.Sub(cost)
+	if err != nil {
+		return Realization{}, err
+	}
+	return Realization{TradeID: trade.ID, Date: trade.Date, Quantity: new(big.Rat).Set(trade.Quantity), Proceeds: proceeds, Cost: cost, Gain: gain}, nil
+}
+
+// Quantity returns the number of shares held.
+func (p *Position) Quantity() *big.Rat {
+	total := new(big.Rat)
+	for _, lot := range p.Lots {
+		total.Add(total, lot.Quantity)
+	}
+	return total
+}
+
+// CostBasis returns the total cost of the shares held.
+func (p *Position) CostBasis() money.Money {
+	basis, _ := money.Zero(p.Dividends.Currency())
+	for _, lot := range p.Lots {
+		basis, _ = basis.Add(lot.Cost)
+	}
+	return basis
+}
+
+// RealizedGain returns the sum of all realized gains and losses.
+func (p *Position) RealizedGain() money.Money {
+	gain, _ := money.Zero(p.Dividends.Currency())
+	for _, r := range p.Realized {
+		gain, _ = gain.Add(r.Gain)
+	}
+	return gain
+}
+
+// Valuation is the market value of a position at a price.
+type Valuation struct {
+	MarketValue    money.Money `json:"market_value"`
+	CostBasis      money.Money `json:"cost_basis"`
+	UnrealizedGain money.Money `json:"unrealized_gain"`
+}
+
+// Value prices the shares held.
+func (p *Position) Value(price money.Money) (Valuation, error) {
+	value, err := price.MulRat(p.Quantity())
+	if err != nil {
+		return Valuation{}, err
+	}
+	basis := p.CostBasis()
+	gain, err := value.Sub(basis)
+	if err != nil {
+		return Valuation{}, err
+	}
+	return Valuation{MarketValue: value, CostBasis: basis, UnrealizedGain: gain}, nil
+}
+
+// pool merges all lots into one, dated at the earliest purchase, for average cost.
+func pool(lots []Lot, zero money.Money) []Lot {
+	if len(lots) < 2 {
+		return lots
+	}
+	pooled := Lot{TradeID: lots[0].TradeID, Date: lots[0].Date, Quantity: new(big.Rat), Cost: zero}
+	for _, lot := range lots {
+		pooled.Quantity.Add(pooled.Quantity, lot.Quantity)
+		pooled.Cost, _ = pooled.Cost.Add(lot.Cost)
+	}
+	return []Lot{pooled}
+}
+
+// tradeValue returns quantity times price, rounded to the currency's minor unit.
+func tradeValue(trade Trade) (money.Money, error) {
+	return trade.Price.MulRat(trade.Quantity)
+}
+
+// fees returns the trade's fees, or zero when it has none.
+func fees(trade Trade, zero money.Money) money.Money {
+	if trade.Fees.Currency() == "" {
+		return zero
+	}
+	return trade.Fees
+}
+
+// validateTrade checks that a trade has the fields its kind needs in the right currency.
+func validateTrade(trade Trade, currency string) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s on %s %s", ErrInvalidTrade, trade.Kind, trade.Date.Format(time.DateOnly), reason)
+	}
+	switch trade.Kind {
+	case Buy, Sell:
+		if trade.Quantity == nil || trade.Quantity.Sign() <= 0 {
+			return invalid("needs a positive quantity")
+		}
+		if trade.Price.Currency() != currency || trade.Price.IsNegative() {
+			return invalid("needs a non-negative price in " + currency)
+		}
+		if trade.Fees.Currency() != "" && (trade.Fees.Currency() != currency || trade.Fees.IsNegative()) {
+			return invalid("needs non-negative fees in " + currency)
+		}
+	case Dividend:
+		if trade.Amount.Currency() != currency || !trade.Amount.IsPositive() {
+			return invalid("needs a positive amount in " + currency)
+		}
+	case Split:
+		if trade.SplitFrom <= 0 || trade.SplitTo <= 0 {
+			return invalid("needs a positive split ratio")
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidTrade, trade.Kind)
+	}
+	return nil
+}
+
+// ParseQuantity parses a decimal share quantity such as "12.5".
+func ParseQuantity(value string) (*big.Rat, error) {
+	quantity, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || strings.ContainsAny(value, "/eE") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidQuantity, value)
+	}
+	return quantity, nil
+}
+
+// FormatQuantity formats a quantity with up to eight decimals and no trailing zeros.
+func FormatQuantity(quantity *big.Rat) string {
+	s := quantity.FloatString(quantityDecimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
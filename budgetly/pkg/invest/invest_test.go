@@ -0,0 +1,168 @@
+package invest
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func usd(amount int64) money.Money {
+	return money.MustNew(amount, "USD")
+}
+
+func qty(s string) *big.Rat {
+	q, err := ParseQuantity(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// trades buys 10 at 100.00 with 5.00 fees, 10 at 120.00, then sells 15 at 130.00 with 5.00 fees.
+func trades() []Trade {
+	return []Trade{
+		{ID: 3, Date: day(3, 1), Kind: Sell, Quantity: qty("15"), Price: usd(13000), Fees: usd(500)},
+		{ID: 1, Date: day(1, 10), Kind: Buy, Quantity: qty("10"), Price: usd(10000), Fees: usd(500)},
+		{ID: 2, Date: day(2, 10), Kind: Buy, Quantity: qty("10"), Price: usd(12000)},
+		{ID: 4, Date: day(3, 15), Kind: Dividend, Amount: usd(250)},
+	}
+}
+
+func TestReplayMethods(t *testing.T) {
+	tests := []struct {
+		method   Method
+		cost     int64
+		basis    int64
+		realized int64
+	}{
+		// The first lot (1005.00) and half the second (600.00).
+		{FIFO, 160500, 60000, 34000},
+		// The second lot (1200.00) and half the first (502.50).
+		{LIFO, 170250, 50250, 24250},
+		// 15 of 20 shares pooled at 2205.00.
+		{Average, 165375, 55125, 29125},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			position, err := Replay(trades(), tt.method, "USD")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := FormatQuantity(position.Quantity()); got != "5" {
+				t.Errorf("Expected 5 shares, got %s", got)
+			}
+			if len(position.Realized) != 1 {
+				t.Fatalf("Expected one realization, got %+v", position.Realized)
+			}
+			r := position.Realized[0]
+			if r.Proceeds.Amount() != 194500 || r.Cost.Amount() != tt.cost || r.Gain.Amount() != tt.realized {
+				t.Errorf("Unexpected realization: proceeds %s, cost %s, gain %s", r.Proceeds, r.Cost, r.Gain)
+			}
+			if position.CostBasis().Amount() != tt.basis {
+				t.Errorf("Expected cost basis %d, got %s", tt.basis, position.CostBasis())
+			}
+			if position.RealizedGain().Amount() != tt.realized {
+				t.Errorf("Expected realized gain %d, got %s", tt.realized, position.RealizedGain())
+			}
+			if position.Dividends.Amount() != 250 {
+				t.Errorf("Expected 2.50 in dividends, got %s", position.Dividends)
+			}
+		})
+	}
+}
+
+func TestReplaySplitAndValue(t *testing.T) {
+	history := []Trade{
+		{ID: 1, Date: day(1, 10), Kind: Buy, Quantity: qty("3"), Price: usd(30000)},
+		{ID: 2, Date: day(2, 1), Kind: Split, SplitFrom: 1, SplitTo: 3},
+		{ID: 3, Date: day(2, 20), Kind: Sell, Quantity: qty("2.5"), Price: usd(11000)},
+	}
+
+	position, err := Replay(history, FIFO, "USD")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := FormatQuantity(position.Quantity()); got != "6.5" {
+		t.Errorf("Expected 6.5 shares after the split and sale, got %s", got)
+	}
+	// 2.5 of 9 shares of a 900.00 lot cost 250.00.
+	if gain := position.Realized[0].Gain.Amount(); gain != 2500 {
+		t.Errorf("Expected a 25.00 gain, got %d", gain)
+	}
+
+	valuation, err := position.Value(usd(9000))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if valuation.MarketValue.Amount() != 58500 || valuation.CostBasis.Amount() != 65000 || valuation.UnrealizedGain.Amount() != -6500 {
+		t.Errorf("Unexpected valuation %+v", valuation)
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	oversold := []Trade{
+		{Date: day(1, 1), Kind: Buy, Quantity: qty("1"), Price: usd(100)},
+		{Date: day(1, 2), Kind: Sell, Quantity: qty("1.5"), Price: usd(100)},
+	}
+	if _, err := Replay(oversold, FIFO, "USD"); !errors.Is(err, ErrOversold) {
+		t.Errorf("Expected ErrOversold, got %v", err)
+	}
+
+	wrongCurrency := []Trade{{Date: day(1, 1), Kind: Buy, Quantity: qty("1"), Price: money.MustNew(100, "EUR")}}
+	if _, err := Replay(wrongCurrency, FIFO, "USD"); !errors.Is(err, ErrInvalidTrade) {
+		t.Errorf("Expected ErrInvalidTrade, got %v", err)
+	}
+
+	badSplit := []Trade{{Date: day(1, 1), Kind: Split, SplitTo: 2}}
+	if _, err := Replay(badSplit, FIFO, "USD"); !errors.Is(err, ErrInvalidTrade) {
+		t.Errorf("Expected ErrInvalidTrade, got %v", err)
+	}
+
+	if _, err := Replay(nil, "hifo", "USD"); !errors.Is(err, ErrUnknownMethod) {
+		t.Errorf("Expected ErrUnknownMethod, got %v", err)
+	}
+}
+
+func TestQuantity(t *testing.T) {
+	if got := FormatQuantity(qty("0012.50000")); got != "12.5" {
+		t.Errorf("Expected 12.5, got %s", got)
+	}
+	for _, value := range []string{"", "abc", "1/3", "1e3"} {
+		if _, err := ParseQuantity(value); !errors.Is(err, ErrInvalidQuantity) {
+			t.Errorf("Expected ErrInvalidQuantity for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestParsePricesCSV(t *testing.T) {
+	input := "Date,Symbol,Close,Currency\n2026-03-02, vwce ,118.42,EUR\n2026-03-03,AAPL,241,usd\n"
+	prices, err := ParsePricesCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prices) != 2 {
+		t.Fatalf("Expected 2 prices, got %d", len(prices))
+	}
+	if prices[0].Symbol != "VWCE" || !prices[0].Date.Equal(day(3, 2)) || prices[0].Close.String() != "118.42 EUR" {
+		t.Errorf("Unexpected first price %+v", prices[0])
+	}
+	if prices[1].Close.String() != "241.00 USD" {
+		t.Errorf("Unexpected second price %s", prices[1].Close)
+	}
+
+	if _, err := ParsePricesCSV(strings.NewReader("symbol,date,close\n")); err == nil {
+		t.Error("Expected an error for a missing currency column")
+	}
+	if _, err := ParsePricesCSV(strings.NewReader("symbol,date,close,currency\nAAPL,2026-03-02,-1,USD\n")); err == nil {
+		t.Error("Expected an error for a negative price")
+	}
+}
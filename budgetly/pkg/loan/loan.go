@@ -0,0 +1,296 @@
+// Package loan builds amortization schedules for fixed-rate installment loans such as
+// mortgages and car loans, and splits payments into interest and principal.
+package loan
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var (
+	ErrInvalidLoan      = errors.New("invalid loan")
+	ErrUnknownFrequency = errors.New("unknown payment frequency")
+	ErrCurrencyMismatch = errors.New("payment must be in the loan's currency")
+)
+
+// Frequency is how often loan payments are due.
+type Frequency string
+
+const (
+	FrequencyMonthly  Frequency = "monthly"
+	FrequencyBiweekly Frequency = "biweekly"
+	FrequencyWeekly   Frequency = "weekly"
+)
+
+// periodsPerYear returns the number of payments a year for the frequency.
+func (f Frequency) periodsPerYear() (int64, error) {
+	switch f {
+	case FrequencyMonthly:
+		return 12, nil
+	case FrequencyBiweekly:
+		return 26, nil
+	case FrequencyWeekly:
+		return 52, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownFrequency, f)
+	}
+}
+
+// dueDate returns the date of the payment with the given zero-based index. Monthly payments
+// keep the first payment's day of the month, moved to the last day of shorter months: a loan
+// first paid on January 31st is due on February 28th, then March 31st.
+func (f Frequency) dueDate(first time.Time, index int) time.Time {
+	switch f {
+	case FrequencyBiweekly:
+		return first.AddDate(0, 0, 14*index)
+	case FrequencyWeekly:
+		return first.AddDate(0, 0, 7*index)
+	default:
+		// The day before the next month starts is the target month's last day.
+		month := time.Date(first.Year(), first.Month()+time.Month(index), 1, 0, 0, 0, 0, first.Location())
+		lastDay := month.AddDate(0, 1, -1).Day()
+		return time.Date(month.Year(), month.Month(), min(first.Day(), lastDay),
+			first.Hour(), first.Minute(), first.Second(), first.Nanosecond(), first.Location())
+	}
+}
+
+// ExtraPayment is a one-off payment towards the principal on top of a scheduled payment.
+type ExtraPayment struct {
+	Date   time.Time   `json:"date"`
+	Amount money.Money `json:"amount"`
+}
+
+// Loan describes a fixed-rate installment loan.
+type Loan struct {
+	Principal money.Money
+	// APR is the nominal annual rate in percent, e.g. 4.5, compounded once per payment period.
+	APR *big.Rat
+	// TermMonths is the length of the loan, e.g. 360 for a 30 year mortgage.
+	TermMonths int
+	// FirstPayment is the due date of the first payment.
+	FirstPayment time.Time
+	Frequency    Frequency
+	// ExtraPerPayment is paid towards the principal with every scheduled payment.
+	ExtraPerPayment money.Money
+	// Extras are one-off extra payments, applied to the first payment due on or after their date.
+	Extras []ExtraPayment
+}
+
+// Installment is one row of an amortization schedule.
+type Installment struct {
+	Number    int         `json:"number"`
+	Date      time.Time   `json:"date"`
+	Payment   money.Money `json:"payment"`
+	Interest  money.Money `json:"interest"`
+	Principal money.Money `json:"principal"`
+	Extra     money.Money `json:"extra"`
+	Balance   money.Money `json:"balance"`
+}
+
+// Schedule is a loan's amortization schedule.
+type Schedule struct {
+	// Payment is the regular scheduled payment, excluding extras.
+	Payment       money.Money   `json:"payment"`
+	TotalInterest money.Money   `json:"total_interest"`
+	PayoffDate    time.Time     `json:"payoff_date"`
+	Installments  []Installment `json:"installments"`
+}
+
+// Payment returns the regular payment that pays the loan off over its term:
+// principal * r / (1 - (1 + r)^-n), rounded half to even to the minor unit.
+func (l Loan) Payment() (money.Money, error) {
+	if err := l.validate(); err != nil {
+		return money.Money{}, err
+	}
+
+	n, err := l.payments()
+	if err != nil {
+		return money.Money{}, err
+	}
+	rate, err := l.periodicRate()
+	if err != nil {
+		return money.Money{}, err
+	}
+	if rate.Sign() == 0 {
+		return l.Principal.MulRat(big.NewRat(1, int64(n)))
+	}
+
+	// (1 + r)^n computed exactly, then payment = principal * r * g / (g - 1).
+	growth := new(big.Rat).Add(big.NewRat(1, 1), rate)
+	power := new(big.Rat).SetFrac(
+		new(big.Int).Exp(growth.Num(), big.NewInt(int64(n)), nil),
+		new(big.Int).Exp(growth.Denom(), big.NewInt(int64(n)), nil),
+	)
+	factor := new(big.Rat).Mul(rate, power)
+	factor.Quo(factor, new(big.Rat).Sub(power, big.NewRat(1, 1)))
+	return l.Principal.MulRat(factor)
+}
+
+// Amortize builds the schedule. Each payment first covers the interest accrued on the
+// balance since the previous payment; the rest, and any extra, reduces the principal.
+// The last payment is adjusted to clear the balance exactly.
+func (l Loan) Amortize() (Schedule, error) {
+	payment, err := l.Payment()
+	if err != nil {
+		return Schedule{}, err
+	}
+	n, _ := l.payments()
+	rate, _ := l.periodicRate()
+
+	extras := append([]ExtraPayment(nil), l.Extras...)
+	sort.SliceStable(extras, func(i, j int) bool { return extras[i].Date.Before(extras[j].Date) })
+
+	zero, _ := money.Zero(l.Principal.Currency())
+	schedule := Schedule{Payment: payment, TotalInterest: zero, Installments: []Installment{}}
+	balance := l.Principal
+
+	for i := 0; balance.IsPositive() && i < n; i++ {
+		date := l.Frequency.dueDate(l.FirstPayment, i)
+		interest, principal, err := l.split(balance, rate, payment)
+		if err != nil {
+			return Schedule{}, err
+		}
+
+		// Collect the recurring extra and any one-off extras that are now due.
+		extra := zero
+		if l.ExtraPerPayment.Currency() != "" {
+			extra, _ = extra.Add(l.ExtraPerPayment)
+		}
+		for len(extras) > 0 && !extras[0].Date.After(date) {
+			extra, _ = extra.Add(extras[0].Amount)
+			extras = extras[1:]
+		}
+
+		// The final installment, or one made final by extras, pays exactly what is owed.
+		if i == n-1 || cmpMoney(principal, balance) >= 0 {
+			principal, extra = balance, zero
+		} else if remaining, _ := balance.Sub(principal); cmpMoney(extra, remaining) > 0 {
+			extra = remaining
+		}
+
+		paid, _ := interest.Add(principal)
+		paid, _ = paid.Add(extra)
+		balance, _ = balance.Sub(principal)
+		balance, _ = balance.Sub(extra)
+
+		schedule.TotalInterest, _ = schedule.TotalInterest.Add(interest)
+		schedule.PayoffDate = date
+		schedule.Installments = append(schedule.Installments, Installment{
+			Number:    i + 1,
+			Date:      date,
+			Payment:   paid,
+			Interest:  interest,
+			Principal: principal,
+			Extra:     extra,
+			Balance:   balance,
+		})
+	}
+	return schedule, nil
+}
+
+// Split divides a payment made against the given outstanding balance into interest for
+// one period and principal, e.g. to categorize an incoming payment transaction.
+// Payments smaller than the interest leave a negative principal (the balance grows).
+func (l Loan) Split(balance, payment money.Money) (interest, principal money.Money, err error) {
+	if balance.Currency() != l.Principal.Currency() || payment.Currency() != l.Principal.Currency() {
+		return money.Money{}, money.Money{}, ErrCurrencyMismatch
+	}
+	rate, err := l.periodicRate()
+	if err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	return l.split(balance, rate, payment)
+}
+
+// BalanceOn returns the balance the schedule projects after all payments due on or before date.
+func (s Schedule) BalanceOn(date time.Time, principal money.Money) money.Money {
+	balance := principal
+	for _, installment := range s.Installments {
+		if installment.Date.After(date) {
+			break
+		}
+		balance = installment.Balance
+	}
+	return balance
+}
+
+// Variance returns the bank's reported balance minus the projected balance on date.
+// A positive variance means the bank says more is owed than the schedule expects.
+func (l Loan) Variance(schedule Schedule, date time.Time, bankBalance money.Money) (money.Money, error) {
+	return bankBalance.Sub(schedule.BalanceOn(date, l.Principal))
+}
+
+// split divides a payment into the interest accrued on balance at rate and the principal.
+func (l Loan) split(balance money.Money, rate *big.Rat, payment money.Money) (money.Money, money.Money, error) {
+	interest, err := balance.MulRat(rate)
+	if err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	principal, err := payment.Sub(interest)
+	if err != nil {
+		return money.Money{}, money.Money{}, err
+	}
+	return interest, principal, nil
+}
+
+// payments returns the number of scheduled payments over the term.
+func (l Loan) payments() (int, error) {
+	perYear, err := l.Frequency.periodsPerYear()
+	if err != nil {
+		return 0, err
+	}
+	// Round to the nearest whole payment, e.g. 30 years of biweekly payments is 780.
+	return int((int64(l.TermMonths)*perYear + 6) / 12), nil
+}
+
+// periodicRate returns the interest rate per payment period as a fraction.
+func (l Loan) periodicRate() (*big.Rat, error) {
+	perYear, err := l.Frequency.periodsPerYear()
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Rat).Quo(l.APR, big.NewRat(100*perYear, 1)), nil
+}
+
+// validate checks that the loan can be amortized.
+func (l Loan) validate() error {
+	if !l.Principal.IsPositive() {
+		return fmt.Errorf("%w: principal must be greater than zero", ErrInvalidLoan)
+	}
+	if l.APR == nil || l.APR.Sign() < 0 {
+		return fmt.Errorf("%w: APR must not be negative", ErrInvalidLoan)
+	}
+	if l.TermMonths <= 0 {
+		return fmt.Errorf("%w: term must be at least one month", ErrInvalidLoan)
+	}
+	if l.FirstPayment.IsZero() {
+		return fmt.Errorf("%w: first payment date is required", ErrInvalidLoan)
+	}
+	if l.ExtraPerPayment.Currency() != "" && l.ExtraPerPayment.Currency() != l.Principal.Currency() {
+		return ErrCurrencyMismatch
+	}
+	if l.ExtraPerPayment.IsNegative() {
+		return fmt.Errorf("%w: extra payments must not be negative", ErrInvalidLoan)
+	}
+	for _, extra := range l.Extras {
+		if extra.Amount.Currency() != l.Principal.Currency() {
+			return ErrCurrencyMismatch
+		}
+		if extra.Amount.IsNegative() {
+			return fmt.Errorf("%w: extra payments must not be negative", ErrInvalidLoan)
+		}
+	}
+	_, err := l.Frequency.periodsPerYear()
+	return err
+}
+
+// cmpMoney compares two amounts in the same currency.
+func cmpMoney(a, b money.Money) int {
+	cmp, _ := a.Cmp(b)
+	return cmp
+}
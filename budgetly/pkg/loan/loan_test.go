@@ -0,0 +1,176 @@
+package loan
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var firstPayment = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+func usd(amount int64) money.Money {
+	return money.MustNew(amount, "USD")
+}
+
+func mortgage() Loan {
+	return Loan{
+		Principal:    usd(20000000),
+		APR:          big.NewRat(6, 1),
+		TermMonths:   360,
+		FirstPayment: firstPayment,
+		Frequency:    FrequencyMonthly,
+	}
+}
+
+// checkSchedule verifies that the principal and extras repay the loan exactly.
+func checkSchedule(t *testing.T, loan Loan, schedule Schedule) {
+	t.Helper()
+
+	repaid, _ := money.Zero(loan.Principal.Currency())
+	for _, installment := range schedule.Installments {
+		repaid, _ = repaid.Add(installment.Principal)
+		repaid, _ = repaid.Add(installment.Extra)
+	}
+	if !repaid.Equal(loan.Principal) {
+		t.Errorf("Expected %s to be repaid, got %s", loan.Principal, repaid)
+	}
+	if last := schedule.Installments[len(schedule.Installments)-1]; !last.Balance.IsZero() {
+		t.Errorf("Expected the last installment to clear the balance, got %s", last.Balance)
+	}
+}
+
+func TestPayment(t *testing.T) {
+	tests := []struct {
+		name     string
+		loan     Loan
+		expected int64
+	}{
+		{"30 year mortgage", mortgage(), 119910},
+		{"5 year car loan", Loan{Principal: usd(3000000), APR: big.NewRat(5, 1), TermMonths: 60, FirstPayment: firstPayment, Frequency: FrequencyMonthly}, 56614},
+		{"interest free", Loan{Principal: usd(2000000), APR: new(big.Rat), TermMonths: 48, FirstPayment: firstPayment, Frequency: FrequencyMonthly}, 41667},
+	}
+
+	for _, tt := range tests {
+		payment, err := tt.loan.Payment()
+		if err != nil || payment.Amount() != tt.expected {
+			t.Errorf("%s: expected %d, got %s (%v)", tt.name, tt.expected, payment, err)
+		}
+	}
+}
+
+func TestAmortize(t *testing.T) {
+	loan := mortgage()
+	schedule, err := loan.Amortize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(schedule.Installments) != 360 {
+		t.Fatalf("Expected 360 installments, got %d", len(schedule.Installments))
+	}
+	first := schedule.Installments[0]
+	if first.Interest.Amount() != 100000 || first.Principal.Amount() != 19910 || first.Balance.Amount() != 19980090 {
+		t.Errorf("Unexpected first installment %+v", first)
+	}
+	if !schedule.PayoffDate.Equal(time.Date(2056, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected payoff date %v", schedule.PayoffDate)
+	}
+	checkSchedule(t, loan, schedule)
+
+	// A biweekly schedule pays half-month installments 26 times a year.
+	loan.Frequency = FrequencyBiweekly
+	biweekly, err := loan.Amortize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(biweekly.Installments) != 780 || !biweekly.Installments[1].Date.Equal(firstPayment.AddDate(0, 0, 14)) {
+		t.Errorf("Unexpected biweekly schedule of %d installments", len(biweekly.Installments))
+	}
+	checkSchedule(t, loan, biweekly)
+}
+
+func TestMonthEndDueDates(t *testing.T) {
+	loan := mortgage()
+	loan.TermMonths = 14
+	loan.FirstPayment = time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+	schedule, err := loan.Amortize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[int]time.Time{
+		1:  time.Date(2027, 2, 28, 0, 0, 0, 0, time.UTC),
+		2:  time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC),
+		3:  time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+		13: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+	}
+	for index, date := range expected {
+		if got := schedule.Installments[index].Date; !got.Equal(date) {
+			t.Errorf("Expected installment %d on %s, got %s", index, date.Format(time.DateOnly), got.Format(time.DateOnly))
+		}
+	}
+	checkSchedule(t, loan, schedule)
+}
+
+func TestAmortizeWithExtras(t *testing.T) {
+	base, _ := mortgage().Amortize()
+
+	loan := mortgage()
+	loan.ExtraPerPayment = usd(10000)
+	loan.Extras = []ExtraPayment{{Date: time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC), Amount: usd(1000000)}}
+
+	schedule, err := loan.Amortize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkSchedule(t, loan, schedule)
+
+	if len(schedule.Installments) >= len(base.Installments) {
+		t.Errorf("Expected extras to shorten the loan, got %d installments", len(schedule.Installments))
+	}
+	if cmp, _ := schedule.TotalInterest.Cmp(base.TotalInterest); cmp >= 0 {
+		t.Errorf("Expected extras to save interest, got %s vs %s", schedule.TotalInterest, base.TotalInterest)
+	}
+	// The one-off extra lands on the first payment due after its date.
+	if extra := schedule.Installments[5].Extra; extra.Amount() != 1010000 {
+		t.Errorf("Expected the July installment to carry the one-off extra, got %s", extra)
+	}
+}
+
+func TestSplitAndVariance(t *testing.T) {
+	loan := mortgage()
+
+	interest, principal, err := loan.Split(usd(20000000), usd(130000))
+	if err != nil || interest.Amount() != 100000 || principal.Amount() != 30000 {
+		t.Errorf("Unexpected split %s/%s (%v)", interest, principal, err)
+	}
+	if _, _, err := loan.Split(usd(20000000), money.MustNew(130000, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+
+	schedule, _ := loan.Amortize()
+	if balance := schedule.BalanceOn(firstPayment.AddDate(0, 0, -1), loan.Principal); !balance.Equal(loan.Principal) {
+		t.Errorf("Expected the full principal before the first payment, got %s", balance)
+	}
+	variance, err := loan.Variance(schedule, firstPayment.AddDate(0, 0, 10), usd(19980000))
+	if err != nil || variance.Amount() != -90 {
+		t.Errorf("Expected the bank to report 0.90 less, got %s (%v)", variance, err)
+	}
+}
+
+func TestInvalidLoan(t *testing.T) {
+	loan := mortgage()
+	loan.TermMonths = 0
+	if _, err := loan.Amortize(); !errors.Is(err, ErrInvalidLoan) {
+		t.Errorf("Expected ErrInvalidLoan, got %v", err)
+	}
+
+	loan = mortgage()
+	loan.Frequency = "daily"
+	if _, err := loan.Amortize(); !errors.Is(err, ErrUnknownFrequency) {
+		t.Errorf("Expected ErrUnknownFrequency, got %v", err)
+	}
+}
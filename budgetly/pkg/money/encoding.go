@@ -0,0 +1,96 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// moneyJSON is the JSON representation of Money. The amount is a decimal string in
+// major units so that no precision is lost to floating point parsers.
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON implements json.Marshaler, e.g. {"amount":"12.30","currency":"EUR"}. The
+// zero value, which has no currency, is null.
+func (m Money) MarshalJSON() ([]byte, error) {
+	if m.currency == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.currency})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The amount may be a decimal string or a number,
+// and null leaves m unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var raw struct {
+		Amount   any    `json:"amount"`
+		Currency string `json:"currency"`
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	var amount string
+	switch v := raw.Amount.(type) {
+	case json.Number:
+		amount = v.String()
+	case string:
+		amount = v
+	default:
+		return fmt.Errorf("%w: amount must be a decimal string or number", ErrInvalidAmount)
+	}
+
+	parsed, err := Parse(amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, storing Money as text such as "12.30 EUR".
+func (m Money) Value() (driver.Value, error) {
+	if m.currency == "" {
+		return nil, nil
+	}
+	return m.String(), nil
+}
+
+// Scan implements sql.Scanner for values stored by Value.
+func (m *Money) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case nil:
+		*m = Money{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	amount, currency, ok := strings.Cut(strings.TrimSpace(text), " ")
+	if !ok {
+		return fmt.Errorf("%w: %q is not in \"<amount> <currency>\" form", ErrInvalidAmount, text)
+	}
+
+	parsed, err := Parse(amount, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,323 @@
+// Package money represents monetary amounts exactly as integer minor units of an ISO 4217 currency.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrOverflow         = errors.New("amount out of range")
+	ErrInvalidRatios    = errors.New("invalid allocation ratios")
+)
+
+// Money is an immutable amount in minor units (e.g. cents) of a currency.
+// The zero value has no currency and is only useful as a placeholder.
+type Money struct {
+	amount   int64
+	currency string
+}
+
+// New creates Money from an amount in minor units of a known ISO 4217 currency.
+func New(amount int64, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if !IsCurrency(currency) {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	return Money{amount: amount, currency: currency}, nil
+}
+
+// MustNew is like New but panics on an unknown currency. It is intended for constants and tests.
+func MustNew(amount int64, currency string) Money {
+	m, err := New(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Zero returns a zero amount of the currency.
+func Zero(currency string) (Money, error) {
+	return New(0, currency)
+}
+
+// Parse creates Money from a decimal string in major units such as "12.34" or "-5".
+// Digits beyond the currency's exponent are only accepted when they are zeros.
+func Parse(value, currency string) (Money, error) {
+	m, err := New(0, currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	value = strings.TrimSpace(value)
+	negative := false
+	if value != "" && (value[0] == '-' || value[0] == '+') {
+		negative = value[0] == '-'
+		value = value[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" && fraction == "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	exponent := Exponent(m.currency)
+	if len(fraction) > exponent {
+		if strings.Trim(fraction[exponent:], "0") != "" {
+			return Money{}, fmt.Errorf("%w: %q has more than %d decimals", ErrInvalidAmount, value, exponent)
+		}
+		fraction = fraction[:exponent]
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	digits := strings.TrimLeft(whole+fraction, "0")
+	if digits == "" {
+		return m, nil
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+		}
+	}
+
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok || !amount.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q", ErrOverflow, value)
+	}
+
+	m.amount = amount.Int64()
+	if negative {
+		m.amount = -m.amount
+	}
+	return m, nil
+}
+
+// FromRat creates Money from an exact amount in major units, rounding half to even
+// (banker's rounding) to the currency's minor unit.
+func FromRat(value *big.Rat, currency string) (Money, error) {
+	m, err := New(0, currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	minor := new(big.Rat).Mul(value, pow10(Exponent(m.currency)))
+	amount, err := RoundHalfEven(minor)
+	if err != nil {
+		return Money{}, err
+	}
+	m.amount = amount
+	return m, nil
+}
+
+// Amount returns the amount in minor units.
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+// Currency returns the ISO 4217 currency code.
+func (m Money) Currency() string {
+	return m.currency
+}
+
+// Rat returns the exact amount in major units.
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(m.amount), pow10(Exponent(m.currency)).Num())
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// IsNegative reports whether the amount is below zero.
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// IsPositive reports whether the amount is above zero.
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+// Add returns m + other. Both must be in the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	sum := m.amount + other.amount
+	if (other.amount > 0 && sum < m.amount) || (other.amount < 0 && sum > m.amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: sum, currency: m.currency}, nil
+}
+
+// Sub returns m - other. Both must be in the same currency.
+func (m Money) Sub(other Money) (Money, error) {
+	if other.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(Money{amount: -other.amount, currency: other.currency})
+}
+
+// Negate returns -m.
+func (m Money) Negate() Money {
+	return Money{amount: -m.amount, currency: m.currency}
+}
+
+// Abs returns the absolute value of m.
+func (m Money) Abs() Money {
+	if m.amount < 0 {
+		return m.Negate()
+	}
+	return m
+}
+
+// Cmp compares m and other and returns -1, 0 or +1. Both must be in the same currency.
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Equal reports whether m and other have the same amount and currency.
+func (m Money) Equal(other Money) bool {
+	return m == other
+}
+
+// MulRat returns m multiplied by a rational factor, rounding half to even.
+func (m Money) MulRat(factor *big.Rat) (Money, error) {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(m.amount), factor)
+	amount, err := RoundHalfEven(product)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{amount: amount, currency: m.currency}, nil
+}
+
+// Allocate splits m into parts proportional to the ratios without losing a minor unit.
+// Each part gets its rounded-down share, and the leftover units go one at a time to the
+// parts with the largest remainders (ties to the earliest part), so the parts always
+// sum to m and never differ from their exact share by a whole unit or more.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, fmt.Errorf("%w: at least one ratio is required", ErrInvalidRatios)
+	}
+
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("%w: ratios must not be negative", ErrInvalidRatios)
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+	if total.Sign() == 0 {
+		return nil, fmt.Errorf("%w: ratios must not all be zero", ErrInvalidRatios)
+	}
+
+	// Allocate the absolute amount and restore the sign afterwards.
+	amount := new(big.Int).Abs(big.NewInt(m.amount))
+
+	parts := make([]int64, len(ratios))
+	remainders := make([]*big.Int, len(ratios))
+	allocated := new(big.Int)
+	for i, ratio := range ratios {
+		share, remainder := new(big.Int).QuoRem(new(big.Int).Mul(amount, big.NewInt(ratio)), total, new(big.Int))
+		parts[i] = share.Int64()
+		remainders[i] = remainder
+		allocated.Add(allocated, share)
+	}
+
+	leftover := new(big.Int).Sub(amount, allocated).Int64()
+	order := make([]int, len(ratios))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]].Cmp(remainders[order[j]]) > 0
+	})
+	for i := int64(0); i < leftover; i++ {
+		parts[order[i]]++
+	}
+
+	result := make([]Money, len(parts))
+	for i, part := range parts {
+		if m.amount < 0 {
+			part = -part
+		}
+		result[i] = Money{amount: part, currency: m.currency}
+	}
+	return result, nil
+}
+
+// Decimal formats the amount in major units with the currency's number of decimals, e.g. "-12.30".
+func (m Money) Decimal() string {
+	exponent := Exponent(m.currency)
+	digits := new(big.Int).Abs(big.NewInt(m.amount)).String()
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	sign := ""
+	if m.amount < 0 {
+		sign = "-"
+	}
+	if exponent == 0 {
+		return sign + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+// String formats the amount with its currency code, e.g. "12.30 EUR".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.currency
+}
+
+// sameCurrency returns ErrCurrencyMismatch when the currencies differ.
+func (m Money) sameCurrency(other Money) error {
+	if m.currency != other.currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency)
+	}
+	return nil
+}
+
+// RoundHalfEven rounds a rational to the nearest integer, ties to even, returning
+// ErrOverflow when the result does not fit in an int64.
+func RoundHalfEven(value *big.Rat) (int64, error) {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+
+	// Compare twice the remainder with the denominator to decide the direction.
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+	if cmp := twice.Cmp(value.Denom()); cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1) {
+		if value.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return quotient.Int64(), nil
+}
+
+// pow10 returns 10^n as a rational.
+func pow10(n int) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
@@ -0,0 +1,199 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+func TestParseAndFormat(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		amount   int64
+		decimal  string
+	}{
+		{"12.34", "EUR", 1234, "12.34"},
+		{"-0.5", "usd", -50, "-0.50"},
+		{"1500", "JPY", 1500, "1500"},
+		{"1.250", "KWD", 1250, "1.250"},
+		{"0.001", "KWD", 1, "0.001"},
+		{"7.000", "EUR", 700, "7.00"},
+		{".99", "EUR", 99, "0.99"},
+	}
+
+	for _, tt := range tests {
+		m, err := Parse(tt.value, tt.currency)
+		if err != nil {
+			t.Errorf("Parse(%q, %q) returned error %v", tt.value, tt.currency, err)
+			continue
+		}
+		if m.Amount() != tt.amount || m.Decimal() != tt.decimal {
+			t.Errorf("Parse(%q, %q) = %d (%s); expected %d (%s)", tt.value, tt.currency, m.Amount(), m.Decimal(), tt.amount, tt.decimal)
+		}
+	}
+
+	if _, err := Parse("1.005", "EUR"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount for extra decimals, got %v", err)
+	}
+	if _, err := Parse("1.5", "JPY"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount for JPY decimals, got %v", err)
+	}
+	if _, err := Parse("10", "XXX"); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Expected ErrUnknownCurrency, got %v", err)
+	}
+	if _, err := Parse("99999999999999999999", "EUR"); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := MustNew(1050, "EUR"), MustNew(-300, "EUR")
+
+	sum, err := a.Add(b)
+	if err != nil || !sum.Equal(MustNew(750, "EUR")) {
+		t.Errorf("Expected 7.50 EUR, got %v, %v", sum, err)
+	}
+
+	diff, err := a.Sub(b)
+	if err != nil || !diff.Equal(MustNew(1350, "EUR")) {
+		t.Errorf("Expected 13.50 EUR, got %v, %v", diff, err)
+	}
+
+	if _, err := a.Add(MustNew(100, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := MustNew(math.MaxInt64, "EUR").Add(MustNew(1, "EUR")); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+
+	if cmp, _ := a.Cmp(b); cmp != 1 {
+		t.Errorf("Expected a > b, got %d", cmp)
+	}
+	if !b.Abs().Equal(MustNew(300, "EUR")) || !b.Negate().IsPositive() {
+		t.Errorf("Unexpected Abs/Negate of %v", b)
+	}
+}
+
+func TestBankersRounding(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		factor   *big.Rat
+		expected int64
+	}{
+		{5, big.NewRat(1, 2), 2},   // 2.5 -> 2
+		{7, big.NewRat(1, 2), 4},   // 3.5 -> 4
+		{-5, big.NewRat(1, 2), -2}, // -2.5 -> -2
+		{10, big.NewRat(1, 3), 3},  // 3.33 -> 3
+		{1000, big.NewRat(19, 100), 190},
+	}
+	for _, tt := range tests {
+		got, err := MustNew(tt.amount, "EUR").MulRat(tt.factor)
+		if err != nil || got.Amount() != tt.expected {
+			t.Errorf("%d * %v = %d, %v; expected %d", tt.amount, tt.factor, got.Amount(), err, tt.expected)
+		}
+	}
+
+	m, err := FromRat(big.NewRat(2345, 1000), "EUR")
+	if err != nil || m.Amount() != 234 {
+		t.Errorf("Expected 2.345 to round to 2.34, got %v, %v", m, err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	parts, err := MustNew(100, "EUR").Allocate(1, 1, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []int64{34, 33, 33}
+	for i, part := range parts {
+		if part.Amount() != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, parts)
+			break
+		}
+	}
+
+	parts, _ = MustNew(-1001, "EUR").Allocate(70, 20, 10)
+	var total int64
+	for _, part := range parts {
+		total += part.Amount()
+	}
+	if total != -1001 || parts[0].Amount() != -701 || parts[1].Amount() != -200 || parts[2].Amount() != -100 {
+		t.Errorf("Unexpected allocation %v", parts)
+	}
+
+	parts, _ = MustNew(4, "EUR").Allocate(1, 2)
+	if parts[0].Amount() != 1 || parts[1].Amount() != 3 {
+		t.Errorf("Expected the leftover cent to go to the largest remainder, got %v", parts)
+	}
+
+	if _, err := MustNew(5, "EUR").Allocate(0, 0); !errors.Is(err, ErrInvalidRatios) {
+		t.Errorf("Expected ErrInvalidRatios, got %v", err)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	data, err := json.Marshal(MustNew(-1230, "EUR"))
+	if err != nil || string(data) != `{"amount":"-12.30","currency":"EUR"}` {
+		t.Errorf("Unexpected JSON %s, %v", data, err)
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte(`{"amount":19.99,"currency":"usd"}`), &m); err != nil || !m.Equal(MustNew(1999, "USD")) {
+		t.Errorf("Expected 19.99 USD, got %v, %v", m, err)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"1.2345","currency":"EUR"}`), &m); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+
+	var holder struct {
+		Amount Money `json:"amount"`
+	}
+	data, err = json.Marshal(holder)
+	if err != nil || string(data) != `{"amount":null}` {
+		t.Errorf("Unexpected JSON for the zero value %s, %v", data, err)
+	}
+	if err := json.Unmarshal(data, &holder); err != nil || holder.Amount != (Money{}) {
+		t.Errorf("Expected the zero value to round-trip, got %v, %v", holder.Amount, err)
+	}
+
+	value, err := MustNew(1250, "KWD").Value()
+	if err != nil || value != "1.250 KWD" {
+		t.Errorf("Unexpected SQL value %v, %v", value, err)
+	}
+	if err := m.Scan([]byte("1.250 KWD")); err != nil || !m.Equal(MustNew(1250, "KWD")) {
+		t.Errorf("Expected 1.250 KWD, got %v, %v", m, err)
+	}
+}
+
+func TestValidationRules(t *testing.T) {
+	data := struct {
+		Price  Money
+		Refund Money
+		Fee    Money
+	}{
+		Price:  MustNew(100, "EUR"),
+		Refund: MustNew(-100, "EUR"),
+		Fee:    MustNew(50, "USD"),
+	}
+
+	errors := validate.Validate(data, validate.ValidationFields{
+		"Price":  validate.Rules(Valid, Positive, InCurrency("EUR")),
+		"Refund": validate.Rules(Valid, NonNegative),
+		"Fee":    validate.Rules(Valid, InCurrency("eur")),
+	})
+
+	if len(errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errors)
+	}
+	if errors["Refund"] != "Refund must not be negative" {
+		t.Errorf("Unexpected Refund error %q", errors["Refund"])
+	}
+	if errors["Fee"] != "Fee must be in EUR" {
+		t.Errorf("Unexpected Fee error %q", errors["Fee"])
+	}
+}
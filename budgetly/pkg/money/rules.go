@@ -0,0 +1,67 @@
+package money
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ZiadMansourM/budgetly/pkg/validate"
+)
+
+// Valid rule ensures that the field is Money with a known currency.
+func Valid() validate.ValidationRule {
+	return validate.ValidationRule{
+		Name: "money",
+		ErrorMessageFunc: func(rule validate.ValidationRule) string {
+			return fmt.Sprintf("%s must be a valid amount", rule.FieldName)
+		},
+		ValidationFunc: func(rule validate.ValidationRule) bool {
+			m, ok := rule.FieldValue.(Money)
+			return ok && IsCurrency(m.currency)
+		},
+	}
+}
+
+// Positive rule ensures that the field is Money greater than zero.
+func Positive() validate.ValidationRule {
+	return validate.ValidationRule{
+		Name: "positive",
+		ErrorMessageFunc: func(rule validate.ValidationRule) string {
+			return fmt.Sprintf("%s must be greater than zero", rule.FieldName)
+		},
+		ValidationFunc: func(rule validate.ValidationRule) bool {
+			m, ok := rule.FieldValue.(Money)
+			return ok && m.IsPositive()
+		},
+	}
+}
+
+// NonNegative rule ensures that the field is Money of zero or more.
+func NonNegative() validate.ValidationRule {
+	return validate.ValidationRule{
+		Name: "non_negative",
+		ErrorMessageFunc: func(rule validate.ValidationRule) string {
+			return fmt.Sprintf("%s must not be negative", rule.FieldName)
+		},
+		ValidationFunc: func(rule validate.ValidationRule) bool {
+			m, ok := rule.FieldValue.(Money)
+			return ok && !m.IsNegative()
+		},
+	}
+}
+
+// InCurrency rule validates that the field is Money in the given currency.
+func InCurrency(currency string) validate.ValidationRuleFunc {
+	return func() validate.ValidationRule {
+		return validate.ValidationRule{
+			Name:      "currency",
+			RuleValue: strings.ToUpper(currency),
+			ErrorMessageFunc: func(rule validate.ValidationRule) string {
+				return fmt.Sprintf("%s must be in %s", rule.FieldName, rule.RuleValue)
+			},
+			ValidationFunc: func(rule validate.ValidationRule) bool {
+				m, ok := rule.FieldValue.(Money)
+				return ok && m.currency == rule.RuleValue.(string)
+			},
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package payees normalizes raw payee strings from bank imports and resolves them to
+// canonical payees through alias patterns.
+package payees
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ZiadMansourM/budgetly/pkg/statement"
+)
+
+var ErrInvalidPattern = errors.New("invalid alias pattern")
+
+var (
+	// datePattern matches dates such as 12/03, 12.03.24, 2024-03-12 and 03/12/2024.
+	datePattern = regexp.MustCompile(`\b(\d{4}-\d{2}-\d{2}|\d{1,2}[./-]\d{1,2}(?:[./-]\d{2,4})?)\b`)
+	// cardPattern matches masked or partial card numbers such as XXXX1234, ****1234 and
+	// "CARD 1234".
+	cardPattern = regexp.MustCompile(`(?i)\b(?:card|crd)\s*(?:no\.?|#)?\s*\d{4}\b|(?:[x*]{2,}[\s-]?)+\d{2,4}\b`)
+	// storePattern matches store numbers such as "#123" and "STORE 0412".
+	storePattern = regexp.MustCompile(`(?i)#\s*\d+|\b(?:store|str|no)\.?\s*\d+\b`)
+	// codePattern matches tokens containing digits, such as "2K4L", "AB12" or "000123".
+	codePattern = regexp.MustCompile(`\b[\pL\d]*\d[\pL\d]*\b`)
+	// domainPattern matches a domain suffix such as ".com" or ".co.uk".
+	domainPattern = regexp.MustCompile(`(?i)\.(?:com|net|org|co|io|de|fr|nl|es|it)(?:\.[a-z]{2})?\b`)
+)
+
+// processors are payment processor prefixes that precede the merchant, as in
+// "SQ *BLUE BOTTLE" or "PAYPAL *SPOTIFY".
+var processors = map[string]bool{
+	"sq": true, "tst": true, "paypal": true, "pp": true, "sp": true, "sumup": true, "zettle": true, "izettle": true,
+}
+
+// locationSuffixes are trailing US state and country codes, e.g. "SEATTLE WA" or "LONDON GB".
+var locationSuffixes = map[string]bool{
+	"al": true, "ak": true, "az": true, "ar": true, "ca": true, "co": true, "ct": true, "de": true, "fl": true,
+	"ga": true, "hi": true, "id": true, "il": true, "in": true, "ia": true, "ks": true, "ky": true, "la": true,
+	"me": true, "md": true, "ma": true, "mi": true, "mn": true, "ms": true, "mo": true, "mt": true, "ne": true,
+	"nv": true, "nh": true, "nj": true, "nm": true, "ny": true, "nc": true, "nd": true, "oh": true, "ok": true,
+	"or": true, "pa": true, "ri": true, "sc": true, "sd": true, "tn": true, "tx": true, "ut": true, "vt": true,
+	"va": true, "wa": true, "wv": true, "wi": true, "wy": true, "dc": true,
+	"us": true, "usa": true, "gb": true, "uk": true, "fr": true, "nl": true, "es": true, "it": true, "ie": true,
+	"at": true, "ch": true, "be": true, "lu": true, "eg": true,
+}
+
+// Normalize cleans a raw payee string from a bank import: it takes the merchant out of
+// payment processor prefixes and strips card numbers, dates, reference codes, domain
+// suffixes and trailing state or country codes, then title-cases what is left.
+// "AMZN Mktp US*2K4L" becomes "Amzn Mktp" and "AMAZON.COM*AB12" becomes "Amazon".
+// When nothing would be left, the trimmed input is returned unchanged.
+func Normalize(raw string) string {
+	s := strings.TrimSpace(raw)
+
+	// "SQ *MERCHANT": the merchant follows the star. Otherwise what follows a star is a
+	// reference code.
+	if before, after, ok := strings.Cut(s, "*"); ok {
+		if processors[strings.ToLower(strings.TrimSpace(before))] {
+			s = after
+		} else {
+			s = before + " " + after
+		}
+	}
+
+	s = cardPattern.ReplaceAllString(s, " ")
+	s = datePattern.ReplaceAllString(s, " ")
+	s = domainPattern.ReplaceAllString(s, " ")
+	s = storePattern.ReplaceAllString(s, " ")
+	// Short tokens such as the "7" of "7-Eleven" are part of the name, not a reference.
+	s = codePattern.ReplaceAllStringFunc(s, func(code string) string {
+		if len(code) < 3 {
+			return code
+		}
+		return " "
+	})
+
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&' && r != '\''
+	})
+	for len(words) > 1 && locationSuffixes[strings.ToLower(words[len(words)-1])] {
+		words = words[:len(words)-1]
+	}
+	if len(words) == 0 {
+		return strings.Join(strings.Fields(raw), " ")
+	}
+
+	for i, word := range words {
+		words[i] = titleCase(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// Key is the form payee names and patterns are compared in: lowercase letters and digits
+// with single spaces.
+func Key(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+func titleCase(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// Payee is a canonical payee with an optional default category for its transactions.
+type Payee struct {
+	ID       int
+	Name     string
+	Category string
+}
+
+// Alias maps payee names matching Pattern to a canonical payee. Patterns are compared
+// case-insensitively against both the raw and the normalized name, and may use * to
+// match any run of characters, e.g. "amzn*" or "*amazon*".
+type Alias struct {
+	PayeeID int
+	Pattern string
+}
+
+// CompilePattern checks an alias pattern and returns its matcher.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	literal := false
+	b.WriteString("^")
+	for i, part := range strings.Split(pattern, "*") {
+		if i > 0 {
+			b.WriteString(".*")
+		}
+		if key := Key(part); key != "" {
+			b.WriteString(regexp.QuoteMeta(key))
+			literal = true
+		}
+	}
+	b.WriteString("$")
+
+	if !literal {
+		return nil, fmt.Errorf("%w: %q must contain letters or digits", ErrInvalidPattern, pattern)
+	}
+	return regexp.Compile(b.String())
+}
+
+// Match is the result of resolving a raw payee name.
+type Match struct {
+	// Normalized is the cleaned name, used as the payee name when nothing matched.
+	Normalized string
+	// Payee is the canonical payee, or nil when no payee or alias matched.
+	Payee *Payee
+	// Pattern is the alias pattern that matched, empty for exact name matches.
+	Pattern string
+}
+
+type compiledAlias struct {
+	payeeID int
+	pattern string
+	re      *regexp.Regexp
+}
+
+// Directory resolves raw payee names to canonical payees.
+type Directory struct {
+	payees  map[int]*Payee
+	names   map[string]*Payee
+	aliases []compiledAlias
+}
+
+// NewDirectory builds a directory from payees and their aliases. Aliases of unknown payees
+// are ignored; invalid patterns are an error.
+func NewDirectory(payees []Payee, aliases []Alias) (*Directory, error) {
+	d := &Directory{payees: map[int]*Payee{}, names: map[string]*Payee{}}
+	for i := range payees {
+		p := &payees[i]
+		d.payees[p.ID] = p
+		d.names[Key(p.Name)] = p
+	}
+	for _, alias := range aliases {
+		if _, ok := d.payees[alias.PayeeID]; !ok {
+			continue
+		}
+		re, err := CompilePattern(alias.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		d.aliases = append(d.aliases, compiledAlias{payeeID: alias.PayeeID, pattern: alias.Pattern, re: re})
+	}
+
+	// Try the most specific patterns first: those with the most literal characters.
+	sort.SliceStable(d.aliases, func(i, j int) bool {
+		return literalLength(d.aliases[i].pattern) > literalLength(d.aliases[j].pattern)
+	})
+	return d, nil
+}
+
+// Resolve normalizes a raw payee name and finds its canonical payee: first by exact name,
+// then through the most specific matching alias.
+func (d *Directory) Resolve(raw string) Match {
+	match := Match{Normalized: Normalize(raw)}
+	normalized, original := Key(match.Normalized), Key(raw)
+
+	if p, ok := d.names[normalized]; ok {
+		match.Payee = p
+		return match
+	}
+	if p, ok := d.names[original]; ok {
+		match.Payee = p
+		return match
+	}
+	for _, alias := range d.aliases {
+		if alias.re.MatchString(normalized) || alias.re.MatchString(original) {
+			match.Payee = d.payees[alias.payeeID]
+			match.Pattern = alias.pattern
+			return match
+		}
+	}
+	return match
+}
+
+// NormalizeLines is the payee step of the import pipeline. It rewrites each line's
+// counterparty name to its canonical payee, or to the normalized name when no payee
+// matches, and returns the matches so callers can apply the payees' default categories.
+func (d *Directory) NormalizeLines(lines []statement.Line) []Match {
+	matches := make([]Match, len(lines))
+	for i := range lines {
+		if lines[i].CounterpartyName == "" {
+			continue
+		}
+		matches[i] = d.Resolve(lines[i].CounterpartyName)
+		lines[i].CounterpartyName = matches[i].Normalized
+		if matches[i].Payee != nil {
+			lines[i].CounterpartyName = matches[i].Payee.Name
+		}
+	}
+	return matches
+}
+
+func literalLength(pattern string) int {
+	return len(Key(strings.ReplaceAll(pattern, "*", "")))
+}
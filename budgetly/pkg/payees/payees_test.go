@@ -0,0 +1,104 @@
+package payees
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ZiadMansourM/budgetly/pkg/statement"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"AMZN Mktp US*2K4L":                        "Amzn Mktp",
+		"AMAZON.COM*AB12":                          "Amazon",
+		"SQ *BLUE BOTTLE COFFEE":                   "Blue Bottle Coffee",
+		"PAYPAL *SPOTIFY":                          "Spotify",
+		"STARBUCKS STORE 12345 SEATTLE WA":         "Starbucks Seattle",
+		"REWE SAGT DANKE 12.03.24 XXXX1234":        "Rewe Sagt Danke",
+		"7-ELEVEN #3344":                           "7 Eleven",
+		"Netflix.com":                              "Netflix",
+		"LIDL DIENSTLEISTUNG 2024-03-12 CARD 4821": "Lidl Dienstleistung",
+		"123456": "123456",
+	}
+	for raw, want := range tests {
+		if got := Normalize(raw); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestCompilePattern(t *testing.T) {
+	re, err := CompilePattern("AMZN*")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !re.MatchString(Key("Amzn Mktp")) || re.MatchString(Key("Zamzn")) {
+		t.Errorf("Unexpected matches for %s", re)
+	}
+	if _, err := CompilePattern("**"); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("Expected ErrInvalidPattern, got %v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	directory, err := NewDirectory(
+		[]Payee{
+			{ID: 1, Name: "Amazon", Category: "Shopping"},
+			{ID: 2, Name: "Amazon Prime", Category: "Subscriptions"},
+			{ID: 3, Name: "Spotify", Category: "Subscriptions"},
+		},
+		[]Alias{
+			{PayeeID: 1, Pattern: "amzn*"},
+			{PayeeID: 2, Pattern: "amzn prime*"},
+			{PayeeID: 9, Pattern: "orphan*"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		raw     string
+		payee   int
+		pattern string
+	}{
+		{"AMZN Mktp US*2K4L", 1, "amzn*"},
+		{"AMAZON.COM*AB12", 1, ""},
+		{"AMZN PRIME*1X2Y3", 2, "amzn prime*"},
+		{"PAYPAL *SPOTIFY", 3, ""},
+	}
+	for _, tt := range tests {
+		match := directory.Resolve(tt.raw)
+		if match.Payee == nil || match.Payee.ID != tt.payee || match.Pattern != tt.pattern {
+			t.Errorf("Resolve(%q) = %+v, want payee %d via %q", tt.raw, match, tt.payee, tt.pattern)
+		}
+	}
+
+	match := directory.Resolve("CORNER BAKERY 0412")
+	if match.Payee != nil || match.Normalized != "Corner Bakery" {
+		t.Errorf("Expected an unmatched, normalized payee, got %+v", match)
+	}
+}
+
+func TestNormalizeLines(t *testing.T) {
+	directory, err := NewDirectory([]Payee{{ID: 1, Name: "Amazon", Category: "Shopping"}}, []Alias{{PayeeID: 1, Pattern: "amzn*"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := []statement.Line{
+		{CounterpartyName: "AMZN Mktp US*2K4L"},
+		{CounterpartyName: "CORNER BAKERY 0412"},
+		{RemittanceInfo: "Interest"},
+	}
+	matches := directory.NormalizeLines(lines)
+	if lines[0].CounterpartyName != "Amazon" || matches[0].Payee.Category != "Shopping" {
+		t.Errorf("Expected the canonical payee, got %q", lines[0].CounterpartyName)
+	}
+	if lines[1].CounterpartyName != "Corner Bakery" || matches[1].Payee != nil {
+		t.Errorf("Expected the normalized name, got %q", lines[1].CounterpartyName)
+	}
+	if lines[2].CounterpartyName != "" {
+		t.Errorf("Expected lines without a counterparty to be left alone, got %q", lines[2].CounterpartyName)
+	}
+}
@@ -0,0 +1,227 @@
+// Package reconcile matches an account's cleared transactions against a bank statement balance.
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var (
+	ErrSessionFinished     = errors.New("reconciliation session is already finished")
+	ErrUnknownTransaction  = errors.New("transaction is not part of this reconciliation")
+	ErrAfterStatementDate  = errors.New("transaction is dated after the statement")
+	ErrUnbalanced          = errors.New("cleared balance does not match the statement balance")
+	ErrReconciled          = errors.New("transaction is reconciled and can only be changed when forced")
+	ErrStatementBeforeLast = errors.New("statement date is before the last reconciliation")
+)
+
+// Status is the clearing state of a transaction.
+type Status string
+
+const (
+	// StatusUncleared transactions have not shown up on a statement yet.
+	StatusUncleared Status = "uncleared"
+	// StatusCleared transactions have shown up on a statement but are not reconciled yet.
+	StatusCleared Status = "cleared"
+	// StatusReconciled transactions were locked by a finished reconciliation.
+	StatusReconciled Status = "reconciled"
+)
+
+// Transaction is the subset of a transaction reconciliation needs.
+type Transaction struct {
+	ID     string      `json:"id"`
+	Date   time.Time   `json:"date"`
+	Payee  string      `json:"payee"`
+	Amount money.Money `json:"amount"`
+	Status Status      `json:"status"`
+}
+
+// Account is the reconciliation state of an account: its currency, the balance of its
+// reconciled transactions, and the statement date of its last reconciliation.
+type Account struct {
+	ID                 string      `json:"id"`
+	ReconciledBalance  money.Money `json:"reconciled_balance"`
+	LastReconciledDate time.Time   `json:"last_reconciled_date"`
+}
+
+// Session reconciles one account against one statement. Transactions are toggled between
+// cleared and uncleared until the cleared balance matches the statement ending balance.
+type Session struct {
+	AccountID        string
+	StatementDate    time.Time
+	StatementBalance money.Money
+
+	openingBalance money.Money
+	transactions   map[string]*Transaction
+	finished       bool
+}
+
+// NewSession starts reconciling the account against a statement. Transactions already
+// reconciled are ignored; the others start with their current status, so transactions
+// cleared while importing count towards the cleared balance straight away. Transactions
+// dated after the statement start uncleared whatever their status, as they cannot be on it.
+func NewSession(account Account, statementDate time.Time, statementBalance money.Money, transactions []Transaction) (*Session, error) {
+	if statementBalance.Currency() != account.ReconciledBalance.Currency() {
+		return nil, fmt.Errorf("%w: statement in %s, account in %s",
+			money.ErrCurrencyMismatch, statementBalance.Currency(), account.ReconciledBalance.Currency())
+	}
+	if statementDate.Before(account.LastReconciledDate) {
+		return nil, fmt.Errorf("%w on %s", ErrStatementBeforeLast, account.LastReconciledDate.Format(time.DateOnly))
+	}
+
+	session := &Session{
+		AccountID:        account.ID,
+		StatementDate:    statementDate,
+		StatementBalance: statementBalance,
+		openingBalance:   account.ReconciledBalance,
+		transactions:     make(map[string]*Transaction),
+	}
+	for _, tx := range transactions {
+		if tx.Status == StatusReconciled {
+			continue
+		}
+		if tx.Amount.Currency() != statementBalance.Currency() {
+			return nil, fmt.Errorf("%w: transaction %s in %s", money.ErrCurrencyMismatch, tx.ID, tx.Amount.Currency())
+		}
+		if tx.Status != StatusCleared || tx.Date.After(statementDate) {
+			tx.Status = StatusUncleared
+		}
+		session.transactions[tx.ID] = &tx
+	}
+	return session, nil
+}
+
+// Toggle flips a transaction between cleared and uncleared and returns its new status.
+// Transactions dated after the statement cannot be cleared against it.
+func (s *Session) Toggle(id string) (Status, error) {
+	if s.finished {
+		return "", ErrSessionFinished
+	}
+
+	tx, ok := s.transactions[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownTransaction, id)
+	}
+
+	if tx.Status == StatusCleared {
+		tx.Status = StatusUncleared
+		return tx.Status, nil
+	}
+	if tx.Date.After(s.StatementDate) {
+		return "", fmt.Errorf("%w: %s", ErrAfterStatementDate, id)
+	}
+	tx.Status = StatusCleared
+	return tx.Status, nil
+}
+
+// Transactions returns the transactions under reconciliation ordered by date.
+func (s *Session) Transactions() []Transaction {
+	transactions := make([]Transaction, 0, len(s.transactions))
+	for _, tx := range s.transactions {
+		transactions = append(transactions, *tx)
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		if !transactions[i].Date.Equal(transactions[j].Date) {
+			return transactions[i].Date.Before(transactions[j].Date)
+		}
+		return transactions[i].ID < transactions[j].ID
+	})
+	return transactions
+}
+
+// ClearedBalance returns the reconciled balance plus every cleared transaction.
+func (s *Session) ClearedBalance() (money.Money, error) {
+	balance := s.openingBalance
+	for _, tx := range s.transactions {
+		if tx.Status != StatusCleared {
+			continue
+		}
+		var err error
+		if balance, err = balance.Add(tx.Amount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return balance, nil
+}
+
+// Difference returns the statement balance minus the cleared balance. The account is
+// reconciled when it reaches zero.
+func (s *Session) Difference() (money.Money, error) {
+	cleared, err := s.ClearedBalance()
+	if err != nil {
+		return money.Money{}, err
+	}
+	return s.StatementBalance.Sub(cleared)
+}
+
+// Adjustment is a transaction posted to absorb a difference that could not be explained.
+type Adjustment struct {
+	Date   time.Time   `json:"date"`
+	Amount money.Money `json:"amount"`
+	Memo   string      `json:"memo"`
+}
+
+// Result is the outcome of a finished reconciliation.
+type Result struct {
+	// Reconciled holds the IDs of the transactions to lock as reconciled.
+	Reconciled []string `json:"reconciled"`
+	// Adjustment is set when a leftover difference was posted.
+	Adjustment *Adjustment `json:"adjustment,omitempty"`
+	// Account is the account's reconciliation state after finishing.
+	Account Account `json:"account"`
+}
+
+// Finish ends the session and returns the transactions to lock. A leftover difference is
+// rejected with ErrUnbalanced unless adjust is set, in which case it is posted as an
+// adjustment dated on the statement date.
+func (s *Session) Finish(adjust bool) (Result, error) {
+	if s.finished {
+		return Result{}, ErrSessionFinished
+	}
+
+	difference, err := s.Difference()
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Reconciled: []string{},
+		Account: Account{
+			ID:                 s.AccountID,
+			ReconciledBalance:  s.StatementBalance,
+			LastReconciledDate: s.StatementDate,
+		},
+	}
+	if !difference.IsZero() {
+		if !adjust {
+			return Result{}, fmt.Errorf("%w: %s left", ErrUnbalanced, difference)
+		}
+		result.Adjustment = &Adjustment{
+			Date:   s.StatementDate,
+			Amount: difference,
+			Memo:   "Reconciliation balance adjustment",
+		}
+	}
+
+	for _, tx := range s.Transactions() {
+		if tx.Status == StatusCleared {
+			result.Reconciled = append(result.Reconciled, tx.ID)
+			s.transactions[tx.ID].Status = StatusReconciled
+		}
+	}
+	s.finished = true
+	return result, nil
+}
+
+// CheckEdit guards changes to a transaction. Reconciled transactions are part of a balance
+// the user agreed with the bank, so changing them is rejected unless force is set.
+func CheckEdit(tx Transaction, force bool) error {
+	if tx.Status == StatusReconciled && !force {
+		return fmt.Errorf("%w: %s", ErrReconciled, tx.ID)
+	}
+	return nil
+}
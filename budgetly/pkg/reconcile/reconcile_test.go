@@ -0,0 +1,146 @@
+package reconcile
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func date(day int) time.Time {
+	return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC)
+}
+
+func newTestSession(t *testing.T, statementBalance int64) *Session {
+	account := Account{ID: "checking", ReconciledBalance: money.MustNew(100000, "EUR"), LastReconciledDate: date(1)}
+	transactions := []Transaction{
+		{ID: "rent", Date: date(3), Amount: money.MustNew(-80000, "EUR")},
+		{ID: "salary", Date: date(25), Amount: money.MustNew(250000, "EUR"), Status: StatusCleared},
+		{ID: "groceries", Date: date(28), Amount: money.MustNew(-4550, "EUR")},
+		{ID: "april", Date: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), Amount: money.MustNew(-1000, "EUR")},
+		// Cleared while importing a later statement, so it must not count towards this one.
+		{ID: "may", Date: time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC), Amount: money.MustNew(-2000, "EUR"), Status: StatusCleared},
+		{ID: "old", Date: date(1), Amount: money.MustNew(-500, "EUR"), Status: StatusReconciled},
+	}
+
+	session, err := NewSession(account, date(31), money.MustNew(statementBalance, "EUR"), transactions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return session
+}
+
+func TestSessionDifference(t *testing.T) {
+	session := newTestSession(t, 265450)
+
+	if len(session.Transactions()) != 5 {
+		t.Errorf("Expected reconciled transactions to be left out, got %d", len(session.Transactions()))
+	}
+
+	// The opening balance plus the salary cleared during import.
+	if difference, _ := session.Difference(); difference.Amount() != 265450-350000 {
+		t.Errorf("Unexpected initial difference %s", difference)
+	}
+
+	for _, id := range []string{"rent", "groceries"} {
+		if status, err := session.Toggle(id); err != nil || status != StatusCleared {
+			t.Fatalf("Toggle(%q) = %q, %v", id, status, err)
+		}
+	}
+	if difference, _ := session.Difference(); !difference.IsZero() {
+		t.Errorf("Expected no difference, got %s", difference)
+	}
+
+	if status, _ := session.Toggle("groceries"); status != StatusUncleared {
+		t.Errorf("Expected toggling twice to unclear, got %q", status)
+	}
+	if difference, _ := session.Difference(); difference.Amount() != -4550 {
+		t.Errorf("Expected the groceries to be missing, got %s", difference)
+	}
+
+	if status := session.Transactions()[4].Status; status != StatusUncleared {
+		t.Errorf("Expected a cleared transaction after the statement to start uncleared, got %q", status)
+	}
+	if _, err := session.Toggle("april"); !errors.Is(err, ErrAfterStatementDate) {
+		t.Errorf("Expected ErrAfterStatementDate, got %v", err)
+	}
+	if _, err := session.Toggle("old"); !errors.Is(err, ErrUnknownTransaction) {
+		t.Errorf("Expected ErrUnknownTransaction, got %v", err)
+	}
+}
+
+func TestSessionFinish(t *testing.T) {
+	session := newTestSession(t, 265450)
+	session.Toggle("rent")
+	session.Toggle("groceries")
+
+	result, err := session.Finish(false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !slices.Equal(result.Reconciled, []string{"rent", "salary", "groceries"}) {
+		t.Errorf("Unexpected reconciled transactions %v", result.Reconciled)
+	}
+	if result.Adjustment != nil {
+		t.Errorf("Expected no adjustment, got %+v", result.Adjustment)
+	}
+	if result.Account.ReconciledBalance.Amount() != 265450 || !result.Account.LastReconciledDate.Equal(date(31)) {
+		t.Errorf("Unexpected account state %+v", result.Account)
+	}
+
+	if _, err := session.Toggle("rent"); !errors.Is(err, ErrSessionFinished) {
+		t.Errorf("Expected ErrSessionFinished, got %v", err)
+	}
+	if _, err := session.Finish(false); !errors.Is(err, ErrSessionFinished) {
+		t.Errorf("Expected ErrSessionFinished, got %v", err)
+	}
+}
+
+func TestSessionFinishWithAdjustment(t *testing.T) {
+	// The bank charged a fee of 2.50 that was never entered.
+	session := newTestSession(t, 265200)
+	session.Toggle("rent")
+	session.Toggle("groceries")
+
+	if _, err := session.Finish(false); !errors.Is(err, ErrUnbalanced) {
+		t.Fatalf("Expected ErrUnbalanced, got %v", err)
+	}
+
+	result, err := session.Finish(true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Adjustment == nil || result.Adjustment.Amount.Amount() != -250 || !result.Adjustment.Date.Equal(date(31)) {
+		t.Errorf("Unexpected adjustment %+v", result.Adjustment)
+	}
+	if result.Account.ReconciledBalance.Amount() != 265200 {
+		t.Errorf("Expected the statement balance to become the reconciled balance, got %s", result.Account.ReconciledBalance)
+	}
+}
+
+func TestNewSessionErrors(t *testing.T) {
+	account := Account{ID: "checking", ReconciledBalance: money.MustNew(0, "EUR"), LastReconciledDate: date(15)}
+
+	if _, err := NewSession(account, date(10), money.MustNew(0, "EUR"), nil); !errors.Is(err, ErrStatementBeforeLast) {
+		t.Errorf("Expected ErrStatementBeforeLast, got %v", err)
+	}
+	if _, err := NewSession(account, date(31), money.MustNew(0, "USD"), nil); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
+
+func TestCheckEdit(t *testing.T) {
+	reconciled := Transaction{ID: "rent", Status: StatusReconciled}
+
+	if err := CheckEdit(reconciled, false); !errors.Is(err, ErrReconciled) {
+		t.Errorf("Expected ErrReconciled, got %v", err)
+	}
+	if err := CheckEdit(reconciled, true); err != nil {
+		t.Errorf("Expected a forced edit to pass, got %v", err)
+	}
+	if err := CheckEdit(Transaction{ID: "groceries", Status: StatusCleared}, false); err != nil {
+		t.Errorf("Expected cleared transactions to be editable, got %v", err)
+	}
+}
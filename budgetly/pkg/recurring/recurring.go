@@ -0,0 +1,235 @@
+// Package recurring detects subscriptions and other recurring bills in transaction history.
+package recurring
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+// Cadence is how often a recurring payment happens.
+type Cadence string
+
+const (
+	CadenceWeekly    Cadence = "weekly"
+	CadenceBiweekly  Cadence = "biweekly"
+	CadenceMonthly   Cadence = "monthly"
+	CadenceQuarterly Cadence = "quarterly"
+	CadenceYearly    Cadence = "yearly"
+)
+
+// cadenceSpec is the typical interval of a cadence in days and how far an interval may
+// stray from it and still count.
+type cadenceSpec struct {
+	cadence   Cadence
+	days      float64
+	tolerance float64
+}
+
+// cadences lists the detected cadences, shortest first.
+var cadences = []cadenceSpec{
+	{CadenceWeekly, 7, 1},
+	{CadenceBiweekly, 14, 2},
+	{CadenceMonthly, 30.44, 4},
+	{CadenceQuarterly, 91.31, 8},
+	{CadenceYearly, 365.25, 12},
+}
+
+// Transaction is the subset of a transaction detection needs.
+type Transaction struct {
+	ID     string
+	Date   time.Time
+	Payee  string
+	Amount money.Money
+}
+
+// Options configures detection.
+type Options struct {
+	// MinOccurrences is the number of payments needed before a payee counts as recurring.
+	MinOccurrences int
+	// MinRegularity is the share of intervals that must match the cadence, between 0 and 1.
+	MinRegularity float64
+}
+
+// DefaultOptions returns the options used when none are configured.
+func DefaultOptions() Options {
+	return Options{
+		MinOccurrences: 3,
+		MinRegularity:  0.75,
+	}
+}
+
+// PriceChange reports that the latest payment differs from the one before it.
+type PriceChange struct {
+	From money.Money `json:"from"`
+	To   money.Money `json:"to"`
+}
+
+// Subscription is a detected recurring payment.
+type Subscription struct {
+	Payee   string  `json:"payee"`
+	Cadence Cadence `json:"cadence"`
+	// Amount is the latest amount, which is the best estimate of the next charge.
+	Amount     money.Money `json:"amount"`
+	LastDate   time.Time   `json:"last_date"`
+	NextDate   time.Time   `json:"next_date"`
+	Count      int         `json:"count"`
+	Regularity float64     `json:"regularity"`
+	// PriceChange is set when the latest payment costs more than the one before it.
+	PriceChange *PriceChange `json:"price_change,omitempty"`
+	// Missed is set when the next charge is overdue by more than the cadence's tolerance.
+	Missed         bool     `json:"missed"`
+	TransactionIDs []string `json:"transaction_ids"`
+}
+
+// Schedule is a scheduled transaction created from a detected subscription.
+type Schedule struct {
+	Payee     string      `json:"payee"`
+	Amount    money.Money `json:"amount"`
+	Frequency Cadence     `json:"frequency"`
+	NextDate  time.Time   `json:"next_date"`
+}
+
+// ToSchedule converts the subscription into a scheduled transaction for its next charge.
+func (s Subscription) ToSchedule() Schedule {
+	return Schedule{Payee: s.Payee, Amount: s.Amount, Frequency: s.Cadence, NextDate: s.NextDate}
+}
+
+// Detect groups outgoing transactions by normalized payee and currency and returns the
+// groups that repeat on a regular cadence, ordered by their next expected charge.
+// today is used to flag missed charges.
+func Detect(transactions []Transaction, today time.Time, options Options) []Subscription {
+	groups := make(map[string][]Transaction)
+	var keys []string
+	for _, tx := range transactions {
+		if !tx.Amount.IsNegative() {
+			continue
+		}
+		key := normalizePayee(tx.Payee) + "|" + tx.Amount.Currency()
+		if strings.HasPrefix(key, "|") {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], tx)
+	}
+
+	subscriptions := []Subscription{}
+	for _, key := range keys {
+		if subscription, ok := detectGroup(groups[key], today, options); ok {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].NextDate.Before(subscriptions[j].NextDate)
+	})
+	return subscriptions
+}
+
+// detectGroup checks whether the payments to one payee follow a cadence.
+func detectGroup(group []Transaction, today time.Time, options Options) (Subscription, bool) {
+	if len(group) < options.MinOccurrences {
+		return Subscription{}, false
+	}
+
+	sort.SliceStable(group, func(i, j int) bool { return group[i].Date.Before(group[j].Date) })
+
+	intervals := make([]float64, 0, len(group)-1)
+	for i := 1; i < len(group); i++ {
+		intervals = append(intervals, group[i].Date.Sub(group[i-1].Date).Hours()/24)
+	}
+
+	spec, regularity, ok := matchCadence(intervals)
+	if !ok || regularity < options.MinRegularity {
+		return Subscription{}, false
+	}
+
+	last := group[len(group)-1]
+	subscription := Subscription{
+		Payee:      last.Payee,
+		Cadence:    spec.cadence,
+		Amount:     last.Amount,
+		LastDate:   last.Date,
+		NextDate:   next(last.Date, spec.cadence),
+		Count:      len(group),
+		Regularity: regularity,
+	}
+	for _, tx := range group {
+		subscription.TransactionIDs = append(subscription.TransactionIDs, tx.ID)
+	}
+
+	// Amounts are negative, so a lower amount is a higher price.
+	previous := group[len(group)-2].Amount
+	if cmp, _ := last.Amount.Cmp(previous); cmp < 0 {
+		subscription.PriceChange = &PriceChange{From: previous.Abs(), To: last.Amount.Abs()}
+	}
+
+	deadline := subscription.NextDate.Add(time.Duration(spec.tolerance*24) * time.Hour)
+	subscription.Missed = today.After(deadline)
+	return subscription, true
+}
+
+// matchCadence returns the cadence closest to the median interval and the share of
+// intervals within its tolerance.
+func matchCadence(intervals []float64) (cadenceSpec, float64, bool) {
+	sorted := append([]float64(nil), intervals...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+	if len(sorted)%2 == 0 {
+		median = (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+	}
+
+	for _, spec := range cadences {
+		if abs(median-spec.days) > spec.tolerance {
+			continue
+		}
+		matching := 0
+		for _, interval := range intervals {
+			if abs(interval-spec.days) <= spec.tolerance {
+				matching++
+			}
+		}
+		return spec, float64(matching) / float64(len(intervals)), true
+	}
+	return cadenceSpec{}, 0, false
+}
+
+// next returns the expected date of the charge after date.
+func next(date time.Time, cadence Cadence) time.Time {
+	switch cadence {
+	case CadenceWeekly:
+		return date.AddDate(0, 0, 7)
+	case CadenceBiweekly:
+		return date.AddDate(0, 0, 14)
+	case CadenceMonthly:
+		return date.AddDate(0, 1, 0)
+	case CadenceQuarterly:
+		return date.AddDate(0, 3, 0)
+	default:
+		return date.AddDate(1, 0, 0)
+	}
+}
+
+// normalizePayee lowercases the payee and keeps only its letters, so reference numbers
+// and punctuation such as "NETFLIX.COM 8839" and "Netflix.com 9912" group together.
+func normalizePayee(payee string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, payee)
+}
+
+// abs returns the absolute value of x.
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,120 @@
+package recurring
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func eur(amount int64) money.Money {
+	return money.MustNew(amount, "EUR")
+}
+
+func history() []Transaction {
+	var transactions []Transaction
+
+	// A streaming service charged around the 3rd, with a price increase in June.
+	for i, date := range []time.Time{day(2026, 1, 3), day(2026, 2, 4), day(2026, 3, 3), day(2026, 4, 2), day(2026, 5, 3), day(2026, 6, 3)} {
+		amount := eur(-1299)
+		if date.Month() == time.June {
+			amount = eur(-1499)
+		}
+		transactions = append(transactions, Transaction{ID: fmt.Sprintf("stream-%d", i), Date: date, Payee: fmt.Sprintf("NETFLIX.COM %d", 8800+i), Amount: amount})
+	}
+
+	// A weekly gym class that stopped in May.
+	for i := 0; i < 6; i++ {
+		transactions = append(transactions, Transaction{ID: fmt.Sprintf("gym-%d", i), Date: day(2026, 4, 1).AddDate(0, 0, 7*i), Payee: "Gym", Amount: eur(-1000)})
+	}
+
+	// A yearly domain renewal.
+	for i, year := range []int{2024, 2025, 2026} {
+		transactions = append(transactions, Transaction{ID: fmt.Sprintf("domain-%d", i), Date: day(year, 3, 10+i), Payee: "Domains Inc", Amount: eur(-1500)})
+	}
+
+	// Irregular groceries, a salary, and a payee seen twice are not subscriptions.
+	for i, d := range []int{2, 3, 11, 25, 26} {
+		transactions = append(transactions, Transaction{ID: fmt.Sprintf("food-%d", i), Date: day(2026, 5, d), Payee: "Supermarket", Amount: eur(-4000)})
+	}
+	for i := 1; i <= 6; i++ {
+		transactions = append(transactions, Transaction{ID: fmt.Sprintf("salary-%d", i), Date: day(2026, time.Month(i), 25), Payee: "Employer", Amount: eur(300000)})
+	}
+	transactions = append(transactions,
+		Transaction{ID: "once-1", Date: day(2026, 1, 1), Payee: "Dentist", Amount: eur(-9000)},
+		Transaction{ID: "once-2", Date: day(2026, 2, 1), Payee: "Dentist", Amount: eur(-9000)},
+	)
+	return transactions
+}
+
+func TestDetect(t *testing.T) {
+	subscriptions := Detect(history(), day(2026, 6, 20), DefaultOptions())
+	if len(subscriptions) != 3 {
+		t.Fatalf("Expected 3 subscriptions, got %+v", subscriptions)
+	}
+
+	byCadence := make(map[Cadence]Subscription)
+	for _, s := range subscriptions {
+		byCadence[s.Cadence] = s
+	}
+
+	stream, ok := byCadence[CadenceMonthly]
+	if !ok || stream.Count != 6 || !stream.NextDate.Equal(day(2026, 7, 3)) {
+		t.Errorf("Unexpected monthly subscription %+v", stream)
+	}
+	if stream.PriceChange == nil || stream.PriceChange.From.Amount() != 1299 || stream.PriceChange.To.Amount() != 1499 {
+		t.Errorf("Expected a price increase from 12.99 to 14.99, got %+v", stream.PriceChange)
+	}
+	if stream.Missed {
+		t.Errorf("Expected the streaming service not to be missed yet")
+	}
+
+	gym, ok := byCadence[CadenceWeekly]
+	if !ok || !gym.Missed || gym.PriceChange != nil {
+		t.Errorf("Expected the stopped weekly gym class to be missed, got %+v", gym)
+	}
+
+	domain, ok := byCadence[CadenceYearly]
+	if !ok || !domain.NextDate.Equal(day(2027, 3, 12)) {
+		t.Errorf("Unexpected yearly subscription %+v", domain)
+	}
+
+	// Subscriptions are ordered by their next charge.
+	if subscriptions[0].Payee != "Gym" || subscriptions[2].Payee != "Domains Inc" {
+		t.Errorf("Unexpected order %q, %q, %q", subscriptions[0].Payee, subscriptions[1].Payee, subscriptions[2].Payee)
+	}
+}
+
+func TestDetectRegularity(t *testing.T) {
+	// Two of four intervals are monthly, which is below the default regularity.
+	transactions := []Transaction{
+		{ID: "1", Date: day(2026, 1, 1), Payee: "Utility", Amount: eur(-5000)},
+		{ID: "2", Date: day(2026, 2, 1), Payee: "Utility", Amount: eur(-5000)},
+		{ID: "3", Date: day(2026, 3, 1), Payee: "Utility", Amount: eur(-5000)},
+		{ID: "4", Date: day(2026, 3, 15), Payee: "Utility", Amount: eur(-5000)},
+		{ID: "5", Date: day(2026, 5, 20), Payee: "Utility", Amount: eur(-5000)},
+	}
+	if subscriptions := Detect(transactions, day(2026, 6, 1), DefaultOptions()); len(subscriptions) != 0 {
+		t.Errorf("Expected irregular payments to be ignored, got %+v", subscriptions)
+	}
+
+	options := DefaultOptions()
+	options.MinRegularity = 0.5
+	if subscriptions := Detect(transactions, day(2026, 6, 1), options); len(subscriptions) != 1 || subscriptions[0].Regularity != 0.5 {
+		t.Errorf("Expected a lower regularity to accept them, got %+v", subscriptions)
+	}
+}
+
+func TestToSchedule(t *testing.T) {
+	subscription := Subscription{Payee: "Netflix", Cadence: CadenceMonthly, Amount: eur(-1499), NextDate: day(2026, 7, 3)}
+
+	schedule := subscription.ToSchedule()
+	if schedule.Payee != "Netflix" || schedule.Frequency != CadenceMonthly || !schedule.Amount.Equal(eur(-1499)) || !schedule.NextDate.Equal(day(2026, 7, 3)) {
+		t.Errorf("Unexpected schedule %+v", schedule)
+	}
+}
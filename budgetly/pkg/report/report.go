@@ -0,0 +1,218 @@
+// Package report buckets dated amounts into calendar periods and totals them per currency.
+package report
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var (
+	ErrUnknownGranularity = errors.New("unknown granularity")
+	ErrInvalidRange       = errors.New("invalid date range")
+)
+
+// maxBuckets caps how many periods a single report may span.
+const maxBuckets = 1200
+
+// Granularity is the length of a report bucket.
+type Granularity string
+
+const (
+	Monthly   Granularity = "monthly"
+	Quarterly Granularity = "quarterly"
+	Yearly    Granularity = "yearly"
+)
+
+// ParseGranularity parses a granularity name, defaulting to monthly when empty.
+func ParseGranularity(name string) (Granularity, error) {
+	switch g := Granularity(name); g {
+	case "":
+		return Monthly, nil
+	case Monthly, Quarterly, Yearly:
+		return g, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownGranularity, name)
+	}
+}
+
+// Trunc returns the start of the bucket containing t.
+func (g Granularity) Trunc(t time.Time) time.Time {
+	t = fx.Day(t)
+	switch g {
+	case Quarterly:
+		return time.Date(t.Year(), t.Month()-(t.Month()-1)%3, 1, 0, 0, 0, 0, time.UTC)
+	case Yearly:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Next returns the start of the bucket after the one starting at start.
+func (g Granularity) Next(start time.Time) time.Time {
+	switch g {
+	case Quarterly:
+		return start.AddDate(0, 3, 0)
+	case Yearly:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// SQL returns a PostgreSQL expression truncating column to the bucket start, so that
+// aggregation can be grouped in the database.
+func (g Granularity) SQL(column string) string {
+	unit := "month"
+	switch g {
+	case Quarterly:
+		unit = "quarter"
+	case Yearly:
+		unit = "year"
+	}
+	return fmt.Sprintf("date_trunc('%s', %s)::date", unit, column)
+}
+
+// Period is a half-open date range [Start, End).
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains reports whether t falls inside the period.
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// Previous returns the period of the same length immediately before p. Periods made of
+// whole months step back by months so that e.g. March compares with February.
+func (p Period) Previous() Period {
+	if p.Start.Day() == 1 && p.End.Day() == 1 {
+		months := (p.End.Year()-p.Start.Year())*12 + int(p.End.Month()-p.Start.Month())
+		return Period{Start: p.Start.AddDate(0, -months, 0), End: p.Start}
+	}
+	return Period{Start: p.Start.Add(-p.End.Sub(p.Start)), End: p.Start}
+}
+
+// Buckets splits the dates from..to (inclusive) into periods of the given granularity.
+// The first and last buckets are whole periods even when from and to fall inside them.
+func Buckets(from, to time.Time, g Granularity) ([]Period, error) {
+	from, to = fx.Day(from), fx.Day(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: %s is before %s", ErrInvalidRange, to.Format(time.DateOnly), from.Format(time.DateOnly))
+	}
+
+	var periods []Period
+	for start := g.Trunc(from); !start.After(to); start = g.Next(start) {
+		if len(periods) == maxBuckets {
+			return nil, fmt.Errorf("%w: more than %d buckets", ErrInvalidRange, maxBuckets)
+		}
+		periods = append(periods, Period{Start: start, End: g.Next(start)})
+	}
+	return periods, nil
+}
+
+// Totals sums amounts separately per currency. The zero value is empty and ready to use.
+type Totals map[string]money.Money
+
+// Add adds an amount to the total of its currency.
+func (t Totals) Add(amount money.Money) error {
+	current, ok := t[amount.Currency()]
+	if !ok {
+		t[amount.Currency()] = amount
+		return nil
+	}
+	sum, err := current.Add(amount)
+	if err != nil {
+		return err
+	}
+	t[amount.Currency()] = sum
+	return nil
+}
+
+// Currencies returns the currencies present in the totals, sorted.
+func (t Totals) Currencies() []string {
+	currencies := make([]string, 0, len(t))
+	for currency := range t {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Convert folds every currency into one, converting at the rates in effect on date.
+func (t Totals) Convert(source fx.RateSource, to string, date time.Time) (money.Money, error) {
+	total, err := money.Zero(to)
+	if err != nil {
+		return money.Money{}, err
+	}
+	for _, currency := range t.Currencies() {
+		amount := t[currency]
+		if currency != total.Currency() {
+			minor, err := fx.Convert(source, amount.Amount(), currency, total.Currency(), date)
+			if err != nil {
+				return money.Money{}, err
+			}
+			if amount, err = money.New(minor, total.Currency()); err != nil {
+				return money.Money{}, err
+			}
+		}
+		if total, err = total.Add(amount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Change is the difference of a total against the previous period in one currency.
+type Change struct {
+	Currency string      `json:"currency"`
+	Current  money.Money `json:"current"`
+	Previous money.Money `json:"previous"`
+	Delta    money.Money `json:"delta"`
+	// Percent is the change relative to the previous total, or nil when it was zero.
+	Percent *float64 `json:"percent"`
+}
+
+// Compare compares current totals with those of the previous period, one change per
+// currency present in either.
+func Compare(current, previous Totals) ([]Change, error) {
+	seen := Totals{}
+	for currency, amount := range current {
+		seen[currency] = amount
+	}
+	for currency, amount := range previous {
+		if _, ok := seen[currency]; !ok {
+			seen[currency] = amount
+		}
+	}
+
+	changes := make([]Change, 0, len(seen))
+	for _, currency := range seen.Currencies() {
+		zero, err := money.Zero(currency)
+		if err != nil {
+			return nil, err
+		}
+		change := Change{Currency: currency, Current: zero, Previous: zero}
+		if amount, ok := current[currency]; ok {
+			change.Current = amount
+		}
+		if amount, ok := previous[currency]; ok {
+			change.Previous = amount
+		}
+		if change.Delta, err = change.Current.Sub(change.Previous); err != nil {
+			return nil, err
+		}
+		if !change.Previous.IsZero() {
+			percent := float64(change.Delta.Amount()) / float64(change.Previous.Abs().Amount()) * 100
+			change.Percent = &percent
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/fx"
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBuckets(t *testing.T) {
+	tests := []struct {
+		granularity Granularity
+		from, to    time.Time
+		starts      []time.Time
+	}{
+		{Monthly, day(2026, 1, 15), day(2026, 3, 1), []time.Time{day(2026, 1, 1), day(2026, 2, 1), day(2026, 3, 1)}},
+		{Quarterly, day(2026, 2, 10), day(2026, 8, 31), []time.Time{day(2026, 1, 1), day(2026, 4, 1), day(2026, 7, 1)}},
+		{Yearly, day(2025, 12, 31), day(2026, 1, 1), []time.Time{day(2025, 1, 1), day(2026, 1, 1)}},
+	}
+
+	for _, tt := range tests {
+		periods, err := Buckets(tt.from, tt.to, tt.granularity)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.granularity, err)
+		}
+		if len(periods) != len(tt.starts) {
+			t.Fatalf("%s: expected %d buckets, got %+v", tt.granularity, len(tt.starts), periods)
+		}
+		for i, period := range periods {
+			if !period.Start.Equal(tt.starts[i]) || !period.End.Equal(tt.granularity.Next(tt.starts[i])) {
+				t.Errorf("%s: unexpected bucket %d %+v", tt.granularity, i, period)
+			}
+		}
+	}
+
+	if _, err := Buckets(day(2026, 2, 1), day(2026, 1, 1), Monthly); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+	if _, err := ParseGranularity("weekly"); !errors.Is(err, ErrUnknownGranularity) {
+		t.Errorf("Expected ErrUnknownGranularity, got %v", err)
+	}
+}
+
+func TestGranularitySQL(t *testing.T) {
+	if got := Quarterly.SQL("date"); got != "date_trunc('quarter', date)::date" {
+		t.Errorf("Unexpected SQL %q", got)
+	}
+}
+
+func TestPrevious(t *testing.T) {
+	march := Period{Start: day(2026, 3, 1), End: day(2026, 4, 1)}
+	if previous := march.Previous(); !previous.Start.Equal(day(2026, 2, 1)) || !previous.End.Equal(march.Start) {
+		t.Errorf("Unexpected previous month %+v", previous)
+	}
+
+	week := Period{Start: day(2026, 3, 10), End: day(2026, 3, 17)}
+	if previous := week.Previous(); !previous.Start.Equal(day(2026, 3, 3)) || !previous.End.Equal(week.Start) {
+		t.Errorf("Unexpected previous week %+v", previous)
+	}
+}
+
+func TestTotalsConvert(t *testing.T) {
+	totals := Totals{}
+	for _, amount := range []money.Money{
+		money.MustNew(1000, "EUR"),
+		money.MustNew(2500, "EUR"),
+		money.MustNew(1000, "USD"),
+	} {
+		if err := totals.Add(amount); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if !totals["EUR"].Equal(money.MustNew(3500, "EUR")) {
+		t.Errorf("Unexpected EUR total %v", totals["EUR"])
+	}
+
+	rate, err := fx.NewRate(day(2026, 1, 1), "USD", "EUR", "0.9")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	total, err := totals.Convert(fx.NewTable(rate), "EUR", day(2026, 3, 1))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !total.Equal(money.MustNew(4400, "EUR")) {
+		t.Errorf("Expected 44.00 EUR, got %v", total)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	current := Totals{"EUR": money.MustNew(-15000, "EUR"), "USD": money.MustNew(-500, "USD")}
+	previous := Totals{"EUR": money.MustNew(-10000, "EUR"), "GBP": money.MustNew(-200, "GBP")}
+
+	changes, err := Compare(current, previous)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %+v", changes)
+	}
+
+	eur := changes[0]
+	if eur.Currency != "EUR" || !eur.Delta.Equal(money.MustNew(-5000, "EUR")) || eur.Percent == nil || *eur.Percent != -50 {
+		t.Errorf("Unexpected EUR change %+v", eur)
+	}
+	if gbp := changes[1]; gbp.Currency != "GBP" || !gbp.Current.IsZero() || !gbp.Delta.Equal(money.MustNew(200, "GBP")) {
+		t.Errorf("Unexpected GBP change %+v", gbp)
+	}
+	if usd := changes[2]; usd.Currency != "USD" || usd.Percent != nil {
+		t.Errorf("Expected no percentage for a new currency, got %+v", usd)
+	}
+}
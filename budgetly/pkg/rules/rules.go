@@ -0,0 +1,326 @@
+// Package rules applies user-defined categorization rules to transactions.
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field is a transaction attribute a condition inspects.
+type Field string
+
+const (
+	FieldPayee   Field = "payee"
+	FieldMemo    Field = "memo"
+	FieldAccount Field = "account"
+	FieldAmount  Field = "amount"
+)
+
+// Operator is the comparison a condition performs.
+type Operator string
+
+const (
+	OperatorContains Operator = "contains"
+	OperatorEquals   Operator = "equals"
+	OperatorRegex    Operator = "regex"
+	OperatorBetween  Operator = "between"
+)
+
+// ActionType is the change an action makes to a transaction.
+type ActionType string
+
+const (
+	ActionSetCategory ActionType = "set_category"
+	ActionRenamePayee ActionType = "rename_payee"
+	ActionAddTag      ActionType = "add_tag"
+	ActionMarkCleared ActionType = "mark_cleared"
+)
+
+var ErrInvalidRule = errors.New("invalid rule")
+
+// Transaction is the subset of a transaction rules can inspect and change.
+// Amount is expressed in minor units (e.g. cents).
+type Transaction struct {
+	ID        string   `json:"id"`
+	AccountID string   `json:"account_id"`
+	Payee     string   `json:"payee"`
+	Memo      string   `json:"memo"`
+	Amount    int64    `json:"amount"`
+	Category  string   `json:"category"`
+	Tags      []string `json:"tags"`
+	Cleared   bool     `json:"cleared"`
+}
+
+// Condition is a single test against a transaction field.
+// Text comparisons are case-insensitive. For OperatorBetween, Min and Max bound
+// the amount inclusively and either may be omitted.
+type Condition struct {
+	Field    Field    `json:"field"`
+	Operator Operator `json:"operator"`
+	Value    string   `json:"value,omitempty"`
+	Min      *int64   `json:"min,omitempty"`
+	Max      *int64   `json:"max,omitempty"`
+}
+
+// Action is a change applied to a transaction when a rule matches.
+type Action struct {
+	Type  ActionType `json:"type"`
+	Value string     `json:"value,omitempty"`
+}
+
+// Rule applies its actions to transactions that satisfy all of its conditions.
+// Rules run in ascending Priority order; later rules see the changes of earlier ones.
+type Rule struct {
+	ID         int         `json:"id"`
+	Name       string      `json:"name"`
+	Priority   int         `json:"priority"`
+	Conditions []Condition `json:"conditions"`
+	Actions    []Action    `json:"actions"`
+}
+
+// compiledRule is a rule with its regular expressions compiled.
+type compiledRule struct {
+	Rule
+	patterns map[int]*regexp.Regexp
+}
+
+// Engine evaluates an ordered set of rules against transactions.
+type Engine struct {
+	rules []compiledRule
+}
+
+// NewEngine validates and compiles the rules, ordering them by priority.
+func NewEngine(rules []Rule) (*Engine, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		c, err := compile(rule)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, c)
+	}
+
+	sort.SliceStable(compiled, func(i, j int) bool {
+		return compiled[i].Priority < compiled[j].Priority
+	})
+	return &Engine{rules: compiled}, nil
+}
+
+// Apply runs every matching rule against the transaction and returns the changed
+// transaction together with the IDs of the rules that matched.
+func (e *Engine) Apply(tx Transaction) (Transaction, []int) {
+	tx.Tags = slices.Clone(tx.Tags)
+
+	var matched []int
+	for _, rule := range e.rules {
+		if !rule.matches(tx) {
+			continue
+		}
+		for _, action := range rule.Actions {
+			apply(&tx, action)
+		}
+		matched = append(matched, rule.ID)
+	}
+	return tx, matched
+}
+
+// Change describes how a dry run would modify a transaction.
+type Change struct {
+	Before  Transaction `json:"before"`
+	After   Transaction `json:"after"`
+	RuleIDs []int       `json:"rule_ids"`
+}
+
+// DryRun applies the rules to the transactions without persisting anything and
+// returns only the transactions that would change.
+func (e *Engine) DryRun(transactions []Transaction) []Change {
+	changes := []Change{}
+	for _, tx := range transactions {
+		after, matched := e.Apply(tx)
+		if len(matched) == 0 || equal(tx, after) {
+			continue
+		}
+		changes = append(changes, Change{Before: tx, After: after, RuleIDs: matched})
+	}
+	return changes
+}
+
+// Validate checks that the rule is well formed and returns a map of field errors.
+func (r *Rule) Validate() map[string]string {
+	errors := make(map[string]string)
+
+	if strings.TrimSpace(r.Name) == "" {
+		errors["Name"] = "Name is required"
+	}
+	if len(r.Conditions) == 0 {
+		errors["Conditions"] = "At least one condition is required"
+	}
+	if len(r.Actions) == 0 {
+		errors["Actions"] = "At least one action is required"
+	}
+
+	for i, condition := range r.Conditions {
+		if err := condition.validate(); err != nil {
+			errors[fmt.Sprintf("Conditions[%d]", i)] = err.Error()
+		}
+	}
+	for i, action := range r.Actions {
+		if err := action.validate(); err != nil {
+			errors[fmt.Sprintf("Actions[%d]", i)] = err.Error()
+		}
+	}
+
+	return errors
+}
+
+// compile validates a rule and compiles its regular expressions.
+func compile(rule Rule) (compiledRule, error) {
+	if errs := rule.Validate(); len(errs) > 0 {
+		return compiledRule{}, fmt.Errorf("%w %q: %v", ErrInvalidRule, rule.Name, errs)
+	}
+
+	patterns := make(map[int]*regexp.Regexp)
+	for i, condition := range rule.Conditions {
+		if condition.Operator == OperatorRegex {
+			patterns[i] = regexp.MustCompile("(?i)" + condition.Value)
+		}
+	}
+	return compiledRule{Rule: rule, patterns: patterns}, nil
+}
+
+// matches reports whether all conditions hold, stopping at the first that does not.
+func (r *compiledRule) matches(tx Transaction) bool {
+	for i, condition := range r.Conditions {
+		if condition.Field == FieldAmount {
+			if !condition.matchesAmount(tx.Amount) {
+				return false
+			}
+			continue
+		}
+
+		value := condition.text(tx)
+		switch condition.Operator {
+		case OperatorContains:
+			if !strings.Contains(strings.ToLower(value), strings.ToLower(condition.Value)) {
+				return false
+			}
+		case OperatorEquals:
+			if !strings.EqualFold(value, condition.Value) {
+				return false
+			}
+		case OperatorRegex:
+			if !r.patterns[i].MatchString(value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// text returns the transaction text the condition inspects.
+func (c Condition) text(tx Transaction) string {
+	switch c.Field {
+	case FieldPayee:
+		return tx.Payee
+	case FieldMemo:
+		return tx.Memo
+	case FieldAccount:
+		return tx.AccountID
+	default:
+		return ""
+	}
+}
+
+// matchesAmount reports whether the amount satisfies an amount condition.
+func (c Condition) matchesAmount(amount int64) bool {
+	switch c.Operator {
+	case OperatorEquals:
+		value, _ := strconv.ParseInt(c.Value, 10, 64)
+		return amount == value
+	case OperatorBetween:
+		return (c.Min == nil || amount >= *c.Min) && (c.Max == nil || amount <= *c.Max)
+	default:
+		return false
+	}
+}
+
+// validate checks that the field and operator combination is supported.
+func (c Condition) validate() error {
+	switch c.Field {
+	case FieldAmount:
+		switch c.Operator {
+		case OperatorEquals:
+			if _, err := strconv.ParseInt(c.Value, 10, 64); err != nil {
+				return fmt.Errorf("amount %q must be an integer in minor units", c.Value)
+			}
+		case OperatorBetween:
+			if c.Min == nil && c.Max == nil {
+				return errors.New("between requires min, max or both")
+			}
+			if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+				return errors.New("min must not be greater than max")
+			}
+		default:
+			return fmt.Errorf("operator %q is not supported for amount", c.Operator)
+		}
+	case FieldPayee, FieldMemo, FieldAccount:
+		switch c.Operator {
+		case OperatorContains, OperatorEquals:
+			if c.Value == "" {
+				return fmt.Errorf("%s requires a value", c.Operator)
+			}
+		case OperatorRegex:
+			if _, err := regexp.Compile("(?i)" + c.Value); err != nil {
+				return fmt.Errorf("invalid regex: %v", err)
+			}
+		default:
+			return fmt.Errorf("operator %q is not supported for %s", c.Operator, c.Field)
+		}
+	default:
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+	return nil
+}
+
+// validate checks that the action type is supported and has the value it needs.
+func (a Action) validate() error {
+	switch a.Type {
+	case ActionSetCategory, ActionRenamePayee, ActionAddTag:
+		if strings.TrimSpace(a.Value) == "" {
+			return fmt.Errorf("%s requires a value", a.Type)
+		}
+	case ActionMarkCleared:
+	default:
+		return fmt.Errorf("unknown action %q", a.Type)
+	}
+	return nil
+}
+
+// apply performs a single action on the transaction.
+func apply(tx *Transaction, action Action) {
+	switch action.Type {
+	case ActionSetCategory:
+		tx.Category = action.Value
+	case ActionRenamePayee:
+		tx.Payee = action.Value
+	case ActionAddTag:
+		if !slices.Contains(tx.Tags, action.Value) {
+			tx.Tags = append(tx.Tags, action.Value)
+		}
+	case ActionMarkCleared:
+		tx.Cleared = true
+	}
+}
+
+// equal reports whether two transactions have the same rule-controlled attributes.
+func equal(a, b Transaction) bool {
+	return a.Payee == b.Payee &&
+		a.Category == b.Category &&
+		a.Cleared == b.Cleared &&
+		slices.Equal(a.Tags, b.Tags)
+}
@@ -0,0 +1,124 @@
+package rules
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestEngineApply(t *testing.T) {
+	engine, err := NewEngine([]Rule{
+		{
+			ID:       2,
+			Name:     "Tag large groceries",
+			Priority: 20,
+			Conditions: []Condition{
+				{Field: FieldPayee, Operator: OperatorEquals, Value: "Supermarket"},
+				{Field: FieldAmount, Operator: OperatorBetween, Max: int64Ptr(-10000)},
+			},
+			Actions: []Action{{Type: ActionAddTag, Value: "bulk"}},
+		},
+		{
+			ID:         1,
+			Name:       "Groceries",
+			Priority:   10,
+			Conditions: []Condition{{Field: FieldPayee, Operator: OperatorRegex, Value: `^rewe\s+\d+`}},
+			Actions: []Action{
+				{Type: ActionRenamePayee, Value: "Supermarket"},
+				{Type: ActionSetCategory, Value: "Groceries"},
+				{Type: ActionMarkCleared},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tx, matched := engine.Apply(Transaction{Payee: "REWE 4711 BERLIN", Amount: -12550})
+	if !slices.Equal(matched, []int{1, 2}) {
+		t.Errorf("Expected rules 1 and 2 to match in priority order, got %v", matched)
+	}
+	if tx.Payee != "Supermarket" || tx.Category != "Groceries" || !tx.Cleared || !slices.Equal(tx.Tags, []string{"bulk"}) {
+		t.Errorf("Unexpected transaction after rules: %+v", tx)
+	}
+
+	tx, matched = engine.Apply(Transaction{Payee: "REWE 4711 BERLIN", Amount: -550})
+	if !slices.Equal(matched, []int{1}) || len(tx.Tags) != 0 {
+		t.Errorf("Expected only rule 1 to match a small purchase, got %v with tags %v", matched, tx.Tags)
+	}
+}
+
+func TestEngineDryRun(t *testing.T) {
+	engine, err := NewEngine([]Rule{{
+		ID:         1,
+		Name:       "Streaming",
+		Conditions: []Condition{{Field: FieldMemo, Operator: OperatorContains, Value: "netflix"}},
+		Actions:    []Action{{Type: ActionSetCategory, Value: "Subscriptions"}},
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	changes := engine.DryRun([]Transaction{
+		{ID: "a", Memo: "NETFLIX.COM monthly", Category: "Uncategorized"},
+		{ID: "b", Memo: "Netflix", Category: "Subscriptions"},
+		{ID: "c", Memo: "Bakery"},
+	})
+	if len(changes) != 1 || changes[0].Before.ID != "a" || changes[0].After.Category != "Subscriptions" {
+		t.Errorf("Expected only transaction a to change, got %+v", changes)
+	}
+}
+
+func TestRuleValidation(t *testing.T) {
+	_, err := NewEngine([]Rule{{
+		Name:       "Broken",
+		Conditions: []Condition{{Field: FieldPayee, Operator: OperatorRegex, Value: "("}},
+		Actions:    []Action{{Type: ActionSetCategory, Value: "X"}},
+	}})
+	if !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("Expected ErrInvalidRule for a bad regex, got %v", err)
+	}
+
+	rule := Rule{
+		Conditions: []Condition{{Field: FieldAmount, Operator: OperatorContains, Value: "1"}},
+		Actions:    []Action{{Type: ActionSetCategory}},
+	}
+	errs := rule.Validate()
+	for _, field := range []string{"Name", "Conditions[0]", "Actions[0]"} {
+		if _, ok := errs[field]; !ok {
+			t.Errorf("Expected a validation error for %s, got %v", field, errs)
+		}
+	}
+}
+
+func TestSuggester(t *testing.T) {
+	suggester := NewSuggester(3)
+
+	for i := 0; i < 2; i++ {
+		if _, ok := suggester.Observe(nil, "Corner Cafe", "Dining"); ok {
+			t.Fatalf("Did not expect a suggestion after %d observations", i+1)
+		}
+	}
+
+	suggestion, ok := suggester.Observe(nil, "Corner Cafe", "Dining")
+	if !ok || suggestion.Rule.Actions[0].Value != "Dining" || suggestion.Count != 3 {
+		t.Fatalf("Expected a Dining suggestion on the third observation, got %+v", suggestion)
+	}
+	if errs := suggestion.Rule.Validate(); len(errs) > 0 {
+		t.Errorf("Expected the suggested rule to be valid, got %v", errs)
+	}
+
+	if _, ok := suggester.Observe(nil, "corner cafe", "Dining"); ok {
+		t.Errorf("Did not expect the same suggestion twice")
+	}
+
+	engine, _ := NewEngine([]Rule{suggestion.Rule})
+	other := NewSuggester(1)
+	if _, ok := other.Observe(engine, "Corner Cafe", "Dining"); ok {
+		t.Errorf("Did not expect a suggestion already covered by a rule")
+	}
+}
@@ -0,0 +1,79 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Suggestion proposes a rule for a payee the user keeps recategorizing by hand.
+type Suggestion struct {
+	Payee    string `json:"payee"`
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+	Rule     Rule   `json:"rule"`
+}
+
+// Suggester watches manual recategorizations and proposes a rule once the same
+// payee has been moved to the same category often enough. It is safe for concurrent use.
+type Suggester struct {
+	mu        sync.Mutex
+	threshold int
+	counts    map[string]map[string]int
+	suggested map[string]bool
+}
+
+// NewSuggester creates a suggester that proposes a rule after threshold recategorizations.
+func NewSuggester(threshold int) *Suggester {
+	return &Suggester{
+		threshold: threshold,
+		counts:    make(map[string]map[string]int),
+		suggested: make(map[string]bool),
+	}
+}
+
+// Observe records that a transaction from payee was recategorized to category.
+// It returns a suggestion the first time the threshold is reached for that payee
+// and category, unless the engine already categorizes the payee that way.
+func (s *Suggester) Observe(engine *Engine, payee, category string) (*Suggestion, bool) {
+	key := strings.ToLower(strings.TrimSpace(payee))
+	if key == "" || category == "" {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.counts[key] == nil {
+		s.counts[key] = make(map[string]int)
+	}
+	s.counts[key][category]++
+	count := s.counts[key][category]
+
+	suggestedKey := key + "\x00" + category
+	if count < s.threshold || s.suggested[suggestedKey] {
+		return nil, false
+	}
+
+	if engine != nil {
+		if tx, _ := engine.Apply(Transaction{Payee: payee}); tx.Category == category {
+			return nil, false
+		}
+	}
+
+	s.suggested[suggestedKey] = true
+	return &Suggestion{
+		Payee:    payee,
+		Category: category,
+		Count:    count,
+		Rule: Rule{
+			Name: fmt.Sprintf("Categorize %s as %s", payee, category),
+			Conditions: []Condition{
+				{Field: FieldPayee, Operator: OperatorEquals, Value: payee},
+			},
+			Actions: []Action{
+				{Type: ActionSetCategory, Value: category},
+			},
+		},
+	}, true
+}
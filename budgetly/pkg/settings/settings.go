@@ -7,11 +7,16 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/ZiadMansourM/budgetly/pkg/blob"
 	"github.com/ZiadMansourM/budgetly/pkg/prettylog"
 )
 
+// defaultMaxAttachmentSize limits attachment uploads when MAX_ATTACHMENT_SIZE is not set.
+const defaultMaxAttachmentSize = 10 << 20
+
 // Settings holds the application's configuration, including database connection and server address.
 type Settings struct {
 	BaseDir            string
@@ -19,6 +24,8 @@ type Settings struct {
 	ServerAddress      string
 	Logger             *slog.Logger
 	EnvironmentMode    string
+	BlobStore          blob.Store
+	MaxAttachmentSize  int64
 }
 
 // SettingsBuilder is used to build the settings step by step.
@@ -119,6 +126,53 @@ func (b *SettingsBuilder) WithServerAddress() *SettingsBuilder {
 	return b
 }
 
+// WithBlobStore sets up the attachment blob store from environment variables.
+// BLOB_STORE selects "local" (default, under BLOB_DIR) or "s3" (configured by the S3_* variables).
+func (b *SettingsBuilder) WithBlobStore() *SettingsBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	maxSize := int64(defaultMaxAttachmentSize)
+	if value := os.Getenv("MAX_ATTACHMENT_SIZE"); value != "" {
+		size, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || size <= 0 {
+			b.err = fmt.Errorf("MAX_ATTACHMENT_SIZE must be a positive number of bytes, got %q", value)
+			return b
+		}
+		maxSize = size
+	}
+	b.settings.MaxAttachmentSize = maxSize
+
+	var store blob.Store
+	var err error
+	switch backend := os.Getenv("BLOB_STORE"); backend {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = filepath.Join(b.settings.BaseDir, "blobs")
+		}
+		store, err = blob.NewLocalStore(dir)
+	case "s3":
+		store, err = blob.NewS3Store(blob.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			Prefix:          os.Getenv("S3_PREFIX"),
+		}, nil)
+	default:
+		err = fmt.Errorf("unknown BLOB_STORE %q", backend)
+	}
+	if err != nil {
+		b.err = fmt.Errorf("error setting up blob store: %v", err)
+		return b
+	}
+	b.settings.BlobStore = store
+	return b
+}
+
 // Build finalizes the settings creation and returns the Settings or an error.
 func (b *SettingsBuilder) Build() (*Settings, error) {
 	if b.err != nil {
@@ -0,0 +1,108 @@
+// Package splits divides a single transaction across several categories.
+package splits
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var ErrSplitMismatch = errors.New("split lines do not sum to the transaction amount")
+
+// Split is one line of a split transaction with its own category, memo and amount.
+type Split struct {
+	Category string      `json:"category"`
+	Memo     string      `json:"memo"`
+	Amount   money.Money `json:"amount"`
+}
+
+// Validate checks that every split has a category and an amount in the parent's currency,
+// and that the splits add up exactly to the parent amount. It returns a map of field errors.
+func Validate(parent money.Money, splits []Split) map[string]string {
+	errors := make(map[string]string)
+	if len(splits) == 0 {
+		return errors
+	}
+	if len(splits) == 1 {
+		errors["Splits"] = "A split transaction needs at least two lines"
+	}
+
+	total, _ := money.Zero(parent.Currency())
+	for i, split := range splits {
+		field := fmt.Sprintf("Splits[%d]", i)
+		if strings.TrimSpace(split.Category) == "" {
+			errors[field] = "Category is required"
+			continue
+		}
+		if split.Amount.Currency() != parent.Currency() {
+			errors[field] = fmt.Sprintf("Amount must be in %s", parent.Currency())
+			continue
+		}
+
+		sum, err := total.Add(split.Amount)
+		if err != nil {
+			errors[field] = err.Error()
+			continue
+		}
+		total = sum
+	}
+
+	if len(errors) == 0 && !total.Equal(parent) {
+		errors["Splits"] = fmt.Sprintf("%v: splits total %s, transaction is %s", ErrSplitMismatch, total, parent)
+	}
+	return errors
+}
+
+// Even splits the parent amount across the categories by the given ratios without
+// losing a minor unit, e.g. to split a receipt 50/30/20.
+func Even(parent money.Money, categories []string, ratios []int64) ([]Split, error) {
+	if len(categories) != len(ratios) {
+		return nil, fmt.Errorf("%w: %d categories for %d ratios", money.ErrInvalidRatios, len(categories), len(ratios))
+	}
+
+	amounts, err := parent.Allocate(ratios...)
+	if err != nil {
+		return nil, err
+	}
+
+	splits := make([]Split, len(amounts))
+	for i, amount := range amounts {
+		splits[i] = Split{Category: categories[i], Amount: amount}
+	}
+	return splits, nil
+}
+
+// CategoryAmount is the part of a transaction that counts towards a category.
+type CategoryAmount struct {
+	Category string      `json:"category"`
+	Amount   money.Money `json:"amount"`
+}
+
+// ByCategory returns what budgets and reports should count for a transaction: one entry
+// per category of its splits, or the parent category and amount when it is not split.
+// Splits sharing a category are combined, keeping the order of first appearance.
+func ByCategory(category string, amount money.Money, splits []Split) ([]CategoryAmount, error) {
+	if len(splits) == 0 {
+		return []CategoryAmount{{Category: category, Amount: amount}}, nil
+	}
+
+	var result []CategoryAmount
+	index := make(map[string]int)
+	for _, split := range splits {
+		i, ok := index[split.Category]
+		if !ok {
+			index[split.Category] = len(result)
+			result = append(result, CategoryAmount{Category: split.Category, Amount: split.Amount})
+			continue
+		}
+
+		sum, err := result[i].Amount.Add(split.Amount)
+		if err != nil {
+			return nil, err
+		}
+		result[i].Amount = sum
+	}
+	return result, nil
+}
@@ -0,0 +1,84 @@
+package splits
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+func TestValidate(t *testing.T) {
+	parent := money.MustNew(-8450, "EUR")
+
+	valid := []Split{
+		{Category: "Groceries", Amount: money.MustNew(-6000, "EUR")},
+		{Category: "Household", Amount: money.MustNew(-1650, "EUR")},
+		{Category: "Pharmacy", Memo: "Vitamins", Amount: money.MustNew(-800, "EUR")},
+	}
+	if errs := Validate(parent, valid); len(errs) > 0 {
+		t.Errorf("Expected splits to be valid, got %v", errs)
+	}
+
+	short := []Split{
+		{Category: "Groceries", Amount: money.MustNew(-6000, "EUR")},
+		{Category: "Household", Amount: money.MustNew(-1650, "EUR")},
+	}
+	if errs := Validate(parent, short); !strings.Contains(errs["Splits"], ErrSplitMismatch.Error()) {
+		t.Errorf("Expected a mismatch error, got %v", errs)
+	}
+
+	mixed := []Split{
+		{Category: "Groceries", Amount: money.MustNew(-6000, "EUR")},
+		{Amount: money.MustNew(-1650, "EUR")},
+		{Category: "Pharmacy", Amount: money.MustNew(-800, "USD")},
+	}
+	errs := Validate(parent, mixed)
+	if errs["Splits[1]"] != "Category is required" || errs["Splits[2]"] != "Amount must be in EUR" {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	if errs := Validate(parent, valid[:1]); errs["Splits"] == "" {
+		t.Errorf("Expected a single split to be rejected, got %v", errs)
+	}
+	if errs := Validate(parent, nil); len(errs) > 0 {
+		t.Errorf("Expected an unsplit transaction to be valid, got %v", errs)
+	}
+}
+
+func TestEven(t *testing.T) {
+	splits, err := Even(money.MustNew(-1000, "EUR"), []string{"A", "B", "C"}, []int64{1, 1, 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if errs := Validate(money.MustNew(-1000, "EUR"), splits); len(errs) > 0 {
+		t.Errorf("Expected even splits to be valid, got %v", errs)
+	}
+	if splits[0].Amount.Amount() != -334 || splits[2].Amount.Amount() != -333 {
+		t.Errorf("Unexpected split amounts %v", splits)
+	}
+
+	if _, err := Even(money.MustNew(-1000, "EUR"), []string{"A"}, []int64{1, 1}); err == nil {
+		t.Errorf("Expected an error for mismatched categories and ratios")
+	}
+}
+
+func TestByCategory(t *testing.T) {
+	parent := money.MustNew(-5000, "EUR")
+
+	unsplit, _ := ByCategory("Dining", parent, nil)
+	if len(unsplit) != 1 || unsplit[0].Category != "Dining" || !unsplit[0].Amount.Equal(parent) {
+		t.Errorf("Expected the parent category, got %v", unsplit)
+	}
+
+	split, err := ByCategory("Ignored", parent, []Split{
+		{Category: "Groceries", Amount: money.MustNew(-2000, "EUR")},
+		{Category: "Household", Amount: money.MustNew(-1000, "EUR")},
+		{Category: "Groceries", Amount: money.MustNew(-2000, "EUR")},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(split) != 2 || split[0].Category != "Groceries" || split[0].Amount.Amount() != -4000 {
+		t.Errorf("Unexpected category amounts %v", split)
+	}
+}
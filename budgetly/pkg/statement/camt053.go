@@ -0,0 +1,246 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Camt053Parser parses ISO 20022 camt.053 (Bank to Customer Statement) XML files.
+// Element names are matched without their namespace, so any camt.053.001.xx version is accepted.
+type Camt053Parser struct{}
+
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	ID       string        `xml:"Id"`
+	IBAN     string        `xml:"Acct>Id>IBAN"`
+	Other    string        `xml:"Acct>Id>Othr>Id"`
+	Currency string        `xml:"Acct>Ccy"`
+	Balances []camtBalance `xml:"Bal"`
+	Entries  []camtEntry   `xml:"Ntry"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtBalance struct {
+	Code        string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount      camtAmount `xml:"Amt"`
+	CreditDebit string     `xml:"CdtDbtInd"`
+	Date        camtDate   `xml:"Dt"`
+}
+
+type camtParty struct {
+	Name string `xml:"Nm"`
+}
+
+type camtAccount struct {
+	IBAN  string `xml:"Id>IBAN"`
+	Other string `xml:"Id>Othr>Id"`
+}
+
+type camtTransactionDetails struct {
+	EndToEndID      string      `xml:"Refs>EndToEndId"`
+	ServicerRef     string      `xml:"Refs>AcctSvcrRef"`
+	Debtor          camtParty   `xml:"RltdPties>Dbtr"`
+	DebtorAccount   camtAccount `xml:"RltdPties>DbtrAcct"`
+	Creditor        camtParty   `xml:"RltdPties>Cdtr"`
+	CreditorAccount camtAccount `xml:"RltdPties>CdtrAcct"`
+	Unstructured    []string    `xml:"RmtInf>Ustrd"`
+	CreditorRef     []string    `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	AdditionalInfo  string      `xml:"AddtlTxInf"`
+}
+
+type camtEntry struct {
+	EntryRef       string                   `xml:"NtryRef"`
+	ServicerRef    string                   `xml:"AcctSvcrRef"`
+	Amount         camtAmount               `xml:"Amt"`
+	CreditDebit    string                   `xml:"CdtDbtInd"`
+	BookingDate    camtDate                 `xml:"BookgDt"`
+	ValueDate      camtDate                 `xml:"ValDt"`
+	Details        []camtTransactionDetails `xml:"NtryDtls>TxDtls"`
+	AdditionalInfo string                   `xml:"AddtlNtryInf"`
+}
+
+// Parse decodes a camt.053 document and returns one Statement per <Stmt> element.
+func (p *Camt053Parser) Parse(r io.Reader) ([]Statement, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error decoding camt.053 document: %w", err)
+	}
+
+	statements := make([]Statement, 0, len(doc.Statements))
+	for _, stmt := range doc.Statements {
+		statement, err := stmt.normalize()
+		if err != nil {
+			return nil, fmt.Errorf("error parsing camt.053 statement %q: %w", stmt.ID, err)
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+// normalize converts a decoded camt.053 statement into a Statement.
+func (s camtStatement) normalize() (Statement, error) {
+	statement := Statement{
+		ID:          s.ID,
+		AccountIBAN: firstNonEmpty(s.IBAN, s.Other),
+		Currency:    s.Currency,
+	}
+
+	for _, bal := range s.Balances {
+		balance, err := bal.normalize()
+		if err != nil {
+			return Statement{}, err
+		}
+		switch bal.Code {
+		case "OPBD", "PRCD":
+			statement.OpeningBalance = balance
+		case "CLBD":
+			statement.ClosingBalance = balance
+		}
+	}
+	if statement.Currency == "" {
+		statement.Currency = statement.ClosingBalance.Currency
+	}
+
+	for _, entry := range s.Entries {
+		line, err := entry.normalize(statement.Currency)
+		if err != nil {
+			return Statement{}, err
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+	return statement, nil
+}
+
+// normalize converts a decoded camt.053 balance into a Balance.
+func (b camtBalance) normalize() (Balance, error) {
+	amount, err := parseAmount(b.Amount.Value, b.Amount.Currency)
+	if err != nil {
+		return Balance{}, err
+	}
+	if b.CreditDebit == "DBIT" {
+		amount = -amount
+	}
+
+	date, err := b.Date.parse()
+	if err != nil {
+		return Balance{}, err
+	}
+	return Balance{Date: date, Amount: amount, Currency: b.Amount.Currency}, nil
+}
+
+// normalize converts a decoded camt.053 entry into a Line.
+// The counterparty is the creditor for debits and the debtor for credits.
+func (e camtEntry) normalize(defaultCurrency string) (Line, error) {
+	currency := firstNonEmpty(e.Amount.Currency, defaultCurrency)
+	amount, err := parseAmount(e.Amount.Value, currency)
+	if err != nil {
+		return Line{}, err
+	}
+
+	debit := e.CreditDebit == "DBIT"
+	if debit {
+		amount = -amount
+	}
+
+	bookingDate, err := e.BookingDate.parse()
+	if err != nil {
+		return Line{}, err
+	}
+	valueDate, err := e.ValueDate.parse()
+	if err != nil {
+		return Line{}, err
+	}
+	if valueDate.IsZero() {
+		valueDate = bookingDate
+	}
+
+	line := Line{
+		Reference:   firstNonEmpty(e.ServicerRef, e.EntryRef),
+		BookingDate: bookingDate,
+		ValueDate:   valueDate,
+		Amount:      amount,
+		Currency:    currency,
+	}
+
+	var remittance []string
+	for _, tx := range e.Details {
+		if line.Reference == "" {
+			line.Reference = firstNonEmpty(tx.ServicerRef, tx.EndToEndID)
+		}
+
+		party, account := tx.Debtor, tx.DebtorAccount
+		if debit {
+			party, account = tx.Creditor, tx.CreditorAccount
+		}
+		if line.CounterpartyName == "" {
+			line.CounterpartyName = strings.TrimSpace(party.Name)
+		}
+		if line.CounterpartyIBAN == "" {
+			line.CounterpartyIBAN = firstNonEmpty(account.IBAN, account.Other)
+		}
+
+		remittance = append(remittance, tx.Unstructured...)
+		remittance = append(remittance, tx.CreditorRef...)
+		if len(tx.Unstructured) == 0 && len(tx.CreditorRef) == 0 && tx.AdditionalInfo != "" {
+			remittance = append(remittance, tx.AdditionalInfo)
+		}
+	}
+	if len(remittance) == 0 && e.AdditionalInfo != "" {
+		remittance = append(remittance, e.AdditionalInfo)
+	}
+	line.RemittanceInfo = joinText(remittance)
+
+	return line, nil
+}
+
+// parse returns the date of a camt.053 date choice (<Dt> or <DtTm>), truncated to the day.
+func (d camtDate) parse() (time.Time, error) {
+	switch {
+	case d.Date != "":
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(d.Date))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q: %w", d.Date, err)
+		}
+		return date, nil
+	case d.DateTime != "":
+		value := strings.TrimSpace(d.DateTime)
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+			if dateTime, err := time.Parse(layout, value); err == nil {
+				return time.Date(dateTime.Year(), dateTime.Month(), dateTime.Day(), 0, 0, 0, 0, time.UTC), nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid date time %q", d.DateTime)
+	default:
+		return time.Time{}, nil
+	}
+}
+
+// firstNonEmpty returns the first value that is not blank.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// joinText joins the non-blank parts with a single space and collapses repeated whitespace.
+func joinText(parts []string) string {
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
@@ -0,0 +1,287 @@
+package statement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MT940Parser parses SWIFT MT940 (Customer Statement Message) files.
+// Both bare tag files and files wrapped in SWIFT {1:}{2:}{4:} blocks are accepted.
+type MT940Parser struct{}
+
+// mt940Field is a single tagged field, e.g. ":61:" with its (possibly multi-line) value.
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+var (
+	// mt940TagRegex matches the start of a field such as ":20:" or ":60F:".
+	mt940TagRegex = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+
+	// mt940BalanceRegex matches a balance field: mark, date, currency and amount.
+	mt940BalanceRegex = regexp.MustCompile(`^([CD])(\d{6})([A-Z]{3})([\d,]+)$`)
+
+	// mt940LineRegex matches a statement line: value date, optional booking date, mark,
+	// optional funds code, amount, transaction type and the references.
+	mt940LineRegex = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?([\d,]+)([NFS][A-Z0-9]{3})([^\n]*)(?:\n(.*))?$`)
+
+	// mt940SlashKeyRegex matches the structured "/KEY/value" notation used by several banks in :86:.
+	mt940SlashKeyRegex = regexp.MustCompile(`/(TRTP|IBAN|BIC|NAME|REMI|EREF|MARF|CSID|SVCL)/`)
+
+	// mt940SubfieldRegex matches the German "?nn" subfield notation used in :86:.
+	mt940SubfieldRegex = regexp.MustCompile(`\?\d{2}`)
+)
+
+// Parse reads an MT940 file and returns one Statement per :20: block.
+func (p *MT940Parser) Parse(r io.Reader) ([]Statement, error) {
+	fields, err := readMT940Fields(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []Statement
+	var current *Statement
+	var currency string
+
+	for _, field := range fields {
+		if field.tag == "20" {
+			statements = append(statements, Statement{ID: strings.TrimSpace(field.value)})
+			current = &statements[len(statements)-1]
+			currency = ""
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("error parsing MT940 file: field :%s: found before :20:", field.tag)
+		}
+
+		switch field.tag {
+		case "25":
+			current.AccountIBAN = strings.TrimSpace(field.value)
+		case "60F", "60M":
+			balance, err := parseMT940Balance(field.value)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing MT940 opening balance in %q: %w", current.ID, err)
+			}
+			current.OpeningBalance = balance
+			current.Currency = balance.Currency
+			currency = balance.Currency
+		case "62F", "62M":
+			balance, err := parseMT940Balance(field.value)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing MT940 closing balance in %q: %w", current.ID, err)
+			}
+			current.ClosingBalance = balance
+		case "61":
+			line, err := parseMT940Line(field.value, currency)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing MT940 statement line in %q: %w", current.ID, err)
+			}
+			current.Lines = append(current.Lines, line)
+		case "86":
+			// :86: carries the information to account owner for the preceding :61: line.
+			if len(current.Lines) > 0 {
+				applyMT940Information(&current.Lines[len(current.Lines)-1], field.value)
+			}
+		}
+	}
+
+	return statements, nil
+}
+
+// readMT940Fields splits an MT940 file into tagged fields, joining continuation lines
+// and skipping the SWIFT block envelope.
+func readMT940Fields(r io.Reader) ([]mt940Field, error) {
+	var fields []mt940Field
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		// Strip the SWIFT envelope, e.g. "{1:F01...}{2:I940...}{4:" and the trailing "-}".
+		if strings.HasPrefix(line, "{") {
+			if idx := strings.Index(line, "{4:"); idx >= 0 {
+				line = line[idx+len("{4:"):]
+			} else {
+				continue
+			}
+		}
+		if line == "" || line == "-" || strings.HasPrefix(line, "-}") {
+			continue
+		}
+
+		if match := mt940TagRegex.FindStringSubmatch(line); match != nil {
+			fields = append(fields, mt940Field{tag: match[1], value: match[2]})
+			continue
+		}
+
+		// Lines without a tag continue the previous field.
+		if len(fields) > 0 {
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading MT940 file: %w", err)
+	}
+	return fields, nil
+}
+
+// parseMT940Balance parses the value of a :60a: or :62a: balance field, e.g. "C240131EUR1234,56".
+func parseMT940Balance(value string) (Balance, error) {
+	match := mt940BalanceRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return Balance{}, fmt.Errorf("malformed balance %q", value)
+	}
+
+	date, err := time.Parse("060102", match[2])
+	if err != nil {
+		return Balance{}, fmt.Errorf("invalid balance date %q: %w", match[2], err)
+	}
+
+	amount, err := parseAmount(match[4], match[3])
+	if err != nil {
+		return Balance{}, err
+	}
+	if match[1] == "D" {
+		amount = -amount
+	}
+	return Balance{Date: date, Amount: amount, Currency: match[3]}, nil
+}
+
+// parseMT940Line parses the value of a :61: statement line field.
+func parseMT940Line(value, currency string) (Line, error) {
+	match := mt940LineRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return Line{}, fmt.Errorf("malformed statement line %q", value)
+	}
+
+	valueDate, err := time.Parse("060102", match[1])
+	if err != nil {
+		return Line{}, fmt.Errorf("invalid value date %q: %w", match[1], err)
+	}
+
+	bookingDate := valueDate
+	if match[2] != "" {
+		bookingDate, err = mt940BookingDate(valueDate, match[2])
+		if err != nil {
+			return Line{}, err
+		}
+	}
+
+	amount, err := parseAmount(match[5], currency)
+	if err != nil {
+		return Line{}, err
+	}
+	// "D" debits and "RC" (reversal of credit) both reduce the balance.
+	if match[3] == "D" || match[3] == "RC" {
+		amount = -amount
+	}
+
+	// The references are "<customer ref>[//<bank ref>]", followed by optional supplementary details.
+	customerRef, bankRef, _ := strings.Cut(match[7], "//")
+	reference := firstNonEmpty(bankRef, customerRef)
+	if reference == "NONREF" {
+		reference = ""
+	}
+
+	return Line{
+		Reference:      reference,
+		BookingDate:    bookingDate,
+		ValueDate:      valueDate,
+		Amount:         amount,
+		Currency:       currency,
+		RemittanceInfo: joinText([]string{match[8]}),
+	}, nil
+}
+
+// mt940BookingDate resolves the year of the optional MMDD booking date relative to the value date,
+// handling statements that cross a year boundary.
+func mt940BookingDate(valueDate time.Time, mmdd string) (time.Time, error) {
+	date, err := time.Parse("0102", mmdd)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid booking date %q: %w", mmdd, err)
+	}
+
+	booking := time.Date(valueDate.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	switch {
+	case booking.Sub(valueDate) > 180*24*time.Hour:
+		booking = booking.AddDate(-1, 0, 0)
+	case valueDate.Sub(booking) > 180*24*time.Hour:
+		booking = booking.AddDate(1, 0, 0)
+	}
+	return booking, nil
+}
+
+// applyMT940Information fills the counterparty and remittance details of a line from a :86: field.
+// It understands the German "?nn" subfield layout and the "/KEY/value" layout, and falls back
+// to treating the whole field as free-form remittance text.
+func applyMT940Information(line *Line, value string) {
+	switch {
+	case mt940SubfieldRegex.MatchString(value):
+		applyMT940Subfields(line, value)
+	case mt940SlashKeyRegex.MatchString(value):
+		applyMT940SlashKeys(line, value)
+	default:
+		line.RemittanceInfo = joinText([]string{line.RemittanceInfo, value})
+	}
+}
+
+// applyMT940Subfields parses the "?nn" subfield layout, e.g. "166?00SEPA-UEBERWEISUNG?20Invoice 1?32ACME".
+func applyMT940Subfields(line *Line, value string) {
+	value = strings.ReplaceAll(value, "\n", "")
+
+	var remittance, names []string
+	for _, part := range strings.Split(value, "?")[1:] {
+		if len(part) < 2 {
+			continue
+		}
+		code, text := part[:2], part[2:]
+		switch {
+		case code >= "20" && code <= "29", code >= "60" && code <= "63":
+			remittance = append(remittance, text)
+		case code == "31":
+			line.CounterpartyIBAN = strings.TrimSpace(text)
+		case code == "32" || code == "33":
+			names = append(names, text)
+		}
+	}
+
+	if len(names) > 0 {
+		line.CounterpartyName = joinText(names)
+	}
+	if len(remittance) > 0 {
+		line.RemittanceInfo = joinText(append([]string{line.RemittanceInfo}, remittance...))
+	}
+}
+
+// applyMT940SlashKeys parses the "/KEY/value" layout, e.g. "/IBAN/NL91ABNA0417164300/NAME/ACME/REMI/Invoice 1".
+func applyMT940SlashKeys(line *Line, value string) {
+	value = strings.ReplaceAll(value, "\n", "")
+
+	keys := mt940SlashKeyRegex.FindAllStringSubmatchIndex(value, -1)
+	for i, key := range keys {
+		end := len(value)
+		if i+1 < len(keys) {
+			end = keys[i+1][0]
+		}
+		name, text := value[key[2]:key[3]], strings.TrimSpace(value[key[1]:end])
+
+		switch name {
+		case "IBAN":
+			line.CounterpartyIBAN = text
+		case "NAME":
+			line.CounterpartyName = text
+		case "REMI":
+			line.RemittanceInfo = joinText([]string{line.RemittanceInfo, text})
+		case "EREF":
+			if line.Reference == "" {
+				line.Reference = text
+			}
+		}
+	}
+}
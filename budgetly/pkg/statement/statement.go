@@ -0,0 +1,125 @@
+// Package statement parses bank statement files into normalized statement lines.
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+// Format identifies a supported bank statement file format.
+type Format string
+
+const (
+	FormatCamt053 Format = "camt.053"
+	FormatMT940   Format = "mt940"
+)
+
+var (
+	ErrUnknownFormat   = errors.New("unknown statement format")
+	ErrInvalidAmount   = errors.New("invalid amount")
+	ErrBalanceMismatch = errors.New("opening balance plus statement lines does not match closing balance")
+)
+
+// Balance is a booked balance reported by the bank at a given date.
+// Amount is expressed in minor units (e.g. cents) and is negative for debit balances.
+type Balance struct {
+	Date     time.Time
+	Amount   int64
+	Currency string
+}
+
+// Line is a single normalized booking on a bank statement.
+// Amount is expressed in minor units, positive for credits and negative for debits.
+type Line struct {
+	Reference        string
+	BookingDate      time.Time
+	ValueDate        time.Time
+	Amount           int64
+	Currency         string
+	CounterpartyName string
+	CounterpartyIBAN string
+	RemittanceInfo   string
+}
+
+// Statement is a normalized bank statement for a single account.
+type Statement struct {
+	ID             string
+	AccountIBAN    string
+	Currency       string
+	OpeningBalance Balance
+	ClosingBalance Balance
+	Lines          []Line
+}
+
+// Parser reads a statement file and returns the statements it contains.
+type Parser interface {
+	Parse(r io.Reader) ([]Statement, error)
+}
+
+// NewParser returns the parser registered for the given format.
+func NewParser(format Format) (Parser, error) {
+	switch Format(strings.ToLower(string(format))) {
+	case FormatCamt053:
+		return &Camt053Parser{}, nil
+	case FormatMT940:
+		return &MT940Parser{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// CheckBalance verifies that the opening balance plus the sum of all lines equals the closing balance.
+func (s *Statement) CheckBalance() error {
+	total := s.OpeningBalance.Amount
+	for _, line := range s.Lines {
+		total += line.Amount
+	}
+
+	if total != s.ClosingBalance.Amount {
+		return fmt.Errorf(
+			"%w: statement %q expected %d, got %d",
+			ErrBalanceMismatch, s.ID, s.ClosingBalance.Amount, total,
+		)
+	}
+	return nil
+}
+
+// parseAmount converts a decimal string such as "1234.5" or "1234,50" into minor units of the currency.
+// An amount whose currency is not known yet, such as an MT940 :61: line before :60F:, or not given,
+// such as a camt.053 entry without Ccy, is read with two decimals.
+func parseAmount(value string, currency string) (int64, error) {
+	value = strings.Replace(strings.TrimSpace(value), ",", ".", 1)
+	if !money.IsCurrency(currency) {
+		return parseMinorUnits(value, 2)
+	}
+
+	amount, err := money.Parse(value, currency)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+	}
+	return amount.Amount(), nil
+}
+
+// parseMinorUnits converts a decimal string into minor units with the given number of decimals.
+// Digits beyond them are only accepted when they are zeros.
+func parseMinorUnits(value string, exponent int) (int64, error) {
+	if value == "" || strings.ContainsAny(value, "/eE") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	amount, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	amount.Mul(amount, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)))
+	if !amount.IsInt() || !amount.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: %q has too many decimals or is out of range", ErrInvalidAmount, value)
+	}
+	return amount.Num().Int64(), nil
+}
@@ -0,0 +1,215 @@
+package statement
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const camt053Sample = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <GrpHdr><MsgId>MSG-1</MsgId></GrpHdr>
+    <Stmt>
+      <Id>STMT-2024-01</Id>
+      <Acct><Id><IBAN>DE89370400440532013000</IBAN></Id><Ccy>EUR</Ccy></Acct>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="EUR">1000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Dt><Dt>2024-01-01</Dt></Dt>
+      </Bal>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="EUR">2950.01</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Dt><Dt>2024-01-31</Dt></Dt>
+      </Bal>
+      <Ntry>
+        <Amt Ccy="EUR">49.99</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><Dt>2024-01-05</Dt></BookgDt>
+        <ValDt><Dt>2024-01-04</Dt></ValDt>
+        <AcctSvcrRef>BANK-REF-1</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <RltdPties>
+            <Cdtr><Nm>Super Market GmbH</Nm></Cdtr>
+            <CdtrAcct><Id><IBAN>DE44500105175407324931</IBAN></Id></CdtrAcct>
+          </RltdPties>
+          <RmtInf><Ustrd>Receipt 4711</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">2000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><DtTm>2024-01-25T08:30:00+01:00</DtTm></BookgDt>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>SALARY-01</EndToEndId></Refs>
+          <RltdPties>
+            <Dbtr><Nm>Employer Ltd</Nm></Dbtr>
+            <DbtrAcct><Id><IBAN>GB29NWBK60161331926819</IBAN></Id></DbtrAcct>
+          </RltdPties>
+          <RmtInf><Ustrd>Salary</Ustrd><Ustrd>January</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+const mt940Sample = `{1:F01BANKDEFFAXXX0000000000}{2:I940BANKDEFFXXXXN}{4:
+:20:STMT-2024-02
+:25:DE89370400440532013000
+:28C:00002/001
+:60F:C240131EUR2950,01
+:61:2402010201DR49,99NDDTNONREF//BANK-REF-2
+:86:105?00SEPA-LASTSCHRIFT?20Mobile phone?21February?31DE0210010010
+9876543210?32Telco AG
+:61:2402150215CR120,00NTRFREF-3
+:86:/TRTP/SEPA OVERBOEKING/IBAN/NL91ABNA0417164300/BIC/ABNANL2A/NAME/J. Doe/REMI/Dinner share/EREF/E2E-3
+:61:2402200220D10,02NMSCNONREF
+:86:Cash withdrawal ATM 42
+:62F:C240229EUR3010,00
+-}`
+
+func TestCamt053Parser(t *testing.T) {
+	statements, err := (&Camt053Parser{}).Parse(strings.NewReader(camt053Sample))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(statements) != 1 {
+		t.Fatalf("Expected 1 statement, got %d", len(statements))
+	}
+
+	stmt := statements[0]
+	if stmt.AccountIBAN != "DE89370400440532013000" || stmt.Currency != "EUR" {
+		t.Errorf("Unexpected account %q/%q", stmt.AccountIBAN, stmt.Currency)
+	}
+	if stmt.OpeningBalance.Amount != 100000 || stmt.ClosingBalance.Amount != 295001 {
+		t.Errorf("Unexpected balances %d/%d", stmt.OpeningBalance.Amount, stmt.ClosingBalance.Amount)
+	}
+	if len(stmt.Lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(stmt.Lines))
+	}
+
+	debit := stmt.Lines[0]
+	if debit.Amount != -4999 {
+		t.Errorf("Expected debit of -4999, got %d", debit.Amount)
+	}
+	if debit.CounterpartyName != "Super Market GmbH" || debit.CounterpartyIBAN != "DE44500105175407324931" {
+		t.Errorf("Unexpected debit counterparty %q/%q", debit.CounterpartyName, debit.CounterpartyIBAN)
+	}
+	if !debit.BookingDate.Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)) ||
+		!debit.ValueDate.Equal(time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected debit dates %v/%v", debit.BookingDate, debit.ValueDate)
+	}
+	if debit.Reference != "BANK-REF-1" || debit.RemittanceInfo != "Receipt 4711" {
+		t.Errorf("Unexpected debit reference/remittance %q/%q", debit.Reference, debit.RemittanceInfo)
+	}
+
+	credit := stmt.Lines[1]
+	if credit.Amount != 200000 || credit.CounterpartyName != "Employer Ltd" {
+		t.Errorf("Unexpected credit %d from %q", credit.Amount, credit.CounterpartyName)
+	}
+	if credit.Reference != "SALARY-01" || credit.RemittanceInfo != "Salary January" {
+		t.Errorf("Unexpected credit reference/remittance %q/%q", credit.Reference, credit.RemittanceInfo)
+	}
+	if !credit.ValueDate.Equal(credit.BookingDate) {
+		t.Errorf("Expected value date to default to booking date, got %v", credit.ValueDate)
+	}
+
+	if err := stmt.CheckBalance(); err != nil {
+		t.Errorf("Expected balance check to pass, got %v", err)
+	}
+}
+
+func TestMT940Parser(t *testing.T) {
+	statements, err := (&MT940Parser{}).Parse(strings.NewReader(mt940Sample))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(statements) != 1 {
+		t.Fatalf("Expected 1 statement, got %d", len(statements))
+	}
+
+	stmt := statements[0]
+	if stmt.ID != "STMT-2024-02" || stmt.Currency != "EUR" {
+		t.Errorf("Unexpected statement %q/%q", stmt.ID, stmt.Currency)
+	}
+	if len(stmt.Lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(stmt.Lines))
+	}
+
+	direct := stmt.Lines[0]
+	if direct.Amount != -4999 || direct.Reference != "BANK-REF-2" {
+		t.Errorf("Unexpected direct debit %d/%q", direct.Amount, direct.Reference)
+	}
+	if direct.CounterpartyName != "Telco AG" || direct.CounterpartyIBAN != "DE02100100109876543210" {
+		t.Errorf("Unexpected direct debit counterparty %q/%q", direct.CounterpartyName, direct.CounterpartyIBAN)
+	}
+	if direct.RemittanceInfo != "Mobile phone February" {
+		t.Errorf("Unexpected direct debit remittance %q", direct.RemittanceInfo)
+	}
+
+	transfer := stmt.Lines[1]
+	if transfer.Amount != 12000 || transfer.CounterpartyName != "J. Doe" ||
+		transfer.CounterpartyIBAN != "NL91ABNA0417164300" || transfer.RemittanceInfo != "Dinner share" {
+		t.Errorf("Unexpected transfer %+v", transfer)
+	}
+	if !transfer.BookingDate.Equal(time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected transfer booking date %v", transfer.BookingDate)
+	}
+
+	cash := stmt.Lines[2]
+	if cash.Amount != -1002 || cash.Reference != "" || cash.RemittanceInfo != "Cash withdrawal ATM 42" {
+		t.Errorf("Unexpected cash withdrawal %+v", cash)
+	}
+
+	if err := stmt.CheckBalance(); err != nil {
+		t.Errorf("Expected balance check to pass, got %v", err)
+	}
+}
+
+func TestCheckBalanceMismatch(t *testing.T) {
+	stmt := Statement{
+		ID:             "broken",
+		OpeningBalance: Balance{Amount: 1000},
+		ClosingBalance: Balance{Amount: 1500},
+		Lines:          []Line{{Amount: 400}},
+	}
+
+	if err := stmt.CheckBalance(); !errors.Is(err, ErrBalanceMismatch) {
+		t.Errorf("Expected ErrBalanceMismatch, got %v", err)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		expected int64
+	}{
+		{"12,5", "EUR", 1250},
+		{"0.01", "USD", 1},
+		{"1500", "JPY", 1500},
+		{"1.250", "KWD", 1250},
+		{"-3.10", "EUR", -310},
+		{"7.000", "EUR", 700},
+		{"12,5", "", 1250},
+		{"-0.40", "XYZ", -40},
+	}
+
+	for _, tt := range tests {
+		amount, err := parseAmount(tt.value, tt.currency)
+		if err != nil || amount != tt.expected {
+			t.Errorf("parseAmount(%q, %q) = %d, %v; expected %d", tt.value, tt.currency, amount, err, tt.expected)
+		}
+	}
+
+	if _, err := parseAmount("1.005", "EUR"); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount for extra decimals, got %v", err)
+	}
+	if _, err := parseAmount("1.005", ""); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount for extra decimals without a currency, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+// Package transfers models money moved between two accounts as a linked pair of transactions.
+package transfers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var (
+	ErrSameAccount     = errors.New("a transfer needs two different accounts")
+	ErrInvalidAmount   = errors.New("transfer amount must be greater than zero")
+	ErrCurrencyMissing = errors.New("transfers between currencies need the received amount")
+	ErrCategoryMissing = errors.New("transfers out of the budget need a category")
+)
+
+// Account is the subset of an account a transfer needs.
+// Off-budget (tracking) accounts hold money that is not part of the envelope budget.
+type Account struct {
+	ID       string
+	Currency string
+	OnBudget bool
+}
+
+// Effect describes how a transfer affects the envelope budget.
+type Effect string
+
+const (
+	// EffectNone moves money within the budget or between tracking accounts.
+	EffectNone Effect = "none"
+	// EffectOutflow moves money out of the budget and is spent from a category.
+	EffectOutflow Effect = "outflow"
+	// EffectInflow moves money into the budget, where it becomes available to assign.
+	EffectInflow Effect = "inflow"
+)
+
+// EffectOf returns how moving money from one account to another affects the budget.
+func EffectOf(from, to Account) Effect {
+	switch {
+	case from.OnBudget && !to.OnBudget:
+		return EffectOutflow
+	case !from.OnBudget && to.OnBudget:
+		return EffectInflow
+	default:
+		return EffectNone
+	}
+}
+
+// Leg is one side of a transfer as it appears in an account's register.
+// Amount is negative on the sending side and positive on the receiving side.
+type Leg struct {
+	AccountID string      `json:"account_id"`
+	Amount    money.Money `json:"amount"`
+	Category  string      `json:"category,omitempty"`
+}
+
+// Transfer is a linked pair of legs. Editing either side keeps the other in sync,
+// and neither leg counts as income or expense in reports.
+type Transfer struct {
+	Date   time.Time `json:"date"`
+	Memo   string    `json:"memo"`
+	Effect Effect    `json:"effect"`
+	From   Leg       `json:"from"`
+	To     Leg       `json:"to"`
+}
+
+// New creates a transfer of sent (in the sending account's currency) from one account to
+// another. For accounts in different currencies received is the amount credited to the
+// receiving account; for the same currency it may be the zero Money. Each amount must be in
+// its account's currency, compared case-insensitively. A category is required when the
+// transfer leaves the budget and is ignored otherwise.
+func New(date time.Time, from, to Account, sent, received money.Money, category string) (Transfer, error) {
+	if from.ID == to.ID {
+		return Transfer{}, ErrSameAccount
+	}
+
+	from.Currency = strings.ToUpper(strings.TrimSpace(from.Currency))
+	to.Currency = strings.ToUpper(strings.TrimSpace(to.Currency))
+	if sent.Currency() != from.Currency {
+		return Transfer{}, fmt.Errorf("%w: sending %s from a %s account", money.ErrCurrencyMismatch, sent.Currency(), from.Currency)
+	}
+	if received != (money.Money{}) && received.Currency() != to.Currency {
+		return Transfer{}, fmt.Errorf("%w: receiving %s in a %s account", money.ErrCurrencyMismatch, received.Currency(), to.Currency)
+	}
+
+	effect := EffectOf(from, to)
+	if effect == EffectOutflow && category == "" {
+		return Transfer{}, ErrCategoryMissing
+	}
+	if effect != EffectOutflow {
+		category = ""
+	}
+
+	t := Transfer{
+		Date:   date,
+		Effect: effect,
+		From:   Leg{AccountID: from.ID, Category: category},
+		To:     Leg{AccountID: to.ID},
+	}
+	if err := t.setAmounts(sent, received, to.Currency); err != nil {
+		return Transfer{}, err
+	}
+	return t, nil
+}
+
+// SetSent changes the amount leaving the sending account. For a same-currency transfer the
+// receiving side follows; for a cross-currency transfer it is scaled by the original rate.
+func (t *Transfer) SetSent(sent money.Money) error {
+	if sent.Currency() != t.From.Amount.Currency() {
+		return fmt.Errorf("%w: %s and %s", money.ErrCurrencyMismatch, sent.Currency(), t.From.Amount.Currency())
+	}
+
+	received := money.Money{}
+	if sent.Currency() != t.To.Amount.Currency() {
+		var err error
+		if received, err = t.scaleReceived(sent); err != nil {
+			return err
+		}
+	}
+	return t.setAmounts(sent, received, t.To.Amount.Currency())
+}
+
+// SetReceived changes the amount arriving in the receiving account, keeping the amount sent
+// unless both accounts share a currency.
+func (t *Transfer) SetReceived(received money.Money) error {
+	if received.Currency() != t.To.Amount.Currency() {
+		return fmt.Errorf("%w: %s and %s", money.ErrCurrencyMismatch, received.Currency(), t.To.Amount.Currency())
+	}
+
+	sent := t.From.Amount.Negate()
+	if received.Currency() == sent.Currency() {
+		sent = received
+	}
+	return t.setAmounts(sent, received, received.Currency())
+}
+
+// Rate returns the exchange rate implied by the two legs (received per unit sent).
+func (t *Transfer) Rate() *big.Rat {
+	sent := t.From.Amount.Negate().Rat()
+	if sent.Sign() == 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Quo(t.To.Amount.Rat(), sent)
+}
+
+// setAmounts validates and stores both legs.
+func (t *Transfer) setAmounts(sent, received money.Money, toCurrency string) error {
+	if !sent.IsPositive() {
+		return ErrInvalidAmount
+	}
+
+	if sent.Currency() == toCurrency {
+		received = sent
+	} else if received.Currency() != toCurrency {
+		return fmt.Errorf("%w: %s to %s", ErrCurrencyMissing, sent.Currency(), toCurrency)
+	} else if !received.IsPositive() {
+		return ErrInvalidAmount
+	}
+
+	t.From.Amount = sent.Negate()
+	t.To.Amount = received
+	return nil
+}
+
+// scaleReceived keeps the current implied rate when the amount sent changes.
+func (t *Transfer) scaleReceived(sent money.Money) (money.Money, error) {
+	received := new(big.Rat).Mul(sent.Rat(), t.Rate())
+	return money.FromRat(received, t.To.Amount.Currency())
+}
@@ -0,0 +1,99 @@
+package transfers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ZiadMansourM/budgetly/pkg/money"
+)
+
+var (
+	checking = Account{ID: "checking", Currency: "EUR", OnBudget: true}
+	savings  = Account{ID: "savings", Currency: "EUR", OnBudget: true}
+	pension  = Account{ID: "pension", Currency: "EUR", OnBudget: false}
+	dollars  = Account{ID: "dollars", Currency: "USD", OnBudget: true}
+	today    = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestNewSameCurrency(t *testing.T) {
+	transfer, err := New(today, checking, savings, money.MustNew(50000, "EUR"), money.Money{}, "Ignored")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transfer.Effect != EffectNone || transfer.From.Category != "" {
+		t.Errorf("Expected an on-budget transfer to need no category, got %+v", transfer)
+	}
+	if transfer.From.Amount.Amount() != -50000 || transfer.To.Amount.Amount() != 50000 {
+		t.Errorf("Unexpected legs %+v / %+v", transfer.From, transfer.To)
+	}
+
+	if err := transfer.SetReceived(money.MustNew(42000, "EUR")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transfer.From.Amount.Amount() != -42000 {
+		t.Errorf("Expected editing one side to update the other, got %+v", transfer.From)
+	}
+
+	if _, err := New(today, checking, checking, money.MustNew(1, "EUR"), money.Money{}, ""); !errors.Is(err, ErrSameAccount) {
+		t.Errorf("Expected ErrSameAccount, got %v", err)
+	}
+	if _, err := New(today, checking, savings, money.MustNew(-1, "EUR"), money.Money{}, ""); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestBudgetEffect(t *testing.T) {
+	if _, err := New(today, checking, pension, money.MustNew(1000, "EUR"), money.Money{}, ""); !errors.Is(err, ErrCategoryMissing) {
+		t.Errorf("Expected ErrCategoryMissing for a transfer out of the budget, got %v", err)
+	}
+
+	out, err := New(today, checking, pension, money.MustNew(1000, "EUR"), money.Money{}, "Retirement")
+	if err != nil || out.Effect != EffectOutflow || out.From.Category != "Retirement" {
+		t.Errorf("Expected a categorized outflow, got %+v, %v", out, err)
+	}
+
+	in, err := New(today, pension, checking, money.MustNew(1000, "EUR"), money.Money{}, "Retirement")
+	if err != nil || in.Effect != EffectInflow || in.From.Category != "" {
+		t.Errorf("Expected an uncategorized inflow, got %+v, %v", in, err)
+	}
+}
+
+func TestCrossCurrency(t *testing.T) {
+	if _, err := New(today, checking, dollars, money.MustNew(10000, "EUR"), money.Money{}, ""); !errors.Is(err, ErrCurrencyMissing) {
+		t.Errorf("Expected ErrCurrencyMissing, got %v", err)
+	}
+
+	if _, err := New(today, checking, dollars, money.MustNew(10000, "USD"), money.MustNew(10850, "USD"), ""); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch for an amount sent in another currency, got %v", err)
+	}
+	if _, err := New(today, checking, dollars, money.MustNew(10000, "EUR"), money.MustNew(10850, "GBP"), ""); !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch for an amount received in another currency, got %v", err)
+	}
+	lower := Account{ID: "lower", Currency: "usd", OnBudget: true}
+	if _, err := New(today, checking, lower, money.MustNew(10000, "EUR"), money.MustNew(10850, "USD"), ""); err != nil {
+		t.Errorf("Expected account currencies to be compared case-insensitively, got %v", err)
+	}
+
+	transfer, err := New(today, checking, dollars, money.MustNew(10000, "EUR"), money.MustNew(10850, "USD"), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transfer.From.Amount.String() != "-100.00 EUR" || transfer.To.Amount.String() != "108.50 USD" {
+		t.Errorf("Unexpected legs %v / %v", transfer.From.Amount, transfer.To.Amount)
+	}
+
+	if err := transfer.SetSent(money.MustNew(20000, "EUR")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transfer.To.Amount.String() != "217.00 USD" {
+		t.Errorf("Expected the received side to keep the implied rate, got %v", transfer.To.Amount)
+	}
+
+	if err := transfer.SetReceived(money.MustNew(21500, "USD")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if transfer.From.Amount.String() != "-200.00 EUR" || transfer.To.Amount.String() != "215.00 USD" {
+		t.Errorf("Expected only the received side to change, got %v / %v", transfer.From.Amount, transfer.To.Amount)
+	}
+}
@@ -96,3 +96,24 @@ func Min(min int) ValidationRuleFunc {
 		}
 	}
 }
+
+// Regex rule validates that the field matches the regular expression `pattern`.
+func Regex(pattern string) ValidationRuleFunc {
+	re := regexp.MustCompile(pattern)
+	return func() ValidationRule {
+		return ValidationRule{
+			Name:      "regex",
+			RuleValue: re,
+			ErrorMessageFunc: func(rule ValidationRule) string {
+				return fmt.Sprintf("%s has an invalid format", rule.FieldName)
+			},
+			ValidationFunc: func(rule ValidationRule) bool {
+				str, ok := rule.FieldValue.(string)
+				if !ok {
+					return false
+				}
+				return rule.RuleValue.(*regexp.Regexp).MatchString(str)
+			},
+		}
+	}
+}
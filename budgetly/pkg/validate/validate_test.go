@@ -112,3 +112,18 @@ func TestMin(t *testing.T) {
 		t.Errorf("Expected %s to pass min length validation", rule.FieldValue)
 	}
 }
+
+func TestRegex(t *testing.T) {
+	rule := Regex(`^#[0-9a-fA-F]{6}$`)()
+	rule.FieldName = "color"
+	rule.FieldValue = "red"
+
+	if rule.ValidationFunc(rule) {
+		t.Errorf("Expected %s to fail regex validation", rule.FieldValue)
+	}
+
+	rule.FieldValue = "#FF8800"
+	if !rule.ValidationFunc(rule) {
+		t.Errorf("Expected %s to pass regex validation", rule.FieldValue)
+	}
+}
@@ -0,0 +1,244 @@
+// Package webhook signs and sends outbound webhook deliveries.
+//
+// Each delivery is a POST of a JSON envelope. The Budgetly-Signature header carries the
+// send time and an HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription's
+// secret, e.g. "t=1700000000,v1=5257a869...". Receivers recompute the HMAC and reject
+// stale timestamps to guard against replays.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrExpired          = errors.New("webhook timestamp outside tolerance")
+	ErrUnknownEventType = errors.New("unknown webhook event type")
+)
+
+// Delivery headers.
+const (
+	SignatureHeader = "Budgetly-Signature"
+	EventHeader     = "Budgetly-Event"
+	DeliveryHeader  = "Budgetly-Delivery"
+)
+
+// Retry policy.
+const (
+	// MaxAttempts is how many times a delivery is tried before it is marked failed.
+	MaxAttempts = 8
+	// DisableAfter is how many consecutive failed attempts disable a subscription.
+	DisableAfter = 20
+
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 12 * time.Hour
+	// maxResponseBody caps how much of a receiver's response is kept for the delivery log.
+	maxResponseBody = 1024
+)
+
+// Event types that can be subscribed to.
+const (
+	TransactionCreated = "transaction.created"
+	TransactionUpdated = "transaction.updated"
+	TransactionDeleted = "transaction.deleted"
+	BudgetOverspent    = "budget.overspent"
+	ImportCompleted    = "import.completed"
+	AlertTriggered     = "alert.triggered"
+	// Ping is sent on request to test a subscription; it is delivered whatever the filter.
+	Ping = "ping"
+)
+
+// EventTypes lists the event types subscriptions may filter on.
+var EventTypes = []string{
+	TransactionCreated,
+	TransactionUpdated,
+	TransactionDeleted,
+	BudgetOverspent,
+	ImportCompleted,
+	AlertTriggered,
+}
+
+// ValidFilter reports whether a subscription filter is "*", a known event type, or a
+// wildcard over known types such as "transaction.*".
+func ValidFilter(filter string) bool {
+	for _, eventType := range EventTypes {
+		if Matches([]string{filter}, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether an event type passes any of the subscription filters.
+func Matches(filters []string, eventType string) bool {
+	if eventType == Ping {
+		return true
+	}
+	for _, filter := range filters {
+		if filter == "*" || filter == eventType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(filter, ".*"); ok && strings.HasPrefix(eventType, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Envelope is the JSON body of every delivery.
+type Envelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewEnvelope wraps event data in an envelope with a fresh random ID.
+func NewEnvelope(eventType string, data any, now time.Time) (Envelope, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return Envelope{}, err
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{ID: "evt_" + id, Type: eventType, CreatedAt: now.UTC(), Data: raw}, nil
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + secret, nil
+}
+
+// Sign returns the signature header value for a body sent at timestamp.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp.Unix(), hex.EncodeToString(mac(secret, timestamp.Unix(), body)))
+}
+
+// Verify checks a signature header against the body, rejecting timestamps more than
+// tolerance away from now. Receivers written in Go can use it directly.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var timestamp int64
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+			}
+			timestamp = t
+		case "v1":
+			if signature, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return fmt.Errorf("%w: missing timestamp or signature", ErrInvalidSignature)
+	}
+
+	if age := now.Sub(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: signed %s ago", ErrExpired, age.Round(time.Second))
+	}
+	expected := mac(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+func mac(secret string, timestamp int64, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// Backoff returns how long to wait after the given failed attempt (1-based) before the
+// next one: 30s, 1m, 2m, 4m... capped at 12 hours.
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := baseBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// Result is the outcome of one delivery attempt.
+type Result struct {
+	StatusCode int
+	// Body is the start of the receiver's response, kept for the delivery log.
+	Body     string
+	Duration time.Duration
+	Err      error
+}
+
+// OK reports whether the receiver accepted the delivery with a 2xx response.
+func (r Result) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+// Send posts a signed body to the subscription URL. Transport errors and non-2xx
+// responses are reported in the result rather than as an error.
+func Send(ctx context.Context, client *http.Client, url, secret, deliveryID, eventType string, body []byte, now time.Time) Result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Result{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "budgetly-webhooks/1")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(DeliveryHeader, deliveryID)
+	req.Header.Set(SignatureHeader, Sign(secret, now, body))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{Duration: time.Since(start), Err: err}
+	}
+	defer resp.Body.Close()
+
+	response, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	// Drain the rest so the connection can be reused.
+	io.Copy(io.Discard, resp.Body)
+
+	result := Result{StatusCode: resp.StatusCode, Body: string(response), Duration: time.Since(start)}
+	if !result.OK() {
+		result.Err = fmt.Errorf("receiver responded with %s", resp.Status)
+	}
+	return result
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
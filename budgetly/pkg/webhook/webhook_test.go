@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret := "whsec_test"
+	body := []byte(`{"id":"evt_1"}`)
+	sent := time.Unix(1700000000, 0)
+
+	header := Sign(secret, sent, body)
+	if !strings.HasPrefix(header, "t=1700000000,v1=") {
+		t.Fatalf("Unexpected header %q", header)
+	}
+	if err := Verify(secret, header, body, sent.Add(time.Minute), 5*time.Minute); err != nil {
+		t.Errorf("Expected a valid signature, got %v", err)
+	}
+	if err := Verify(secret, header, []byte(`{"id":"evt_2"}`), sent, 5*time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a tampered body, got %v", err)
+	}
+	if err := Verify("whsec_other", header, body, sent, 5*time.Minute); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for another secret, got %v", err)
+	}
+	if err := Verify(secret, header, body, sent.Add(time.Hour), 5*time.Minute); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected ErrExpired for a replay, got %v", err)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		filters   []string
+		eventType string
+		want      bool
+	}{
+		{[]string{"*"}, BudgetOverspent, true},
+		{[]string{TransactionCreated}, TransactionCreated, true},
+		{[]string{TransactionCreated}, TransactionDeleted, false},
+		{[]string{"transaction.*"}, TransactionDeleted, true},
+		{[]string{"transaction.*"}, ImportCompleted, false},
+		{nil, Ping, true},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.filters, tt.eventType); got != tt.want {
+			t.Errorf("Matches(%v, %s) = %v, want %v", tt.filters, tt.eventType, got, tt.want)
+		}
+	}
+
+	if !ValidFilter("budget.*") || ValidFilter("account.*") || ValidFilter("transaction.renamed") {
+		t.Errorf("Unexpected filter validation")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	want := map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 5: 8 * time.Minute, 30: 12 * time.Hour}
+	for attempt, delay := range want {
+		if got := Backoff(attempt); got != delay {
+			t.Errorf("Backoff(%d) = %s, want %s", attempt, got, delay)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		if err := Verify("whsec_test", r.Header.Get(SignatureHeader), body, time.Now(), time.Minute); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("thanks"))
+	}))
+	defer server.Close()
+
+	envelope, err := NewEnvelope(TransactionCreated, map[string]int{"id": 7}, time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	body := []byte(`{"id":"` + envelope.ID + `"}`)
+
+	result := Send(context.Background(), server.Client(), server.URL, "whsec_test", "42", TransactionCreated, body, time.Now())
+	if !result.OK() || result.Body != "thanks" {
+		t.Fatalf("Expected a successful delivery, got %+v", result)
+	}
+	if received.Get(EventHeader) != TransactionCreated || received.Get(DeliveryHeader) != "42" {
+		t.Errorf("Unexpected headers %v", received)
+	}
+
+	result = Send(context.Background(), server.Client(), server.URL, "whsec_wrong", "43", TransactionCreated, body, time.Now())
+	if result.OK() || result.StatusCode != http.StatusUnauthorized || result.Err == nil {
+		t.Errorf("Expected a rejected delivery, got %+v", result)
+	}
+}
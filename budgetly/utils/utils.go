@@ -2,7 +2,12 @@ package utils
 
 import (
 	"encoding/json"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ZiadMansourM/budgetly/pkg/export"
 )
 
 // WriteJson writes a JSON response with the given status code and data
@@ -11,3 +16,51 @@ func WriteJson(w http.ResponseWriter, status int, data any) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
+
+// NegotiateFormat picks the export format a client asked for, preferring the ?format=
+// parameter over the Accept header. It returns false when JSON should be served instead.
+func NegotiateFormat(r *http.Request) (export.Format, bool, error) {
+	if name := r.URL.Query().Get("format"); name == "json" {
+		return "", false, nil
+	} else if name != "" {
+		format, err := export.ParseFormat(name)
+		if err != nil {
+			return "", false, err
+		}
+		return format, true, nil
+	}
+
+	// Take the most preferred media type we can serve; ties go to the first listed.
+	var best export.Format
+	bestQ := 0.0
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		if mediaType == "application/json" {
+			best, bestQ = "", q
+		} else if format, ok := export.FormatFor(mediaType); ok {
+			best, bestQ = format, q
+		}
+	}
+	return best, best != "", nil
+}
+
+// WriteExport streams a report as a file download in the given format. Headers are sent
+// before rendering starts, so an error can only be logged, not reported to the client.
+func WriteExport(w http.ResponseWriter, format export.Format, filename string, report export.Report) error {
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename + format.Extension()}))
+	w.WriteHeader(http.StatusOK)
+	return export.Write(w, format, report)
+}
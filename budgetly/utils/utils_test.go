@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ZiadMansourM/budgetly/pkg/export"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		target string
+		accept string
+		want   export.Format
+	}{
+		{"/reports", "", ""},
+		{"/reports?format=pdf", "text/csv", export.PDF},
+		{"/reports?format=json", "text/csv", ""},
+		{"/reports", "text/csv", export.CSV},
+		{"/reports", "text/csv;q=0.2, application/json;q=0.8", ""},
+		{"/reports", "text/html, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet;q=0.9", export.XLSX},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodGet, test.target, nil)
+		r.Header.Set("Accept", test.accept)
+		format, ok, err := NegotiateFormat(r)
+		if err != nil || format != test.want || ok != (test.want != "") {
+			t.Errorf("%s (Accept %q): expected %q, got %q, %v, %v", test.target, test.accept, test.want, format, ok, err)
+		}
+	}
+
+	if _, _, err := NegotiateFormat(httptest.NewRequest(http.MethodGet, "/reports?format=docx", nil)); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+func TestWriteExport(t *testing.T) {
+	report := export.Report{
+		Title: "Payees",
+		Sheets: []export.Sheet{{
+			Name:    "Payees",
+			Columns: []export.Column{{Title: "Payee", Kind: export.Text}, {Title: "Count", Kind: export.Number}},
+			Rows:    export.SliceRows([][]any{{"Bakery", 4}}),
+		}},
+	}
+
+	w := httptest.NewRecorder()
+	if err := WriteExport(w, export.CSV, "payees", report); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := w.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Errorf("Unexpected Content-Type %q", got)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "attachment; filename=payees.csv" {
+		t.Errorf("Unexpected Content-Disposition %q", got)
+	}
+	if w.Body.String() != "Payee,Count\nBakery,4\n" {
+		t.Errorf("Unexpected body:\n%s", w.Body.String())
+	}
+}
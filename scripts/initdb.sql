@@ -6,3 +6,389 @@ CREATE TABLE users (
     password_hash VARCHAR(255) NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+
+-- Create Households Table
+CREATE TABLE households (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    reporting_currency CHAR(3) NOT NULL DEFAULT 'EUR',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Accounts Table
+CREATE TABLE accounts (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    iban VARCHAR(34),
+    currency CHAR(3) NOT NULL,
+    on_budget BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Transactions Table
+CREATE TABLE transactions (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    amount VARCHAR(40) NOT NULL,
+    payee VARCHAR(200) NOT NULL DEFAULT '',
+    memo TEXT NOT NULL DEFAULT '',
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    cleared BOOLEAN NOT NULL DEFAULT FALSE,
+    scheduled BOOLEAN NOT NULL DEFAULT FALSE,
+    reconciled BOOLEAN NOT NULL DEFAULT FALSE,
+    external_id VARCHAR(100),
+    transfer_id INTEGER REFERENCES transactions (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX transactions_household_date_idx ON transactions (household_id, date);
+CREATE INDEX transactions_account_date_idx ON transactions (account_id, date);
+
+-- Create Transaction Splits Table
+CREATE TABLE transaction_splits (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    category VARCHAR(100) NOT NULL,
+    memo TEXT NOT NULL DEFAULT '',
+    amount VARCHAR(40) NOT NULL,
+    UNIQUE (transaction_id, position)
+);
+
+-- Create Transaction Lines View: a split transaction counts once per split line, any
+-- other transaction once with its own category and amount
+CREATE VIEW transaction_lines AS
+SELECT t.id, t.household_id, t.account_id, t.date, t.payee, t.scheduled, t.transfer_id, s.category, s.amount
+FROM transactions t
+JOIN transaction_splits s ON s.transaction_id = t.id
+UNION ALL
+SELECT t.id, t.household_id, t.account_id, t.date, t.payee, t.scheduled, t.transfer_id, t.category, t.amount
+FROM transactions t
+WHERE NOT EXISTS (SELECT 1 FROM transaction_splits s WHERE s.transaction_id = t.id);
+
+-- Create Duplicate Candidates Table
+CREATE TABLE duplicate_candidates (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    existing_id INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    amount VARCHAR(40) NOT NULL,
+    payee VARCHAR(200) NOT NULL DEFAULT '',
+    memo TEXT NOT NULL DEFAULT '',
+    external_id VARCHAR(100),
+    score DOUBLE PRECISION NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+CREATE INDEX duplicate_candidates_household_status_idx ON duplicate_candidates (household_id, status);
+
+-- Create Rules Table
+CREATE TABLE rules (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    conditions JSONB NOT NULL,
+    actions JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Recategorizations Table
+CREATE TABLE recategorizations (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    payee VARCHAR(200) NOT NULL,
+    category VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX recategorizations_household_idx ON recategorizations (household_id);
+
+-- Create Exchange Rates Table
+CREATE TABLE exchange_rates (
+    date DATE NOT NULL,
+    base CHAR(3) NOT NULL,
+    quote CHAR(3) NOT NULL,
+    rate NUMERIC(24, 10) NOT NULL CHECK (rate > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (date, base, quote)
+);
+
+-- Create Tags Table
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    color CHAR(7) NOT NULL DEFAULT '#808080',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (household_id, name)
+);
+
+-- Create Transaction Tags Table
+CREATE TABLE transaction_tags (
+    transaction_id INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (transaction_id, tag_id)
+);
+CREATE INDEX transaction_tags_tag_idx ON transaction_tags (tag_id);
+
+-- Create Saved Filters Table
+CREATE TABLE saved_filters (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    query TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (household_id, name)
+);
+
+-- Create Attachments Table
+CREATE TABLE attachments (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    blob_key CHAR(64) NOT NULL,
+    thumbnail_key CHAR(64),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX attachments_transaction_id_idx ON attachments (transaction_id);
+
+-- Create Budgets Table: the amount budgeted for a category in each of the household's
+-- budget periods
+CREATE TABLE budgets (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    category VARCHAR(100) NOT NULL,
+    amount VARCHAR(40) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX budgets_household_category_idx ON budgets (household_id, lower(category));
+
+-- Create Goals Table
+CREATE TABLE goals (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    target VARCHAR(40) NOT NULL,
+    target_date DATE,
+    category VARCHAR(100),
+    account_id INTEGER REFERENCES accounts (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((category IS NULL) <> (account_id IS NULL))
+);
+CREATE INDEX goals_household_idx ON goals (household_id);
+
+-- Create Debts Table: the payoff terms of a liability account, whose balance is the
+-- amount owed
+CREATE TABLE debts (
+    account_id INTEGER PRIMARY KEY REFERENCES accounts (id) ON DELETE CASCADE,
+    apr VARCHAR(20) NOT NULL,
+    minimum_payment VARCHAR(40) NOT NULL,
+    priority INTEGER,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Loans Table: the terms of an installment loan tracked in a liability account.
+-- Payments to payee are split into interest and principal using the loan's schedule.
+CREATE TABLE loans (
+    account_id INTEGER PRIMARY KEY REFERENCES accounts (id) ON DELETE CASCADE,
+    principal VARCHAR(40) NOT NULL,
+    apr VARCHAR(20) NOT NULL,
+    term_months INTEGER NOT NULL,
+    first_payment DATE NOT NULL,
+    frequency VARCHAR(20) NOT NULL,
+    extra_per_payment VARCHAR(40),
+    payee VARCHAR(200) NOT NULL,
+    interest_category VARCHAR(100) NOT NULL,
+    principal_category VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Loan Extra Payments Table
+CREATE TABLE loan_extra_payments (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES loans (account_id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    amount VARCHAR(40) NOT NULL
+);
+CREATE INDEX loan_extra_payments_account_idx ON loan_extra_payments (account_id);
+
+-- Create Alert Rules Table
+CREATE TABLE alert_rules (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    category VARCHAR(100),
+    account_id INTEGER,
+    percent INTEGER,
+    amount VARCHAR(40),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Notifications Table
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    rule_id INTEGER REFERENCES alert_rules (id) ON DELETE SET NULL,
+    dedupe_key VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (household_id, dedupe_key)
+);
+CREATE INDEX notifications_unread_idx ON notifications (household_id, created_at) WHERE read_at IS NULL;
+
+-- Create Notification Channels Table
+CREATE TABLE notification_channels (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    target VARCHAR(2048) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Webhook Subscriptions Table
+CREATE TABLE webhook_subscriptions (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    event_types TEXT[] NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Webhook Deliveries Table
+CREATE TABLE webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    response_status INTEGER,
+    response_body TEXT,
+    error TEXT,
+    duration_ms BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_subscription_id_idx ON webhook_deliveries (subscription_id, created_at);
+
+-- Create Payees Table
+CREATE TABLE payees (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    category VARCHAR(100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX payees_name_idx ON payees (household_id, lower(name));
+
+-- Create Payee Aliases Table
+CREATE TABLE payee_aliases (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    payee_id INTEGER NOT NULL REFERENCES payees (id) ON DELETE CASCADE,
+    pattern VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX payee_aliases_pattern_idx ON payee_aliases (household_id, lower(pattern));
+
+-- Create Securities Table
+CREATE TABLE securities (
+    id SERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Security Prices Table
+CREATE TABLE security_prices (
+    security_id INTEGER NOT NULL REFERENCES securities (id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    close VARCHAR(40) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (security_id, date)
+);
+
+-- Create Investment Trades Table
+CREATE TABLE investment_trades (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL,
+    security_id INTEGER NOT NULL REFERENCES securities (id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    trade_date DATE NOT NULL,
+    quantity NUMERIC(24, 8),
+    price VARCHAR(40),
+    fees VARCHAR(40),
+    amount VARCHAR(40),
+    split_from INTEGER,
+    split_to INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX investment_trades_position_idx ON investment_trades (account_id, security_id, trade_date);
+
+-- Create Account Daily Changes Table
+CREATE TABLE account_daily_changes (
+    account_id INTEGER NOT NULL,
+    date DATE NOT NULL,
+    currency CHAR(3) NOT NULL,
+    amount BIGINT NOT NULL,
+    PRIMARY KEY (account_id, date)
+);
+
+-- Create Balance Snapshots Table
+CREATE TABLE balance_snapshots (
+    account_id INTEGER NOT NULL,
+    date DATE NOT NULL,
+    currency CHAR(3) NOT NULL,
+    balance BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, date)
+);
+
+-- Create Fiscal Calendars Table
+CREATE TABLE fiscal_calendars (
+    household_id INTEGER PRIMARY KEY REFERENCES households (id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    start_day INTEGER,
+    anchor DATE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Closed Periods Table
+CREATE TABLE closed_periods (
+    id SERIAL PRIMARY KEY,
+    household_id INTEGER NOT NULL REFERENCES households (id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    closed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (household_id, start_date)
+);
+
+-- Create Reconciliations Table
+CREATE TABLE reconciliations (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    statement_date DATE NOT NULL,
+    statement_balance VARCHAR(40) NOT NULL,
+    adjustment_id INTEGER REFERENCES transactions (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+-- An account is reconciled against one statement at a time.
+CREATE UNIQUE INDEX reconciliations_open_account_idx ON reconciliations (account_id) WHERE finished_at IS NULL;